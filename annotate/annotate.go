@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package annotate
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/trace"
+)
+
+func init() {
+	dsn.RegisterProps("annotate", "application-name")
+}
+
+// Field is a key/value pair included in the comment.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Annotator prepends a comment describing the origin of a command to
+// outgoing language commands.
+type Annotator struct {
+	// Application is included as "application" if it is set.
+	Application string
+	// Fields returns additional fields for the request of ctx, e.g.
+	// the user of the application on whose behalf the request is sent.
+	// It must be safe for concurrent use.
+	Fields func(ctx context.Context) []Field
+}
+
+// Comment returns the comment for the request of ctx, e.g.
+// "/* application='app',trace_id='abc' */", or an empty string if there
+// are no fields.
+//
+// The fields are the application, the trace id of ctx, see
+// trace.TraceID, and the fields returned by .Fields. Values are
+// URL-encoded, so they cannot terminate the comment.
+func (annotator *Annotator) Comment(ctx context.Context) string {
+	fields := []Field{}
+
+	if annotator.Application != "" {
+		fields = append(fields, Field{"application", annotator.Application})
+	}
+
+	if id := trace.TraceID(ctx); id != "" {
+		fields = append(fields, Field{"trace_id", id})
+	}
+
+	if annotator.Fields != nil {
+		fields = append(fields, annotator.Fields(ctx)...)
+	}
+
+	if len(fields) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, fmt.Sprintf("%s='%s'", escape(field.Key), escape(field.Value)))
+	}
+
+	return "/* " + strings.Join(parts, ",") + " */"
+}
+
+// Annotate returns cmd with the comment for the request of ctx
+// prepended. If the comment is empty cmd is returned unchanged.
+func (annotator *Annotator) Annotate(ctx context.Context, cmd string) string {
+	comment := annotator.Comment(ctx)
+	if comment == "" {
+		return cmd
+	}
+
+	return comment + " " + cmd
+}
+
+// escape URL-encodes s, encoding spaces as "%20".
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// FromDSN returns the Annotator configured by the properties of info
+// or nil if annotation is disabled.
+//
+// The property "annotate" enables annotation and the property
+// "application-name" sets .Application.
+func FromDSN(info *dsn.Info) (*Annotator, error) {
+	prop := info.Prop("annotate")
+	if prop == "" {
+		return nil, nil
+	}
+
+	enabled, err := strconv.ParseBool(prop)
+	if err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error parsing bool from annotate '%s': %w", prop, err)
+	}
+
+	if !enabled {
+		return nil, nil
+	}
+
+	return &Annotator{Application: info.Prop("application-name")}, nil
+}
+
+// annotatorHolder allows to store a nil *Annotator in an atomic.Value.
+type annotatorHolder struct {
+	annotator *Annotator
+}
+
+var globalAnnotator atomic.Value
+
+// SetAnnotator sets the global Annotator. Passing nil disables
+// annotation.
+func SetAnnotator(annotator *Annotator) {
+	globalAnnotator.Store(annotatorHolder{annotator: annotator})
+}
+
+type contextKey int
+
+const annotatorKey contextKey = iota
+
+// WithAnnotator returns a context whose requests are annotated by
+// annotator instead of the Annotator of the connection or the global
+// Annotator. Passing nil disables annotation.
+func WithAnnotator(ctx context.Context, annotator *Annotator) context.Context {
+	return context.WithValue(ctx, annotatorKey, annotatorHolder{annotator: annotator})
+}
+
+// From returns the Annotator for the request of ctx. The Annotator set
+// with WithAnnotator takes precedence over fallback, e.g. the
+// Annotator configured for a connection, which takes precedence over
+// the global Annotator.
+func From(ctx context.Context, fallback *Annotator) *Annotator {
+	if ctx != nil {
+		if holder, ok := ctx.Value(annotatorKey).(annotatorHolder); ok {
+			return holder.annotator
+		}
+	}
+
+	if fallback != nil {
+		return fallback
+	}
+
+	holder, _ := globalAnnotator.Load().(annotatorHolder)
+	return holder.annotator
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package annotate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/trace"
+)
+
+func TestAnnotator_Annotate(t *testing.T) {
+	annotator := &Annotator{
+		Application: "billing",
+		Fields: func(ctx context.Context) []Field {
+			return []Field{{"user", "jane doe */ drop table t"}}
+		},
+	}
+
+	ctx := trace.WithTraceID(context.Background(), "abc")
+
+	expected := "/* application='billing',trace_id='abc',user='jane%20doe%20%2A%2F%20drop%20table%20t' */ select 1"
+	if cmd := annotator.Annotate(ctx, "select 1"); cmd != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, cmd)
+	}
+}
+
+func TestAnnotator_Annotate_Empty(t *testing.T) {
+	annotator := &Annotator{}
+
+	if cmd := annotator.Annotate(context.Background(), "select 1"); cmd != "select 1" {
+		t.Errorf("Expected unchanged command, got '%s'", cmd)
+	}
+}
+
+func TestFrom(t *testing.T) {
+	defer SetAnnotator(nil)
+
+	global := &Annotator{Application: "global"}
+	conn := &Annotator{Application: "conn"}
+	scoped := &Annotator{Application: "scoped"}
+
+	SetAnnotator(global)
+
+	if From(context.Background(), nil) != global {
+		t.Errorf("Expected global Annotator")
+	}
+
+	if From(context.Background(), conn) != conn {
+		t.Errorf("Expected Annotator of the connection")
+	}
+
+	if From(WithAnnotator(context.Background(), scoped), conn) != scoped {
+		t.Errorf("Expected Annotator of the context")
+	}
+
+	if From(WithAnnotator(context.Background(), nil), conn) != nil {
+		t.Errorf("Expected annotation to be disabled by the context")
+	}
+}
+
+func TestFromDSN(t *testing.T) {
+	info := dsn.NewInfo()
+
+	if annotator, err := FromDSN(info); annotator != nil || err != nil {
+		t.Errorf("Expected annotation to be disabled, got %v, %v", annotator, err)
+	}
+
+	info.ConnectProps.Set("annotate", "true")
+	info.ConnectProps.Set("application-name", "billing")
+
+	annotator, err := FromDSN(info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if annotator.Application != "billing" {
+		t.Errorf("Expected application 'billing', got '%s'", annotator.Application)
+	}
+
+	info.ConnectProps.Set("annotate", "maybe")
+	if _, err := FromDSN(info); !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package annotate prepends comments describing their origin to the
+language commands sent to the server, so database administrators can
+correlate the monitoring data of the server with the traces of the
+client. The comment lists fields as URL-encoded key/value pairs:
+
+	application='billing',trace_id='4bf92f3577b34da6'
+
+Connections of the tds package annotate their commands if the property
+"annotate" of the dsn is set, the property "application-name" names the
+application:
+
+	info.ConnectProps.Set("annotate", "true")
+	info.ConnectProps.Set("application-name", "billing")
+
+The trace id is read from the context of the request, see
+trace.WithTraceID:
+
+	ctx = trace.WithTraceID(ctx, span.SpanContext().TraceID().String())
+
+An Annotator can also be set globally with SetAnnotator or for a
+single context with WithAnnotator, e.g. to add fields computed from the
+context:
+
+	ctx = annotate.WithAnnotator(ctx, &annotate.Annotator{
+		Application: "billing",
+		Fields: func(ctx context.Context) []annotate.Field {
+			return []annotate.Field{{Key: "user", Value: userFrom(ctx)}}
+		},
+	})
+*/
+package annotate
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package asetime
+
+import (
+	"time"
+)
+
+// The functions in this file convert between time.Time and the
+// representations of the ASE date and time data types:
+//
+//   - DATE: days since 1900-01-01
+//   - TIME: 1/300 second ticks since midnight
+//   - SHORTDATE: days since 1900-01-01 and minutes since midnight
+//   - DATETIME: days since 1900-01-01 and 1/300 second ticks since
+//     midnight
+//   - BIGDATETIME: microseconds since 0000-01-01
+//   - BIGTIME: microseconds since midnight
+
+// sinceEpoch1900 returns the duration between 1900-01-01 and t.
+func sinceEpoch1900(t time.Time) ASEDuration {
+	return DurationFromDateTime(t) - DurationFromDateTime(Epoch1900())
+}
+
+// DateToDays returns the number of days between 1900-01-01 and the
+// date of t as stored in the ASE data type DATE.
+func DateToDays(t time.Time) int {
+	return sinceEpoch1900(t).Days()
+}
+
+// DaysToDate returns the date that is days after 1900-01-01.
+func DaysToDate(days int) time.Time {
+	return Epoch1900().AddDate(0, 0, days)
+}
+
+// TimeToTicks returns the number of 1/300 second ticks between
+// midnight and the time of t as stored in the ASE data type TIME.
+func TimeToTicks(t time.Time) int {
+	return MillisecondToFractionalSecond(DurationFromTime(t).Microseconds())
+}
+
+// TicksToTime returns the time that is ticks 1/300 seconds after
+// midnight on 0001-01-01.
+func TicksToTime(ticks int) time.Time {
+	dur := FractionalSecondToMillisecond(ticks)
+	return EpochRataDie().Add(time.Duration(dur.Milliseconds()) * time.Millisecond)
+}
+
+// DateTimeToDaysTicks returns the number of days between 1900-01-01
+// and t and the number of 1/300 second ticks of the remainder as
+// stored in the ASE data type DATETIME.
+func DateTimeToDaysTicks(t time.Time) (int, int) {
+	dur := sinceEpoch1900(t)
+
+	days := dur.Days()
+	ticks := MillisecondToFractionalSecond(dur.Microseconds() - days*int(Day))
+
+	return days, ticks
+}
+
+// DaysTicksToDateTime returns the time that is days and ticks 1/300
+// seconds after 1900-01-01.
+func DaysTicksToDateTime(days, ticks int) time.Time {
+	dur := FractionalSecondToMillisecond(ticks)
+	return DaysToDate(days).Add(time.Duration(dur.Microseconds()) * time.Microsecond)
+}
+
+// ShortDateTimeToDaysMinutes returns the number of days between
+// 1900-01-01 and t and the number of minutes of the remainder as
+// stored in the ASE data type SHORTDATE.
+func ShortDateTimeToDaysMinutes(t time.Time) (int, int) {
+	dur := sinceEpoch1900(t)
+
+	days := dur.Days()
+	remainder := ASEDuration(dur.Microseconds() - days*int(Day))
+
+	return days, remainder.Minutes()
+}
+
+// DaysMinutesToShortDateTime returns the time that is days and minutes
+// after 1900-01-01.
+func DaysMinutesToShortDateTime(days, minutes int) time.Time {
+	return DaysToDate(days).Add(time.Duration(minutes) * time.Minute)
+}
+
+// BigDateTimeToMicroseconds returns the number of microseconds between
+// 0000-01-01 and t as stored in the ASE data type BIGDATETIME.
+func BigDateTimeToMicroseconds(t time.Time) uint64 {
+	return uint64(DurationFromDateTime(t))
+}
+
+// MicrosecondsToBigDateTime returns the time that is microseconds
+// after 0000-01-01.
+func MicrosecondsToBigDateTime(microseconds uint64) time.Time {
+	dur := ASEDuration(microseconds)
+
+	t := time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC)
+	t = t.AddDate(0, 0, dur.Days())
+	remainder := dur.Microseconds() - (dur.Days() * int(Day))
+
+	return t.Add(time.Duration(remainder) * time.Microsecond)
+}
+
+// BigTimeToMicroseconds returns the number of microseconds between
+// midnight and the time of t as stored in the ASE data type BIGTIME.
+func BigTimeToMicroseconds(t time.Time) uint64 {
+	return uint64(DurationFromTime(t))
+}
+
+// MicrosecondsToBigTime returns the time that is microseconds after
+// midnight on 0001-01-01.
+func MicrosecondsToBigTime(microseconds uint64) time.Time {
+	return EpochRataDie().Add(time.Duration(microseconds) * time.Microsecond)
+}
@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package asetime
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour, min, sec, nsec int) time.Time {
+	return time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+}
+
+func TestDateToDays(t *testing.T) {
+	cases := map[string]struct {
+		t    time.Time
+		days int
+	}{
+		"epoch":            {date(1900, time.January, 1, 0, 0, 0, 0), 0},
+		"day after epoch":  {date(1900, time.January, 2, 0, 0, 0, 0), 1},
+		"day before epoch": {date(1899, time.December, 31, 0, 0, 0, 0), -1},
+		"non-leap 1900":    {date(1900, time.March, 1, 0, 0, 0, 0), 59},
+		"millennium":       {date(2000, time.January, 1, 0, 0, 0, 0), 36524},
+		"leap day":         {date(2000, time.February, 29, 0, 0, 0, 0), 36583},
+		"time is ignored":  {date(2000, time.January, 1, 23, 59, 59, 0), 36524},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := DateToDays(cas.t); recv != cas.days {
+				t.Errorf("Expected %d days, received %d", cas.days, recv)
+			}
+
+			expected := date(cas.t.Year(), cas.t.Month(), cas.t.Day(), 0, 0, 0, 0)
+			if recv := DaysToDate(cas.days); !recv.Equal(expected) {
+				t.Errorf("Expected %s, received %s", expected, recv)
+			}
+		})
+	}
+}
+
+func TestTimeToTicks(t *testing.T) {
+	cases := map[string]struct {
+		t     time.Time
+		ticks int
+		// back is the time after converting the ticks back, as
+		// ticks are less precise than milliseconds.
+		back time.Time
+	}{
+		"midnight": {
+			date(2000, time.January, 1, 0, 0, 0, 0), 0,
+			date(1, time.January, 1, 0, 0, 0, 0),
+		},
+		"one second": {
+			date(2000, time.January, 1, 0, 0, 1, 0), 300,
+			date(1, time.January, 1, 0, 0, 1, 0),
+		},
+		"noon": {
+			date(2000, time.January, 1, 12, 0, 0, 0), 12960000,
+			date(1, time.January, 1, 12, 0, 0, 0),
+		},
+		"ten milliseconds": {
+			date(2000, time.January, 1, 0, 0, 0, int(10*time.Millisecond)), 3,
+			date(1, time.January, 1, 0, 0, 0, int(10*time.Millisecond)),
+		},
+		"rounded to one tick": {
+			date(2000, time.January, 1, 0, 0, 0, int(2*time.Millisecond)), 1,
+			date(1, time.January, 1, 0, 0, 0, int(3*time.Millisecond)),
+		},
+		"last tick": {
+			date(2000, time.January, 1, 23, 59, 59, int(997*time.Millisecond)), 25919999,
+			date(1, time.January, 1, 23, 59, 59, int(996*time.Millisecond)),
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := TimeToTicks(cas.t); recv != cas.ticks {
+				t.Errorf("Expected %d ticks, received %d", cas.ticks, recv)
+			}
+
+			if recv := TicksToTime(cas.ticks); !recv.Equal(cas.back) {
+				t.Errorf("Expected %s, received %s", cas.back, recv)
+			}
+		})
+	}
+}
+
+func TestDateTimeToDaysTicks(t *testing.T) {
+	cases := map[string]struct {
+		t           time.Time
+		days, ticks int
+	}{
+		"epoch":      {date(1900, time.January, 1, 0, 0, 0, 0), 0, 0},
+		"millennium": {date(2000, time.January, 1, 12, 0, 0, 0), 36524, 12960000},
+		"seconds":    {date(2020, time.June, 15, 10, 11, 12, 0), 43995, 11001600},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			days, ticks := DateTimeToDaysTicks(cas.t)
+			if days != cas.days || ticks != cas.ticks {
+				t.Errorf("Expected %d days and %d ticks, received %d days and %d ticks",
+					cas.days, cas.ticks, days, ticks)
+			}
+
+			if recv := DaysTicksToDateTime(cas.days, cas.ticks); !recv.Equal(cas.t) {
+				t.Errorf("Expected %s, received %s", cas.t, recv)
+			}
+		})
+	}
+}
+
+func TestShortDateTimeToDaysMinutes(t *testing.T) {
+	cases := map[string]struct {
+		t             time.Time
+		days, minutes int
+	}{
+		"epoch":       {date(1900, time.January, 1, 0, 0, 0, 0), 0, 0},
+		"millennium":  {date(2000, time.January, 1, 13, 37, 0, 0), 36524, 817},
+		"last minute": {date(2079, time.June, 6, 23, 59, 0, 0), 65535, 1439},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			days, minutes := ShortDateTimeToDaysMinutes(cas.t)
+			if days != cas.days || minutes != cas.minutes {
+				t.Errorf("Expected %d days and %d minutes, received %d days and %d minutes",
+					cas.days, cas.minutes, days, minutes)
+			}
+
+			if recv := DaysMinutesToShortDateTime(cas.days, cas.minutes); !recv.Equal(cas.t) {
+				t.Errorf("Expected %s, received %s", cas.t, recv)
+			}
+		})
+	}
+}
+
+func TestBigDateTimeToMicroseconds(t *testing.T) {
+	cases := map[string]struct {
+		t            time.Time
+		microseconds uint64
+	}{
+		"first day of year one": {
+			date(1, time.January, 1, 0, 0, 0, 0),
+			366 * uint64(Day),
+		},
+		"one microsecond": {
+			date(1, time.January, 1, 0, 0, 0, int(time.Microsecond)),
+			366*uint64(Day) + 1,
+		},
+		"epoch 1900": {
+			date(1900, time.January, 1, 0, 0, 0, 0),
+			uint64(DurationFromDateTime(Epoch1900())),
+		},
+		"microseconds": {
+			date(2020, time.June, 15, 10, 11, 12, int(123456*time.Microsecond)),
+			uint64(DurationFromDateTime(date(2020, time.June, 15, 0, 0, 0, 0))) +
+				uint64(10*Hour+11*Minute+12*Second) + 123456,
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := BigDateTimeToMicroseconds(cas.t); recv != cas.microseconds {
+				t.Errorf("Expected %d microseconds, received %d", cas.microseconds, recv)
+			}
+
+			if recv := MicrosecondsToBigDateTime(cas.microseconds); !recv.Equal(cas.t) {
+				t.Errorf("Expected %s, received %s", cas.t, recv)
+			}
+		})
+	}
+}
+
+func TestBigDateTime_RoundTrip(t *testing.T) {
+	for year := 1; year <= 9999; year += 37 {
+		for _, month := range []time.Month{time.January, time.February, time.December} {
+			expected := date(year, month, 28, 23, 59, 59, int(999999*time.Microsecond))
+
+			recv := MicrosecondsToBigDateTime(BigDateTimeToMicroseconds(expected))
+			if !recv.Equal(expected) {
+				t.Errorf("Expected %s, received %s", expected, recv)
+			}
+
+			if recv := MicrosecondsToTime(TimeToMicroseconds(expected)); !recv.Equal(expected) {
+				t.Errorf("Expected %s from MicrosecondsToTime, received %s", expected, recv)
+			}
+		}
+	}
+}
+
+func TestBigTimeToMicroseconds(t *testing.T) {
+	cases := map[string]struct {
+		t            time.Time
+		microseconds uint64
+	}{
+		"midnight":        {date(1, time.January, 1, 0, 0, 0, 0), 0},
+		"one hour":        {date(1, time.January, 1, 1, 0, 0, int(time.Microsecond)), 3600000001},
+		"last of the day": {date(1, time.January, 1, 23, 59, 59, int(999999*time.Microsecond)), 86399999999},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := BigTimeToMicroseconds(cas.t); recv != cas.microseconds {
+				t.Errorf("Expected %d microseconds, received %d", cas.microseconds, recv)
+			}
+
+			if recv := MicrosecondsToBigTime(cas.microseconds); !recv.Equal(cas.t) {
+				t.Errorf("Expected %s, received %s", cas.t, recv)
+			}
+		})
+	}
+}
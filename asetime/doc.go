@@ -7,4 +7,9 @@
 //
 // The types, functions, and variables/constants defined in this package
 // are mainly used in asetype/goValue.go as well as in asetype/bytes.go.
+//
+// The conversions between time.Time and the representations of the
+// ASE date and time data types (e.g. DateToDays, DateTimeToDaysTicks
+// and BigDateTimeToMicroseconds) are exported to be shared by drivers
+// and tools.
 package asetime
@@ -47,54 +47,35 @@ func (t DataType) Bytes(endian binary.ByteOrder, value interface{}) ([]byte, err
 		}
 		return bs, nil
 	case DATE, DATEN:
-		t := asetime.DurationFromDateTime(value.(time.Time))
-		t -= asetime.DurationFromDateTime(asetime.Epoch1900())
-
 		bs := make([]byte, 4)
-		endian.PutUint32(bs, uint32(t.Days()))
+		endian.PutUint32(bs, uint32(asetime.DateToDays(value.(time.Time))))
 		return bs, nil
 	case TIME, TIMEN:
-		dur := asetime.DurationFromTime(value.(time.Time))
-		fract := asetime.MillisecondToFractionalSecond(dur.Microseconds())
-
 		bs := make([]byte, 4)
-		endian.PutUint32(bs, uint32(fract))
+		endian.PutUint32(bs, uint32(asetime.TimeToTicks(value.(time.Time))))
 		return bs, nil
 	case SHORTDATE:
-		t := asetime.DurationFromDateTime(value.(time.Time))
-		t -= asetime.DurationFromDateTime(asetime.Epoch1900())
-
-		days := t.Days()
-		s := asetime.ASEDuration(t.Microseconds() - days*int(asetime.Day))
+		days, minutes := asetime.ShortDateTimeToDaysMinutes(value.(time.Time))
 
 		bs := make([]byte, 4)
 		// TODO replace all binary.Littleendian
 		binary.LittleEndian.PutUint16(bs[:2], uint16(days))
-		binary.LittleEndian.PutUint16(bs[2:], uint16(s.Minutes()))
+		binary.LittleEndian.PutUint16(bs[2:], uint16(minutes))
 		return bs, nil
 	case DATETIME:
-		t := asetime.DurationFromDateTime(value.(time.Time))
-		t -= asetime.DurationFromDateTime(asetime.Epoch1900())
-
-		days := t.Days()
-		s := t.Microseconds() - days*int(asetime.Day)
-		s = asetime.MillisecondToFractionalSecond(s)
+		days, ticks := asetime.DateTimeToDaysTicks(value.(time.Time))
 
 		bs := make([]byte, 8)
 		binary.LittleEndian.PutUint32(bs[:4], uint32(days))
-		binary.LittleEndian.PutUint32(bs[4:], uint32(s))
+		binary.LittleEndian.PutUint32(bs[4:], uint32(ticks))
 		return bs, nil
 	case BIGDATETIMEN:
-		dur := asetime.DurationFromDateTime(value.(time.Time))
-
 		bs := make([]byte, 8)
-		binary.LittleEndian.PutUint64(bs, uint64(dur))
+		binary.LittleEndian.PutUint64(bs, asetime.BigDateTimeToMicroseconds(value.(time.Time)))
 		return bs, nil
 	case BIGTIMEN:
-		dur := asetime.DurationFromTime(value.(time.Time))
-
 		bs := make([]byte, 8)
-		binary.LittleEndian.PutUint64(bs, uint64(dur))
+		binary.LittleEndian.PutUint64(bs, asetime.BigTimeToMicroseconds(value.(time.Time)))
 		return bs, nil
 	case UNITEXT:
 		// convert go string to utf16 code points
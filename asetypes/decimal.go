@@ -9,6 +9,8 @@ import (
 	"math/big"
 	"strconv"
 	"strings"
+
+	dberrors "github.com/SAP/go-dblib/errors"
 )
 
 // Default properties for ASE data type 'decimal'.
@@ -27,10 +29,10 @@ const (
 
 // Errors of ASE data type 'decimal' operations.
 var (
-	ErrDecimalPrecisionTooHigh         = fmt.Errorf("precision is set to more than %d digits", aseMaxDecimalDigits)
-	ErrDecimalPrecisionTooLow          = fmt.Errorf("precision is set to less than 0 digits")
-	ErrDecimalScaleTooHigh             = fmt.Errorf("scale is set to more than %d digits", aseMaxDecimalDigits)
-	ErrDecimalScaleBiggerThanPrecision = fmt.Errorf("scale is bigger then precision")
+	ErrDecimalPrecisionTooHigh         = dberrors.Errorf(dberrors.CategoryConversion, "precision is set to more than %d digits", aseMaxDecimalDigits)
+	ErrDecimalPrecisionTooLow          = dberrors.New(dberrors.CategoryConversion, "precision is set to less than 0 digits")
+	ErrDecimalScaleTooHigh             = dberrors.Errorf(dberrors.CategoryConversion, "scale is set to more than %d digits", aseMaxDecimalDigits)
+	ErrDecimalScaleBiggerThanPrecision = dberrors.New(dberrors.CategoryConversion, "scale is bigger then precision")
 )
 
 // Number of bytes required to store the integer representation of
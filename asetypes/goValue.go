@@ -9,7 +9,6 @@ import (
 	"encoding/binary"
 	"fmt"
 	"strings"
-	"time"
 	"unicode/utf16"
 
 	"github.com/SAP/go-dblib/asetime"
@@ -180,50 +179,24 @@ func (t DataType) goValue(endian binary.ByteOrder, bs []byte) (interface{}, erro
 		// User must set precision and scale
 		return dec, nil
 	case DATE:
-		x := int32(endian.Uint32(bs))
-		days := asetime.ASEDuration(x) * asetime.Day
-		return asetime.Epoch1900().AddDate(0, 0, days.Days()), nil
+		return asetime.DaysToDate(int(int32(endian.Uint32(bs)))), nil
 	case TIME:
-		x := int(int32(endian.Uint32(bs)))
-		dur := asetime.FractionalSecondToMillisecond(x)
-		t := time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC)
-		return t.Add(time.Duration(dur.Milliseconds()) * time.Millisecond), nil
+		return asetime.TicksToTime(int(int32(endian.Uint32(bs)))), nil
 	case SHORTDATE:
 		days := endian.Uint16(bs[:2])
 		mins := endian.Uint16(bs[2:])
-
-		t := asetime.Epoch1900()
-		t = t.AddDate(0, 0, int(days))
-		t = t.Add(time.Duration(int(mins)) * time.Minute)
-		return t, nil
+		return asetime.DaysMinutesToShortDateTime(int(days), int(mins)), nil
 	case DATETIME:
-		days := asetime.ASEDuration(int32(endian.Uint32(bs[:4]))) * asetime.Day
-		ms := asetime.FractionalSecondToMillisecond(int(endian.Uint32(bs[4:])))
-
-		t := asetime.Epoch1900()
-		t = t.AddDate(0, 0, days.Days())
-		t = t.Add(time.Duration(ms.Microseconds()) * time.Microsecond)
-
-		return t, nil
+		days := int(int32(endian.Uint32(bs[:4])))
+		ticks := int(endian.Uint32(bs[4:]))
+		return asetime.DaysTicksToDateTime(days, ticks), nil
 	case DATETIMEN:
 		// TODO length-based
 		return nil, nil
 	case BIGDATETIMEN:
-		dur := asetime.ASEDuration(endian.Uint64(bs))
-
-		t := time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC)
-		t = t.AddDate(0, 0, dur.Days())
-		ms := dur.Microseconds() - (dur.Days() * int(asetime.Day))
-		t = t.Add(time.Duration(ms) * time.Microsecond)
-
-		return t, nil
+		return asetime.MicrosecondsToBigDateTime(endian.Uint64(bs)), nil
 	case BIGTIMEN:
-		dur := asetime.ASEDuration(endian.Uint64(bs))
-
-		t := asetime.EpochRataDie()
-		t = t.Add(time.Duration(dur) * time.Microsecond)
-
-		return t, nil
+		return asetime.MicrosecondsToBigTime(endian.Uint64(bs)), nil
 	default:
 		return nil, fmt.Errorf("unhandled data type %s", t)
 	}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+)
+
+// Kind is the kind of an audited request.
+type Kind int
+
+// Kinds of audited requests.
+const (
+	KindLanguage Kind = iota
+	KindDynamic
+	KindRPC
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case KindLanguage:
+		return "language"
+	case KindDynamic:
+		return "dynamic"
+	case KindRPC:
+		return "rpc"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(kind))
+	}
+}
+
+// Identity identifies the connection a request was executed on.
+type Identity struct {
+	Host, Port string
+	Username   string
+	Database   string
+	// Server is the name of the server sent during login.
+	Server string
+}
+
+// IdentityFromDSN returns the Identity of connections to the server of
+// info.
+func IdentityFromDSN(info *dsn.Info) Identity {
+	return Identity{
+		Host:     info.Host,
+		Port:     info.Port,
+		Username: info.Username,
+		Database: info.Database,
+	}
+}
+
+func (identity Identity) String() string {
+	return fmt.Sprintf("%s@%s/%s", identity.Username, dsn.JoinHostPort(identity.Host, identity.Port), identity.Database)
+}
+
+// Record describes an executed request.
+type Record struct {
+	// Time is the time the request was sent.
+	Time     time.Time
+	Identity Identity
+	Kind     Kind
+	// Statement is the statement text or the name of the procedure,
+	// passed through Auditor.Redact.
+	Statement string
+	Duration  time.Duration
+	// Err is the error the request failed with or nil.
+	Err error
+}
+
+// Succeeded reports whether the request succeeded.
+func (record Record) Succeeded() bool {
+	return record.Err == nil
+}
+
+// Auditor receives a Record for every executed request.
+type Auditor struct {
+	// Hook is called with the Record of every executed request. It
+	// must be safe for concurrent use.
+	Hook func(ctx context.Context, record Record)
+	// Redact is applied to the statement text before it is recorded,
+	// e.g. RedactLiterals. If Redact is nil the statement is recorded
+	// unchanged.
+	Redact func(statement string) string
+}
+
+// auditorHolder allows to store a nil *Auditor in an atomic.Value.
+type auditorHolder struct {
+	auditor *Auditor
+}
+
+var globalAuditor atomic.Value
+
+// SetAuditor sets the global Auditor. Passing nil disables auditing.
+func SetAuditor(auditor *Auditor) {
+	globalAuditor.Store(auditorHolder{auditor: auditor})
+}
+
+type contextKey int
+
+const auditorKey contextKey = iota
+
+// WithAuditor returns a context whose requests are reported to auditor
+// instead of the global Auditor.
+func WithAuditor(ctx context.Context, auditor *Auditor) context.Context {
+	return context.WithValue(ctx, auditorKey, auditorHolder{auditor: auditor})
+}
+
+// auditorFrom returns the Auditor for ctx.
+func auditorFrom(ctx context.Context) *Auditor {
+	if ctx != nil {
+		if holder, ok := ctx.Value(auditorKey).(auditorHolder); ok {
+			return holder.auditor
+		}
+	}
+
+	holder, _ := globalAuditor.Load().(auditorHolder)
+	return holder.auditor
+}
+
+// Entry is a request being executed.
+type Entry struct {
+	ctx     context.Context
+	auditor *Auditor
+	record  Record
+}
+
+// Start starts the audit of a request executing statement on the
+// connection identified by identity.
+//
+// If auditing is disabled a nil *Entry is returned, whose methods are
+// no-ops.
+func Start(ctx context.Context, identity Identity, kind Kind, statement string) *Entry {
+	auditor := auditorFrom(ctx)
+	if auditor == nil || auditor.Hook == nil {
+		return nil
+	}
+
+	if auditor.Redact != nil {
+		statement = auditor.Redact(statement)
+	}
+
+	return &Entry{
+		ctx:     ctx,
+		auditor: auditor,
+		record: Record{
+			Time:      time.Now(),
+			Identity:  identity,
+			Kind:      kind,
+			Statement: statement,
+		},
+	}
+}
+
+// End records the end of the request with its result err.
+func (entry *Entry) End(err error) {
+	if entry == nil {
+		return
+	}
+
+	entry.record.Duration = time.Since(entry.record.Time)
+	entry.record.Err = err
+	entry.auditor.Hook(entry.ctx, entry.record)
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SAP/go-dblib/dsn"
+)
+
+func TestStart_Disabled(t *testing.T) {
+	entry := Start(context.Background(), Identity{}, KindLanguage, "select 1")
+	if entry != nil {
+		t.Errorf("Expected nil entry, received %v", entry)
+	}
+
+	// Methods of nil entries are no-ops.
+	entry.End(nil)
+}
+
+func TestWithAuditor(t *testing.T) {
+	records := []Record{}
+	ctx := WithAuditor(context.Background(), &Auditor{
+		Hook:   func(ctx context.Context, record Record) { records = append(records, record) },
+		Redact: RedactLiterals,
+	})
+
+	info := dsn.NewInfo()
+	info.Host = "localhost"
+	info.Port = "5000"
+	info.Username = "sa"
+	identity := IdentityFromDSN(info)
+
+	Start(ctx, identity, KindLanguage, "select * from t where a = 'secret'").End(nil)
+
+	errFailed := errors.New("failed")
+	Start(ctx, identity, KindRPC, "sp_who").End(errFailed)
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, received %d", len(records))
+	}
+
+	if records[0].Statement != "select * from t where a = ?" {
+		t.Errorf("Expected redacted statement, received %q", records[0].Statement)
+	}
+
+	if records[0].Identity != identity || records[0].Kind != KindLanguage || !records[0].Succeeded() {
+		t.Errorf("Unexpected record: %+v", records[0])
+	}
+
+	if records[0].Time.IsZero() || records[0].Duration < 0 {
+		t.Errorf("Expected time and duration to be set, received %+v", records[0])
+	}
+
+	if records[1].Succeeded() || !errors.Is(records[1].Err, errFailed) {
+		t.Errorf("Expected failed record, received %+v", records[1])
+	}
+}
+
+func TestSetAuditor(t *testing.T) {
+	count := 0
+	SetAuditor(&Auditor{Hook: func(ctx context.Context, record Record) { count++ }})
+	defer SetAuditor(nil)
+
+	Start(context.Background(), Identity{}, KindLanguage, "select 1").End(nil)
+
+	// The auditor of the context takes precedence.
+	Start(WithAuditor(context.Background(), nil), Identity{}, KindLanguage, "select 1").End(nil)
+
+	if count != 1 {
+		t.Errorf("Expected 1 record, received %d", count)
+	}
+}
+
+func TestRedactLiterals(t *testing.T) {
+	cases := map[string]string{
+		"select 1":                                  "select ?",
+		"select * from t1 where c2 = 'it''s'":       "select * from t1 where c2 = ?",
+		`insert into t values ("a", -1.5e-3, 0x1F)`: "insert into t values (?, -?, ?)",
+		"select @var1, #tmp2.col3 from [my table]":  "select @var1, #tmp2.col3 from [my table]",
+		"update t set a = .5 where b=2":             "update t set a = ? where b=?",
+		"select 'unterminated":                      "select ?",
+	}
+
+	for statement, expected := range cases {
+		if redacted := RedactLiterals(statement); redacted != expected {
+			t.Errorf("Expected %q for %q, received %q", expected, statement, redacted)
+		}
+	}
+}
+
+func TestKind_String(t *testing.T) {
+	if s := KindRPC.String(); s != "rpc" {
+		t.Errorf("Expected rpc, got %s", s)
+	}
+
+	if s := Kind(99).String(); s != "Kind(99)" {
+		t.Errorf("Expected Kind(99), got %s", s)
+	}
+}
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package audit reports executed statements to an audit hook, so
+applications can ship database activity to their audit pipeline.
+
+Each Record contains the time the request was sent, the identity of
+the connection, the statement text, the duration and the outcome of the
+request. Statements may contain sensitive literals, which are removed
+with a redaction callback before the Record is created:
+
+	audit.SetAuditor(&audit.Auditor{
+		Hook: func(ctx context.Context, record audit.Record) {
+			pipeline.Send(record.Time, record.Identity.String(), record.Statement,
+				record.Duration, record.Succeeded())
+		},
+		Redact: audit.RedactLiterals,
+	})
+
+An Auditor can also be set for a single context with WithAuditor,
+which takes precedence over the global Auditor.
+
+Executing requests is audited with Start and Entry.End:
+
+	entry := audit.Start(ctx, identity, audit.KindLanguage, cmd)
+	err := execute(ctx, cmd)
+	entry.End(err)
+
+If no Auditor is set auditing is disabled and Start returns a nil
+*Entry, whose methods are no-ops. pool.TDSConn audits the commands
+passed to Exec.
+*/
+package audit
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// RedactLiterals replaces string and numeric literals in statement
+// with a question mark.
+//
+// Double quoted strings are treated as literals as with the option
+// quoted_identifier off. Quoted identifiers in brackets are kept.
+// Identifiers containing
+// digits, variables and temporary tables are not considered numeric
+// literals.
+func RedactLiterals(statement string) string {
+	var b strings.Builder
+	runes := []rune(statement)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\'' || r == '"':
+			// Quotes within a literal are escaped by doubling.
+			for i++; i < len(runes); i++ {
+				if runes[i] != r {
+					continue
+				}
+				if i+1 < len(runes) && runes[i+1] == r {
+					i++
+					continue
+				}
+				break
+			}
+			b.WriteRune('?')
+		case r == '[':
+			end := i
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				end--
+			}
+			b.WriteString(string(runes[i : end+1]))
+			i = end
+		case isIdentRune(r):
+			end := i
+			for end+1 < len(runes) && (isIdentRune(runes[end+1]) || unicode.IsDigit(runes[end+1])) {
+				end++
+			}
+			b.WriteString(string(runes[i : end+1]))
+			i = end
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			// Covers decimals, exponents and hexadecimal literals.
+			for i+1 < len(runes) && (isIdentRune(runes[i+1]) || unicode.IsDigit(runes[i+1]) ||
+				runes[i+1] == '.' || isExponentSign(runes, i+1)) {
+				i++
+			}
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// isIdentRune reports whether r starts an identifier. Identifiers are
+// continued by the same runes and digits.
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || r == '_' || r == '@' || r == '#' || r == '$'
+}
+
+// isExponentSign reports whether the rune at i is the sign of an
+// exponent such as in 1e-5.
+func isExponentSign(runes []rune, i int) bool {
+	return (runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E')
+}
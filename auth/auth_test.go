@@ -0,0 +1,242 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+func TestNew_BuiltIn(t *testing.T) {
+	info := dsn.NewInfo()
+	info.Password = "secret"
+
+	for _, name := range []string{Plaintext, EncryptedPassword} {
+		mech, err := New(name, info)
+		if err != nil {
+			t.Errorf("Unexpected error creating %s: %v", name, err)
+			continue
+		}
+
+		if mech.Name() != name {
+			t.Errorf("Expected name %s, received %s", name, mech.Name())
+		}
+	}
+}
+
+func TestNew_Unknown(t *testing.T) {
+	_, err := New("unknown", dsn.NewInfo())
+	if !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error, received: %v", err)
+	}
+}
+
+type testMechanism struct {
+	plaintext
+}
+
+func (mech *testMechanism) Name() string {
+	return "test"
+}
+
+func TestRegister(t *testing.T) {
+	factory := func(info *dsn.Info) (Mechanism, error) {
+		return &testMechanism{}, nil
+	}
+
+	if err := Register("test", factory); err != nil {
+		t.Fatalf("Unexpected error registering: %v", err)
+	}
+
+	if err := Register("test", factory); err == nil {
+		t.Errorf("Expected error registering duplicate name")
+	}
+
+	if err := Register("", factory); err == nil {
+		t.Errorf("Expected error registering empty name")
+	}
+
+	mech, err := New("test", dsn.NewInfo())
+	if err != nil {
+		t.Fatalf("Unexpected error creating mechanism: %v", err)
+	}
+
+	if mech.Name() != "test" {
+		t.Errorf("Expected registered mechanism, received %s", mech.Name())
+	}
+
+	found := false
+	for _, name := range Mechanisms() {
+		if name == "test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Registered mechanism not listed: %v", Mechanisms())
+	}
+}
+
+func TestPlaintext(t *testing.T) {
+	mech := NewPlaintext("secret")
+
+	if mech.Negotiate() {
+		t.Errorf("Plaintext must not negotiate")
+	}
+
+	token, err := mech.Initial()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if string(token) != "secret" {
+		t.Errorf("Expected password as initial token, received %q", token)
+	}
+
+	if _, err := mech.Continue([]byte("challenge")); !errors.Is(err, ErrUnexpectedChallenge) {
+		t.Errorf("Expected ErrUnexpectedChallenge, received: %v", err)
+	}
+}
+
+func TestEncryptedPassword(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	pemPubKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PUBLIC KEY",
+		Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey),
+	})
+	nonce := []byte("nonce")
+
+	mech := NewEncryptedPassword("secret")
+
+	if !mech.Negotiate() {
+		t.Errorf("Encrypted password must negotiate")
+	}
+
+	token, err := mech.Initial()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(token) != 0 {
+		t.Errorf("Expected empty initial token, received %q", token)
+	}
+
+	encrypted, err := mech.Continue(EncodeChallenge(pemPubKey, nonce))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	decrypted, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, key, encrypted, []byte{})
+	if err != nil {
+		t.Fatalf("Error decrypting: %v", err)
+	}
+
+	if expected := []byte("noncesecret"); !bytes.Equal(decrypted, expected) {
+		t.Errorf("Expected %q, received %q", expected, decrypted)
+	}
+}
+
+func TestDecodeChallenge(t *testing.T) {
+	key, nonce, err := DecodeChallenge(EncodeChallenge([]byte("key"), []byte("nonce")))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if string(key) != "key" || string(nonce) != "nonce" {
+		t.Errorf("Expected key and nonce, received %q and %q", key, nonce)
+	}
+
+	for _, token := range [][]byte{nil, {0, 0}, {0, 0, 0, 4, 'a'}} {
+		if _, _, err := DecodeChallenge(token); !errors.Is(err, dberrors.CategoryProtocol) {
+			t.Errorf("Expected protocol error for %v, received: %v", token, err)
+		}
+	}
+}
+
+func TestFIPSMode(t *testing.T) {
+	info := dsn.NewInfo()
+
+	if fips, err := FIPSMode(info); err != nil || fips {
+		t.Errorf("Expected FIPS mode to be disabled by default, received %t, %v", fips, err)
+	}
+
+	info.ConnectProps.Set("fips", "true")
+	if fips, err := FIPSMode(info); err != nil || !fips {
+		t.Errorf("Expected FIPS mode to be enabled, received %t, %v", fips, err)
+	}
+
+	info.ConnectProps.Set("fips", "maybe")
+	if _, err := FIPSMode(info); !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error, received: %v", err)
+	}
+}
+
+func TestCheckFIPS(t *testing.T) {
+	if err := CheckFIPS(NewEncryptedPasswordFIPS("secret")); err != nil {
+		t.Errorf("Expected encrypted-password to be approved, received: %v", err)
+	}
+
+	if err := CheckFIPS(NewEncryptedPassword("secret")); !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error for encrypted-password without FIPS, received: %v", err)
+	}
+
+	if err := CheckFIPS(NewPlaintext("secret")); !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error for plaintext, received: %v", err)
+	}
+}
+
+func TestEncryptRSAFIPS(t *testing.T) {
+	cases := map[string]struct {
+		bits  int
+		valid bool
+	}{
+		"1024 bits": {bits: 1024},
+		"2048 bits": {bits: 2048, valid: true},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			key, err := rsa.GenerateKey(rand.Reader, cas.bits)
+			if err != nil {
+				t.Fatalf("Error generating key: %v", err)
+			}
+
+			pemPubKey := pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PUBLIC KEY",
+				Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey),
+			})
+
+			info := dsn.NewInfo()
+			info.Password = "secret"
+			info.ConnectProps.Set("fips", "true")
+
+			mech, err := New(EncryptedPassword, info)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			_, err = mech.Continue(EncodeChallenge(pemPubKey, []byte("nonce")))
+			if cas.valid && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if !cas.valid && !errors.Is(err, dberrors.CategoryProtocol) {
+				t.Errorf("Expected protocol error, received: %v", err)
+			}
+		})
+	}
+}
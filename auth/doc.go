@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package auth provides the authentication mechanisms used while logging
+in to a server.
+
+A Mechanism supplies the token sent with the login record and answers
+challenges sent by the server. The built-in mechanisms are:
+
+	plaintext           sends the password in the login record
+	encrypted-password  encrypts the password with the public key sent
+	                    by the server
+
+Further mechanisms such as Kerberos or LDAP are made available by
+registering a Factory:
+
+	func init() {
+		auth.Register("kerberos", newKerberosMechanism)
+	}
+
+The tds package selects the mechanism with the property "auth" of the
+dsn.
+
+Kerberos mechanisms are configured with the fields .KerberosSPN,
+.KerberosKeytab, .KerberosCCache and .KerberosMutualAuth of the dsn.
+
+Setting the property "fips" of the dsn to true restricts the login to
+FIPS-approved primitives. Only mechanisms implementing FIPSApprover are
+accepted and the login fails if the server sends a public key of less
+than MinFIPSKeyBits bits or requests another asymmetric encryption.
+*/
+package auth
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// encryptedPassword sends the password encrypted with the public key
+// sent by the server.
+type encryptedPassword struct {
+	password string
+	fips     bool
+}
+
+// NewEncryptedPassword returns a Mechanism sending password encrypted
+// with the public key and nonce of the challenge, see EncodeChallenge.
+func NewEncryptedPassword(password string) Mechanism {
+	return &encryptedPassword{password: password}
+}
+
+// NewEncryptedPasswordFIPS returns a Mechanism like
+// NewEncryptedPassword, which encrypts password with EncryptRSAFIPS.
+func NewEncryptedPasswordFIPS(password string) Mechanism {
+	return &encryptedPassword{password: password, fips: true}
+}
+
+func (mech *encryptedPassword) Name() string {
+	return EncryptedPassword
+}
+
+func (mech *encryptedPassword) Negotiate() bool {
+	return true
+}
+
+// FIPSApproved is only true for mechanisms created with
+// NewEncryptedPasswordFIPS, as NewEncryptedPassword encrypts with
+// EncryptRSA.
+func (mech *encryptedPassword) FIPSApproved() bool {
+	return mech.fips
+}
+
+func (mech *encryptedPassword) Initial() ([]byte, error) {
+	return nil, nil
+}
+
+func (mech *encryptedPassword) Continue(serverToken []byte) ([]byte, error) {
+	pemPubKey, nonce, err := DecodeChallenge(serverToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if mech.fips {
+		return EncryptRSAFIPS(pemPubKey, nonce, []byte(mech.password))
+	}
+
+	return EncryptRSA(pemPubKey, nonce, []byte(mech.password))
+}
+
+// EncodeChallenge encodes the PEM encoded public key and the nonce sent
+// by the server as token for Mechanism.Continue.
+//
+// The token consists of the length of the public key as big endian
+// uint32, the public key and the nonce.
+func EncodeChallenge(pemPubKey, nonce []byte) []byte {
+	token := make([]byte, 4, 4+len(pemPubKey)+len(nonce))
+	binary.BigEndian.PutUint32(token, uint32(len(pemPubKey)))
+	token = append(token, pemPubKey...)
+	return append(token, nonce...)
+}
+
+// DecodeChallenge returns the public key and nonce of a token created
+// by EncodeChallenge.
+func DecodeChallenge(token []byte) ([]byte, []byte, error) {
+	if len(token) < 4 {
+		return nil, nil, dberrors.Errorf(dberrors.CategoryProtocol,
+			"challenge of %d bytes is too short", len(token))
+	}
+
+	keyLen := binary.BigEndian.Uint32(token)
+	if uint64(keyLen) > uint64(len(token)-4) {
+		return nil, nil, dberrors.Errorf(dberrors.CategoryProtocol,
+			"challenge announces public key of %d bytes, only %d bytes remaining",
+			keyLen, len(token)-4)
+	}
+
+	return token[4 : 4+keyLen], token[4+keyLen:], nil
+}
+
+// EncryptRSA encrypts plaintext prefixed with nonce with the PEM
+// encoded PKCS#1 public key.
+func EncryptRSA(pemPubKey, nonce, plaintext []byte) ([]byte, error) {
+	publicKey, err := parsePublicKey(pemPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptOAEP(publicKey, nonce, plaintext)
+}
+
+// parsePublicKey parses the PEM encoded PKCS#1 public key.
+func parsePublicKey(pemPubKey []byte) (*rsa.PublicKey, error) {
+	pubKeyBlock, rest := pem.Decode(pemPubKey)
+	if pubKeyBlock == nil {
+		return nil, dberrors.New(dberrors.CategoryProtocol, "no PEM data in public key")
+	}
+
+	if len(rest) > 0 {
+		return nil, dberrors.Errorf(dberrors.CategoryProtocol, "trailing bytes in public key: %#v", rest)
+	}
+
+	publicKey, err := x509.ParsePKCS1PublicKey(pubKeyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PKCS#1 public key: %w",
+			dberrors.Wrap(dberrors.CategoryProtocol, err))
+	}
+
+	return publicKey, nil
+}
+
+// encryptOAEP encrypts plaintext prefixed with nonce with RSA-OAEP.
+// SHA-1 is required by the server and approved for use in OAEP.
+func encryptOAEP(publicKey *rsa.PublicKey, nonce, plaintext []byte) ([]byte, error) {
+	msg := make([]byte, 0, len(nonce)+len(plaintext))
+	msg = append(msg, nonce...)
+	msg = append(msg, plaintext...)
+
+	return rsa.EncryptOAEP(sha1.New(), rand.Reader, publicKey, msg, []byte{})
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+func init() {
+	dsn.RegisterProps("fips")
+}
+
+// MinFIPSKeyBits is the minimum size of the public key of the server
+// accepted in FIPS mode.
+const MinFIPSKeyBits = 2048
+
+// FIPSMode reports whether FIPS mode is enabled by the property "fips"
+// of info.
+//
+// In FIPS mode only mechanisms implementing FIPSApprover are used and
+// secrets are only encrypted with RSA public keys of at least
+// MinFIPSKeyBits bits.
+func FIPSMode(info *dsn.Info) (bool, error) {
+	return info.PropBool("fips", false)
+}
+
+// FIPSApprover is implemented by mechanisms that only use FIPS-approved
+// primitives.
+type FIPSApprover interface {
+	// FIPSApproved reports whether the mechanism only uses
+	// FIPS-approved primitives.
+	FIPSApproved() bool
+}
+
+// CheckFIPS returns an error if mech is not approved for FIPS mode.
+func CheckFIPS(mech Mechanism) error {
+	if approver, ok := mech.(FIPSApprover); ok && approver.FIPSApproved() {
+		return nil
+	}
+
+	return dberrors.Errorf(dberrors.CategoryConfig, "mechanism %q is not approved in FIPS mode", mech.Name())
+}
+
+// EncryptRSAFIPS is EncryptRSA restricted to FIPS-approved parameters.
+// An error is returned if the public key is smaller than
+// MinFIPSKeyBits.
+func EncryptRSAFIPS(pemPubKey, nonce, plaintext []byte) ([]byte, error) {
+	publicKey, err := parsePublicKey(pemPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if bits := publicKey.N.BitLen(); bits < MinFIPSKeyBits {
+		return nil, dberrors.Errorf(dberrors.CategoryProtocol,
+			"public key of server has %d bits, FIPS mode requires at least %d bits",
+			bits, MinFIPSKeyBits)
+	}
+
+	return encryptOAEP(publicKey, nonce, plaintext)
+}
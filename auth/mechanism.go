@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// Names of the built-in mechanisms.
+const (
+	Plaintext         = "plaintext"
+	EncryptedPassword = "encrypted-password"
+)
+
+// Mechanism authenticates a client during the login sequence.
+type Mechanism interface {
+	// Name returns the name the mechanism is registered with.
+	Name() string
+	// Negotiate reports whether the mechanism expects the server to
+	// send a challenge after the login record.
+	Negotiate() bool
+	// Initial returns the token sent as password in the login record.
+	Initial() ([]byte, error)
+	// Continue returns the response to the challenge serverToken.
+	Continue(serverToken []byte) ([]byte, error)
+}
+
+// Factory creates a Mechanism for the passed dsn.Info.
+type Factory func(info *dsn.Info) (Mechanism, error)
+
+var (
+	registryLock = &sync.RWMutex{}
+	registry     = map[string]Factory{
+		Plaintext: func(info *dsn.Info) (Mechanism, error) {
+			return NewPlaintext(info.Password), nil
+		},
+		EncryptedPassword: func(info *dsn.Info) (Mechanism, error) {
+			fips, err := FIPSMode(info)
+			if err != nil {
+				return nil, err
+			}
+
+			if fips {
+				return NewEncryptedPasswordFIPS(info.Password), nil
+			}
+			return NewEncryptedPassword(info.Password), nil
+		},
+	}
+)
+
+// Register makes a mechanism available under name.
+//
+// An error is returned if name is empty or already registered.
+func Register(name string, factory Factory) error {
+	if name == "" {
+		return dberrors.New(dberrors.CategoryConfig, "mechanism name is empty")
+	}
+
+	if factory == nil {
+		return dberrors.Errorf(dberrors.CategoryConfig, "factory for mechanism %q is nil", name)
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if _, ok := registry[name]; ok {
+		return dberrors.Errorf(dberrors.CategoryConfig, "mechanism %q is already registered", name)
+	}
+
+	registry[name] = factory
+	return nil
+}
+
+// New creates the mechanism registered as name for info.
+func New(name string, info *dsn.Info) (Mechanism, error) {
+	registryLock.RLock()
+	factory, ok := registry[name]
+	registryLock.RUnlock()
+
+	if !ok {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "unknown mechanism %q", name)
+	}
+
+	return factory(info)
+}
+
+// Mechanisms returns the sorted names of the registered mechanisms.
+func Mechanisms() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
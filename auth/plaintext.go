@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// ErrUnexpectedChallenge is returned by mechanisms that do not expect
+// a challenge from the server.
+var ErrUnexpectedChallenge = dberrors.New(dberrors.CategoryProtocol, "unexpected challenge from server")
+
+// plaintext sends the password unencrypted in the login record.
+type plaintext struct {
+	password string
+}
+
+// NewPlaintext returns a Mechanism sending password unencrypted in the
+// login record.
+func NewPlaintext(password string) Mechanism {
+	return &plaintext{password: password}
+}
+
+func (mech *plaintext) Name() string {
+	return Plaintext
+}
+
+func (mech *plaintext) Negotiate() bool {
+	return false
+}
+
+func (mech *plaintext) Initial() ([]byte, error) {
+	return []byte(mech.password), nil
+}
+
+func (mech *plaintext) Continue(serverToken []byte) ([]byte, error) {
+	return nil, ErrUnexpectedChallenge
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package bench provides a benchmark harness with canonical workloads to
+compare database/sql drivers, e.g. the cgo and the pure go
+implementation, or the effect of changes on a driver.
+
+The workloads are a point select, a scan of 10000 rows, a bulk insert
+and the round-trip of a LOB. They are run against any driver
+registered with database/sql:
+
+	cgoReport, err := bench.Run(ctx, bench.Config{Driver: "cgoase", DSN: cgoDSN})
+	if err != nil {
+		return err
+	}
+
+	goReport, err := bench.Run(ctx, bench.Config{Driver: "ase", DSN: goDSN})
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(goReport)
+	for _, comparison := range bench.Compare(cgoReport, goReport) {
+		fmt.Println(comparison)
+	}
+
+Each workload creates its table, runs warmup operations and measures
+the configured number of operations. Results contain the mean and the
+percentiles of the latency as well as the transferred rows and bytes
+and can be encoded as JSON to compare runs over time.
+
+Custom workloads are implemented with Workload. The bulk insert uses
+prepared statements by default, a LoadFunc using the bulk copy protocol
+of a driver can be passed to BulkInsert.
+*/
+package bench
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bench
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Result are the metrics of a workload.
+type Result struct {
+	Workload string `json:"workload"`
+	// Ops is the number of measured operations.
+	Ops int `json:"ops"`
+	// Total is the summed duration of the measured operations.
+	Total time.Duration `json:"total"`
+	// PerOp is the mean duration of an operation.
+	PerOp time.Duration `json:"per_op"`
+	P50   time.Duration `json:"p50"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+	// Rows and Bytes are the totals transferred by the measured
+	// operations.
+	Rows  int64 `json:"rows"`
+	Bytes int64 `json:"bytes"`
+	// Error is set if the workload failed.
+	Error string `json:"error,omitempty"`
+}
+
+// newResult returns the result of the operations with latencies and
+// counts.
+func newResult(workload string, latencies []time.Duration, counts Counts) Result {
+	result := Result{
+		Workload: workload,
+		Ops:      len(latencies),
+		Rows:     counts.Rows,
+		Bytes:    counts.Bytes,
+	}
+
+	if len(latencies) == 0 {
+		return result
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	for _, latency := range sorted {
+		result.Total += latency
+	}
+
+	result.PerOp = result.Total / time.Duration(len(sorted))
+	result.P50 = percentile(sorted, 50)
+	result.P95 = percentile(sorted, 95)
+	result.P99 = percentile(sorted, 99)
+
+	return result
+}
+
+// percentile returns the p-th percentile of the sorted latencies with
+// the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// OpsPerSecond returns the throughput in operations.
+func (result Result) OpsPerSecond() float64 {
+	return perSecond(int64(result.Ops), result.Total)
+}
+
+// RowsPerSecond returns the throughput in rows.
+func (result Result) RowsPerSecond() float64 {
+	return perSecond(result.Rows, result.Total)
+}
+
+// BytesPerSecond returns the throughput in bytes.
+func (result Result) BytesPerSecond() float64 {
+	return perSecond(result.Bytes, result.Total)
+}
+
+func perSecond(n int64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds()
+}
+
+// Report is the result of running workloads with a driver.
+type Report struct {
+	Driver  string    `json:"driver"`
+	Started time.Time `json:"started"`
+	Results []Result  `json:"results"`
+}
+
+// Result returns the result of workload. The returned boolean is false
+// if the report contains no result of workload.
+func (report Report) Result(workload string) (Result, bool) {
+	for _, result := range report.Results {
+		if result.Workload == workload {
+			return result, true
+		}
+	}
+
+	return Result{}, false
+}
+
+func (report Report) String() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "driver %s\n", report.Driver)
+
+	w := tabwriter.NewWriter(sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "workload\tops\tper op\tp50\tp95\tp99\trows/s\tMB/s\t")
+	for _, result := range report.Results {
+		if result.Error != "" {
+			fmt.Fprintf(w, "%s\terror: %s\t\n", result.Workload, result.Error)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%.0f\t%.2f\t\n",
+			result.Workload, result.Ops, result.PerOp, result.P50, result.P95, result.P99,
+			result.RowsPerSecond(), result.BytesPerSecond()/1e6)
+	}
+	w.Flush()
+
+	return sb.String()
+}
+
+// Comparison compares the results of a workload in two reports.
+type Comparison struct {
+	Workload string
+	Base     Result
+	Other    Result
+}
+
+// Delta returns the relative change of the mean duration of an
+// operation from Base to Other, e.g. -0.25 if Other is 25% faster.
+func (comparison Comparison) Delta() float64 {
+	if comparison.Base.PerOp == 0 {
+		return 0
+	}
+
+	return float64(comparison.Other.PerOp-comparison.Base.PerOp) / float64(comparison.Base.PerOp)
+}
+
+func (comparison Comparison) String() string {
+	return fmt.Sprintf("%s: %s -> %s (%+.1f%%)", comparison.Workload,
+		comparison.Base.PerOp, comparison.Other.PerOp, 100*comparison.Delta())
+}
+
+// Compare returns the comparisons of the workloads that succeeded in
+// both base and other, in the order of base.
+func Compare(base, other Report) []Comparison {
+	comparisons := []Comparison{}
+	for _, baseResult := range base.Results {
+		otherResult, ok := other.Result(baseResult.Workload)
+		if !ok || baseResult.Error != "" || otherResult.Error != "" {
+			continue
+		}
+
+		comparisons = append(comparisons, Comparison{
+			Workload: baseResult.Workload,
+			Base:     baseResult,
+			Other:    otherResult,
+		})
+	}
+
+	return comparisons
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewResult(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		// Unsorted latencies of 1ms to 100ms.
+		latencies[i] = time.Duration(100-i) * time.Millisecond
+	}
+
+	result := newResult("test", latencies, Counts{Rows: 200, Bytes: 1000})
+
+	if result.Ops != 100 {
+		t.Errorf("Expected 100 ops, got %d", result.Ops)
+	}
+
+	if result.PerOp != 50500*time.Microsecond {
+		t.Errorf("Expected mean of 50.5ms, got %s", result.PerOp)
+	}
+
+	percentiles := []struct {
+		received, expected time.Duration
+	}{
+		{result.P50, 50 * time.Millisecond},
+		{result.P95, 95 * time.Millisecond},
+		{result.P99, 99 * time.Millisecond},
+	}
+	for _, p := range percentiles {
+		if p.received != p.expected {
+			t.Errorf("Expected percentile %s, got %s", p.expected, p.received)
+		}
+	}
+
+	if rps := result.RowsPerSecond(); rps < 39 || rps > 40 {
+		t.Errorf("Expected about 39.6 rows/s, got %f", rps)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := Report{Results: []Result{
+		{Workload: "a", PerOp: 100 * time.Millisecond},
+		{Workload: "b", PerOp: 100 * time.Millisecond},
+		{Workload: "c", PerOp: 100 * time.Millisecond},
+	}}
+	other := Report{Results: []Result{
+		{Workload: "a", PerOp: 75 * time.Millisecond},
+		{Workload: "b", Error: "failed"},
+	}}
+
+	comparisons := Compare(base, other)
+	if len(comparisons) != 1 || comparisons[0].Workload != "a" {
+		t.Fatalf("Expected only workload a to be compared, got %v", comparisons)
+	}
+
+	if delta := comparisons[0].Delta(); delta != -0.25 {
+		t.Errorf("Expected delta of -0.25, got %f", delta)
+	}
+}
+
+func TestRunDB(t *testing.T) {
+	ops := 0
+	workloads := []Workload{
+		{
+			Name: "count",
+			Op: func(context.Context, *sql.DB, string) (Counts, error) {
+				ops++
+				return Counts{Rows: 1}, nil
+			},
+		},
+		{
+			Name: "fail",
+			Op: func(context.Context, *sql.DB, string) (Counts, error) {
+				return Counts{}, errors.New("failed")
+			},
+		},
+	}
+
+	report := RunDB(context.Background(), nil, Config{Driver: "test", Iterations: 5, Warmup: 2}, workloads...)
+
+	if ops != 7 {
+		t.Errorf("Expected 2 warmup and 5 measured operations, got %d", ops)
+	}
+
+	count, ok := report.Result("count")
+	if !ok || count.Ops != 5 || count.Rows != 5 || count.Error != "" {
+		t.Errorf("Expected 5 measured operations, got %+v", count)
+	}
+
+	if fail, ok := report.Result("fail"); !ok || fail.Error == "" {
+		t.Errorf("Expected failed workload to be reported, got %+v", fail)
+	}
+}
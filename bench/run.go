@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SAP/go-dblib/logging"
+)
+
+// Defaults of Config.
+const (
+	DefaultIterations  = 100
+	DefaultWarmup      = 10
+	DefaultTablePrefix = "bench_"
+)
+
+// Config configures a benchmark run.
+type Config struct {
+	// Driver is the name of the database/sql driver, e.g. "ase".
+	Driver string
+	// DSN is passed to sql.Open.
+	DSN string
+	// Iterations is the number of measured operations per workload.
+	Iterations int
+	// Warmup is the number of operations run before the measured
+	// operations.
+	Warmup int
+	// TablePrefix is prepended to the names of the tables created by
+	// the workloads.
+	TablePrefix string
+}
+
+// withDefaults returns config with the defaults for unset fields.
+func (config Config) withDefaults() Config {
+	if config.Iterations <= 0 {
+		config.Iterations = DefaultIterations
+	}
+
+	if config.Warmup < 0 {
+		config.Warmup = 0
+	} else if config.Warmup == 0 {
+		config.Warmup = DefaultWarmup
+	}
+
+	if config.TablePrefix == "" {
+		config.TablePrefix = DefaultTablePrefix
+	}
+
+	return config
+}
+
+// Run opens a database with the driver and DSN of config and runs
+// workloads. If no workloads are passed DefaultWorkloads are run.
+//
+// The database is limited to a single connection, so the operations
+// of a workload are run on the same connection and the results of
+// different drivers are comparable.
+func Run(ctx context.Context, config Config, workloads ...Workload) (Report, error) {
+	db, err := sql.Open(config.Driver, config.DSN)
+	if err != nil {
+		return Report{}, fmt.Errorf("error opening database with driver %s: %w", config.Driver, err)
+	}
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		return Report{}, fmt.Errorf("error connecting with driver %s: %w", config.Driver, err)
+	}
+
+	return RunDB(ctx, db, config, workloads...), nil
+}
+
+// RunDB runs workloads against db. The driver and DSN of config are
+// only used to label the report. If no workloads are passed
+// DefaultWorkloads are run.
+//
+// Failing workloads are reported with the error in their result.
+func RunDB(ctx context.Context, db *sql.DB, config Config, workloads ...Workload) Report {
+	config = config.withDefaults()
+
+	if len(workloads) == 0 {
+		workloads = DefaultWorkloads()
+	}
+
+	report := Report{
+		Driver:  config.Driver,
+		Started: time.Now(),
+		Results: make([]Result, 0, len(workloads)),
+	}
+
+	for _, workload := range workloads {
+		result, err := runWorkload(ctx, db, config, workload)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// runWorkload sets up the table of workload and measures its
+// operations.
+func runWorkload(ctx context.Context, db *sql.DB, config Config, workload Workload) (Result, error) {
+	result := Result{Workload: workload.Name}
+	table := config.TablePrefix + strings.ToLower(workload.Name)
+
+	if workload.Setup != nil {
+		defer func() {
+			if _, err := db.ExecContext(ctx, "drop table "+table); err != nil {
+				logging.Default().Warn("error dropping benchmark table", "table", table, "error", err)
+			}
+		}()
+
+		if err := workload.Setup(ctx, db, table); err != nil {
+			return result, fmt.Errorf("error setting up workload: %w", err)
+		}
+	}
+
+	for i := 0; i < config.Warmup; i++ {
+		if _, err := workload.Op(ctx, db, table); err != nil {
+			return result, fmt.Errorf("error in warmup operation %d: %w", i, err)
+		}
+	}
+
+	latencies := make([]time.Duration, 0, config.Iterations)
+	total := Counts{}
+	for i := 0; i < config.Iterations; i++ {
+		start := time.Now()
+		counts, err := workload.Op(ctx, db, table)
+		latency := time.Since(start)
+		if err != nil {
+			return newResult(workload.Name, latencies, total), fmt.Errorf("error in operation %d: %w", i, err)
+		}
+
+		latencies = append(latencies, latency)
+		total.Rows += counts.Rows
+		total.Bytes += counts.Bytes
+	}
+
+	return newResult(workload.Name, latencies, total), nil
+}
@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Counts are the rows and bytes transferred by an operation.
+type Counts struct {
+	Rows  int64
+	Bytes int64
+}
+
+// Workload is a benchmarked operation.
+type Workload struct {
+	Name string
+	// Setup creates and fills the table of the workload. It is not
+	// measured and may be nil.
+	Setup func(ctx context.Context, db *sql.DB, table string) error
+	// Op performs a single measured operation.
+	Op func(ctx context.Context, db *sql.DB, table string) (Counts, error)
+}
+
+// DefaultWorkloads returns the canonical workloads: a point select on
+// a table of 1000 rows, a scan of 10000 rows, a bulk insert of 1000
+// rows and a round-trip of a 64 KiB LOB.
+func DefaultWorkloads() []Workload {
+	return []Workload{
+		PointSelect(1000),
+		Scan(10000),
+		BulkInsert(1000, 100, nil),
+		LOBRoundTrip(64 * 1024),
+	}
+}
+
+// rowValue returns the value of the column b of row i of the tables
+// created by fillTable.
+func rowValue(i int) string {
+	return fmt.Sprintf("row %d", i)
+}
+
+// fillTable creates table with rowCount rows.
+func fillTable(ctx context.Context, db *sql.DB, table string, rowCount int) error {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("create table %s (a int primary key, b varchar(30))", table)); err != nil {
+		return fmt.Errorf("error creating table %s: %w", table, err)
+	}
+
+	rows := make([][]interface{}, rowCount)
+	for i := range rows {
+		rows[i] = []interface{}{int32(i), rowValue(i)}
+	}
+
+	return PreparedLoad(ctx, db, table, []string{"a", "b"}, 1000, rows)
+}
+
+// PointSelect returns a workload selecting a single row by its
+// primary key from a table of rowCount rows.
+func PointSelect(rowCount int) Workload {
+	next := 0
+
+	return Workload{
+		Name: "PointSelect",
+		Setup: func(ctx context.Context, db *sql.DB, table string) error {
+			return fillTable(ctx, db, table, rowCount)
+		},
+		Op: func(ctx context.Context, db *sql.DB, table string) (Counts, error) {
+			// Rows are selected round-robin, so all drivers read
+			// the same rows.
+			id := next % rowCount
+			next++
+
+			var value string
+			if err := db.QueryRowContext(ctx, fmt.Sprintf("select b from %s where a = ?", table), int32(id)).Scan(&value); err != nil {
+				return Counts{}, fmt.Errorf("error selecting row %d: %w", id, err)
+			}
+
+			if value != rowValue(id) {
+				return Counts{}, fmt.Errorf("received %q for row %d, expected %q", value, id, rowValue(id))
+			}
+
+			return Counts{Rows: 1, Bytes: int64(len(value))}, nil
+		},
+	}
+}
+
+// Scan returns a workload selecting and scanning all rows of a table
+// of rowCount rows.
+func Scan(rowCount int) Workload {
+	return Workload{
+		Name: "Scan",
+		Setup: func(ctx context.Context, db *sql.DB, table string) error {
+			return fillTable(ctx, db, table, rowCount)
+		},
+		Op: func(ctx context.Context, db *sql.DB, table string) (Counts, error) {
+			rows, err := db.QueryContext(ctx, fmt.Sprintf("select a, b from %s", table))
+			if err != nil {
+				return Counts{}, fmt.Errorf("error selecting rows: %w", err)
+			}
+			defer rows.Close()
+
+			counts := Counts{}
+			var id int32
+			var value string
+			for rows.Next() {
+				if err := rows.Scan(&id, &value); err != nil {
+					return counts, fmt.Errorf("error scanning row: %w", err)
+				}
+				counts.Rows++
+				counts.Bytes += 4 + int64(len(value))
+			}
+
+			if err := rows.Err(); err != nil {
+				return counts, fmt.Errorf("error reading rows: %w", err)
+			}
+
+			if counts.Rows != int64(rowCount) {
+				return counts, fmt.Errorf("read %d rows, expected %d", counts.Rows, rowCount)
+			}
+
+			return counts, nil
+		},
+	}
+}
+
+// LoadFunc loads rows into the columns of a table, sending batchSize
+// rows per batch.
+type LoadFunc func(ctx context.Context, db *sql.DB, table string, columns []string, batchSize int, rows [][]interface{}) error
+
+// PreparedLoad is a LoadFunc inserting rows with a prepared statement,
+// committing a transaction after each batch.
+func PreparedLoad(ctx context.Context, db *sql.DB, table string, columns []string, batchSize int, rows [][]interface{}) error {
+	if batchSize < 1 {
+		return fmt.Errorf("invalid batch size %d", batchSize)
+	}
+
+	query := fmt.Sprintf("insert into %s (%s) values (%s)", table, strings.Join(columns, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		if err := preparedBatch(ctx, db, query, rows[start:end]); err != nil {
+			return fmt.Errorf("error loading rows %d to %d: %w", start, end, err)
+		}
+	}
+
+	return nil
+}
+
+// preparedBatch inserts rows in a single transaction.
+func preparedBatch(ctx context.Context, db *sql.DB, query string, rows [][]interface{}) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error inserting row %v: %w", row, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// BulkInsert returns a workload loading rowCount rows in batches of
+// batchSize rows with load. PreparedLoad is used if load is nil, e.g.
+// a function using the bulk copy protocol of a driver can be passed
+// instead.
+func BulkInsert(rowCount, batchSize int, load LoadFunc) Workload {
+	if load == nil {
+		load = PreparedLoad
+	}
+
+	rows := make([][]interface{}, rowCount)
+	bytes := int64(0)
+	for i := range rows {
+		rows[i] = []interface{}{int32(i), rowValue(i)}
+		bytes += 4 + int64(len(rowValue(i)))
+	}
+
+	return Workload{
+		Name: "BulkInsert",
+		Setup: func(ctx context.Context, db *sql.DB, table string) error {
+			if _, err := db.ExecContext(ctx, fmt.Sprintf("create table %s (a int, b varchar(30))", table)); err != nil {
+				return fmt.Errorf("error creating table %s: %w", table, err)
+			}
+			return nil
+		},
+		Op: func(ctx context.Context, db *sql.DB, table string) (Counts, error) {
+			if err := load(ctx, db, table, []string{"a", "b"}, batchSize, rows); err != nil {
+				return Counts{}, err
+			}
+
+			return Counts{Rows: int64(rowCount), Bytes: bytes}, nil
+		},
+	}
+}
+
+// LOBRoundTrip returns a workload updating a text column with a value
+// of size bytes and selecting it again.
+func LOBRoundTrip(size int) Workload {
+	sb := &strings.Builder{}
+	sb.Grow(size)
+	for i := 0; sb.Len() < size; i++ {
+		sb.WriteByte(byte('a' + i%26))
+	}
+	lob := sb.String()
+
+	return Workload{
+		Name: "LOBRoundTrip",
+		Setup: func(ctx context.Context, db *sql.DB, table string) error {
+			if _, err := db.ExecContext(ctx, fmt.Sprintf("create table %s (a int, b text null)", table)); err != nil {
+				return fmt.Errorf("error creating table %s: %w", table, err)
+			}
+
+			if _, err := db.ExecContext(ctx, fmt.Sprintf("insert into %s (a, b) values (1, null)", table)); err != nil {
+				return fmt.Errorf("error inserting row: %w", err)
+			}
+
+			return nil
+		},
+		Op: func(ctx context.Context, db *sql.DB, table string) (Counts, error) {
+			// @@textsize is set per connection and limits the size
+			// of selected LOBs.
+			conn, err := db.Conn(ctx)
+			if err != nil {
+				return Counts{}, fmt.Errorf("error opening connection: %w", err)
+			}
+			defer conn.Close()
+
+			if _, err := conn.ExecContext(ctx, fmt.Sprintf("set textsize %d", size)); err != nil {
+				return Counts{}, fmt.Errorf("error setting textsize: %w", err)
+			}
+
+			if _, err := conn.ExecContext(ctx, fmt.Sprintf("update %s set b = ? where a = 1", table), lob); err != nil {
+				return Counts{}, fmt.Errorf("error updating LOB: %w", err)
+			}
+
+			var received string
+			if err := conn.QueryRowContext(ctx, fmt.Sprintf("select b from %s where a = 1", table)).Scan(&received); err != nil {
+				return Counts{}, fmt.Errorf("error selecting LOB: %w", err)
+			}
+
+			if received != lob {
+				return Counts{}, fmt.Errorf("received LOB of %d bytes differs from the sent LOB of %d bytes",
+					len(received), len(lob))
+			}
+
+			return Counts{Rows: 1, Bytes: 2 * int64(size)}, nil
+		},
+	}
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bulkload
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// TimeLayouts are the layouts used to parse date and time values, in
+// the order they are tried.
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999",
+}
+
+// Column is a column of the target table.
+type Column struct {
+	Name string
+	Type asetypes.DataType
+	// Precision and Scale are used for DECN and NUMN columns.
+	// Precision defaults to asetypes.ASEDecimalDefaultPrecision.
+	Precision, Scale int
+}
+
+// ParseText converts the text s into the Go type of the column, see
+// asetypes.ReflectTypes.
+//
+// Binary values are hex encoded with an optional "0x" prefix.
+func (column Column) ParseText(s string) (interface{}, error) {
+	value, err := column.parseText(s)
+	if err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConversion, "error converting '%s' for column %s of type %s: %w",
+			s, column.Name, column.Type, err)
+	}
+	return value, nil
+}
+
+func (column Column) parseText(s string) (interface{}, error) {
+	switch column.Type {
+	case asetypes.INT1:
+		i, err := strconv.ParseUint(s, 10, 8)
+		return uint8(i), err
+	case asetypes.INT2:
+		i, err := strconv.ParseInt(s, 10, 16)
+		return int16(i), err
+	case asetypes.INT4:
+		i, err := strconv.ParseInt(s, 10, 32)
+		return int32(i), err
+	case asetypes.INT8, asetypes.INTN:
+		return strconv.ParseInt(s, 10, 64)
+	case asetypes.UINT2:
+		i, err := strconv.ParseUint(s, 10, 16)
+		return uint16(i), err
+	case asetypes.UINT4:
+		i, err := strconv.ParseUint(s, 10, 32)
+		return uint32(i), err
+	case asetypes.UINT8, asetypes.UINTN:
+		return strconv.ParseUint(s, 10, 64)
+	case asetypes.FLT4:
+		f, err := strconv.ParseFloat(s, 32)
+		return float32(f), err
+	case asetypes.FLT8, asetypes.FLTN:
+		return strconv.ParseFloat(s, 64)
+	case asetypes.BIT:
+		return strconv.ParseBool(s)
+	case asetypes.CHAR, asetypes.VARCHAR, asetypes.TEXT, asetypes.LONGCHAR, asetypes.UNITEXT:
+		return s, nil
+	case asetypes.BINARY, asetypes.VARBINARY, asetypes.LONGBINARY, asetypes.IMAGE, asetypes.BLOB, asetypes.XML:
+		return hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"))
+	case asetypes.DECN, asetypes.NUMN:
+		precision := column.Precision
+		if precision == 0 {
+			precision = asetypes.ASEDecimalDefaultPrecision
+		}
+		return asetypes.NewDecimalString(precision, column.Scale, s)
+	case asetypes.MONEY, asetypes.MONEYN:
+		return asetypes.NewDecimalString(asetypes.ASEMoneyPrecision, asetypes.ASEMoneyScale, s)
+	case asetypes.SHORTMONEY:
+		return asetypes.NewDecimalString(asetypes.ASEShortMoneyPrecision, asetypes.ASEShortMoneyScale, s)
+	case asetypes.DATE, asetypes.DATEN, asetypes.TIME, asetypes.TIMEN, asetypes.SHORTDATE,
+		asetypes.DATETIME, asetypes.DATETIMEN, asetypes.BIGDATETIMEN, asetypes.BIGTIMEN:
+		return parseTime(s)
+	}
+
+	return nil, fmt.Errorf("unsupported data type")
+}
+
+// parseJSON converts a value decoded from JSON with json.Number for
+// numbers into the Go type of the column.
+func (column Column) parseJSON(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return column.ParseText(typed)
+	case json.Number:
+		return column.ParseText(typed.String())
+	case bool:
+		if column.Type == asetypes.BIT {
+			return typed, nil
+		}
+		return column.ParseText(strconv.FormatBool(typed))
+	}
+
+	return nil, dberrors.Errorf(dberrors.CategoryConversion, "cannot convert JSON value of type %T for column %s", value, column.Name)
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range TimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("value does not match any of the layouts %v", TimeLayouts)
+}
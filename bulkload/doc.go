@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package bulkload loads CSV or newline delimited JSON into a table.
+
+The fields of each record are converted to the Go types of the target
+columns' ASE data types and inserted in batches:
+
+	progress, err := bulkload.Load(ctx, file, bulkload.Config{
+		Table: "employees",
+		Columns: []bulkload.Column{
+			{Name: "id", Type: asetypes.INT4},
+			{Name: "name", Type: asetypes.VARCHAR},
+			{Name: "salary", Type: asetypes.DECN, Precision: 10, Scale: 2},
+		},
+		Insert:    bulkload.SQLInsert(db),
+		Header:    true,
+		MaxErrors: -1,
+		OnError: func(row bulkload.ErrorRow) {
+			log.Printf("rejected record %d: %v", row.Record, row.Err)
+		},
+	})
+
+Records that cannot be converted are rejected and reported, the
+remaining records are loaded. OnProgress is called after each batch.
+
+Batches are inserted by an InsertFunc. SQLInsert inserts batches with
+a prepared statement in a transaction, as tds does not implement the
+bulk copy protocol yet.
+*/
+package bulkload
@@ -0,0 +1,262 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bulkload
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// DefaultBatchSize is the default of Config.BatchSize.
+const DefaultBatchSize = 1000
+
+// ErrTooManyErrors is returned when more rows were rejected than
+// Config.MaxErrors allows.
+var ErrTooManyErrors = errors.New("too many rejected rows")
+
+// InsertFunc inserts a batch of rows into the columns of table.
+type InsertFunc func(ctx context.Context, table string, columns []string, rows [][]interface{}) error
+
+// Config configures Load.
+type Config struct {
+	Table   string
+	Columns []Column
+	Insert  InsertFunc
+	Format  Format
+
+	// BatchSize is the number of rows passed to Insert at once.
+	// Defaults to DefaultBatchSize.
+	BatchSize int
+
+	// Header signals that the first CSV record contains the column
+	// names. Otherwise the fields of CSV records are in the order of
+	// Columns.
+	Header bool
+	// Comma is the field delimiter of CSV records. Defaults to ','.
+	Comma rune
+	// Null is the CSV field value loaded as NULL.
+	Null string
+
+	// MaxErrors is the number of rejected rows after which loading is
+	// aborted with ErrTooManyErrors. A negative value allows any
+	// number of rejected rows.
+	MaxErrors int
+	// OnError is called with each rejected row.
+	OnError func(ErrorRow)
+	// OnProgress is called after each inserted batch.
+	OnProgress func(Progress)
+}
+
+// ErrorRow is a rejected row.
+type ErrorRow struct {
+	// Record is the 1-based number of the record in the input, not
+	// counting the CSV header.
+	Record int
+	// Raw is the record as read from the input.
+	Raw string
+	Err error
+}
+
+// Progress is the progress of Load.
+type Progress struct {
+	// Read is the number of records read.
+	Read int
+	// Loaded is the number of rows inserted.
+	Loaded int
+	// Rejected is the number of rows that could not be converted.
+	Rejected int
+	// Batches is the number of inserted batches.
+	Batches int
+}
+
+// Load reads records from r as configured in config, converts their
+// fields to the types of the columns and inserts them in batches
+// with config.Insert.
+//
+// Records that cannot be converted are rejected and passed to
+// config.OnError. An error inserting a batch aborts loading, batches
+// inserted before are not rolled back.
+//
+// The returned Progress reflects the records processed until loading
+// finished or was aborted.
+func Load(ctx context.Context, r io.Reader, config Config) (Progress, error) {
+	progress := Progress{}
+
+	if err := config.validate(); err != nil {
+		return progress, err
+	}
+
+	var src source
+	switch config.Format {
+	case CSV:
+		csvSrc, err := newCSVSource(r, config)
+		if err != nil {
+			return progress, err
+		}
+		src = csvSrc
+	case NDJSON:
+		src = newNDJSONSource(r, config)
+	default:
+		return progress, dberrors.Errorf(dberrors.CategoryConfig, "unknown format %d", config.Format)
+	}
+
+	columns := make([]string, len(config.Columns))
+	for i, column := range config.Columns {
+		columns[i] = column.Name
+	}
+
+	batch := make([][]interface{}, 0, config.BatchSize)
+
+	insert := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := config.Insert(ctx, config.Table, columns, batch); err != nil {
+			return fmt.Errorf("error inserting batch %d: %w", progress.Batches+1, err)
+		}
+
+		progress.Loaded += len(batch)
+		progress.Batches++
+		batch = make([][]interface{}, 0, config.BatchSize)
+
+		if config.OnProgress != nil {
+			config.OnProgress(progress)
+		}
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		raw, rawRecord, err := src.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) && dberrors.CategoryOf(err) != dberrors.CategoryConversion {
+			return progress, fmt.Errorf("error reading record %d: %w", progress.Read+1, err)
+		}
+		progress.Read++
+
+		var row []interface{}
+		if err == nil {
+			row, err = config.convert(raw)
+		}
+
+		if err != nil {
+			progress.Rejected++
+			if config.OnError != nil {
+				config.OnError(ErrorRow{Record: progress.Read, Raw: rawRecord, Err: err})
+			}
+
+			if config.MaxErrors >= 0 && progress.Rejected > config.MaxErrors {
+				return progress, fmt.Errorf("%w: rejected record %d: %v", ErrTooManyErrors, progress.Read, err)
+			}
+			continue
+		}
+
+		batch = append(batch, row)
+		if len(batch) == config.BatchSize {
+			if err := insert(); err != nil {
+				return progress, err
+			}
+		}
+	}
+
+	return progress, insert()
+}
+
+func (config *Config) validate() error {
+	if config.Table == "" {
+		return dberrors.New(dberrors.CategoryConfig, "no table configured")
+	}
+
+	if len(config.Columns) == 0 {
+		return dberrors.New(dberrors.CategoryConfig, "no columns configured")
+	}
+
+	if config.Insert == nil {
+		return dberrors.New(dberrors.CategoryConfig, "no insert function configured")
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+
+	return nil
+}
+
+// convert converts the raw values of a record to the types of the
+// columns.
+func (config Config) convert(raw []interface{}) ([]interface{}, error) {
+	row := make([]interface{}, len(raw))
+
+	for i, value := range raw {
+		column := config.Columns[i]
+
+		var err error
+		if config.Format == CSV {
+			if value != nil {
+				row[i], err = column.ParseText(value.(string))
+			}
+		} else {
+			row[i], err = column.parseJSON(value)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return row, nil
+}
+
+// SQLInsert returns an InsertFunc inserting each batch with a prepared
+// statement in a transaction on db.
+//
+// It can be replaced by an InsertFunc using the bulk copy protocol
+// once it is implemented in tds.
+func SQLInsert(db *sql.DB) InsertFunc {
+	return func(ctx context.Context, table string, columns []string, rows [][]interface{}) error {
+		query := fmt.Sprintf("insert into %s (%s) values (%s)", table, strings.Join(columns, ", "),
+			strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("error starting transaction: %w", err)
+		}
+
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error preparing statement: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, row := range rows {
+			if _, err := stmt.ExecContext(ctx, row...); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("error inserting row %v: %w", row, err)
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("error committing transaction: %w", err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bulkload
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+type testInserter struct {
+	batches [][][]interface{}
+}
+
+func (inserter *testInserter) insert(ctx context.Context, table string, columns []string, rows [][]interface{}) error {
+	inserter.batches = append(inserter.batches, rows)
+	return nil
+}
+
+func testConfig(inserter *testInserter) Config {
+	return Config{
+		Table: "t",
+		Columns: []Column{
+			{Name: "a", Type: asetypes.INT4},
+			{Name: "b", Type: asetypes.VARCHAR},
+		},
+		Insert:    inserter.insert,
+		BatchSize: 2,
+	}
+}
+
+func TestLoad_CSV(t *testing.T) {
+	inserter := &testInserter{}
+	config := testConfig(inserter)
+	config.Header = true
+	config.Null = "NULL"
+
+	progresses := []Progress{}
+	config.OnProgress = func(progress Progress) {
+		progresses = append(progresses, progress)
+	}
+
+	input := "b,a\nx,1\nNULL,2\ny,3\n"
+
+	progress, err := Load(context.Background(), strings.NewReader(input), config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := [][][]interface{}{
+		{{int32(1), "x"}, {int32(2), nil}},
+		{{int32(3), "y"}},
+	}
+	if !reflect.DeepEqual(inserter.batches, expected) {
+		t.Errorf("Expected batches %v, got %v", expected, inserter.batches)
+	}
+
+	if expected := (Progress{Read: 3, Loaded: 3, Batches: 2}); progress != expected {
+		t.Errorf("Expected progress %+v, got %+v", expected, progress)
+	}
+
+	if len(progresses) != 2 || progresses[0].Loaded != 2 {
+		t.Errorf("Expected progress after each batch, got %+v", progresses)
+	}
+}
+
+func TestLoad_NDJSON(t *testing.T) {
+	inserter := &testInserter{}
+	config := testConfig(inserter)
+	config.Format = NDJSON
+
+	input := `{"a": 1, "b": "x"}
+{"a": 2}
+`
+
+	if _, err := Load(context.Background(), strings.NewReader(input), config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := [][][]interface{}{{{int32(1), "x"}, {int32(2), nil}}}
+	if !reflect.DeepEqual(inserter.batches, expected) {
+		t.Errorf("Expected batches %v, got %v", expected, inserter.batches)
+	}
+}
+
+func TestLoad_ErrorRows(t *testing.T) {
+	inserter := &testInserter{}
+	config := testConfig(inserter)
+	config.MaxErrors = 1
+
+	errorRows := []ErrorRow{}
+	config.OnError = func(row ErrorRow) {
+		errorRows = append(errorRows, row)
+	}
+
+	progress, err := Load(context.Background(), strings.NewReader("1,x\nnan,y\n3\n4,z\n"), config)
+	if !errors.Is(err, ErrTooManyErrors) {
+		t.Fatalf("Expected ErrTooManyErrors, got: %v", err)
+	}
+
+	if len(errorRows) != 2 || errorRows[0].Record != 2 || errorRows[1].Record != 3 {
+		t.Fatalf("Expected records 2 and 3 to be rejected, got %+v", errorRows)
+	}
+
+	if !errors.Is(errorRows[0].Err, dberrors.CategoryConversion) {
+		t.Errorf("Expected conversion error, got: %v", errorRows[0].Err)
+	}
+
+	if progress.Rejected != 2 || progress.Loaded != 0 {
+		t.Errorf("Expected two rejected and no loaded rows, got %+v", progress)
+	}
+}
+
+func TestLoad_MissingHeaderColumn(t *testing.T) {
+	config := testConfig(&testInserter{})
+	config.Header = true
+
+	_, err := Load(context.Background(), strings.NewReader("a,c\n"), config)
+	if !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error, got: %v", err)
+	}
+}
+
+func TestColumn_ParseText(t *testing.T) {
+	cases := map[string]struct {
+		column   Column
+		input    string
+		expected interface{}
+	}{
+		"int1":   {Column{Type: asetypes.INT1}, "255", uint8(255)},
+		"float":  {Column{Type: asetypes.FLT8}, "0.25", float64(0.25)},
+		"bit":    {Column{Type: asetypes.BIT}, "true", true},
+		"binary": {Column{Type: asetypes.VARBINARY}, "0xcafe", []byte{0xca, 0xfe}},
+		"date":   {Column{Type: asetypes.DATE}, "2020-01-02", time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			value, err := cas.column.ParseText(cas.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(value, cas.expected) {
+				t.Errorf("Expected %v, got %v", cas.expected, value)
+			}
+		})
+	}
+
+	if _, err := (Column{Type: asetypes.INT1}).ParseText("256"); !errors.Is(err, dberrors.CategoryConversion) {
+		t.Errorf("Expected conversion error for overflow, got: %v", err)
+	}
+
+	dec, err := (Column{Type: asetypes.DECN, Precision: 10, Scale: 2}).ParseText("12.34")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing decimal: %v", err)
+	}
+
+	if s := dec.(*asetypes.Decimal).String(); s != "12.34" {
+		t.Errorf("Expected 12.34, got %s", s)
+	}
+}
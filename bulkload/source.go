@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bulkload
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// Format is the format of the loaded data.
+type Format int
+
+// Formats of the loaded data.
+const (
+	// CSV is comma separated values as read by encoding/csv.
+	CSV Format = iota
+	// NDJSON is newline delimited JSON with one object per row.
+	NDJSON
+)
+
+func (format Format) String() string {
+	if format == NDJSON {
+		return "ndjson"
+	}
+	return "csv"
+}
+
+// source reads the records of the loaded data.
+type source interface {
+	// next returns the raw values of the next record in the order of
+	// the columns and the raw record for error reports. io.EOF is
+	// returned after the last record.
+	next() ([]interface{}, string, error)
+}
+
+type csvSource struct {
+	reader *csv.Reader
+	config Config
+	// indexes are the positions of the columns in a record.
+	indexes []int
+}
+
+func newCSVSource(r io.Reader, config Config) (*csvSource, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	// The number of fields is validated against the columns.
+	reader.FieldsPerRecord = -1
+	if config.Comma != 0 {
+		reader.Comma = config.Comma
+	}
+
+	src := &csvSource{reader: reader, config: config}
+
+	src.indexes = make([]int, len(config.Columns))
+	for i := range src.indexes {
+		src.indexes[i] = i
+	}
+
+	if !config.Header {
+		return src, nil
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading header: %w", err)
+	}
+
+	positions := map[string]int{}
+	for i, name := range header {
+		positions[name] = i
+	}
+
+	for i, column := range config.Columns {
+		position, ok := positions[column.Name]
+		if !ok {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "column %s is missing in header", column.Name)
+		}
+		src.indexes[i] = position
+	}
+
+	return src, nil
+}
+
+func (src *csvSource) next() ([]interface{}, string, error) {
+	record, err := src.reader.Read()
+	if err != nil {
+		return nil, "", err
+	}
+
+	raw := fmt.Sprintf("%q", record)
+
+	values := make([]interface{}, len(src.indexes))
+	for i, index := range src.indexes {
+		if index >= len(record) {
+			return nil, raw, dberrors.Errorf(dberrors.CategoryConversion, "record has %d fields, column %s is field %d",
+				len(record), src.config.Columns[i].Name, index+1)
+		}
+
+		if record[index] != src.config.Null {
+			values[i] = record[index]
+		}
+	}
+
+	return values, raw, nil
+}
+
+type ndjsonSource struct {
+	decoder *json.Decoder
+	config  Config
+}
+
+func newNDJSONSource(r io.Reader, config Config) *ndjsonSource {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return &ndjsonSource{decoder: decoder, config: config}
+}
+
+func (src *ndjsonSource) next() ([]interface{}, string, error) {
+	var raw json.RawMessage
+	if err := src.decoder.Decode(&raw); err != nil {
+		return nil, "", err
+	}
+
+	object := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return nil, string(raw), dberrors.Errorf(dberrors.CategoryConversion, "error decoding object: %w", err)
+	}
+
+	values := make([]interface{}, len(src.config.Columns))
+	for i, column := range src.config.Columns {
+		// Missing keys are loaded as NULL.
+		values[i] = object[column.Name]
+	}
+
+	return values, string(raw), nil
+}
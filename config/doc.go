@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package config resolves a dsn.Info from multiple sources.
+
+Values are applied with the following precedence, independent of the
+order in which the sources are added:
+
+	defaults < file < environment < flags < explicit
+
+The Provenance returned by Resolver.Resolve reports the source of each
+final value:
+
+	resolver := config.NewResolver()
+	if err := resolver.AddFile("/etc/ase.conf"); err != nil {
+		return err
+	}
+	resolver.AddEnv("ASE")
+	resolver.AddFlagSet(flag.CommandLine, map[string]string{"H": "host"})
+
+	info, provenance, err := resolver.Resolve()
+	if err != nil {
+		return err
+	}
+	fmt.Println(provenance)
+*/
+package config
@@ -0,0 +1,260 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/flagslice"
+)
+
+// Source is the kind of source a value originates from. Sources with
+// a higher value take precedence.
+type Source int
+
+// Sources in order of precedence.
+const (
+	SourceDefault Source = iota
+	SourceFile
+	SourceEnv
+	SourceFlag
+	SourceExplicit
+)
+
+func (source Source) String() string {
+	switch source {
+	case SourceDefault:
+		return "default"
+	case SourceFile:
+		return "file"
+	case SourceEnv:
+		return "env"
+	case SourceFlag:
+		return "flag"
+	case SourceExplicit:
+		return "explicit"
+	default:
+		return fmt.Sprintf("Source(%d)", int(source))
+	}
+}
+
+// Origin describes where a value originates from.
+type Origin struct {
+	Source Source
+	// Name identifies the origin within the source, e.g. the name of
+	// the environment variable or the path and line of the file.
+	Name  string
+	Value string
+}
+
+// Provenance maps the canonical keys of dsn.Info fields and properties
+// to the origin of their final value.
+type Provenance map[string]Origin
+
+// String returns a table of the keys, their values and origins.
+// Passwords are masked.
+func (provenance Provenance) String() string {
+	keys := make([]string, 0, len(provenance))
+	for key := range provenance {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sb := &strings.Builder{}
+	w := tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "key\tvalue\tsource\torigin")
+	for _, key := range keys {
+		origin := provenance[key]
+
+		value := origin.Value
+		if key == "password" && value != "" {
+			value = "***"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key, value, origin.Source, origin.Name)
+	}
+	w.Flush()
+
+	return sb.String()
+}
+
+// setting is a single key/value pair of a source.
+type setting struct {
+	key, value string
+	origin     string
+}
+
+// Resolver merges dsn.Info values from multiple sources.
+type Resolver struct {
+	layers map[Source][]setting
+}
+
+// NewResolver returns an empty Resolver.
+func NewResolver() *Resolver {
+	return &Resolver{layers: map[Source][]setting{}}
+}
+
+func (resolver *Resolver) add(source Source, key, value, origin string) {
+	resolver.layers[source] = append(resolver.layers[source], setting{key: key, value: value, origin: origin})
+}
+
+// SetDefault sets the default value for key.
+func (resolver *Resolver) SetDefault(key, value string) {
+	resolver.add(SourceDefault, key, value, "SetDefault")
+}
+
+// SetField sets key explicitly, taking precedence over all other
+// sources.
+func (resolver *Resolver) SetField(key, value string) {
+	resolver.add(SourceExplicit, key, value, "SetField")
+}
+
+// AddFile reads values from the file at path.
+//
+// The file contains one key=value pair per line. Empty lines and lines
+// starting with # are ignored. Values may be enclosed in single or
+// double quotation marks.
+func (resolver *Resolver) AddFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return dberrors.Wrap(dberrors.CategoryConfig, fmt.Errorf("error opening config file: %w", err))
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineNr := 1; scanner.Scan(); lineNr++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		lineS := strings.SplitN(line, "=", 2)
+		if len(lineS) != 2 {
+			return dberrors.Errorf(dberrors.CategoryConfig, "%s:%d: line is not a key=value pair: %s",
+				path, lineNr, line)
+		}
+
+		key, value := strings.TrimSpace(lineS[0]), strings.TrimSpace(lineS[1])
+		for _, quot := range []string{"'", `"`} {
+			if len(value) >= 2 && strings.HasPrefix(value, quot) && strings.HasSuffix(value, quot) {
+				value = value[1 : len(value)-1]
+			}
+		}
+
+		resolver.add(SourceFile, key, value, fmt.Sprintf("%s:%d", path, lineNr))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return dberrors.Wrap(dberrors.CategoryConfig, fmt.Errorf("error reading config file: %w", err))
+	}
+
+	return nil
+}
+
+// AddEnv reads values from the environment variables with the passed
+// prefix, following the rules of dsn.NewInfoFromEnv.
+func (resolver *Resolver) AddEnv(prefix string) {
+	if prefix == "" {
+		prefix = "ASE"
+	}
+	prefix += "_"
+
+	envs := os.Environ()
+	sort.Strings(envs)
+
+	for _, env := range envs {
+		envSplit := strings.SplitN(env, "=", 2)
+		name, value := envSplit[0], envSplit[1]
+
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimPrefix(name, prefix))
+		key = strings.ReplaceAll(key, "_", "-")
+
+		resolver.add(SourceEnv, key, value, name)
+	}
+}
+
+// AddFlag records the value of the flag name for key.
+func (resolver *Resolver) AddFlag(name, key, value string) {
+	resolver.add(SourceFlag, key, value, "-"+name)
+}
+
+// AddFlagSet records the flags of fs that have been set.
+//
+// keys maps flag names to the keys they set. Flags of type
+// *flagslice.FlagMap set their keys and values directly, other flags
+// without an entry in keys are ignored.
+//
+// The flags are recorded in lexicographical order, as fs does not
+// record the order of the command line, and the values of a FlagMap in
+// the order they were passed. Use AddFlag to record flags in
+// command-line order.
+func (resolver *Resolver) AddFlagSet(fs *flag.FlagSet, keys map[string]string) {
+	fs.Visit(func(f *flag.Flag) {
+		if flagMap, ok := f.Value.(*flagslice.FlagMap); ok {
+			for _, entry := range flagMap.Entries() {
+				resolver.AddFlag(f.Name, entry.Key, entry.Value)
+			}
+			return
+		}
+
+		if key, ok := keys[f.Name]; ok {
+			resolver.AddFlag(f.Name, key, f.Value.String())
+		}
+	})
+}
+
+// Resolve applies the values of all sources in order of precedence to
+// a dsn.Info created with dsn.NewInfo and returns the provenance of the
+// final values.
+//
+// Properties set by a source replace the values of sources with lower
+// precedence, multiple values of the same source are kept.
+func (resolver *Resolver) Resolve() (*dsn.Info, Provenance, error) {
+	info := dsn.NewInfo()
+	provenance := Provenance{}
+
+	if info.PacketReadTimeout != 0 {
+		provenance["packet-read-timeout"] = Origin{
+			Source: SourceDefault,
+			Name:   "dsn.NewInfo",
+			Value:  fmt.Sprintf("%d", info.PacketReadTimeout),
+		}
+	}
+
+	for source := SourceDefault; source <= SourceExplicit; source++ {
+		for _, setting := range resolver.layers[source] {
+			key, isField := dsn.CanonicalKey(setting.key)
+
+			if prev, ok := provenance[key]; !isField && ok && prev.Source < source {
+				info.ConnectProps.Del(key)
+			}
+
+			if err := info.SetField(key, setting.value); err != nil {
+				return nil, nil, fmt.Errorf("error setting %s from %s %s: %w",
+					key, source, setting.origin, err)
+			}
+
+			provenance[key] = Origin{
+				Source: source,
+				Name:   setting.origin,
+				Value:  setting.value,
+			}
+		}
+	}
+
+	return info, provenance, nil
+}
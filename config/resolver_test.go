@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/flagslice"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatalf("Error creating temporary directory: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "ase.conf")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Error writing config file: %v", err)
+	}
+
+	return path
+}
+
+func TestResolver_Precedence(t *testing.T) {
+	path := writeConfigFile(t, `
+# comment
+hostname = filehost
+port='4901'
+username=fileuser
+prop=file
+`)
+
+	os.Setenv("CONFIGTEST_PORT", "5000")
+	os.Setenv("CONFIGTEST_PROP", "env")
+	defer os.Unsetenv("CONFIGTEST_PORT")
+	defer os.Unsetenv("CONFIGTEST_PROP")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("u", "", "username")
+	fs.String("D", "", "database")
+	opts := &flagslice.FlagMap{}
+	fs.Var(opts, "o", "properties")
+	if err := fs.Parse([]string{"-u", "flaguser", "-o", "prop=flag1", "-o", "prop=flag2"}); err != nil {
+		t.Fatalf("Error parsing flags: %v", err)
+	}
+
+	resolver := NewResolver()
+	// Explicit values are added first to verify that precedence does
+	// not depend on the order.
+	resolver.SetField("database", "explicitdb")
+	resolver.AddFlagSet(fs, map[string]string{"u": "username", "D": "database"})
+	resolver.AddEnv("CONFIGTEST")
+	if err := resolver.AddFile(path); err != nil {
+		t.Fatalf("Unexpected error reading file: %v", err)
+	}
+	resolver.SetDefault("host", "defaulthost")
+
+	info, provenance, err := resolver.Resolve()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]struct {
+		value  string
+		source Source
+		origin string
+	}{
+		"host":     {"filehost", SourceFile, path + ":3"},
+		"port":     {"5000", SourceEnv, "CONFIGTEST_PORT"},
+		"username": {"flaguser", SourceFlag, "-u"},
+		"database": {"explicitdb", SourceExplicit, "SetField"},
+		"prop":     {"flag2", SourceFlag, "-o"},
+	}
+
+	for key, exp := range expected {
+		origin, ok := provenance[key]
+		if !ok {
+			t.Errorf("No provenance for %s", key)
+			continue
+		}
+
+		if origin.Value != exp.value || origin.Source != exp.source || origin.Name != exp.origin {
+			t.Errorf("Expected %s from %s %s for %s, received %s from %s %s",
+				exp.value, exp.source, exp.origin, key, origin.Value, origin.Source, origin.Name)
+		}
+	}
+
+	if info.Host != "filehost" || info.Port != "5000" || info.Username != "flaguser" || info.Database != "explicitdb" {
+		t.Errorf("Unexpected info: %s", info.AsSimple())
+	}
+
+	if props := info.ConnectProps["prop"]; len(props) != 2 || props[0] != "flag1" || props[1] != "flag2" {
+		t.Errorf("Expected properties of lower sources to be replaced, received %v", props)
+	}
+}
+
+func TestResolver_AddFile_Invalid(t *testing.T) {
+	path := writeConfigFile(t, "host=localhost\ninvalid\n")
+
+	err := NewResolver().AddFile(path)
+	if !errors.Is(err, dberrors.CategoryConfig) {
+		t.Fatalf("Expected config error, received: %v", err)
+	}
+
+	if !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("Expected line number in error: %v", err)
+	}
+}
+
+func TestResolver_Resolve_Invalid(t *testing.T) {
+	resolver := NewResolver()
+	resolver.SetField("tls", "maybe")
+
+	if _, _, err := resolver.Resolve(); !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error, received: %v", err)
+	}
+}
+
+func TestProvenance_String(t *testing.T) {
+	provenance := Provenance{
+		"password": {Source: SourceEnv, Name: "ASE_PASSWORD", Value: "secret"},
+		"host":     {Source: SourceFlag, Name: "-H", Value: "localhost"},
+	}
+
+	out := provenance.String()
+
+	if strings.Contains(out, "secret") {
+		t.Errorf("Expected password to be masked: %s", out)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "host") {
+		t.Errorf("Expected header and sorted keys: %s", out)
+	}
+}
@@ -0,0 +1,389 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+	"github.com/SAP/go-dblib/tdstest"
+	"github.com/SAP/go-dblib/version"
+)
+
+// ErrSkipped is wrapped by the errors of checks that do not apply to
+// the target.
+var ErrSkipped = errors.New("check skipped")
+
+// Check is a protocol-level check.
+type Check struct {
+	Name string
+	// Run returns an error if the target does not behave as
+	// expected.
+	Run func(ctx context.Context, target Target) error
+
+	// mock scripts the responses the check expects on the mock
+	// server.
+	mock func(server *tdstest.Server) error
+}
+
+// DefaultChecks returns the checks of the suite: the login and the
+// negotiation of capabilities, requests and responses spanning multiple
+// packets, the cancellation of requests and the wire format of the data
+// types.
+func DefaultChecks() []Check {
+	checks := []Check{
+		{Name: "login", Run: checkLogin},
+		{Name: "capabilities", Run: checkCapabilities},
+		{Name: "multi-packet-request", Run: checkMultiPacketRequest, mock: mockMultiPacketRequest},
+		{Name: "multi-packet-response", Run: checkMultiPacketResponse, mock: mockMultiPacketResponse},
+		{Name: "cancel", Run: checkCancel, mock: mockCancel},
+	}
+
+	for _, dataType := range dataTypes {
+		checks = append(checks, dataType.check())
+	}
+
+	return checks
+}
+
+// connect dials target with props and calls fn with the connection.
+func connect(ctx context.Context, target Target, props map[string]string,
+	fn func(conn *tds.Conn, channel *tds.Channel) error) error {
+
+	conn, channel, err := target.Dial(ctx, props)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return fn(conn, channel)
+}
+
+// query sends cmd as language command on channel and returns the
+// values of the received rows. An error is returned if the server
+// reports an error.
+func query(ctx context.Context, channel *tds.Channel, cmd string) ([][]interface{}, error) {
+	if err := channel.SendPackage(ctx, &tds.LanguagePackage{Cmd: cmd}); err != nil {
+		return nil, fmt.Errorf("error sending command: %w", err)
+	}
+
+	rows := [][]interface{}{}
+	var eed *tds.EEDPackage
+	for {
+		pkg, err := channel.NextPackage(ctx, true)
+		if err != nil {
+			return nil, fmt.Errorf("error reading response: %w", err)
+		}
+
+		switch typed := pkg.(type) {
+		case *tds.RowPackage:
+			row := make([]interface{}, len(typed.DataFields))
+			for i, field := range typed.DataFields {
+				row[i] = field.Value()
+			}
+			rows = append(rows, row)
+		case *tds.EEDPackage:
+			if typed.Class > 10 && eed == nil {
+				eed = typed
+			}
+		case *tds.DonePackage:
+			if typed.Status != tds.TDS_DONE_FINAL {
+				continue
+			}
+
+			if eed != nil {
+				return nil, fmt.Errorf("server reported error %d: %s", eed.MsgNumber, eed.Msg)
+			}
+			return rows, nil
+		}
+	}
+}
+
+// queryValue returns the single value returned by cmd.
+func queryValue(ctx context.Context, channel *tds.Channel, cmd string) (interface{}, error) {
+	rows, err := query(ctx, channel, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) != 1 || len(rows[0]) != 1 {
+		return nil, fmt.Errorf("expected a single value, received %v", rows)
+	}
+
+	return rows[0][0], nil
+}
+
+// discard reads packages until the end of the current response.
+func discard(ctx context.Context, channel *tds.Channel) error {
+	for {
+		pkg, err := channel.NextPackage(ctx, true)
+		if err != nil {
+			return err
+		}
+
+		if done, ok := pkg.(*tds.DonePackage); ok && done.Status == tds.TDS_DONE_FINAL {
+			return nil
+		}
+	}
+}
+
+// equal returns true if the received value equals the expected value.
+func equal(expected, received interface{}) bool {
+	switch typed := expected.(type) {
+	case time.Time:
+		t, ok := received.(time.Time)
+		return ok && typed.Equal(t)
+	case []byte:
+		bs, ok := received.([]byte)
+		return ok && bytes.Equal(typed, bs)
+	default:
+		return reflect.DeepEqual(expected, received)
+	}
+}
+
+func checkLogin(ctx context.Context, target Target) error {
+	return connect(ctx, target, nil, func(conn *tds.Conn, channel *tds.Channel) error {
+		if conn.ServerName() == "" {
+			return errors.New("login acknowledgement contains no server name")
+		}
+
+		if tdsVersion := conn.TDSVersion(); !tdsVersion.AtLeast(version.TDS50) {
+			return fmt.Errorf("expected TDS version %s, server acknowledged %s", version.TDS50, tdsVersion)
+		}
+
+		return nil
+	})
+}
+
+func checkCapabilities(ctx context.Context, target Target) error {
+	return connect(ctx, target, nil, func(conn *tds.Conn, channel *tds.Channel) error {
+		if conn.GrantedCapabilities() == nil {
+			return fmt.Errorf("%w: server did not acknowledge capabilities", ErrSkipped)
+		}
+
+		if !conn.HasCapability(tds.TDS_REQ_LANG) {
+			return fmt.Errorf("server did not grant %s", tds.TDS_REQ_LANG)
+		}
+
+		return nil
+	})
+}
+
+// multiPacketCommand is sent by checkMultiPacketRequest. The comment
+// pads the command beyond the packet sizes commonly negotiated.
+var multiPacketCommand = "select 1 -- " + strings.Repeat("x", 8192)
+
+func checkMultiPacketRequest(ctx context.Context, target Target) error {
+	return connect(ctx, target, nil, func(conn *tds.Conn, channel *tds.Channel) error {
+		if len(multiPacketCommand) <= conn.PacketSize() {
+			return fmt.Errorf("%w: command fits into a packet of %d bytes", ErrSkipped, conn.PacketSize())
+		}
+
+		value, err := queryValue(ctx, channel, multiPacketCommand)
+		if err != nil {
+			return err
+		}
+
+		if !equal(int32(1), value) {
+			return fmt.Errorf("expected 1, received %v (%T)", value, value)
+		}
+
+		return nil
+	})
+}
+
+func mockMultiPacketRequest(server *tdstest.Server) error {
+	response, err := tdstest.ResultSet(
+		[]tdstest.Column{{DataType: asetypes.INT4}},
+		[]interface{}{int32(1)},
+	)
+	if err != nil {
+		return err
+	}
+
+	server.Respond(multiPacketCommand, response...)
+	return nil
+}
+
+// multiPacketRows is the number of rows returned by
+// multiPacketResponseCommand.
+const multiPacketRows = 200
+
+var multiPacketResponseCommand = fmt.Sprintf(
+	"select number from master..spt_values where type = 'P' and number < %d order by number",
+	multiPacketRows)
+
+func checkMultiPacketResponse(ctx context.Context, target Target) error {
+	return connect(ctx, target, nil, func(conn *tds.Conn, channel *tds.Channel) error {
+		rows, err := query(ctx, channel, multiPacketResponseCommand)
+		if err != nil {
+			return err
+		}
+
+		if len(rows) != multiPacketRows {
+			return fmt.Errorf("expected %d rows, received %d", multiPacketRows, len(rows))
+		}
+
+		for i, row := range rows {
+			if len(row) != 1 || !equal(int32(i), row[0]) {
+				return fmt.Errorf("expected row %d to be [%d], received %v", i, i, row)
+			}
+		}
+
+		return nil
+	})
+}
+
+func mockMultiPacketResponse(server *tdstest.Server) error {
+	rows := make([][]interface{}, multiPacketRows)
+	for i := range rows {
+		rows[i] = []interface{}{int32(i)}
+	}
+
+	response, err := tdstest.ResultSet([]tdstest.Column{{Name: "number", DataType: asetypes.INT4}}, rows...)
+	if err != nil {
+		return err
+	}
+
+	server.Respond(multiPacketResponseCommand, response...)
+	return nil
+}
+
+// cancelTimeout is the statement timeout after which checkCancel
+// expects cancelCommand to be cancelled.
+const cancelTimeout = 200 * time.Millisecond
+
+const cancelCommand = "waitfor delay '00:00:01'"
+
+func checkCancel(ctx context.Context, target Target) error {
+	props := map[string]string{"statement-timeout": cancelTimeout.String()}
+
+	return connect(ctx, target, props, func(conn *tds.Conn, channel *tds.Channel) error {
+		// The statement timeout only applies to requests without
+		// a deadline.
+		_, err := query(withoutDeadline{ctx}, channel, cancelCommand)
+		if !errors.Is(err, tds.ErrStatementTimeout) {
+			return fmt.Errorf("expected statement timeout, received %v", err)
+		}
+
+		// The response to the attention ends with a final done.
+		if err := discard(ctx, channel); err != nil {
+			return fmt.Errorf("error reading response to attention: %w", err)
+		}
+
+		value, err := queryValue(ctx, channel, "select 1")
+		if err != nil {
+			return fmt.Errorf("error using channel after cancellation: %w", err)
+		}
+
+		if !equal(int32(1), value) {
+			return fmt.Errorf("expected 1 after cancellation, received %v (%T)", value, value)
+		}
+
+		return nil
+	})
+}
+
+func mockCancel(server *tdstest.Server) error {
+	server.Handle(cancelCommand, func(*tdstest.Request) []tds.Package {
+		time.Sleep(2 * cancelTimeout)
+		return []tds.Package{tdstest.Done(0)}
+	})
+
+	response, err := tdstest.ResultSet(
+		[]tdstest.Column{{DataType: asetypes.INT4}},
+		[]interface{}{int32(1)},
+	)
+	if err != nil {
+		return err
+	}
+
+	server.Respond("select 1", response...)
+	return nil
+}
+
+// withoutDeadline is a context without the deadline of the embedded
+// context, which is still cancelled with it.
+type withoutDeadline struct {
+	context.Context
+}
+
+func (withoutDeadline) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// dataTypeCase describes the check of the wire format of a data type.
+type dataTypeCase struct {
+	dataType asetypes.DataType
+	// requires is the capability the server must grant for the
+	// data type.
+	requires tds.RequestCapability
+	query    string
+	value    interface{}
+}
+
+var dataTypes = []dataTypeCase{
+	{asetypes.INT1, tds.TDS_DATA_INT1, "select convert(tinyint, 255)", uint8(255)},
+	{asetypes.INT2, tds.TDS_DATA_INT2, "select convert(smallint, -32768)", int16(math.MinInt16)},
+	{asetypes.INT4, tds.TDS_DATA_INT4, "select convert(int, 2147483647)", int32(math.MaxInt32)},
+	{asetypes.INT8, tds.TDS_DATA_INT8, "select convert(bigint, 9223372036854775807)", int64(math.MaxInt64)},
+	{asetypes.FLT4, tds.TDS_DATA_FLT4, "select convert(real, 1.5)", float32(1.5)},
+	{asetypes.FLT8, tds.TDS_DATA_FLT8, "select convert(float, 2.25)", float64(2.25)},
+	{asetypes.BIT, tds.TDS_DATA_BIT, "select convert(bit, 1)", true},
+	{asetypes.VARCHAR, tds.TDS_DATA_VCHAR, "select convert(varchar(20), 'conformance')", "conformance"},
+	{asetypes.VARBINARY, tds.TDS_DATA_VBIN, "select convert(varbinary(4), 0xdeadbeef)", []byte{0xde, 0xad, 0xbe, 0xef}},
+	{asetypes.DATETIME, tds.TDS_DATA_DATETIMEN, "select convert(datetime, '2020-01-02 03:04:05')",
+		time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)},
+	{asetypes.DATE, tds.TDS_DATA_DATE, "select convert(date, '2020-01-02')",
+		time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)},
+	{asetypes.TIME, tds.TDS_DATA_TIME, "select convert(time, '03:04:05')",
+		time.Date(1, time.January, 1, 3, 4, 5, 0, time.UTC)},
+	{asetypes.BIGDATETIMEN, tds.TDS_DATA_BIGDATETIME, "select convert(bigdatetime, '2020-01-02 03:04:05.123456')",
+		time.Date(2020, time.January, 2, 3, 4, 5, 123456000, time.UTC)},
+}
+
+func (c dataTypeCase) check() Check {
+	return Check{
+		Name: "datatype/" + c.dataType.String(),
+		Run: func(ctx context.Context, target Target) error {
+			return connect(ctx, target, nil, func(conn *tds.Conn, channel *tds.Channel) error {
+				if !conn.HasCapability(c.requires) {
+					return fmt.Errorf("%w: server did not grant %s", ErrSkipped, c.requires)
+				}
+
+				value, err := queryValue(ctx, channel, c.query)
+				if err != nil {
+					return err
+				}
+
+				if !equal(c.value, value) {
+					return fmt.Errorf("expected %v (%T), received %v (%T)", c.value, c.value, value, value)
+				}
+
+				return nil
+			})
+		},
+		mock: func(server *tdstest.Server) error {
+			response, err := tdstest.ResultSet(
+				[]tdstest.Column{{DataType: c.dataType}},
+				[]interface{}{c.value},
+			)
+			if err != nil {
+				return err
+			}
+
+			server.Respond(c.query, response...)
+			return nil
+		},
+	}
+}
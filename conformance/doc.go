@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package conformance provides a suite of protocol-level checks to
+validate the package tds against a server, e.g. a new ASE version.
+
+The checks cover the login and the negotiation of capabilities,
+requests and responses spanning multiple packets, the cancellation of
+requests with the statement timeout and the wire format of the data
+types:
+
+	info, err := dsn.NewInfoFromEnv("")
+	if err != nil {
+		return err
+	}
+
+	report := conformance.Run(ctx, conformance.DSNTarget(info))
+	fmt.Println(report)
+	for _, result := range report.Failed() {
+		fmt.Printf("%s: %s\n", result.Check, result.Error)
+	}
+
+The Report can be encoded as JSON to compare the results of multiple
+servers.
+
+The suite can also be run against the mock server of package tdstest,
+which is scripted with the responses expected by the checks:
+
+	server, err := conformance.NewMockServer()
+	if err != nil {
+		return err
+	}
+
+	report := conformance.Run(ctx, conformance.MockTarget(server))
+*/
+package conformance
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	// Passed signals that the target behaved as expected.
+	Passed Status = iota
+	// Failed signals that the target deviated from the expected
+	// behaviour or the check could not be executed.
+	Failed
+	// Skipped signals that the check does not apply to the target,
+	// e.g. because the server did not grant a capability.
+	Skipped
+)
+
+var statusNames = map[Status]string{
+	Passed:  "passed",
+	Failed:  "failed",
+	Skipped: "skipped",
+}
+
+func (status Status) String() string {
+	if name, ok := statusNames[status]; ok {
+		return name
+	}
+	return fmt.Sprintf("Status(%d)", status)
+}
+
+// MarshalText implements encoding.TextMarshaler, so reports encode
+// the status by name.
+func (status Status) MarshalText() ([]byte, error) {
+	return []byte(status.String()), nil
+}
+
+// Result is the result of a single check.
+type Result struct {
+	Check    string        `json:"check"`
+	Status   Status        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	// Error describes why the check failed or was skipped.
+	Error string `json:"error,omitempty"`
+}
+
+// Report is the result of running checks against a target.
+type Report struct {
+	Target string `json:"target"`
+	// Server describes the server as acknowledged during the login,
+	// see serverinfo.FromLogin. It is empty if the login failed.
+	Server   string        `json:"server,omitempty"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+	Results  []Result      `json:"results"`
+}
+
+// Passed returns true if no check failed.
+func (report Report) Passed() bool {
+	return len(report.Failed()) == 0
+}
+
+// Failed returns the results of the failed checks.
+func (report Report) Failed() []Result {
+	failed := []Result{}
+	for _, result := range report.Results {
+		if result.Status == Failed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+func (report Report) String() string {
+	counts := map[Status]int{}
+	for _, result := range report.Results {
+		counts[result.Status]++
+	}
+
+	return fmt.Sprintf("%s: %d passed, %d failed, %d skipped in %s",
+		report.Target, counts[Passed], counts[Failed], counts[Skipped], report.Duration)
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SAP/go-dblib/serverinfo"
+)
+
+// CheckTimeout is the duration a single check may take.
+const CheckTimeout = 30 * time.Second
+
+// Run runs checks against target and returns the report. If no checks
+// are passed DefaultChecks are run.
+//
+// Checks are run sequentially on their own connections, so a failing
+// check does not affect the following checks.
+func Run(ctx context.Context, target Target, checks ...Check) Report {
+	if len(checks) == 0 {
+		checks = DefaultChecks()
+	}
+
+	report := Report{
+		Target:  target.Name,
+		Started: time.Now(),
+		Results: make([]Result, 0, len(checks)),
+	}
+
+	report.Server = describe(ctx, target)
+
+	for _, check := range checks {
+		report.Results = append(report.Results, runCheck(ctx, target, check))
+	}
+
+	report.Duration = time.Since(report.Started)
+	return report
+}
+
+// runCheck runs check and returns its result.
+func runCheck(ctx context.Context, target Target, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+
+	result := Result{Check: check.Name}
+
+	start := time.Now()
+	err := safeRun(ctx, target, check)
+	result.Duration = time.Since(start)
+
+	switch {
+	case err == nil:
+		result.Status = Passed
+	case errors.Is(err, ErrSkipped):
+		result.Status = Skipped
+		result.Error = err.Error()
+	default:
+		result.Status = Failed
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// safeRun runs check and returns panics as error, as a misbehaving
+// server must not abort the suite.
+func safeRun(ctx context.Context, target Target, check Check) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("check panicked: %v", r)
+		}
+	}()
+
+	return check.Run(ctx, target)
+}
+
+// describe returns the description of the server of target or an
+// empty string if the login fails.
+func describe(ctx context.Context, target Target) string {
+	ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+
+	conn, _, err := target.Dial(ctx, nil)
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+
+	return serverinfo.FromLogin(conn).String()
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRun_Mock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := NewMockServer()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	report := Run(ctx, MockTarget(server))
+
+	if len(report.Results) != len(DefaultChecks()) {
+		t.Errorf("Expected %d results, got %d", len(DefaultChecks()), len(report.Results))
+	}
+
+	for _, result := range report.Results {
+		if result.Status != Passed {
+			t.Errorf("Expected check %s to pass, got %s: %s", result.Check, result.Status, result.Error)
+		}
+	}
+
+	if !strings.HasPrefix(report.Server, "tdstest") {
+		t.Errorf("Expected server description of the mock server, got %q", report.Server)
+	}
+}
+
+func TestRun_Status(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := NewMockServer()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	report := Run(ctx, MockTarget(server),
+		Check{Name: "pass", Run: func(context.Context, Target) error { return nil }},
+		Check{Name: "fail", Run: func(context.Context, Target) error { return errors.New("failed") }},
+		Check{Name: "skip", Run: func(context.Context, Target) error { return ErrSkipped }},
+		Check{Name: "panic", Run: func(context.Context, Target) error { panic("panicked") }},
+	)
+
+	expected := []Status{Passed, Failed, Skipped, Failed}
+	for i, result := range report.Results {
+		if result.Status != expected[i] {
+			t.Errorf("Expected check %s to be %s, got %s", result.Check, expected[i], result.Status)
+		}
+	}
+
+	if report.Passed() || len(report.Failed()) != 2 {
+		t.Errorf("Expected two failed checks, got %v", report.Failed())
+	}
+
+	bs, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(bs), `"status":"skipped"`) {
+		t.Errorf("Expected status to be encoded by name, got %s", bs)
+	}
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/tds"
+	"github.com/SAP/go-dblib/tdstest"
+)
+
+// AppName is the application name the targets log in with.
+const AppName = "conformance"
+
+// Dialer returns a connection and a channel logged in to the target.
+// props are set as connection properties of the DSN, e.g.
+// "statement-timeout".
+type Dialer func(ctx context.Context, props map[string]string) (*tds.Conn, *tds.Channel, error)
+
+// Target is a server the checks are run against.
+type Target struct {
+	// Name identifies the target in the report.
+	Name string
+	Dial Dialer
+}
+
+// DSNTarget returns a Target connecting to the server of info.
+func DSNTarget(info *dsn.Info) Target {
+	return Target{
+		Name: info.Address(),
+		Dial: func(ctx context.Context, props map[string]string) (*tds.Conn, *tds.Channel, error) {
+			info := withProps(info, props)
+
+			conn, err := tds.NewConn(ctx, info)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error connecting: %w", err)
+			}
+
+			return login(ctx, conn, conn.DSN())
+		},
+	}
+}
+
+// MockTarget returns a Target connecting to server over in-memory
+// connections.
+//
+// The server must answer the commands of the checks, see
+// NewMockServer.
+func MockTarget(server *tdstest.Server) Target {
+	return Target{
+		Name: "mock",
+		Dial: func(ctx context.Context, props map[string]string) (*tds.Conn, *tds.Channel, error) {
+			info := dsn.NewInfo()
+			info.Host = "localhost"
+			info.Username = AppName
+			info = withProps(info, props)
+
+			conn, err := tds.NewConnFrom(ctx, info, server.Pipe(ctx))
+			if err != nil {
+				return nil, nil, fmt.Errorf("error connecting: %w", err)
+			}
+
+			return login(ctx, conn, info)
+		},
+	}
+}
+
+// withProps returns a copy of info with props set as connection
+// properties.
+func withProps(info *dsn.Info, props map[string]string) *dsn.Info {
+	copied := info.Clone()
+	for key, value := range props {
+		copied.ConnectProps.Set(key, value)
+	}
+
+	return copied
+}
+
+// login logs in on a new channel of conn. conn is closed if the login
+// fails.
+func login(ctx context.Context, conn *tds.Conn, info *dsn.Info) (*tds.Conn, *tds.Channel, error) {
+	channel, err := conn.NewChannel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("error creating channel: %w", err)
+	}
+
+	config, err := tds.NewLoginConfig(info)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("error creating login config: %w", err)
+	}
+	config.AppName = AppName
+
+	if err := channel.Login(ctx, config); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("error logging in: %w", err)
+	}
+
+	return conn, channel, nil
+}
+
+// NewMockServer returns a tdstest.Server answering the commands of
+// DefaultChecks like an ASE would.
+func NewMockServer() (*tdstest.Server, error) {
+	server := tdstest.NewServer()
+
+	for _, check := range DefaultChecks() {
+		if check.mock == nil {
+			continue
+		}
+
+		if err := check.mock(server); err != nil {
+			return nil, fmt.Errorf("error scripting check %s: %w", check.Name, err)
+		}
+	}
+
+	return server, nil
+}
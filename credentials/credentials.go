@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/pool"
+)
+
+// Credentials are the username and password used to log in.
+type Credentials struct {
+	Username, Password string
+	// Expires is the time after which the credentials must no longer
+	// be used, e.g. of a short-lived token. The zero time never
+	// expires.
+	Expires time.Time
+}
+
+// Provider provides the credentials for logins.
+type Provider interface {
+	// Credentials returns the credentials to log in to the server of
+	// info.
+	Credentials(ctx context.Context, info *dsn.Info) (Credentials, error)
+}
+
+// ProviderFunc is a function implementing the Provider interface.
+type ProviderFunc func(ctx context.Context, info *dsn.Info) (Credentials, error)
+
+// Credentials implements the Provider interface.
+func (fn ProviderFunc) Credentials(ctx context.Context, info *dsn.Info) (Credentials, error) {
+	return fn(ctx, info)
+}
+
+// Static returns a Provider always returning username and password.
+func Static(username, password string) Provider {
+	return ProviderFunc(func(ctx context.Context, info *dsn.Info) (Credentials, error) {
+		return Credentials{Username: username, Password: password}, nil
+	})
+}
+
+// Apply returns a copy of info with the credentials of provider.
+func Apply(ctx context.Context, provider Provider, info *dsn.Info) (*dsn.Info, error) {
+	creds, err := provider.Credentials(ctx, info)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving credentials: %w", err)
+	}
+
+	copied := info.Clone()
+	copied.Username = creds.Username
+	copied.Password = creds.Password
+
+	return copied, nil
+}
+
+// Invalidator is implemented by Providers caching credentials.
+type Invalidator interface {
+	// Invalidate discards cached credentials, e.g. after they were
+	// rejected by the server.
+	Invalidate()
+}
+
+// Dial returns a pool.DialFunc calling dial with the credentials of
+// provider, so every connection uses the current credentials.
+//
+// If dial fails and provider implements Invalidator the credentials
+// are invalidated.
+func Dial(provider Provider, dial pool.DialFunc) pool.DialFunc {
+	return func(ctx context.Context, info *dsn.Info) (pool.Conn, error) {
+		withCreds, err := Apply(ctx, provider, info)
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := dial(ctx, withCreds)
+		if err != nil {
+			if invalidator, ok := provider.(Invalidator); ok {
+				invalidator.Invalidate()
+			}
+			return nil, err
+		}
+
+		return conn, nil
+	}
+}
+
+// Cache caches the credentials of a Provider until they expire.
+type Cache struct {
+	provider Provider
+	// refreshBefore is the duration before expiry at which the
+	// credentials are refreshed.
+	refreshBefore time.Duration
+	now           func() time.Time
+
+	lock   *sync.Mutex
+	cached *Credentials
+}
+
+// NewCache returns a Cache for provider. Credentials are requested
+// from provider again refreshBefore before they expire.
+func NewCache(provider Provider, refreshBefore time.Duration) *Cache {
+	return &Cache{
+		provider:      provider,
+		refreshBefore: refreshBefore,
+		now:           time.Now,
+		lock:          &sync.Mutex{},
+	}
+}
+
+// Credentials implements the Provider interface.
+func (cache *Cache) Credentials(ctx context.Context, info *dsn.Info) (Credentials, error) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if cache.cached != nil && !cache.expiredLocked() {
+		return *cache.cached, nil
+	}
+
+	creds, err := cache.provider.Credentials(ctx, info)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	cache.cached = &creds
+	return creds, nil
+}
+
+// expiredLocked reports whether the cached credentials must be
+// refreshed.
+//
+// The caller must hold cache.lock.
+func (cache *Cache) expiredLocked() bool {
+	if cache.cached.Expires.IsZero() {
+		return false
+	}
+
+	return !cache.now().Before(cache.cached.Expires.Add(-cache.refreshBefore))
+}
+
+// Invalidate implements the Invalidator interface.
+func (cache *Cache) Invalidate() {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	cache.cached = nil
+}
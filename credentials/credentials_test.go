@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/pool"
+)
+
+type testConn struct{}
+
+func (conn testConn) Ping(ctx context.Context) error { return nil }
+func (conn testConn) State() pool.SessionState       { return pool.SessionState{} }
+func (conn testConn) Close() error                   { return nil }
+
+// rotatingProvider returns a new password on each call.
+type rotatingProvider struct {
+	calls   int
+	expires time.Time
+}
+
+func (provider *rotatingProvider) Credentials(ctx context.Context, info *dsn.Info) (Credentials, error) {
+	provider.calls++
+	return Credentials{
+		Username: "user",
+		Password: fmt.Sprintf("password%d", provider.calls),
+		Expires:  provider.expires,
+	}, nil
+}
+
+func TestDial(t *testing.T) {
+	provider := &rotatingProvider{}
+
+	passwords := []string{}
+	dial := Dial(provider, func(ctx context.Context, info *dsn.Info) (pool.Conn, error) {
+		passwords = append(passwords, info.Password)
+		return testConn{}, nil
+	})
+
+	info := dsn.NewInfo()
+	info.Password = "static"
+
+	for i := 0; i < 2; i++ {
+		if _, err := dial(context.Background(), info); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if len(passwords) != 2 || passwords[0] != "password1" || passwords[1] != "password2" {
+		t.Errorf("Expected rotated passwords, got %v", passwords)
+	}
+
+	if info.Password != "static" {
+		t.Errorf("Expected passed info to be unchanged, got password %s", info.Password)
+	}
+}
+
+func TestCache(t *testing.T) {
+	now := time.Now()
+	provider := &rotatingProvider{expires: now.Add(time.Hour)}
+
+	cache := NewCache(provider, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	info := dsn.NewInfo()
+
+	get := func() string {
+		creds, err := cache.Credentials(ctx, info)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return creds.Password
+	}
+
+	if first, second := get(), get(); first != second {
+		t.Errorf("Expected cached credentials, got %s and %s", first, second)
+	}
+
+	// Credentials are refreshed before they expire.
+	now = now.Add(time.Hour - time.Minute)
+	if password := get(); password != "password2" {
+		t.Errorf("Expected refreshed credentials, got %s", password)
+	}
+
+	cache.Invalidate()
+	if password := get(); password != "password3" {
+		t.Errorf("Expected credentials after invalidation, got %s", password)
+	}
+}
+
+func TestDial_InvalidatesOnError(t *testing.T) {
+	provider := &rotatingProvider{}
+	cache := NewCache(provider, 0)
+
+	errLogin := errors.New("login failed")
+	dial := Dial(cache, func(ctx context.Context, info *dsn.Info) (pool.Conn, error) {
+		return nil, errLogin
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := dial(context.Background(), dsn.NewInfo()); !errors.Is(err, errLogin) {
+			t.Fatalf("Expected login error, got: %v", err)
+		}
+	}
+
+	if provider.calls != 2 {
+		t.Errorf("Expected credentials to be requested again after a failed dial, got %d calls", provider.calls)
+	}
+}
@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package credentials provides the credentials for logins on every
+connect, so long-lived processes pick up rotated passwords or
+short-lived tokens when reconnecting.
+
+A Provider is consulted by Dial before each connection is established
+instead of using the username and password of the dsn.Info:
+
+	provider := credentials.NewCache(credentials.ProviderFunc(
+		func(ctx context.Context, info *dsn.Info) (credentials.Credentials, error) {
+			token, expires, err := vault.Token(ctx)
+			if err != nil {
+				return credentials.Credentials{}, err
+			}
+			return credentials.Credentials{Username: "app", Password: token, Expires: expires}, nil
+		},
+	), time.Minute)
+
+	p, err := pool.New(ctx, pool.Config{
+		DSN:  info,
+		Dial: credentials.Dial(provider, pool.DialTDS),
+	})
+
+Cache reuses credentials until shortly before they expire. Dial
+invalidates cached credentials if a connection could not be
+established, e.g. because the server rejected them.
+*/
+package credentials
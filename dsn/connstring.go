@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// connStringAliases maps the lowercased keys of ODBC and jConnect
+// connection strings to the json tags of Info.
+var connStringAliases = map[string]string{
+	"server":          "host",
+	"servername":      "host",
+	"data source":     "host",
+	"address":         "host",
+	"networkaddress":  "host",
+	"uid":             "username",
+	"user id":         "username",
+	"pwd":             "password",
+	"initial catalog": "database",
+}
+
+// connStringIgnored are the keys of connection strings that select the
+// ODBC driver and have no meaning for Info.
+var connStringIgnored = map[string]bool{
+	"driver": true,
+	"dsn":    true,
+}
+
+// NewInfoFromConnString parses a connection string of ODBC or jConnect
+// in the form `key=value;key=value`, e.g.:
+//
+//	Driver=Adaptive Server Enterprise;Server=host;Port=4901;UID=user;PWD={pass;word}
+//
+// Keys are case-insensitive. The common aliases Server, UID, PWD and
+// Database are mapped to the fields of Info, other keys are set with
+// SetField and stored as property if they are not a field. Driver and
+// DSN are ignored.
+//
+// Values containing semicolons must be enclosed in braces, closing
+// braces in such values are escaped by doubling them. The server may
+// contain the port, e.g. "host,4901" or "host:4901". References to
+// environment variables in values are expanded, see ExpandEnv.
+func NewInfoFromConnString(s string, opts ...ParseOption) (*Info, error) {
+	pairs, err := splitConnString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	info := NewInfo()
+	for _, pair := range pairs {
+		key := strings.ToLower(pair[0])
+		if connStringIgnored[key] {
+			continue
+		}
+
+		if alias, ok := connStringAliases[key]; ok {
+			key = alias
+		}
+
+		value, err := ExpandEnv(pair[1])
+		if err != nil {
+			return nil, fmt.Errorf("error expanding value for %s: %w", pair[0], err)
+		}
+
+		if name, _ := CanonicalKey(key); name == "host" {
+			host, port := splitConnStringServer(value)
+			if port != "" {
+				if err := info.SetField("port", port, opts...); err != nil {
+					return nil, err
+				}
+			}
+			value = host
+		}
+
+		if err := info.SetField(key, value, opts...); err != nil {
+			return nil, fmt.Errorf("error setting value '%s' for field %s: %w", value, pair[0], err)
+		}
+	}
+
+	return info, nil
+}
+
+// splitConnString splits a connection string into its key/value
+// pairs.
+func splitConnString(s string) ([][2]string, error) {
+	pairs := [][2]string{}
+
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		i := strings.Index(s, "=")
+		if i < 0 {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "connection string part '%s' does not contain key/value parts", s)
+		}
+
+		key := strings.TrimSpace(s[:i])
+		s = strings.TrimLeft(s[i+1:], " \t")
+
+		var value string
+		if strings.HasPrefix(s, "{") {
+			var err error
+			value, s, err = consumeBraced(s)
+			if err != nil {
+				return nil, err
+			}
+
+			s = strings.TrimLeft(s, " \t")
+			if s != "" && s[0] != ';' {
+				return nil, dberrors.Errorf(dberrors.CategoryConfig, "unexpected '%s' after value of %s", s, key)
+			}
+		} else {
+			end := strings.Index(s, ";")
+			if end < 0 {
+				end = len(s)
+			}
+			value, s = strings.TrimSpace(s[:end]), s[end:]
+		}
+
+		s = strings.TrimPrefix(s, ";")
+
+		if key == "" {
+			return nil, dberrors.New(dberrors.CategoryConfig, "connection string contains empty key")
+		}
+
+		pairs = append(pairs, [2]string{key, value})
+	}
+
+	return pairs, nil
+}
+
+// consumeBraced returns the value enclosed in braces at the start of
+// s and the remainder of s after the closing brace.
+func consumeBraced(s string) (string, string, error) {
+	value := strings.Builder{}
+
+	for i := 1; i < len(s); i++ {
+		if s[i] != '}' {
+			value.WriteByte(s[i])
+			continue
+		}
+
+		// Escaped closing brace
+		if i+1 < len(s) && s[i+1] == '}' {
+			value.WriteByte('}')
+			i++
+			continue
+		}
+
+		return value.String(), s[i+1:], nil
+	}
+
+	return "", "", dberrors.Errorf(dberrors.CategoryConfig, "unterminated brace in connection string value '%s'", s)
+}
+
+// splitConnStringServer splits the port from a server, e.g.
+// "host,4901" or "host:4901". IPv6 literals must be enclosed in
+// brackets to contain a port.
+func splitConnStringServer(server string) (string, string) {
+	if i := strings.LastIndex(server, ","); i >= 0 {
+		return strings.TrimSpace(server[:i]), strings.TrimSpace(server[i+1:])
+	}
+
+	if host, port, err := net.SplitHostPort(server); err == nil {
+		return host, port
+	}
+
+	return server, ""
+}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestNewInfoFromConnString(t *testing.T) {
+	cases := map[string]struct {
+		connString string
+		expected   Info
+	}{
+		"odbc": {
+			connString: "Driver=Adaptive Server Enterprise;Server=hostname;Port=4901;UID=user;PWD=pass;Database=db",
+			expected: Info{
+				Host:              "hostname",
+				Port:              "4901",
+				Username:          "user",
+				Password:          "pass",
+				Database:          "db",
+				PacketReadTimeout: 50,
+				ConnectProps:      url.Values{},
+			},
+		},
+		"server with port": {
+			connString: "server=hostname,4901; uid=user; pwd=pass",
+			expected: Info{
+				Host:              "hostname",
+				Port:              "4901",
+				Username:          "user",
+				Password:          "pass",
+				PacketReadTimeout: 50,
+				ConnectProps:      url.Values{},
+			},
+		},
+		"ipv6": {
+			connString: "NetworkAddress=[::1]:4901;User ID=user",
+			expected: Info{
+				Host:              "::1",
+				Port:              "4901",
+				Username:          "user",
+				PacketReadTimeout: 50,
+				ConnectProps:      url.Values{},
+			},
+		},
+		"braced values and properties": {
+			connString: "Server=hostname;Port=4901;PWD={pa;ss}}word};Initial Catalog=db;charset=utf8;",
+			expected: Info{
+				Host:              "hostname",
+				Port:              "4901",
+				Password:          "pa;ss}word",
+				Database:          "db",
+				PacketReadTimeout: 50,
+				ConnectProps:      url.Values{"charset": []string{"utf8"}},
+			},
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				info, err := NewInfoFromConnString(cas.connString)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				if !reflect.DeepEqual(cas.expected, *info) {
+					t.Errorf("Expected: %#v", cas.expected)
+					t.Errorf("Received: %#v", *info)
+				}
+			},
+		)
+	}
+}
+
+func TestNewInfoFromConnStringFail(t *testing.T) {
+	cases := map[string]string{
+		"no value":         "Server",
+		"empty key":        "=hostname",
+		"unterminated":     "PWD={pass",
+		"after brace":      "PWD={pass}word;Server=hostname",
+		"invalid bool":     "Server=hostname;tls=maybe",
+		"invalid duration": "dial-timeout=soon",
+	}
+
+	for name, connString := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				if _, err := NewInfoFromConnString(connString); err == nil {
+					t.Errorf("Expected error parsing '%s'", connString)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"net"
+	"strings"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// Endpoint is the host and port of a server.
+type Endpoint struct {
+	Host string
+	Port string
+}
+
+// String returns the address of endpoint, see JoinHostPort.
+func (endpoint Endpoint) String() string {
+	return JoinHostPort(endpoint.Host, endpoint.Port)
+}
+
+// ParseEndpoints parses comma separated host:port pairs, e.g.
+// "host1:4901,[::1]:4901".
+func ParseEndpoints(s string) ([]Endpoint, error) {
+	endpoints := []Endpoint{}
+	for _, hostport := range strings.Split(s, ",") {
+		hostport = strings.TrimSpace(hostport)
+		if hostport == "" {
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(hostport)
+		if err != nil {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "error parsing endpoint '%s': %w", hostport, err)
+		}
+
+		endpoints = append(endpoints, Endpoint{Host: NormalizeHost(host), Port: port})
+	}
+
+	if len(endpoints) == 0 {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "no endpoints in '%s'", s)
+	}
+
+	return endpoints, nil
+}
+
+// joinEndpoints returns endpoints as comma separated host:port pairs.
+func joinEndpoints(endpoints []Endpoint) string {
+	addrs := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		addrs[i] = endpoint.String()
+	}
+
+	return strings.Join(addrs, ",")
+}
+
+// Endpoints returns the servers of info in order of their priority.
+//
+// If .Hosts is empty .Host and .Port are returned.
+func (info Info) Endpoints() ([]Endpoint, error) {
+	if len(info.Hosts) > 0 {
+		return append([]Endpoint{}, info.Hosts...), nil
+	}
+
+	return []Endpoint{{Host: info.Host, Port: info.Port}}, nil
+}
+
+// EndpointIterator walks the endpoints of an Info, e.g. to connect to
+// the next server after a connection failed.
+type EndpointIterator struct {
+	endpoints []Endpoint
+	next      int
+}
+
+// NewEndpointIterator returns an EndpointIterator over the endpoints
+// of info, see Info.Endpoints.
+func NewEndpointIterator(info *Info) (*EndpointIterator, error) {
+	endpoints, err := info.Endpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	return &EndpointIterator{endpoints: endpoints}, nil
+}
+
+// Next returns the next endpoint. The returned boolean is false if all
+// endpoints were returned.
+func (iter *EndpointIterator) Next() (Endpoint, bool) {
+	if iter.next >= len(iter.endpoints) {
+		return Endpoint{}, false
+	}
+
+	endpoint := iter.endpoints[iter.next]
+	iter.next++
+	return endpoint, true
+}
+
+// Len returns the number of endpoints.
+func (iter *EndpointIterator) Len() int {
+	return len(iter.endpoints)
+}
+
+// Reset restarts the iteration with the first endpoint.
+func (iter *EndpointIterator) Reset() {
+	iter.next = 0
+}
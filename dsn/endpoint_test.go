@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInfo_Endpoints(t *testing.T) {
+	info := NewInfo()
+	info.Host = "localhost"
+	info.Port = "4901"
+
+	endpoints, err := info.Endpoints()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if expected := []Endpoint{{Host: "localhost", Port: "4901"}}; !reflect.DeepEqual(endpoints, expected) {
+		t.Errorf("Expected %v, received %v", expected, endpoints)
+	}
+
+	if err := info.SetField("hosts", "host1:4901, [::1]:4902"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Endpoint{{Host: "host1", Port: "4901"}, {Host: "::1", Port: "4902"}}
+	if !reflect.DeepEqual(info.Hosts, expected) {
+		t.Errorf("Expected %v, received %v", expected, info.Hosts)
+	}
+
+	iter, err := NewEndpointIterator(info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		walked := []Endpoint{}
+		for endpoint, ok := iter.Next(); ok; endpoint, ok = iter.Next() {
+			walked = append(walked, endpoint)
+		}
+
+		if !reflect.DeepEqual(walked, expected) {
+			t.Errorf("Expected to walk %v, walked %v", expected, walked)
+		}
+		iter.Reset()
+	}
+
+	if simple := info.AsSimple(); simple != "host='localhost' hosts='host1:4901,[::1]:4902' port='4901'" {
+		t.Errorf("Unexpected simple DSN: %s", simple)
+	}
+
+	if err := info.SetField("hosts", "host1"); err == nil {
+		t.Errorf("Expected error for endpoint without port")
+	}
+}
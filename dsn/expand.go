@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"os"
+	"strings"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// ExpandEnv replaces references to environment variables in the form
+// of ${VAR} in s with their value, e.g. "${HOME}/certs/ase-ca.pem".
+//
+// ${VAR:-default} is replaced with default if VAR is unset or empty.
+// References to unset variables without default are an error. $${ is
+// replaced with a literal ${. Dollar signs not followed by a brace are
+// kept, so values such as passwords may contain them.
+//
+// Values loaded by NewInfoFromFile and NewInfoFromConnString are
+// expanded.
+func ExpandEnv(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	b := strings.Builder{}
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+
+		// Escaped reference
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1])
+			b.WriteString("${")
+			s = s[i+2:]
+			continue
+		}
+
+		end := strings.Index(s[i:], "}")
+		if end < 0 {
+			return "", dberrors.Errorf(dberrors.CategoryConfig, "unterminated variable reference in '%s'", s)
+		}
+
+		b.WriteString(s[:i])
+		value, err := expandRef(s[i+2 : i+end])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+
+		s = s[i+end+1:]
+	}
+}
+
+// expandRef returns the value of the reference ref in the form of
+// VAR or VAR:-default.
+func expandRef(ref string) (string, error) {
+	name, def, hasDefault := ref, "", false
+	if i := strings.Index(ref, ":-"); i >= 0 {
+		name, def, hasDefault = ref[:i], ref[i+2:], true
+	}
+
+	if name == "" {
+		return "", dberrors.New(dberrors.CategoryConfig, "empty variable reference")
+	}
+
+	value, ok := os.LookupEnv(name)
+	if hasDefault && value == "" {
+		return def, nil
+	}
+
+	if !ok {
+		return "", dberrors.Errorf(dberrors.CategoryConfig, "environment variable %s referenced in value is not set", name)
+	}
+
+	return value, nil
+}
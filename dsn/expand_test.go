@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	fn, err := setEnv(map[string]string{"EXPAND_HOME": "/home/user", "EXPAND_EMPTY": ""})
+	if err != nil {
+		t.Fatalf("Error preparing environment: %v", err)
+	}
+	defer fn()
+
+	cases := map[string]string{
+		"pa$$word":                       "pa$$word",
+		"${EXPAND_HOME}/a":               "/home/user/a",
+		"a${EXPAND_HOME}b${EXPAND_HOME}": "a/home/userb/home/user",
+		"${EXPAND_UNSET:-default}":       "default",
+		"${EXPAND_EMPTY:-default}":       "default",
+		"${EXPAND_EMPTY}":                "",
+		"$${EXPAND_HOME}":                "${EXPAND_HOME}",
+		"pa$s${EXPAND_HOME}":             "pa$s/home/user",
+	}
+
+	for value, expected := range cases {
+		t.Run(value,
+			func(t *testing.T) {
+				expanded, err := ExpandEnv(value)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				if expanded != expected {
+					t.Errorf("Expected: %s", expected)
+					t.Errorf("Received: %s", expanded)
+				}
+			},
+		)
+	}
+
+	for _, value := range []string{"${EXPAND_UNSET}", "${EXPAND_HOME", "${}"} {
+		if _, err := ExpandEnv(value); err == nil {
+			t.Errorf("Expected error expanding %s", value)
+		}
+	}
+}
+
+func TestExpandEnv_Loaders(t *testing.T) {
+	fn, err := setEnv(map[string]string{"EXPAND_HOME": "/home/user"})
+	if err != nil {
+		t.Fatalf("Error preparing environment: %v", err)
+	}
+	defer fn()
+
+	path := filepath.Join(t.TempDir(), "dsn.json")
+	content := `{"host": "hostname", "tls-ca": "${EXPAND_HOME}/ca.pem", "connectprops": {"record-dir": "${EXPAND_HOME}/records"}}`
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Error writing file: %v", err)
+	}
+
+	info, err := NewInfoFromFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if info.TLSCAFile != "/home/user/ca.pem" || info.Prop("record-dir") != "/home/user/records" {
+		t.Errorf("Expected expanded values, got %s", info.AsSimple())
+	}
+
+	info, err = NewInfoFromConnString("Server=hostname;tls-ca=${EXPAND_HOME}/ca.pem")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if info.TLSCAFile != "/home/user/ca.pem" {
+		t.Errorf("Expected expanded value, got %s", info.TLSCAFile)
+	}
+}
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// NewInfoFromFile returns a new Info and fills it with data from the
+// JSON file at path. YAML files, with the extension .yaml or .yml, are
+// not supported and rejected.
+//
+// The keys are the json tags and their multiref aliases, unknown keys
+// are stored as properties. Additional properties can be set in the
+// object "connectprops" with a single value or a list of values per
+// property:
+//
+//	{
+//		"host": "hostname",
+//		"port": "4901",
+//		"user": "username",
+//		"connectprops": {"statement-timeout": "30s"}
+//	}
+//
+// References to environment variables in values are expanded, e.g.
+// `"tls-ca": "${HOME}/certs/ase-ca.pem"`, see ExpandEnv.
+func NewInfoFromFile(path string, opts ...ParseOption) (*Info, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error reading DSN file %s: YAML is not supported", path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error reading DSN file: %w", err)
+	}
+
+	values, err := parseFileJSON(data)
+	if err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error parsing DSN file %s: %w", path, err)
+	}
+
+	info := NewInfo()
+	if err := info.setValues(values, opts...); err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error in DSN file %s: %w", path, err)
+	}
+
+	return info, nil
+}
+
+// setValues sets the fields of info from values as parsed from a DSN
+// file.
+func (info *Info) setValues(values map[string]interface{}, opts ...ParseOption) error {
+	for key, value := range values {
+		if key == "connectprops" {
+			props, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("connectprops must be an object, got %T", value)
+			}
+
+			for prop, propValue := range props {
+				if err := info.addProp(prop, propValue, opts...); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		s, err := scalarString(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for key %s: %w", key, err)
+		}
+
+		if s, err = ExpandEnv(s); err != nil {
+			return fmt.Errorf("error expanding value for key %s: %w", key, err)
+		}
+
+		if err := info.SetField(key, s, opts...); err != nil {
+			return fmt.Errorf("error setting value '%s' for field %s: %w", s, key, err)
+		}
+	}
+
+	return nil
+}
+
+// addProp adds the single value or list of values to the property
+// prop.
+func (info *Info) addProp(prop string, value interface{}, opts ...ParseOption) error {
+	if err := checkUnknownKey(prop, newParseOptions(opts...).strict); err != nil {
+		return err
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		list = []interface{}{value}
+	}
+
+	for _, elem := range list {
+		s, err := scalarString(elem)
+		if err != nil {
+			return fmt.Errorf("invalid value for property %s: %w", prop, err)
+		}
+
+		if s, err = ExpandEnv(s); err != nil {
+			return fmt.Errorf("error expanding value for property %s: %w", prop, err)
+		}
+
+		info.ConnectProps.Add(prop, s)
+	}
+
+	return nil
+}
+
+// scalarString returns the string representation of a scalar value.
+func scalarString(value interface{}) (string, error) {
+	switch typed := value.(type) {
+	case string:
+		return typed, nil
+	case bool, json.Number:
+		return fmt.Sprint(typed), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("expected scalar value, got %T", value)
+	}
+}
+
+// parseFileJSON parses the JSON object in data.
+func parseFileJSON(data []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Numbers are kept in their textual representation, e.g. to
+	// retain leading zeros of ports.
+	dec.UseNumber()
+
+	values := map[string]interface{}{}
+	if err := dec.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
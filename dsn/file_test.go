@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewInfoFromFile(t *testing.T) {
+	expected := &Info{
+		Host:              "hostname",
+		Port:              "4901",
+		Username:          "user",
+		Password:          "pass # word",
+		Database:          "mydb",
+		PacketReadTimeout: 50,
+		TLSEnable:         true,
+		ConnectProps: url.Values{
+			"statement-timeout": []string{"30s"},
+			"foo":               []string{"bar", "baz"},
+		},
+	}
+
+	cases := map[string]string{
+		"info.json": `{
+	"hostname": "hostname",
+	"port": 4901,
+	"user": "user",
+	"password": "pass # word",
+	"db": "mydb",
+	"tls": true,
+	"statement-timeout": "30s",
+	"connectprops": {"foo": ["bar", "baz"]}
+}`,
+		"info": `{
+	"host": "hostname",
+	"port": "4901",
+	"username": "user",
+	"password": "pass # word",
+	"database": "mydb",
+	"tls": true,
+	"connectprops": {"statement-timeout": "30s", "foo": ["bar", "baz"]}
+}`,
+	}
+
+	for name, content := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), name)
+				if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+					t.Fatalf("Error writing file: %v", err)
+				}
+
+				info, err := NewInfoFromFile(path)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				if !reflect.DeepEqual(info, expected) {
+					t.Errorf("Received invalid Info")
+					t.Errorf("Expected: %+v", expected)
+					t.Errorf("Received: %+v", info)
+				}
+			},
+		)
+	}
+}
+
+func TestNewInfoFromFile_QuotedComma(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "info.json")
+	content := `{"host": "hostname", "connectprops": {"foo": ["a,b", "c"], "bar": "d, e"}}`
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Error writing file: %v", err)
+	}
+
+	info, err := NewInfoFromFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := url.Values{
+		"foo": []string{"a,b", "c"},
+		"bar": []string{"d, e"},
+	}
+
+	if !reflect.DeepEqual(info.ConnectProps, expected) {
+		t.Errorf("Expected: %v", expected)
+		t.Errorf("Received: %v", info.ConnectProps)
+	}
+}
+
+func TestNewInfoFromFileFail(t *testing.T) {
+	cases := map[string]string{
+		"invalid.json": `{"host": "hostname"`,
+		"props.json":   `{"connectprops": "foo"}`,
+		"bool.json":    `{"tls": "maybe"}`,
+		"nested.json":  `{"host": {"name": "hostname"}}`,
+		"yaml":         "host: hostname\n",
+		"info.yaml":    "host: hostname\n",
+		"info.yml":     "host: hostname\n",
+		"block.yaml":   "connectprops:\n  foo:\n    - bar\n    - baz\n",
+		"indent.yaml":  "host: hostname\n  port: 4901\n",
+	}
+
+	for name, content := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), name)
+				if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+					t.Fatalf("Error writing file: %v", err)
+				}
+
+				if _, err := NewInfoFromFile(path); err == nil {
+					t.Errorf("Expected error parsing %q", content)
+				}
+			},
+		)
+	}
+
+	if _, err := NewInfoFromFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("Expected error reading missing file")
+	}
+}
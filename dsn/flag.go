@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// BindFlags registers a flag for each field of info on fs, which is
+// named by the json tag of the field with prefix prepended, e.g.
+// "host" or with the prefix "ase-" "ase-host".
+//
+// Flags passed on the command line are written to info with SetField
+// when fs is parsed. Fields whose flag is not passed keep their value,
+// so info can be prepared from a file or the environment beforehand.
+// The current values of info are shown as default values in the usage
+// message, with the values of sensitive fields redacted.
+func (info *Info) BindFlags(fs *flag.FlagSet, prefix string) {
+	ttf := info.tagToField(false)
+
+	keys := make([]string, 0, len(ttf))
+	for key := range ttf {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fs.Var(&fieldFlag{info: info, key: key}, prefix+key, fmt.Sprintf("Sets the DSN field %s", key))
+	}
+}
+
+// fieldFlag implements the flag.Value interface for a field of an
+// Info.
+type fieldFlag struct {
+	info *Info
+	key  string
+}
+
+// String implements the flag.Value interface.
+func (f *fieldFlag) String() string {
+	// flag.isZeroValue calls String on a zero value.
+	if f.info == nil {
+		return ""
+	}
+
+	field := f.info.tagToField(false)[f.key]
+	if field.IsZero() {
+		return ""
+	}
+
+	if redactedFields[f.key] {
+		return Redacted
+	}
+
+	if redactedURLFields[f.key] {
+		return redactURL(field.String())
+	}
+
+	if m, ok := textMarshaler(field); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	}
+
+	switch value := field.Interface().(type) {
+	case []Endpoint:
+		return joinEndpoints(value)
+	case time.Duration:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Set implements the flag.Value interface.
+func (f *fieldFlag) Set(value string) error {
+	return f.info.SetField(f.key, value)
+}
+
+// IsBoolFlag allows passing flags of bool fields without a value.
+func (f *fieldFlag) IsBoolFlag() bool {
+	if f.info == nil {
+		return false
+	}
+
+	return f.info.tagToField(false)[f.key].Kind() == reflect.Bool
+}
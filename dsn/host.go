@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"net"
+	"strings"
+)
+
+// NormalizeHost returns host without the brackets around IPv6 literals,
+// e.g. "[::1]" is returned as "::1". Percent-encoded zone identifiers
+// as used in URIs are decoded, e.g. "[fe80::1%25eth0]" is returned as
+// "fe80::1%eth0".
+func NormalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+
+	if strings.Contains(host, ":") {
+		host = strings.Replace(host, "%25", "%", 1)
+	}
+
+	return host
+}
+
+// splitBracketedPort splits the port from an IPv6 literal in brackets,
+// e.g. "[::1]:4901" is split into "::1" and "4901". Other hosts are
+// returned normalized with an empty port, see NormalizeHost.
+func splitBracketedPort(host string) (string, string) {
+	host = strings.TrimSpace(host)
+
+	if strings.HasPrefix(host, "[") && !strings.HasSuffix(host, "]") {
+		if h, port, err := net.SplitHostPort(host); err == nil {
+			return NormalizeHost(h), port
+		}
+	}
+
+	return NormalizeHost(host), ""
+}
+
+// SplitZone splits the zone identifier from an IPv6 literal, e.g.
+// "fe80::1%eth0" is split into "fe80::1" and "eth0". If host has no
+// zone identifier the zone is empty.
+func SplitZone(host string) (string, string) {
+	host = NormalizeHost(host)
+
+	if i := strings.LastIndex(host, "%"); i >= 0 && strings.Contains(host, ":") {
+		return host[:i], host[i+1:]
+	}
+
+	return host, ""
+}
+
+// IsIPLiteral reports whether host is an IPv4 or IPv6 literal,
+// including IPv6 literals in brackets or with zone identifiers.
+func IsIPLiteral(host string) bool {
+	ip, _ := SplitZone(host)
+	return net.ParseIP(ip) != nil
+}
+
+// JoinHostPort combines host and port to an address, enclosing IPv6
+// literals in brackets, e.g. "::1" and "4901" are joined to
+// "[::1]:4901". If port is empty host is returned, with brackets if it
+// is an IPv6 literal.
+func JoinHostPort(host, port string) string {
+	host = NormalizeHost(host)
+
+	if port == "" {
+		if strings.Contains(host, ":") {
+			return "[" + host + "]"
+		}
+		return host
+	}
+
+	return net.JoinHostPort(host, port)
+}
+
+// networks are the values accepted for .Network of Info.
+var networks = map[string]bool{
+	"tcp":        true,
+	"tcp4":       true,
+	"tcp6":       true,
+	"unix":       true,
+	"unixpacket": true,
+}
+
+// IsUnixSocket reports whether the server of info is a unix domain
+// socket with the path .Host. That is the case if .Network is "unix"
+// or "unixpacket" or if .Network is empty and .Host is an absolute
+// path.
+//
+// The property "network" is used if .Network is empty.
+func (info Info) IsUnixSocket() bool {
+	network := info.Network
+	if network == "" {
+		network = info.Prop("network")
+	}
+
+	if network == "" {
+		return strings.HasPrefix(info.Host, "/")
+	}
+
+	return strings.HasPrefix(network, "unix")
+}
+
+// Address returns the address of the server of info, see
+// JoinHostPort.
+func (info Info) Address() string {
+	return JoinHostPort(info.Host, info.Port)
+}
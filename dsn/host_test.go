@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import "testing"
+
+func TestJoinHostPort(t *testing.T) {
+	cases := map[string]struct {
+		host, port, address string
+	}{
+		"hostname":        {"hostname", "4901", "hostname:4901"},
+		"IPv4":            {"127.0.0.1", "4901", "127.0.0.1:4901"},
+		"IPv6":            {"::1", "4901", "[::1]:4901"},
+		"IPv6 bracketed":  {"[::1]", "4901", "[::1]:4901"},
+		"IPv6 zone":       {"fe80::1%eth0", "4901", "[fe80::1%eth0]:4901"},
+		"IPv6 URI zone":   {"[fe80::1%25eth0]", "4901", "[fe80::1%eth0]:4901"},
+		"IPv6 empty port": {"::1", "", "[::1]"},
+		"empty port":      {"hostname", "", "hostname"},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			if address := JoinHostPort(cas.host, cas.port); address != cas.address {
+				t.Errorf("Expected address '%s', got '%s'", cas.address, address)
+			}
+		})
+	}
+}
+
+func TestSplitZone(t *testing.T) {
+	cases := map[string]struct {
+		host, ip, zone string
+	}{
+		"hostname":       {"hostname", "hostname", ""},
+		"IPv6":           {"::1", "::1", ""},
+		"IPv6 zone":      {"fe80::1%eth0", "fe80::1", "eth0"},
+		"IPv6 bracketed": {"[fe80::1%25eth0]", "fe80::1", "eth0"},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			ip, zone := SplitZone(cas.host)
+			if ip != cas.ip || zone != cas.zone {
+				t.Errorf("Expected '%s' and '%s', got '%s' and '%s'", cas.ip, cas.zone, ip, zone)
+			}
+		})
+	}
+}
+
+func TestIsIPLiteral(t *testing.T) {
+	cases := map[string]bool{
+		"hostname":         false,
+		"127.0.0.1":        true,
+		"::1":              true,
+		"[::1]":            true,
+		"fe80::1%eth0":     true,
+		"[fe80::1%25eth0]": true,
+	}
+
+	for host, expected := range cases {
+		if IsIPLiteral(host) != expected {
+			t.Errorf("Expected IsIPLiteral(%s) to be %t", host, expected)
+		}
+	}
+}
+
+func TestInfo_IsUnixSocket(t *testing.T) {
+	cases := map[string]struct {
+		host, network string
+		unix          bool
+	}{
+		"hostname":          {"hostname", "", false},
+		"path":              {"/var/run/ase.sock", "", true},
+		"relative path":     {"ase.sock", "unix", true},
+		"unixpacket":        {"ase.sock", "unixpacket", true},
+		"path with tcp":     {"/var/run/ase.sock", "tcp", false},
+		"IPv6 with network": {"::1", "tcp6", false},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			info := Info{Host: cas.host, Network: cas.network}
+			if unix := info.IsUnixSocket(); unix != cas.unix {
+				t.Errorf("Expected %t, got %t", cas.unix, unix)
+			}
+		})
+	}
+}
+
+func TestInfo_AddressIPv6(t *testing.T) {
+	fn, err := setEnv(map[string]string{"TESTIPV6_HOST": "::1", "TESTIPV6_PORT": "4901"})
+	if err != nil {
+		t.Fatalf("Error preparing environment: %v", err)
+	}
+	defer fn()
+
+	envInfo, err := NewInfoFromEnv("TESTIPV6")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cases := map[string]struct {
+		info    func() (*Info, error)
+		address string
+	}{
+		"environment": {
+			info:    func() (*Info, error) { return envInfo, nil },
+			address: "[::1]:4901",
+		},
+		"simple": {
+			info:    func() (*Info, error) { return ParseSimple("host=::1 port=4901") },
+			address: "[::1]:4901",
+		},
+		"simple with bracketed port": {
+			info:    func() (*Info, error) { return ParseSimple("host=[::1]:4901") },
+			address: "[::1]:4901",
+		},
+		"port field takes precedence": {
+			info:    func() (*Info, error) { return ParseSimple("port=4902 host=[fe80::1%25eth0]:4901") },
+			address: "[fe80::1%eth0]:4902",
+		},
+		"uri": {
+			info:    func() (*Info, error) { return parseDsnUri("ase://user:pass@[::1]:4901/db") },
+			address: "[::1]:4901",
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			info, err := cas.info()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if address := info.Address(); address != cas.address {
+				t.Errorf("Expected address '%s', got '%s'", cas.address, address)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@
 package dsn
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -13,6 +14,10 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/logging"
 )
 
 // Info represents all required information to open a connection to
@@ -24,17 +29,82 @@ type Info struct {
 	Port         string `json:"port" validate:"required"`
 	Username     string `json:"username" multiref:"user" validate:"required"`
 	Password     string `json:"password" multiref:"passwd,pass"`
+	PasswordFile string `json:"password-file"`
 	Userstorekey string `json:"userstorekey" multiref:"key" validate:"required"`
 	Database     string `json:"database" multiref:"db"`
 
+	// Server is the logical server name resolved into Host and Port,
+	// see ResolveServer.
+	Server string `json:"server" multiref:"dsquery"`
+
+	// Hosts lists the servers in order of their priority. Host and
+	// Port are not required if Hosts is set, see Endpoints.
+	Hosts []Endpoint `json:"hosts"`
+
+	// Network is the network of the server, "tcp" or "unix". If it is
+	// empty the network is "unix" if Host is an absolute path, e.g.
+	// "/var/run/ase.sock", and "tcp" otherwise, see IsUnixSocket.
+	Network string `json:"network"`
+
 	ClientHostname string `json:"client-hostname"`
 
+	// Proxy is the URL of the proxy the server is connected through,
+	// e.g. "socks5://host:1080" or "http://host:3128". Addresses
+	// without scheme are HTTP proxies. The credentials are taken from
+	// the URL or ProxyUsername and ProxyPassword.
+	Proxy         string `json:"proxy"`
+	ProxyUsername string `json:"proxy-username"`
+	ProxyPassword string `json:"proxy-password"`
+
 	PacketReadTimeout int `json:"packet-read-timeout"`
 
+	// The timeouts of the connection phases are parsed with
+	// time.ParseDuration, e.g. "30s" or "2m". Zero disables the
+	// timeout.
+	DialTimeout time.Duration `json:"dial-timeout"`
+	ReadTimeout time.Duration `json:"read-timeout"`
+	IdleTimeout time.Duration `json:"idle-timeout"`
+
+	// The TCP options configure the connections to the server.
+	// TCPKeepAlive is the period of keepalive probes, zero uses the
+	// default of package net and negative values disable keepalives.
+	// TCPDelay disables TCP_NODELAY. TCPReadBuffer and TCPWriteBuffer
+	// set the socket buffer sizes in bytes, zero keeps the system
+	// default.
+	TCPKeepAlive   time.Duration `json:"tcp-keepalive"`
+	TCPDelay       bool          `json:"tcp-delay"`
+	TCPReadBuffer  int           `json:"tcp-read-buffer"`
+	TCPWriteBuffer int           `json:"tcp-write-buffer"`
+
+	// ConnectRetries is the number of retries of failed connection
+	// attempts, e.g. while the server is still starting. The delay
+	// before the first retry is ConnectRetryDelay, which grows by the
+	// factor ConnectRetryBackoff for each further retry.
+	ConnectRetries      int           `json:"connect-retries"`
+	ConnectRetryDelay   time.Duration `json:"connect-retry-delay"`
+	ConnectRetryBackoff float64       `json:"connect-retry-backoff"`
+
 	TLSEnable         bool   `json:"tls"`
 	TLSHostname       string `json:"tls-hostname" multiref:"ssl"`
 	TLSSkipValidation bool   `json:"tls-skip-validation"`
 	TLSCAFile         string `json:"tls-ca"`
+	TLSCertFile       string `json:"tls-cert"`
+	TLSKeyFile        string `json:"tls-key"`
+	TLSKeyPassword    string `json:"tls-key-password"`
+
+	// The TLS versions and cipher suites are set by their symbolic
+	// names, see ParseTLSVersion and ParseCipherSuites.
+	TLSMinVersion   string `json:"tls-min-version"`
+	TLSMaxVersion   string `json:"tls-max-version"`
+	TLSCipherSuites string `json:"tls-cipher-suites"`
+
+	// The Kerberos fields configure the authentication mechanism
+	// "kerberos" provided by drivers implementing it, see package auth.
+	// Password is not required if KerberosSPN is set.
+	KerberosSPN        string `json:"kerberos-spn" multiref:"krb-spn"`
+	KerberosKeytab     string `json:"kerberos-keytab" multiref:"krb-keytab"`
+	KerberosCCache     string `json:"kerberos-ccache" multiref:"krb-ccache"`
+	KerberosMutualAuth bool   `json:"kerberos-mutual-auth" multiref:"krb-mutual-auth"`
 
 	ConnectProps url.Values `json:"connectprops"`
 }
@@ -47,6 +117,25 @@ func NewInfo() *Info {
 	return dsn
 }
 
+// Clone returns a deep copy of info. Modifying the copy, including
+// .Hosts and .ConnectProps, does not affect info.
+//
+// .ConnectProps of the copy is initialized even if it is nil in info.
+func (info *Info) Clone() *Info {
+	copied := *info
+
+	if info.Hosts != nil {
+		copied.Hosts = append([]Endpoint{}, info.Hosts...)
+	}
+
+	copied.ConnectProps = make(url.Values, len(info.ConnectProps))
+	for key, values := range info.ConnectProps {
+		copied.ConnectProps[key] = append([]string{}, values...)
+	}
+
+	return &copied
+}
+
 // NewInfoFromEnv returns a new Info and fills it with data from
 // the environment.
 //
@@ -59,7 +148,7 @@ func NewInfo() *Info {
 // Properties with dashes are recognized with undescores instead of
 // dashes. E.g. the property `cgo-callback-client` can be passed as
 // `CGO_CALLBACK_CLIENT`.
-func NewInfoFromEnv(prefix string) (*Info, error) {
+func NewInfoFromEnv(prefix string, opts ...ParseOption) (*Info, error) {
 	dsn := NewInfo()
 
 	if prefix == "" {
@@ -78,8 +167,8 @@ func NewInfoFromEnv(prefix string) (*Info, error) {
 		key = strings.ToLower(strings.TrimPrefix(key, prefix))
 		key = strings.ReplaceAll(key, "_", "-")
 
-		if err := dsn.SetField(key, value); err != nil {
-			return nil, fmt.Errorf("error setting value '%s' for field %s: %w", value, key, err)
+		if err := dsn.SetField(key, value, opts...); err != nil {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "error setting value '%s' for field %s: %w", value, key, err)
 		}
 	}
 
@@ -130,21 +219,146 @@ func (info *Info) tagToField(multiref bool) map[string]reflect.Value {
 	return tTF
 }
 
+// CanonicalKey returns the json tag of the field key refers to,
+// resolving multiref aliases such as "hostname" to "host".
+//
+// If key does not refer to a field it is returned unchanged and false
+// is returned, as the key is stored as property by SetField.
+func CanonicalKey(key string) (string, bool) {
+	t := reflect.TypeOf(Info{})
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Name == "ConnectProps" {
+			continue
+		}
+
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		names := append([]string{name}, strings.Split(t.Field(i).Tag.Get("multiref"), ",")...)
+		for _, alias := range names {
+			if alias != "" && alias == key {
+				return name, true
+			}
+		}
+	}
+
+	return key, false
+}
+
+// Redacted is the value AsSimpleRedacted prints instead of the
+// credentials.
+const Redacted = "********"
+
+// redactedFields are the json tags of the fields masked by
+// AsSimpleRedacted.
+var redactedFields = map[string]bool{
+	"password":         true,
+	"userstorekey":     true,
+	"tls-key-password": true,
+	"proxy-password":   true,
+}
+
+// redactedURLFields are the json tags of the fields holding URLs whose
+// password is masked by AsSimpleRedacted.
+var redactedURLFields = map[string]bool{
+	"proxy": true,
+}
+
+// redactURL returns rawURL with the password of its userinfo replaced
+// by Redacted. rawURL is returned as is if it has no password and
+// Redacted if it cannot be parsed.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Redacted
+	}
+
+	if _, ok := u.User.Password(); !ok {
+		return rawURL
+	}
+
+	// The userinfo escapes Redacted, which is unescaped to be readable.
+	u.User = url.UserPassword(u.User.Username(), Redacted)
+	escaped := strings.TrimPrefix(url.UserPassword("", Redacted).String(), ":")
+	return strings.Replace(u.String(), ":"+escaped+"@", ":"+Redacted+"@", 1)
+}
+
 // AsSimple returns all information of a Info struct as a simple
-// key/value string.
+// key/value string, which can be parsed with ParseSimple. Only the last
+// value of each property is included.
+//
+// The credentials are printed in cleartext, use AsSimpleRedacted to
+// log an Info.
 func (info Info) AsSimple() string {
+	return info.asSimple(false)
+}
+
+// AsSimpleRedacted returns the same string as AsSimple with the
+// values of .Password, .Userstorekey, .TLSKeyPassword and
+// .ProxyPassword and the password in .Proxy replaced by Redacted.
+func (info Info) AsSimpleRedacted() string {
+	return info.asSimple(true)
+}
+
+// String implements fmt.Stringer, see AsSimpleRedacted.
+func (info Info) String() string {
+	return info.AsSimpleRedacted()
+}
+
+func (info Info) asSimple(redact bool) string {
 	ret := []string{}
 
+	defaults := NewInfo().tagToField(false)
 	for key, field := range info.tagToField(false) {
+		if m, ok := textMarshaler(field); ok {
+			if field.IsZero() {
+				continue
+			}
+
+			text, err := m.MarshalText()
+			if err != nil {
+				logging.Default().Warn("error marshaling field", "key", key, "error", err)
+				continue
+			}
+
+			ret = append(ret, key+"="+quoteSimple(string(text)))
+			continue
+		}
+
 		switch field.Kind() {
 		case reflect.String:
-			if field.String() != "" {
-				ret = append(ret, fmt.Sprintf("%s='%s'", key, field.String()))
+			if field.String() == "" {
+				continue
+			}
+
+			if redact && redactedFields[key] {
+				ret = append(ret, fmt.Sprintf("%s='%s'", key, Redacted))
+			} else if redact && redactedURLFields[key] {
+				ret = append(ret, key+"="+quoteSimple(redactURL(field.String())))
+			} else {
+				ret = append(ret, key+"="+quoteSimple(field.String()))
 			}
 		case reflect.Bool:
 			if field.Bool() {
 				ret = append(ret, fmt.Sprintf("%s=%t", key, field.Bool()))
 			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			// Numbers are omitted if they are unset or keep the
+			// default of NewInfo.
+			if field.IsZero() || field.Interface() == defaults[key].Interface() {
+				continue
+			}
+
+			if d, ok := field.Interface().(time.Duration); ok {
+				ret = append(ret, fmt.Sprintf("%s='%s'", key, d))
+			} else {
+				ret = append(ret, fmt.Sprintf("%s='%v'", key, field.Interface()))
+			}
+		case reflect.Slice:
+			if endpoints, ok := field.Interface().([]Endpoint); ok && len(endpoints) > 0 {
+				ret = append(ret, fmt.Sprintf("%s='%s'", key, joinEndpoints(endpoints)))
+			}
 		}
 	}
 
@@ -158,7 +372,7 @@ func (info Info) AsSimple() string {
 		if len(valueL) == 0 {
 			props = append(props, key+"=''")
 		} else {
-			props = append(props, fmt.Sprintf("%s='%s'", key, valueL[len(valueL)-1]))
+			props = append(props, key+"="+quoteSimple(valueL[len(valueL)-1]))
 		}
 	}
 
@@ -167,31 +381,126 @@ func (info Info) AsSimple() string {
 	return strings.Join(append(ret, props...), " ")
 }
 
-func (info *Info) SetField(key, value string) error {
+// SetField sets the field of info with the json or multiref tag key to
+// value. Keys that do not refer to a field are stored as property, or
+// rejected in strict mode, see SetStrict and WithStrict.
+//
+// Fields implementing encoding.TextUnmarshaler parse value with
+// UnmarshalText. Values of properties registered with RegisterTextProp
+// must be parsable by the property's type.
+func (info *Info) SetField(key, value string, opts ...ParseOption) error {
 	ttf := info.tagToField(true)
 	field, ok := ttf[key]
 	if !ok {
+		if err := checkUnknownKey(key, newParseOptions(opts...).strict); err != nil {
+			return err
+		}
+
+		if err := checkTextProp(key, value); err != nil {
+			return err
+		}
+
+		logging.Default().Debug("storing unknown key as connect property", "key", key)
 		info.ConnectProps.Add(key, value)
 		return nil
 	}
 
+	if u, ok := textUnmarshaler(field); ok {
+		if err := u.UnmarshalText([]byte(value)); err != nil {
+			return dberrors.Errorf(dberrors.CategoryConfig, "error parsing '%s' for field %s: %w", value, key, err)
+		}
+		return nil
+	}
+
+	if field.Type() == durationType {
+		d, err := parseDuration(value)
+		if err != nil {
+			return dberrors.Errorf(dberrors.CategoryConfig, "error parsing '%s' as duration for field %s: %w",
+				value, key, err)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
 	switch field.Kind() {
 	case reflect.String:
+		if name, _ := CanonicalKey(key); name == "host" {
+			// A port passed with an IPv6 literal is used unless
+			// .Port is set.
+			var port string
+			value, port = splitBracketedPort(value)
+			if port != "" && info.Port == "" {
+				info.Port = port
+			}
+		}
 		field.SetString(value)
 	case reflect.Bool:
 		b, err := strconv.ParseBool(value)
 		if err != nil {
-			return fmt.Errorf("error parsing '%s' as bool for field %s: %w",
+			return dberrors.Errorf(dberrors.CategoryConfig, "error parsing '%s' as bool for field %s: %w",
 				value, key, err)
 		}
 		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return intFieldError(value, key, field, err)
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return intFieldError(value, key, field, err)
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return dberrors.Errorf(dberrors.CategoryConfig, "error parsing '%s' as float for field %s: %w",
+				value, key, err)
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if _, ok := field.Interface().([]Endpoint); !ok {
+			return dberrors.Errorf(dberrors.CategoryConfig, "unhandled field type: %s", field.Type())
+		}
+
+		endpoints, err := ParseEndpoints(value)
+		if err != nil {
+			return fmt.Errorf("error parsing '%s' as endpoints for field %s: %w", value, key, err)
+		}
+		field.Set(reflect.ValueOf(endpoints))
 	default:
-		return fmt.Errorf("unhandled field kind: %s", field.Kind())
+		return dberrors.Errorf(dberrors.CategoryConfig, "unhandled field kind: %s", field.Kind())
 	}
 
 	return nil
 }
 
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parseDuration parses value with time.ParseDuration. Integers without
+// unit are parsed as seconds.
+func parseDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+// intFieldError returns the error for a value that failed to parse as
+// integer for the field key.
+func intFieldError(value, key string, field reflect.Value, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return dberrors.Errorf(dberrors.CategoryConfig, "value '%s' for field %s overflows %s",
+			value, key, field.Type())
+	}
+
+	return dberrors.Errorf(dberrors.CategoryConfig, "error parsing '%s' as %s for field %s: %w",
+		value, field.Type(), key, err)
+}
+
 // Prop returns the last value for a property or empty string.
 // To access other values use ConnectProps directly.
 func (info Info) Prop(property string) string {
@@ -220,3 +529,51 @@ func (info Info) PropDefault(property, defaultValue string) string {
 
 	return defaultValue
 }
+
+// PropInt returns the value of property parsed as int or defaultValue
+// if the property is not set.
+func (info Info) PropInt(property string, defaultValue int) (int, error) {
+	val := info.Prop(property)
+	if val == "" {
+		return defaultValue, nil
+	}
+
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return defaultValue, dberrors.Errorf(dberrors.CategoryConfig, "error parsing int from %s '%s': %w", property, val, err)
+	}
+
+	return i, nil
+}
+
+// PropBool returns the value of property parsed with strconv.ParseBool
+// or defaultValue if the property is not set.
+func (info Info) PropBool(property string, defaultValue bool) (bool, error) {
+	val := info.Prop(property)
+	if val == "" {
+		return defaultValue, nil
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return defaultValue, dberrors.Errorf(dberrors.CategoryConfig, "error parsing bool from %s '%s': %w", property, val, err)
+	}
+
+	return b, nil
+}
+
+// PropDuration returns the value of property parsed with
+// time.ParseDuration or defaultValue if the property is not set.
+func (info Info) PropDuration(property string, defaultValue time.Duration) (time.Duration, error) {
+	val := info.Prop(property)
+	if val == "" {
+		return defaultValue, nil
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return defaultValue, dberrors.Errorf(dberrors.CategoryConfig, "error parsing duration from %s '%s': %w", property, val, err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,270 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// InterfacesServer is the entry of a server in an interfaces file.
+type InterfacesServer struct {
+	Name string
+	// Endpoints are the addresses of the query lines in order.
+	Endpoints []Endpoint
+	// TLS is true if the query lines have the ssl filter set.
+	TLS bool
+}
+
+// DefaultInterfacesPath returns the path of the interfaces file of the
+// Sybase installation in the environment variable SYBASE, which is
+// $SYBASE/interfaces or %SYBASE%\ini\sql.ini on Windows.
+//
+// An empty string is returned if SYBASE is not set.
+func DefaultInterfacesPath() string {
+	sybase := os.Getenv("SYBASE")
+	if sybase == "" {
+		return ""
+	}
+
+	if runtime.GOOS == "windows" {
+		return filepath.Join(sybase, "ini", "sql.ini")
+	}
+
+	return filepath.Join(sybase, "interfaces")
+}
+
+// LoadInterfaces parses the interfaces file at path, see
+// ParseInterfaces.
+func LoadInterfaces(path string) (map[string]InterfacesServer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error opening interfaces file: %w", err)
+	}
+	defer f.Close()
+
+	servers, err := ParseInterfaces(f)
+	if err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error parsing interfaces file %s: %w", path, err)
+	}
+
+	return servers, nil
+}
+
+// ParseInterfaces parses a Sybase interfaces file and returns the
+// servers by their name. Files starting with a section in brackets are
+// parsed as Windows sql.ini.
+//
+// Only the query lines are parsed, e.g.:
+//
+//	SERVER
+//		master tcp ether hostname 4901
+//		query tcp ether hostname 4901 ssl
+//
+// or in sql.ini:
+//
+//	[SERVER]
+//	master=TCP,hostname,4901
+//	query=TCP,hostname,4901,ssl
+//
+// Addresses in the TLI format, e.g. "\x00021325c0a800010000000000000000"
+// with the device /dev/tcp, are supported for IPv4.
+func ParseInterfaces(r io.Reader) (map[string]InterfacesServer, error) {
+	servers := map[string]InterfacesServer{}
+
+	var current *InterfacesServer
+	finish := func() {
+		if current != nil {
+			servers[current.Name] = *current
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+
+		// sql.ini section
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			finish()
+			current = &InterfacesServer{Name: strings.TrimSpace(trimmed[1 : len(trimmed)-1])}
+			continue
+		}
+
+		// sql.ini entries are key=value, the first field of interfaces
+		// entries is separated by whitespace.
+		isSQLIni := false
+		if i := strings.IndexAny(trimmed, "= \t"); i >= 0 && trimmed[i] == '=' {
+			isSQLIni = true
+		}
+
+		// interfaces server name
+		if line[0] != ' ' && line[0] != '\t' && !isSQLIni {
+			finish()
+			current = &InterfacesServer{Name: strings.Fields(trimmed)[0]}
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("line %d: entry without server name", lineNo)
+		}
+
+		var endpoint Endpoint
+		var tls, ok bool
+		var err error
+		if isSQLIni {
+			endpoint, tls, ok, err = parseSQLIniLine(trimmed)
+		} else {
+			endpoint, tls, ok, err = parseInterfacesLine(trimmed)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		if ok {
+			current.Endpoints = append(current.Endpoints, endpoint)
+			current.TLS = current.TLS || tls
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	finish()
+	return servers, nil
+}
+
+// parseInterfacesLine parses a line of an interfaces file, e.g.
+// "query tcp ether hostname 4901 ssl". The returned boolean ok is
+// false if the line is not a query line.
+func parseInterfacesLine(line string) (endpoint Endpoint, tls, ok bool, err error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || fields[0] != "query" {
+		return Endpoint{}, false, false, nil
+	}
+
+	// query tli tcp /dev/tcp \x0002...
+	if len(fields) >= 5 && fields[1] == "tli" {
+		endpoint, err := parseTLIAddress(fields[4])
+		if err != nil {
+			return Endpoint{}, false, false, err
+		}
+		return endpoint, hasSSLFilter(fields[5:]), true, nil
+	}
+
+	// query tcp ether hostname port [filter]
+	if len(fields) < 5 {
+		return Endpoint{}, false, false, fmt.Errorf("expected 'query <protocol> <device> <host> <port>', got %q", line)
+	}
+
+	if err := checkPort(fields[4]); err != nil {
+		return Endpoint{}, false, false, err
+	}
+
+	return Endpoint{Host: NormalizeHost(fields[3]), Port: fields[4]}, hasSSLFilter(fields[5:]), true, nil
+}
+
+// parseSQLIniLine parses a line of a sql.ini file, e.g.
+// "query=TCP,hostname,4901,ssl". The returned boolean ok is false if
+// the line is not a query line.
+func parseSQLIniLine(line string) (endpoint Endpoint, tls, ok bool, err error) {
+	parts := strings.SplitN(line, "=", 2)
+	if !strings.EqualFold(strings.TrimSpace(parts[0]), "query") {
+		return Endpoint{}, false, false, nil
+	}
+
+	fields := strings.Split(parts[1], ",")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	if len(fields) < 3 {
+		return Endpoint{}, false, false, fmt.Errorf("expected 'query=<protocol>,<host>,<port>', got %q", line)
+	}
+
+	if err := checkPort(fields[2]); err != nil {
+		return Endpoint{}, false, false, err
+	}
+
+	return Endpoint{Host: NormalizeHost(fields[1]), Port: fields[2]}, hasSSLFilter(fields[3:]), true, nil
+}
+
+// hasSSLFilter reports whether the filters of a query line contain
+// ssl, e.g. "ssl" or `ssl="CN=hostname"`.
+func hasSSLFilter(filters []string) bool {
+	for _, filter := range filters {
+		if strings.EqualFold(filter, "ssl") || strings.HasPrefix(strings.ToLower(filter), "ssl=") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPort returns an error if port is not a valid port number.
+func checkPort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+// parseTLIAddress parses an address in the TLI format: "\x" followed
+// by the hex encoded address family, port and IPv4 address.
+func parseTLIAddress(s string) (Endpoint, error) {
+	if !strings.HasPrefix(strings.ToLower(s), `\x`) {
+		return Endpoint{}, fmt.Errorf("TLI address %q does not start with \\x", s)
+	}
+
+	bs, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return Endpoint{}, fmt.Errorf("error decoding TLI address %q: %w", s, err)
+	}
+
+	if len(bs) < 8 {
+		return Endpoint{}, fmt.Errorf("TLI address %q is too short", s)
+	}
+
+	port := int(bs[2])<<8 | int(bs[3])
+	ip := net.IPv4(bs[4], bs[5], bs[6], bs[7])
+
+	return Endpoint{Host: ip.String(), Port: strconv.Itoa(port)}, nil
+}
+
+// SetServer sets .Host and .Port of info to the first address of the
+// server name in the interfaces file at path. If the server lists
+// multiple addresses they are set as .Hosts. .TLSEnable is set if the
+// server requires SSL.
+//
+// If path is empty DefaultInterfacesPath is used.
+func (info *Info) SetServer(path, name string) error {
+	if path == "" {
+		path = DefaultInterfacesPath()
+		if path == "" {
+			return dberrors.New(dberrors.CategoryConfig, "no interfaces file passed and SYBASE is not set")
+		}
+	}
+
+	server, err := InterfacesResolver{Path: path}.ResolveServer(name)
+	if err != nil {
+		return dberrors.Errorf(dberrors.CategoryConfig, "error resolving server %s from interfaces file %s: %w", name, path, err)
+	}
+
+	return info.applyServer(server)
+}
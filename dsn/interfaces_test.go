@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testInterfaces = `# interfaces of the test
+PROD
+	master tcp ether prod1 4901
+	query tcp ether prod1 4901 ssl
+	query tcp ether prod2 4901 ssl="CN=prod"
+
+DEV
+	master tli tcp /dev/tcp \x00021325c0a800010000000000000000
+	query tli tcp /dev/tcp \x00021325c0a800010000000000000000
+`
+
+const testSQLIni = `; sql.ini of the test
+[PROD]
+master=TCP,prod1,4901
+query=TCP,prod1,4901,ssl
+query=TCP,prod2,4901
+
+[DEV]
+query=NLWNSCK,192.168.0.1,4901
+`
+
+func TestParseInterfaces(t *testing.T) {
+	expected := map[string]InterfacesServer{
+		"PROD": {
+			Name:      "PROD",
+			Endpoints: []Endpoint{{Host: "prod1", Port: "4901"}, {Host: "prod2", Port: "4901"}},
+			TLS:       true,
+		},
+		"DEV": {
+			Name:      "DEV",
+			Endpoints: []Endpoint{{Host: "192.168.0.1", Port: "4901"}},
+		},
+	}
+
+	for name, content := range map[string]string{"interfaces": testInterfaces, "sql.ini": testSQLIni} {
+		t.Run(name,
+			func(t *testing.T) {
+				servers, err := ParseInterfaces(strings.NewReader(content))
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				if !reflect.DeepEqual(servers, expected) {
+					t.Errorf("Expected: %+v", expected)
+					t.Errorf("Received: %+v", servers)
+				}
+			},
+		)
+	}
+}
+
+func TestParseInterfacesFail(t *testing.T) {
+	cases := map[string]string{
+		"no server":    "\tquery tcp ether host 4901\n",
+		"invalid port": "PROD\n\tquery tcp ether host port\n",
+		"short line":   "PROD\n\tquery tcp ether host\n",
+		"invalid tli":  "PROD\n\tquery tli tcp /dev/tcp \\x0002zz\n",
+		"sql.ini":      "[PROD]\nquery=TCP,host\n",
+	}
+
+	for name, content := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				if _, err := ParseInterfaces(strings.NewReader(content)); err == nil {
+					t.Errorf("Expected error parsing %q", content)
+				}
+			},
+		)
+	}
+}
+
+func TestInfo_SetServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "interfaces")
+	if err := ioutil.WriteFile(path, []byte(testInterfaces), 0600); err != nil {
+		t.Fatalf("Error writing interfaces file: %v", err)
+	}
+
+	info := NewInfo()
+	if err := info.SetServer(path, "PROD"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if info.Host != "prod1" || info.Port != "4901" || len(info.Hosts) != 2 || !info.TLSEnable {
+		t.Errorf("Unexpected Info: %+v", info)
+	}
+
+	if err := info.SetServer(path, "MISSING"); err == nil {
+		t.Errorf("Expected error for missing server")
+	}
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"net/url"
+	"reflect"
+)
+
+// Merge overlays the fields and properties of other onto info.
+//
+// Fields of other are only set in info if they differ from their value
+// in NewInfo, so unset fields do not override info. Consequently a
+// bool field cannot be reset to false and .PacketReadTimeout cannot be
+// reset to its default by a merge. Properties of other replace the
+// values of the same property in info.
+func (info *Info) Merge(other *Info) {
+	if other == nil {
+		return
+	}
+
+	target := reflect.ValueOf(info).Elem()
+	source := reflect.ValueOf(other.Clone()).Elem()
+	defaults := reflect.ValueOf(NewInfo()).Elem()
+
+	for i := 0; i < target.NumField(); i++ {
+		if target.Type().Field(i).Name == "ConnectProps" {
+			continue
+		}
+
+		field := source.Field(i)
+		if field.IsZero() || reflect.DeepEqual(field.Interface(), defaults.Field(i).Interface()) {
+			continue
+		}
+
+		target.Field(i).Set(field)
+	}
+
+	if info.ConnectProps == nil && len(other.ConnectProps) > 0 {
+		info.ConnectProps = make(url.Values, len(other.ConnectProps))
+	}
+
+	for key, values := range other.ConnectProps {
+		info.ConnectProps[key] = append([]string{}, values...)
+	}
+}
+
+// MergeInfos returns a copy of base overlaid with overrides in order,
+// so later overrides take precedence, see Merge. E.g. defaults from a
+// file, the environment and flags are combined with:
+//
+//	info := dsn.MergeInfos(fromFile, fromEnv, fromFlags)
+//
+// base and overrides are not modified.
+func MergeInfos(base *Info, overrides ...*Info) *Info {
+	merged := NewInfo()
+	if base != nil {
+		merged = base.Clone()
+	}
+
+	for _, override := range overrides {
+		merged.Merge(override)
+	}
+
+	return merged
+}
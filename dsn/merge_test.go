@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeInfos(t *testing.T) {
+	file := NewInfo()
+	file.Host = "filehost"
+	file.Port = "4901"
+	file.Username = "fileuser"
+	file.PacketReadTimeout = 30
+	file.TLSEnable = true
+	file.ConnectProps.Set("foo", "file")
+	file.ConnectProps.Set("bar", "file")
+
+	env := NewInfo()
+	env.Host = "envhost"
+	env.Password = "envpass"
+	env.DialTimeout = 5 * time.Second
+	env.ConnectProps.Set("foo", "env")
+
+	flags := NewInfo()
+	flags.Username = "flaguser"
+	flags.Hosts = []Endpoint{{Host: "host1", Port: "4901"}}
+
+	merged := MergeInfos(file, env, flags)
+
+	expected := NewInfo()
+	expected.Host = "envhost"
+	expected.Port = "4901"
+	expected.Username = "flaguser"
+	expected.Password = "envpass"
+	expected.PacketReadTimeout = 30
+	expected.TLSEnable = true
+	expected.DialTimeout = 5 * time.Second
+	expected.Hosts = []Endpoint{{Host: "host1", Port: "4901"}}
+	expected.ConnectProps = url.Values{"foo": {"env"}, "bar": {"file"}}
+
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expected: %#v", expected)
+		t.Errorf("Received: %#v", merged)
+	}
+
+	// The inputs are not modified
+	merged.Hosts[0].Host = "other"
+	merged.ConnectProps.Set("bar", "other")
+	if flags.Hosts[0].Host != "host1" || file.ConnectProps.Get("bar") != "file" || file.Host != "filehost" {
+		t.Errorf("Expected inputs to be unchanged")
+	}
+}
@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import "context"
+
+// Resolve returns a copy of info with the secrets, the userstore key
+// and the server name resolved, in that order, see ResolveSecrets,
+// ResolveUserstoreKey and ResolveServer. Drivers call Resolve at
+// connect time before validating and dialing.
+func (info *Info) Resolve(ctx context.Context) (*Info, error) {
+	resolved, err := info.ResolveSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err = resolved.ResolveUserstoreKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved.ResolveServer()
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestInfo_Resolve(t *testing.T) {
+	SetSecretResolver(SecretResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		return "resolved-" + ref, nil
+	}))
+	defer SetSecretResolver(nil)
+
+	SetUserstoreResolver(StaticUserstore{
+		"MYKEY": {Username: "user", Database: "db"},
+	})
+	defer SetUserstoreResolver(nil)
+
+	SetServerResolver(StaticResolver{
+		"MYSERVER": {{Host: "host1", Port: "4901"}},
+	})
+	defer SetServerResolver(nil)
+
+	info := NewInfo()
+	info.Userstorekey = "MYKEY"
+	info.Password = SecretScheme + "pass"
+	info.Server = "MYSERVER"
+
+	resolved, err := info.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := NewInfo()
+	expected.Host = "host1"
+	expected.Port = "4901"
+	expected.Username = "user"
+	expected.Password = "resolved-pass"
+	expected.Database = "db"
+	expected.Server = "MYSERVER"
+
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("Expected: %#v", expected)
+		t.Errorf("Received: %#v", resolved)
+	}
+
+	if err := resolved.Validate(); err != nil {
+		t.Errorf("Expected resolved Info to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"context"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// SecretScheme is the prefix of values referencing a secret, e.g.
+// "secret://ase/password". References are resolved by the
+// SecretResolver set with SetSecretResolver.
+const SecretScheme = "secret://"
+
+// SecretResolver resolves references to secrets.
+type SecretResolver interface {
+	// ResolveSecret returns the secret ref refers to. ref is the
+	// value without SecretScheme.
+	ResolveSecret(ctx context.Context, ref string) (string, error)
+}
+
+// SecretResolverFunc is a function implementing the SecretResolver
+// interface.
+type SecretResolverFunc func(ctx context.Context, ref string) (string, error)
+
+// ResolveSecret implements the SecretResolver interface.
+func (fn SecretResolverFunc) ResolveSecret(ctx context.Context, ref string) (string, error) {
+	return fn(ctx, ref)
+}
+
+// resolverHolder allows to store different SecretResolver
+// implementations in an atomic.Value.
+type resolverHolder struct {
+	resolver SecretResolver
+}
+
+var secretResolver atomic.Value
+
+func init() {
+	secretResolver.Store(resolverHolder{})
+}
+
+// SetSecretResolver sets the SecretResolver used by ResolveSecrets.
+// Passing nil removes the resolver, references then fail to resolve.
+func SetSecretResolver(resolver SecretResolver) {
+	secretResolver.Store(resolverHolder{resolver: resolver})
+}
+
+// ResolveSecrets returns a copy of info with the secrets resolved,
+// which drivers call at connect time, so secrets are neither stored in
+// the DSN nor in the environment of the process.
+//
+// If PasswordFile is set the password is read from the file, without
+// a trailing newline. Setting both Password and PasswordFile is an
+// error.
+//
+// Fields and properties with values starting with SecretScheme are
+// replaced with the secret returned by the SecretResolver.
+func (info *Info) ResolveSecrets(ctx context.Context) (*Info, error) {
+	copied := info.Clone()
+
+	if copied.PasswordFile != "" {
+		if copied.Password != "" {
+			return nil, dberrors.New(dberrors.CategoryConfig, "password and password-file are mutually exclusive")
+		}
+
+		bs, err := ioutil.ReadFile(copied.PasswordFile)
+		if err != nil {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "error reading password file: %w", err)
+		}
+		copied.Password = strings.TrimRight(string(bs), "\r\n")
+	}
+
+	for key, field := range copied.tagToField(false) {
+		if field.Kind() != reflect.String || !strings.HasPrefix(field.String(), SecretScheme) {
+			continue
+		}
+
+		secret, err := resolveSecret(ctx, field.String())
+		if err != nil {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "error resolving secret of field %s: %w", key, err)
+		}
+		field.SetString(secret)
+	}
+
+	for key, values := range copied.ConnectProps {
+		for i, value := range values {
+			if !strings.HasPrefix(value, SecretScheme) {
+				continue
+			}
+
+			secret, err := resolveSecret(ctx, value)
+			if err != nil {
+				return nil, dberrors.Errorf(dberrors.CategoryConfig, "error resolving secret of property %s: %w", key, err)
+			}
+			values[i] = secret
+		}
+	}
+
+	return copied, nil
+}
+
+// resolveSecret resolves the reference in value with the
+// SecretResolver.
+func resolveSecret(ctx context.Context, value string) (string, error) {
+	resolver := secretResolver.Load().(resolverHolder).resolver
+	if resolver == nil {
+		return "", dberrors.New(dberrors.CategoryConfig, "no secret resolver set")
+	}
+
+	return resolver.ResolveSecret(ctx, strings.TrimPrefix(value, SecretScheme))
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestInfo_ResolveSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	if err := ioutil.WriteFile(path, []byte("from file\n"), 0600); err != nil {
+		t.Fatalf("Error writing password file: %v", err)
+	}
+
+	SetSecretResolver(SecretResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		if ref == "missing" {
+			return "", fmt.Errorf("secret %s not found", ref)
+		}
+		return "resolved " + ref, nil
+	}))
+	defer SetSecretResolver(nil)
+
+	info := NewInfo()
+	info.Username = "secret://user"
+	info.PasswordFile = path
+	info.ConnectProps.Add("token", "secret://token")
+
+	resolved, err := info.ResolveSecrets(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolved.Password != "from file" {
+		t.Errorf("Expected password from file, got %q", resolved.Password)
+	}
+
+	if resolved.Username != "resolved user" {
+		t.Errorf("Expected resolved username, got %q", resolved.Username)
+	}
+
+	if token := resolved.Prop("token"); token != "resolved token" {
+		t.Errorf("Expected resolved property, got %q", token)
+	}
+
+	if info.Username != "secret://user" || info.Prop("token") != "secret://token" {
+		t.Errorf("Expected references of the original Info to be retained, got %s", info.AsSimple())
+	}
+
+	info.Password = "secret://missing"
+	info.PasswordFile = ""
+	if _, err := info.ResolveSecrets(context.Background()); err == nil {
+		t.Errorf("Expected error resolving missing secret")
+	}
+
+	info.Password = "pass"
+	info.PasswordFile = path
+	if _, err := info.ResolveSecrets(context.Background()); err == nil {
+		t.Errorf("Expected error with password and password-file")
+	}
+}
+
+func TestInfo_ResolveSecretsWithoutResolver(t *testing.T) {
+	info := NewInfo()
+	info.Password = "secret://password"
+
+	if _, err := info.ResolveSecrets(context.Background()); err == nil {
+		t.Errorf("Expected error resolving secret without resolver")
+	}
+}
@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// ErrServerNotFound is returned by ServerResolvers that have no entry
+// for a server name. ServerResolverChain continues with the next
+// resolver on this error.
+var ErrServerNotFound = errors.New("server not found")
+
+// ServerResolver resolves logical server names as used by Open Client,
+// e.g. in DSQUERY, into the addresses of the server.
+type ServerResolver interface {
+	ResolveServer(name string) (InterfacesServer, error)
+}
+
+// ServerResolverFunc is a function implementing the ServerResolver
+// interface.
+type ServerResolverFunc func(name string) (InterfacesServer, error)
+
+// ResolveServer implements the ServerResolver interface.
+func (fn ServerResolverFunc) ResolveServer(name string) (InterfacesServer, error) {
+	return fn(name)
+}
+
+// ServerResolverChain tries its resolvers in order and returns the
+// first entry found.
+type ServerResolverChain []ServerResolver
+
+// ResolveServer implements the ServerResolver interface.
+func (chain ServerResolverChain) ResolveServer(name string) (InterfacesServer, error) {
+	for _, resolver := range chain {
+		server, err := resolver.ResolveServer(name)
+		if err == nil {
+			return server, nil
+		}
+
+		if !errors.Is(err, ErrServerNotFound) {
+			return InterfacesServer{}, err
+		}
+	}
+
+	return InterfacesServer{}, fmt.Errorf("%s: %w", name, ErrServerNotFound)
+}
+
+// StaticResolver resolves server names from a static map.
+type StaticResolver map[string][]Endpoint
+
+// ResolveServer implements the ServerResolver interface.
+func (resolver StaticResolver) ResolveServer(name string) (InterfacesServer, error) {
+	endpoints, ok := resolver[name]
+	if !ok || len(endpoints) == 0 {
+		return InterfacesServer{}, fmt.Errorf("%s: %w", name, ErrServerNotFound)
+	}
+
+	return InterfacesServer{Name: name, Endpoints: endpoints}, nil
+}
+
+// EnvResolver resolves server names from environment variables in the
+// form of <prefix>_SERVER_<name>, e.g. ASE_SERVER_PROD=host1:4901,host2:4901
+// for the server PROD. The name is uppercased and characters other
+// than letters and digits are replaced with underscores.
+//
+// If Prefix is empty it is set as `ASE`.
+type EnvResolver struct {
+	Prefix string
+}
+
+// ResolveServer implements the ServerResolver interface.
+func (resolver EnvResolver) ResolveServer(name string) (InterfacesServer, error) {
+	prefix := resolver.Prefix
+	if prefix == "" {
+		prefix = "ASE"
+	}
+
+	key := prefix + "_SERVER_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(name))
+
+	value := os.Getenv(key)
+	if value == "" {
+		return InterfacesServer{}, fmt.Errorf("%s: %w", name, ErrServerNotFound)
+	}
+
+	endpoints, err := ParseEndpoints(value)
+	if err != nil {
+		return InterfacesServer{}, fmt.Errorf("error parsing %s: %w", key, err)
+	}
+
+	return InterfacesServer{Name: name, Endpoints: endpoints}, nil
+}
+
+// InterfacesResolver resolves server names from an interfaces file,
+// see ParseInterfaces. If Path is empty DefaultInterfacesPath is used.
+// Without a path all names are not found.
+type InterfacesResolver struct {
+	Path string
+}
+
+// ResolveServer implements the ServerResolver interface.
+func (resolver InterfacesResolver) ResolveServer(name string) (InterfacesServer, error) {
+	path := resolver.Path
+	if path == "" {
+		path = DefaultInterfacesPath()
+		if path == "" {
+			return InterfacesServer{}, fmt.Errorf("%s: %w", name, ErrServerNotFound)
+		}
+	}
+
+	servers, err := LoadInterfaces(path)
+	if err != nil {
+		return InterfacesServer{}, err
+	}
+
+	server, ok := servers[name]
+	if !ok || len(server.Endpoints) == 0 {
+		return InterfacesServer{}, fmt.Errorf("%s: %w", name, ErrServerNotFound)
+	}
+
+	return server, nil
+}
+
+// DefaultServerResolver resolves server names from the environment
+// first and the interfaces file of the Sybase installation second.
+var DefaultServerResolver = ServerResolverChain{EnvResolver{}, InterfacesResolver{}}
+
+// serverResolverHolder allows to store different ServerResolver
+// implementations in an atomic.Value.
+type serverResolverHolder struct {
+	resolver ServerResolver
+}
+
+var serverResolver atomic.Value
+
+func init() {
+	serverResolver.Store(serverResolverHolder{resolver: DefaultServerResolver})
+}
+
+// SetServerResolver sets the ServerResolver used by ResolveServer.
+// Passing nil restores DefaultServerResolver.
+func SetServerResolver(resolver ServerResolver) {
+	if resolver == nil {
+		resolver = DefaultServerResolver
+	}
+	serverResolver.Store(serverResolverHolder{resolver: resolver})
+}
+
+// ServerName returns the logical server name of info, which is .Server
+// or the environment variable DSQUERY if .Server is not set.
+func (info Info) ServerName() string {
+	if info.Server != "" {
+		return info.Server
+	}
+
+	return os.Getenv("DSQUERY")
+}
+
+// ResolveServer returns a copy of info with .Host and .Port set to the
+// address of the logical server name, see ServerName, which drivers
+// call at connect time. The name is resolved with the ServerResolver
+// set with SetServerResolver.
+//
+// If the server has multiple addresses they are set as .Hosts and if
+// it requires SSL .TLSEnable is set.
+//
+// Server names are only resolved if neither .Host nor .Hosts is set,
+// otherwise info is returned unchanged.
+func (info *Info) ResolveServer() (*Info, error) {
+	name := info.ServerName()
+	if name == "" || info.Host != "" || len(info.Hosts) > 0 {
+		return info, nil
+	}
+
+	resolver := serverResolver.Load().(serverResolverHolder).resolver
+	server, err := resolver.ResolveServer(name)
+	if err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error resolving server name %s: %w", name, err)
+	}
+
+	copied := info.Clone()
+	if err := copied.applyServer(server); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// applyServer sets the address of server in info. An error is returned
+// if server has no addresses.
+func (info *Info) applyServer(server InterfacesServer) error {
+	if len(server.Endpoints) == 0 {
+		return dberrors.Errorf(dberrors.CategoryConfig, "server %s has no addresses", server.Name)
+	}
+
+	info.Host = server.Endpoints[0].Host
+	info.Port = server.Endpoints[0].Port
+	if len(server.Endpoints) > 1 {
+		info.Hosts = server.Endpoints
+	}
+
+	if server.TLS {
+		info.TLSEnable = true
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+func TestServerResolverChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "interfaces")
+	if err := ioutil.WriteFile(path, []byte(testInterfaces), 0600); err != nil {
+		t.Fatalf("Error writing interfaces file: %v", err)
+	}
+
+	fn, err := setEnv(map[string]string{"TEST_SERVER_PROD_2": "envhost:4902"})
+	if err != nil {
+		t.Fatalf("Error preparing environment: %v", err)
+	}
+	defer fn()
+
+	chain := ServerResolverChain{
+		StaticResolver{"STATIC": {{Host: "statichost", Port: "4901"}}},
+		EnvResolver{Prefix: "TEST"},
+		InterfacesResolver{Path: path},
+	}
+
+	cases := map[string][]Endpoint{
+		"STATIC": {{Host: "statichost", Port: "4901"}},
+		"prod-2": {{Host: "envhost", Port: "4902"}},
+		"PROD":   {{Host: "prod1", Port: "4901"}, {Host: "prod2", Port: "4901"}},
+	}
+
+	for name, expected := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				server, err := chain.ResolveServer(name)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				if !reflect.DeepEqual(server.Endpoints, expected) {
+					t.Errorf("Expected: %v", expected)
+					t.Errorf("Received: %v", server.Endpoints)
+				}
+			},
+		)
+	}
+
+	if _, err := chain.ResolveServer("MISSING"); !errors.Is(err, ErrServerNotFound) {
+		t.Errorf("Expected ErrServerNotFound, got %v", err)
+	}
+}
+
+func TestInfo_ResolveServer(t *testing.T) {
+	SetServerResolver(StaticResolver{
+		"PROD": {{Host: "host1", Port: "4901"}, {Host: "host2", Port: "4902"}},
+		"DEV":  {{Host: "devhost", Port: "4901"}},
+	})
+	defer SetServerResolver(nil)
+
+	info := NewInfo()
+	info.Server = "PROD"
+
+	resolved, err := info.ResolveServer()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolved.Host != "host1" || resolved.Port != "4901" || len(resolved.Hosts) != 2 {
+		t.Errorf("Unexpected resolved Info: %+v", resolved)
+	}
+
+	if info.Host != "" {
+		t.Errorf("Expected original Info to be unchanged, got %+v", info)
+	}
+
+	// DSQUERY is used without .Server
+	if err := os.Setenv("DSQUERY", "DEV"); err != nil {
+		t.Fatalf("Error setting DSQUERY: %v", err)
+	}
+	defer os.Unsetenv("DSQUERY")
+
+	info.Server = ""
+	if resolved, err = info.ResolveServer(); err != nil || resolved.Host != "devhost" {
+		t.Errorf("Expected resolution through DSQUERY, got %+v, %v", resolved, err)
+	}
+
+	// Explicit hosts take precedence
+	info.Host = "explicit"
+	if resolved, err = info.ResolveServer(); err != nil || resolved.Host != "explicit" {
+		t.Errorf("Expected explicit host to be kept, got %+v, %v", resolved, err)
+	}
+
+	info.Host = ""
+	info.Server = "MISSING"
+	if _, err := info.ResolveServer(); !errors.Is(err, ErrServerNotFound) {
+		t.Errorf("Expected ErrServerNotFound, got %v", err)
+	}
+}
+
+func TestInfo_ResolveServer_NoEndpoints(t *testing.T) {
+	SetServerResolver(ServerResolverFunc(func(name string) (InterfacesServer, error) {
+		return InterfacesServer{Name: name}, nil
+	}))
+	defer SetServerResolver(nil)
+
+	info := NewInfo()
+	info.Server = "EMPTY"
+
+	if _, err := info.ResolveServer(); !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+var strictMode int32
+
+// SetStrict sets the default of the strict mode, which is used if the
+// strict mode is not passed to SetField or the parsers with
+// WithStrict. The strict mode is disabled by default.
+//
+// In strict mode SetField and the parsers return an error for keys
+// that are neither a field of Info nor a property registered with
+// RegisterProps, instead of storing them as property. This reports
+// typos such as "pasword" when the DSN is parsed instead of failing the
+// login later on.
+func SetStrict(strict bool) {
+	var i int32
+	if strict {
+		i = 1
+	}
+	atomic.StoreInt32(&strictMode, i)
+}
+
+// Strict reports whether the strict mode is enabled by default.
+func Strict() bool {
+	return atomic.LoadInt32(&strictMode) == 1
+}
+
+// ParseOption configures SetField and the parsers of DSNs.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	strict bool
+}
+
+// WithStrict enables or disables the strict mode for a single call,
+// regardless of the default set with SetStrict.
+func WithStrict(strict bool) ParseOption {
+	return func(options *parseOptions) {
+		options.strict = strict
+	}
+}
+
+// newParseOptions returns the defaults with opts applied.
+func newParseOptions(opts ...ParseOption) parseOptions {
+	options := parseOptions{strict: Strict()}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+var (
+	knownPropsLock = &sync.RWMutex{}
+	knownProps     = map[string]bool{}
+)
+
+// RegisterProps registers the names of properties read by a package,
+// which are accepted in strict mode.
+func RegisterProps(names ...string) {
+	knownPropsLock.Lock()
+	defer knownPropsLock.Unlock()
+
+	for _, name := range names {
+		knownProps[name] = true
+	}
+}
+
+// IsKnownProp reports whether name was registered with RegisterProps.
+func IsKnownProp(name string) bool {
+	knownPropsLock.RLock()
+	defer knownPropsLock.RUnlock()
+
+	return knownProps[name]
+}
+
+// checkUnknownKey returns an error for key if strict is set and key is
+// not a registered property. key must not refer to a field.
+func checkUnknownKey(key string, strict bool) error {
+	if !strict || IsKnownProp(key) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("unknown key '%s'", key)
+	if suggestion := suggestKey(key); suggestion != "" {
+		msg += fmt.Sprintf(", did you mean '%s'?", suggestion)
+	}
+
+	return dberrors.New(dberrors.CategoryConfig, msg)
+}
+
+// suggestKey returns the field or registered property closest to key
+// or an empty string if no key is similar.
+func suggestKey(key string) string {
+	candidates := []string{}
+
+	t := reflect.TypeOf(Info{})
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Name == "ConnectProps" {
+			continue
+		}
+
+		candidates = append(candidates, strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+		candidates = append(candidates, strings.Split(t.Field(i).Tag.Get("multiref"), ",")...)
+	}
+
+	knownPropsLock.RLock()
+	for name := range knownProps {
+		candidates = append(candidates, name)
+	}
+	knownPropsLock.RUnlock()
+
+	// Sort for deterministic suggestions with the same distance
+	sort.Strings(candidates)
+
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+
+		if distance := levenshtein(key, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
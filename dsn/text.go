@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"encoding"
+	"reflect"
+	"sync"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+var (
+	textPropsLock = &sync.RWMutex{}
+	textProps     = map[string]func() encoding.TextUnmarshaler{}
+)
+
+// RegisterTextProp registers the property name as extension field,
+// whose values are parsed by the encoding.TextUnmarshaler returned by
+// newValue, e.g.:
+//
+//	dsn.RegisterTextProp("allowed-nets", func() encoding.TextUnmarshaler { return &NetList{} })
+//
+// SetField returns an error for values of the property that cannot be
+// parsed instead of storing them. The value is read with PropText.
+//
+// The property is registered with RegisterProps.
+func RegisterTextProp(name string, newValue func() encoding.TextUnmarshaler) {
+	textPropsLock.Lock()
+	textProps[name] = newValue
+	textPropsLock.Unlock()
+
+	RegisterProps(name)
+}
+
+// checkTextProp returns an error if property is registered with
+// RegisterTextProp and value cannot be parsed.
+func checkTextProp(property, value string) error {
+	textPropsLock.RLock()
+	newValue, ok := textProps[property]
+	textPropsLock.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	if err := newValue().UnmarshalText([]byte(value)); err != nil {
+		return dberrors.Errorf(dberrors.CategoryConfig, "error parsing '%s' for property %s: %w", value, property, err)
+	}
+
+	return nil
+}
+
+// PropText parses the value of property into v. v is not modified if
+// the property is not set.
+func (info Info) PropText(property string, v encoding.TextUnmarshaler) error {
+	val := info.Prop(property)
+	if val == "" {
+		return nil
+	}
+
+	if err := v.UnmarshalText([]byte(val)); err != nil {
+		return dberrors.Errorf(dberrors.CategoryConfig, "error parsing %T from %s '%s': %w", v, property, val, err)
+	}
+
+	return nil
+}
+
+// textUnmarshaler returns field as encoding.TextUnmarshaler if it or
+// its pointer implement the interface.
+func textUnmarshaler(field reflect.Value) (encoding.TextUnmarshaler, bool) {
+	if field.CanAddr() {
+		field = field.Addr()
+	}
+
+	u, ok := field.Interface().(encoding.TextUnmarshaler)
+	return u, ok
+}
+
+// textMarshaler returns field as encoding.TextMarshaler if it or its
+// pointer implement the interface.
+func textMarshaler(field reflect.Value) (encoding.TextMarshaler, bool) {
+	if field.CanAddr() {
+		field = field.Addr()
+	}
+
+	m, ok := field.Interface().(encoding.TextMarshaler)
+	return m, ok
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"crypto/tls"
+	"strconv"
+	"strings"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion parses the symbolic name of a TLS version, e.g.
+// "TLS1.2", "TLSv1.2" or "1.2".
+func ParseTLSVersion(s string) (uint16, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	name = strings.TrimPrefix(name, "tls")
+	name = strings.TrimPrefix(name, "v")
+
+	version, ok := tlsVersions[name]
+	if !ok {
+		return 0, dberrors.Errorf(dberrors.CategoryConfig, "unknown TLS version '%s'", s)
+	}
+
+	return version, nil
+}
+
+// ParseCipherSuites parses comma separated names of cipher suites as
+// returned by tls.CipherSuiteName, e.g.
+// "TLS_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256".
+// Hexadecimal IDs such as "0x1301" are accepted as well.
+func ParseCipherSuites(s string) ([]uint16, error) {
+	suites := []uint16{}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		id, err := parseCipherSuite(name)
+		if err != nil {
+			return nil, err
+		}
+
+		suites = append(suites, id)
+	}
+
+	return suites, nil
+}
+
+func parseCipherSuite(name string) (uint16, error) {
+	if strings.HasPrefix(name, "0x") || strings.HasPrefix(name, "0X") {
+		id, err := strconv.ParseUint(name[2:], 16, 16)
+		if err != nil {
+			return 0, dberrors.Errorf(dberrors.CategoryConfig, "invalid cipher suite ID '%s': %w", name, err)
+		}
+		return uint16(id), nil
+	}
+
+	for _, suites := range [][]*tls.CipherSuite{tls.CipherSuites(), tls.InsecureCipherSuites()} {
+		for _, suite := range suites {
+			if strings.EqualFold(suite.Name, name) {
+				return suite.ID, nil
+			}
+		}
+	}
+
+	return 0, dberrors.Errorf(dberrors.CategoryConfig, "unknown cipher suite '%s'", name)
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	cases := map[string]uint16{
+		"TLS1.0":  tls.VersionTLS10,
+		"TLS1.2":  tls.VersionTLS12,
+		"TLSv1.3": tls.VersionTLS13,
+		"tls1.1":  tls.VersionTLS11,
+		"1.2":     tls.VersionTLS12,
+	}
+
+	for name, expected := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				version, err := ParseTLSVersion(name)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				if version != expected {
+					t.Errorf("Expected %x, got %x", expected, version)
+				}
+			},
+		)
+	}
+
+	if _, err := ParseTLSVersion("SSL3.0"); err == nil {
+		t.Errorf("Expected error parsing unknown version")
+	}
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	suites, err := ParseCipherSuites("TLS_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,0x1302")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []uint16{tls.TLS_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_AES_256_GCM_SHA384}
+	if !reflect.DeepEqual(suites, expected) {
+		t.Errorf("Expected: %v", expected)
+		t.Errorf("Received: %v", suites)
+	}
+
+	if _, err := ParseCipherSuites("TLS_UNKNOWN"); err == nil {
+		t.Errorf("Expected error parsing unknown cipher suite")
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// ErrUserstoreKeyNotFound is returned by UserstoreResolvers that have
+// no entry for a key.
+var ErrUserstoreKeyNotFound = errors.New("userstore key not found")
+
+// UserstoreEntry is the connection information stored for a key in the
+// SAP userstore.
+type UserstoreEntry struct {
+	// Endpoints are the addresses of the server in order.
+	Endpoints []Endpoint
+	Username  string
+	Password  string
+	Database  string
+}
+
+// UserstoreResolver resolves keys of the SAP userstore, as set in
+// .Userstorekey, into the connection information stored for them.
+//
+// The secure store in the file system (SSFS) the SAP clients store
+// the keys in is encrypted and not read by this package. Applications
+// using the pure Go driver provide the entries with an implementation
+// backed by their secret management or StaticUserstore.
+type UserstoreResolver interface {
+	ResolveUserstoreKey(key string) (UserstoreEntry, error)
+}
+
+// UserstoreResolverFunc is a function implementing the
+// UserstoreResolver interface.
+type UserstoreResolverFunc func(key string) (UserstoreEntry, error)
+
+// ResolveUserstoreKey implements the UserstoreResolver interface.
+func (fn UserstoreResolverFunc) ResolveUserstoreKey(key string) (UserstoreEntry, error) {
+	return fn(key)
+}
+
+// StaticUserstore resolves userstore keys from a static map.
+type StaticUserstore map[string]UserstoreEntry
+
+// ResolveUserstoreKey implements the UserstoreResolver interface.
+func (store StaticUserstore) ResolveUserstoreKey(key string) (UserstoreEntry, error) {
+	entry, ok := store[key]
+	if !ok {
+		return UserstoreEntry{}, fmt.Errorf("%s: %w", key, ErrUserstoreKeyNotFound)
+	}
+
+	return entry, nil
+}
+
+// userstoreResolverHolder allows to store different UserstoreResolver
+// implementations, including nil, in an atomic.Value.
+type userstoreResolverHolder struct {
+	resolver UserstoreResolver
+}
+
+var userstoreResolver atomic.Value
+
+func init() {
+	userstoreResolver.Store(userstoreResolverHolder{})
+}
+
+// SetUserstoreResolver sets the UserstoreResolver used by
+// ResolveUserstoreKey. No resolver is set by default, passing nil
+// removes the resolver.
+func SetUserstoreResolver(resolver UserstoreResolver) {
+	userstoreResolver.Store(userstoreResolverHolder{resolver: resolver})
+}
+
+// ResolveUserstoreKey returns a copy of info with the connection
+// information stored for .Userstorekey, which drivers without access
+// to the userstore call at connect time. The key is resolved with the
+// UserstoreResolver set with SetUserstoreResolver.
+//
+// Fields set in info take precedence over the entry. .Userstorekey is
+// unset in the copy, so the copy is validated like a DSN without key.
+//
+// If .Userstorekey is empty or no resolver is set info is returned
+// unchanged, e.g. for drivers resolving keys through the SAP client
+// libraries.
+func (info *Info) ResolveUserstoreKey() (*Info, error) {
+	resolver := userstoreResolver.Load().(userstoreResolverHolder).resolver
+	if info.Userstorekey == "" || resolver == nil {
+		return info, nil
+	}
+
+	entry, err := resolver.ResolveUserstoreKey(info.Userstorekey)
+	if err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error resolving userstore key %s: %w", info.Userstorekey, err)
+	}
+
+	copied := info.Clone()
+	copied.Userstorekey = ""
+
+	if copied.Host == "" && len(copied.Hosts) == 0 && len(entry.Endpoints) > 0 {
+		if err := copied.applyServer(InterfacesServer{Name: info.Userstorekey, Endpoints: entry.Endpoints}); err != nil {
+			return nil, err
+		}
+	}
+
+	if copied.Username == "" {
+		copied.Username = entry.Username
+	}
+
+	if copied.Password == "" && copied.PasswordFile == "" {
+		copied.Password = entry.Password
+	}
+
+	if copied.Database == "" {
+		copied.Database = entry.Database
+	}
+
+	return copied, nil
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInfo_ResolveUserstoreKey(t *testing.T) {
+	info := NewInfo()
+	info.Userstorekey = "MYKEY"
+	info.Database = "explicit"
+
+	// Without resolver info is returned unchanged
+	resolved, err := info.ResolveUserstoreKey()
+	if err != nil || resolved != info {
+		t.Fatalf("Expected info to be returned unchanged, got %+v, %v", resolved, err)
+	}
+
+	SetUserstoreResolver(StaticUserstore{
+		"MYKEY": {
+			Endpoints: []Endpoint{{Host: "host1", Port: "4901"}, {Host: "host2", Port: "4902"}},
+			Username:  "user",
+			Password:  "pass",
+			Database:  "db",
+		},
+	})
+	defer SetUserstoreResolver(nil)
+
+	resolved, err = info.ResolveUserstoreKey()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := NewInfo()
+	expected.Host = "host1"
+	expected.Port = "4901"
+	expected.Hosts = []Endpoint{{Host: "host1", Port: "4901"}, {Host: "host2", Port: "4902"}}
+	expected.Username = "user"
+	expected.Password = "pass"
+	expected.Database = "explicit"
+
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("Expected: %#v", expected)
+		t.Errorf("Received: %#v", resolved)
+	}
+
+	if err := resolved.Validate(); err != nil {
+		t.Errorf("Expected resolved Info to be valid, got %v", err)
+	}
+
+	if info.Userstorekey != "MYKEY" || info.Host != "" {
+		t.Errorf("Expected original Info to be unchanged, got %+v", info)
+	}
+
+	info.Userstorekey = "MISSING"
+	if _, err := info.ResolveUserstoreKey(); !errors.Is(err, ErrUserstoreKeyNotFound) {
+		t.Errorf("Expected ErrUserstoreKeyNotFound, got %v", err)
+	}
+}
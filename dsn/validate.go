@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/hashicorp/go-multierror"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+// FieldError describes a missing or invalid field of an Info.
+type FieldError struct {
+	// Field is the json tag of the field.
+	Field  string
+	Reason string
+}
+
+func (err *FieldError) Error() string {
+	return fmt.Sprintf("field %s: %s", err.Field, err.Reason)
+}
+
+// Validate checks that info contains the fields required to connect
+// to a server. Drivers should call Validate before dialing.
+//
+// The fields tagged with `validate:"required"` must be set, unless
+// Userstorekey is set, which provides the address and the credentials.
+// Without Userstorekey either Password or PasswordFile must be set,
+// unless KerberosSPN is set.
+// Host and Port are not required if Hosts lists the servers or
+// a server name is set, see ServerName. Port is not required for unix
+// domain sockets, see IsUnixSocket.
+//
+// If fields are missing or invalid a *multierror.Error with
+// a *FieldError for each field is returned.
+func (info Info) Validate() error {
+	var me error
+
+	if err := validator.New().StructFiltered(info, validatorFilter(info)); err != nil {
+		var validationErrs validator.ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			return dberrors.Wrap(dberrors.CategoryConfig, err)
+		}
+
+		for _, fieldErr := range validationErrs {
+			me = multierror.Append(me, &FieldError{
+				Field:  jsonName(fieldErr.StructField()),
+				Reason: fmt.Sprintf("failed on the '%s' tag", fieldErr.Tag()),
+			})
+		}
+	}
+
+	if info.Userstorekey == "" && info.KerberosSPN == "" && info.Password == "" && info.PasswordFile == "" {
+		me = multierror.Append(me, &FieldError{
+			Field:  "password",
+			Reason: "either password, password-file, userstorekey or kerberos-spn is required",
+		})
+	}
+
+	if info.Network != "" && !networks[info.Network] {
+		me = multierror.Append(me, &FieldError{
+			Field:  "network",
+			Reason: fmt.Sprintf("'%s' is not a supported network", info.Network),
+		})
+	}
+
+	for _, buffer := range []struct {
+		field string
+		value int
+	}{
+		{"tcp-read-buffer", info.TCPReadBuffer},
+		{"tcp-write-buffer", info.TCPWriteBuffer},
+	} {
+		if buffer.value < 0 {
+			me = multierror.Append(me, &FieldError{
+				Field:  buffer.field,
+				Reason: fmt.Sprintf("%d is not a valid buffer size", buffer.value),
+			})
+		}
+	}
+
+	if info.Port != "" {
+		if port, err := strconv.Atoi(info.Port); err != nil || port < 1 || port > 65535 {
+			me = multierror.Append(me, &FieldError{
+				Field:  "port",
+				Reason: fmt.Sprintf("'%s' is not a valid port", info.Port),
+			})
+		}
+	}
+
+	for _, version := range []struct{ field, value string }{
+		{"tls-min-version", info.TLSMinVersion},
+		{"tls-max-version", info.TLSMaxVersion},
+	} {
+		if version.value == "" {
+			continue
+		}
+
+		if _, err := ParseTLSVersion(version.value); err != nil {
+			me = multierror.Append(me, &FieldError{Field: version.field, Reason: err.Error()})
+		}
+	}
+
+	if _, err := ParseCipherSuites(info.TLSCipherSuites); err != nil {
+		me = multierror.Append(me, &FieldError{Field: "tls-cipher-suites", Reason: err.Error()})
+	}
+
+	return dberrors.Wrap(dberrors.CategoryConfig, me)
+}
+
+// validatorFilter returns the validator.FilterFunc skipping the fields
+// of info that are not required.
+func validatorFilter(info Info) validator.FilterFunc {
+	var filterFn validator.FilterFunc = filterNoUserStoreKey
+	if info.Userstorekey != "" {
+		filterFn = filterUserStoreKey
+	}
+
+	if len(info.Hosts) > 0 || info.ServerName() != "" {
+		filterHosts := filterFn
+		filterFn = func(ns []byte) bool {
+			return filterHosts(ns) || string(ns) == "Info.Host" || string(ns) == "Info.Port"
+		}
+	}
+
+	if info.IsUnixSocket() {
+		filterPort := filterFn
+		filterFn = func(ns []byte) bool {
+			return filterPort(ns) || string(ns) == "Info.Port"
+		}
+	}
+
+	return filterFn
+}
+
+// jsonName returns the json tag of the field of Info with the name
+// structField.
+func jsonName(structField string) string {
+	field, ok := reflect.TypeOf(Info{}).FieldByName(structField)
+	if !ok {
+		return structField
+	}
+
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
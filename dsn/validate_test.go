@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dsn
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/hashicorp/go-multierror"
+)
+
+func TestInfo_Validate(t *testing.T) {
+	cases := map[string]struct {
+		info   Info
+		fields []string
+	}{
+		"valid": {
+			info:   Info{Host: "hostname", Port: "4901", Username: "user", Password: "pass"},
+			fields: nil,
+		},
+		"userstorekey": {
+			info:   Info{Userstorekey: "key"},
+			fields: nil,
+		},
+		"empty": {
+			info:   Info{},
+			fields: []string{"host", "port", "username", "password"},
+		},
+		"missing password": {
+			info:   Info{Host: "hostname", Port: "4901", Username: "user"},
+			fields: []string{"password"},
+		},
+		"unix socket": {
+			info:   Info{Host: "/var/run/ase.sock", Username: "user", Password: "pass"},
+			fields: nil,
+		},
+		"invalid network": {
+			info:   Info{Host: "hostname", Port: "4901", Network: "udp", Username: "user", Password: "pass"},
+			fields: []string{"network"},
+		},
+		"invalid tcp buffer": {
+			info:   Info{Host: "hostname", Port: "4901", Username: "user", Password: "pass", TCPReadBuffer: -1},
+			fields: []string{"tcp-read-buffer"},
+		},
+		"invalid port": {
+			info:   Info{Host: "hostname", Port: "65536", Username: "user", Password: "pass"},
+			fields: []string{"port"},
+		},
+		"server name": {
+			info:   Info{Server: "PROD", Username: "user", Password: "pass"},
+			fields: nil,
+		},
+		"kerberos": {
+			info:   Info{Host: "hostname", Port: "4901", Username: "user", KerberosSPN: "ase/hostname@REALM"},
+			fields: nil,
+		},
+		"invalid tls": {
+			info:   Info{Host: "hostname", Port: "4901", Username: "user", Password: "pass", TLSMinVersion: "SSL3", TLSCipherSuites: "NONE"},
+			fields: []string{"tls-min-version", "tls-cipher-suites"},
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				err := cas.info.Validate()
+				if cas.fields == nil {
+					if err != nil {
+						t.Errorf("Unexpected error: %v", err)
+					}
+					return
+				}
+
+				if !errors.Is(err, dberrors.CategoryConfig) {
+					t.Errorf("Expected configuration error, got %v", err)
+				}
+
+				var me *multierror.Error
+				if !errors.As(err, &me) {
+					t.Fatalf("Expected *multierror.Error, got %T: %v", err, err)
+				}
+
+				fields := []string{}
+				for _, fieldErr := range me.Errors {
+					var typed *FieldError
+					if !errors.As(fieldErr, &typed) {
+						t.Fatalf("Expected *FieldError, got %T: %v", fieldErr, fieldErr)
+					}
+					fields = append(fields, typed.Field)
+				}
+
+				if !reflect.DeepEqual(fields, cas.fields) {
+					t.Errorf("Expected fields %v to be reported, got %v", cas.fields, fields)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+)
+
+// RetryableMsgNumbers are the numbers of server messages reporting
+// transient errors:
+//
+//   - 1205: The transaction was chosen as deadlock victim.
+//   - 12205: A lock could not be acquired within the lock wait period.
+var RetryableMsgNumbers = []uint32{1205, 12205}
+
+// ConstraintViolationMsgNumbers are the numbers of server messages
+// reporting violated constraints:
+//
+//   - 233: A column does not allow null values.
+//   - 546, 547: A foreign key constraint was violated.
+//   - 548: A check constraint was violated.
+//   - 2601, 2615: A duplicate key or row was inserted into a unique
+//     index.
+var ConstraintViolationMsgNumbers = []uint32{233, 546, 547, 548, 2601, 2615}
+
+// msgNumberer is implemented by errors carrying messages sent by the
+// server, e.g. tds.EEDError.
+type msgNumberer interface {
+	MsgNumbers() []uint32
+}
+
+// MsgNumbers returns the numbers of the server messages of the first
+// error in the chain of err carrying server messages.
+func MsgNumbers(err error) []uint32 {
+	var numberer msgNumberer
+	if errors.As(err, &numberer) {
+		return numberer.MsgNumbers()
+	}
+	return nil
+}
+
+// HasMsgNumber reports whether err carries a server message with one of
+// the passed numbers.
+func HasMsgNumber(err error, msgNumbers ...uint32) bool {
+	for _, received := range MsgNumbers(err) {
+		for _, msgNumber := range msgNumbers {
+			if received == msgNumber {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsConnectionError reports whether err was caused by the connection to
+// the server, e.g. failed dials, reset connections or timeouts of the
+// transport. The connection should not be used after such errors.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if category := CategoryOf(err); category != CategoryUnknown {
+		return category == CategoryNetwork
+	}
+
+	switch {
+	case errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNABORTED),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, os.ErrDeadlineExceeded),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsConstraintViolation reports whether err carries a server message
+// with one of the ConstraintViolationMsgNumbers.
+func IsConstraintViolation(err error) bool {
+	return HasMsgNumber(err, ConstraintViolationMsgNumbers...)
+}
+
+// IsRetryable reports whether the operation returning err may succeed
+// when it is retried, e.g. on a new connection.
+//
+// Server messages with one of the RetryableMsgNumbers and connection
+// errors, see IsConnectionError, are retryable. Other server messages
+// and errors of contexts are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if MsgNumbers(err) != nil {
+		return HasMsgNumber(err, RetryableMsgNumbers...)
+	}
+
+	return IsConnectionError(err)
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type serverError struct {
+	msgNumbers []uint32
+}
+
+func (serverError) Error() string            { return "server error" }
+func (serverError) Category() Category       { return CategoryServer }
+func (err serverError) MsgNumbers() []uint32 { return err.msgNumbers }
+
+func TestClassify(t *testing.T) {
+	cases := map[string]struct {
+		err                               error
+		retryable, connection, constraint bool
+	}{
+		"nil":              {nil, false, false, false},
+		"deadlock victim":  {fmt.Errorf("error: %w", serverError{[]uint32{1205}}), true, false, false},
+		"duplicate key":    {serverError{[]uint32{2601}}, false, false, true},
+		"syntax error":     {serverError{[]uint32{102}}, false, false, false},
+		"connection reset": {fmt.Errorf("error reading: %w", syscall.ECONNRESET), true, true, false},
+		"unexpected EOF":   {io.ErrUnexpectedEOF, true, true, false},
+		"network category": {New(CategoryNetwork, "dial failed"), true, true, false},
+		"config category":  {Wrap(CategoryConfig, os.ErrNotExist), false, false, false},
+		"context canceled": {context.Canceled, false, false, false},
+		"plain":            {errors.New("plain"), false, false, false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := IsRetryable(cas.err); recv != cas.retryable {
+				t.Errorf("Expected IsRetryable to be %t", cas.retryable)
+			}
+
+			if recv := IsConnectionError(cas.err); recv != cas.connection {
+				t.Errorf("Expected IsConnectionError to be %t", cas.connection)
+			}
+
+			if recv := IsConstraintViolation(cas.err); recv != cas.constraint {
+				t.Errorf("Expected IsConstraintViolation to be %t", cas.constraint)
+			}
+		})
+	}
+}
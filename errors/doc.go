@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package errors defines categories to classify errors returned by the
+packages of go-dblib uniformly.
+
+Errors are wrapped with their category without changing their
+message. The category can be checked with the errors package of the
+standard library:
+
+	import (
+		"errors"
+
+		dberrors "github.com/SAP/go-dblib/errors"
+	)
+
+	if errors.Is(err, dberrors.CategoryNetwork) {
+		// Retry the operation
+	}
+
+Alternatively CategoryOf returns the category of an error.
+
+IsRetryable, IsConnectionError and IsConstraintViolation classify
+errors by their category and the numbers of the messages sent by the
+server, so applications do not have to match error messages:
+
+	if dberrors.IsConstraintViolation(err) {
+		return errAlreadyExists
+	}
+*/
+package errors
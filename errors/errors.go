@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Category classifies errors by their cause.
+//
+// Category implements the error interface so it can be used as target
+// of errors.Is.
+type Category int
+
+// Categories of errors.
+const (
+	// CategoryUnknown is the category of errors that have not been
+	// classified.
+	CategoryUnknown Category = iota
+	// CategoryConfig is the category of invalid configurations, e.g.
+	// malformed DSNs or unreadable certificate files.
+	CategoryConfig
+	// CategoryNetwork is the category of errors on the transport,
+	// e.g. failed dials or TLS handshakes.
+	CategoryNetwork
+	// CategoryProtocol is the category of violations of the TDS
+	// protocol, e.g. unexpected or malformed packages.
+	CategoryProtocol
+	// CategoryServer is the category of errors reported by the
+	// server, e.g. syntax errors or permission violations.
+	CategoryServer
+	// CategoryConversion is the category of errors converting values
+	// between Go and database types.
+	CategoryConversion
+)
+
+var categoryNames = map[Category]string{
+	CategoryUnknown:    "unknown",
+	CategoryConfig:     "config",
+	CategoryNetwork:    "network",
+	CategoryProtocol:   "protocol",
+	CategoryServer:     "server",
+	CategoryConversion: "conversion",
+}
+
+func (category Category) String() string {
+	if name, ok := categoryNames[category]; ok {
+		return name
+	}
+	return fmt.Sprintf("Category(%d)", int(category))
+}
+
+// Error implements the error interface.
+func (category Category) Error() string {
+	return category.String() + " error"
+}
+
+// Error is an error with a category.
+type Error struct {
+	Category Category
+	Err      error
+}
+
+// Error returns the message of the wrapped error.
+func (err *Error) Error() string {
+	if err.Err == nil {
+		return err.Category.Error()
+	}
+	return err.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// Is reports whether target is the category of err.
+func (err *Error) Is(target error) bool {
+	category, ok := target.(Category)
+	return ok && category == err.Category
+}
+
+// New returns an error with the passed category and text.
+func New(category Category, text string) error {
+	return &Error{Category: category, Err: errors.New(text)}
+}
+
+// Errorf formats the error like fmt.Errorf and returns it with the
+// passed category.
+func Errorf(category Category, format string, a ...interface{}) error {
+	return &Error{Category: category, Err: fmt.Errorf(format, a...)}
+}
+
+// Wrap returns err with the passed category. If err is nil Wrap
+// returns nil.
+//
+// If err already has a category it is returned unchanged, so that the
+// category closest to the cause is retained.
+func Wrap(category Category, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if CategoryOf(err) != CategoryUnknown {
+		return err
+	}
+
+	return &Error{Category: category, Err: err}
+}
+
+// categorizer is implemented by errors that report their category
+// without being wrapped in an *Error.
+type categorizer interface {
+	Category() Category
+}
+
+// CategoryOf returns the category of the first error in the chain of
+// err that has a category or CategoryUnknown.
+func CategoryOf(err error) Category {
+	for err != nil {
+		switch typed := err.(type) {
+		case *Error:
+			return typed.Category
+		case categorizer:
+			return typed.Category()
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return CategoryUnknown
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("error opening connection: %w", Wrap(CategoryNetwork, cause))
+
+	if !errors.Is(err, CategoryNetwork) {
+		t.Errorf("Expected error to match %s", CategoryNetwork)
+	}
+
+	if errors.Is(err, CategoryServer) {
+		t.Errorf("Expected error not to match %s", CategoryServer)
+	}
+
+	if !errors.Is(err, cause) {
+		t.Errorf("Expected error to wrap the cause")
+	}
+
+	if CategoryOf(err) != CategoryNetwork {
+		t.Errorf("Expected category %s, received %s", CategoryNetwork, CategoryOf(err))
+	}
+
+	if err.Error() != "error opening connection: connection refused" {
+		t.Errorf("Wrapping changed the message: %s", err)
+	}
+}
+
+func TestWrap_RetainsCategory(t *testing.T) {
+	err := Wrap(CategoryProtocol, Errorf(CategoryConversion, "invalid value %d", 5))
+
+	if CategoryOf(err) != CategoryConversion {
+		t.Errorf("Expected category %s, received %s", CategoryConversion, CategoryOf(err))
+	}
+}
+
+func TestWrap_Nil(t *testing.T) {
+	if err := Wrap(CategoryConfig, nil); err != nil {
+		t.Errorf("Expected nil, received %v", err)
+	}
+}
+
+type categorized struct{}
+
+func (categorized) Error() string      { return "categorized" }
+func (categorized) Category() Category { return CategoryServer }
+
+func TestCategoryOf(t *testing.T) {
+	cases := map[string]struct {
+		err      error
+		category Category
+	}{
+		"nil":         {nil, CategoryUnknown},
+		"plain":       {errors.New("plain"), CategoryUnknown},
+		"new":         {New(CategoryConfig, "invalid"), CategoryConfig},
+		"categorizer": {fmt.Errorf("wrapped: %w", categorized{}), CategoryServer},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := CategoryOf(cas.err); recv != cas.category {
+				t.Errorf("Expected category %s, received %s", cas.category, recv)
+			}
+		})
+	}
+}
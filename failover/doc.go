@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package failover provides a connection manager that fails over between
+the servers of a host list.
+
+The servers are read from .Hosts of the DSN, see netlib.Endpoints, and
+are tried in order - the first server has the highest priority:
+
+	m, err := failover.New(failover.Config{
+		DSN: info,
+		OnEvent: func(event failover.Event) {
+			log.Printf("failover: %s", event)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	go m.Run(ctx)
+
+	conn, err := m.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = conn.(*pool.TDSConn).Do(ctx, func(channel *tds.Channel) error {
+		// Send requests on channel
+	})
+
+Run checks the liveness of the active connection periodically. Once
+the configured number of consecutive checks failed the connection is
+closed and the servers are tried again, starting with the server with
+the highest priority other than the failed one. Host names are resolved
+again when failing over instead of using cached addresses, see
+netlib.WithFreshResolution.
+
+The liveness checks send requests on the connection returned by Conn.
+Requests of the caller must be serialized with them, which
+pool.TDSConn.Do does for connections established by pool.DialTDS.
+*/
+package failover
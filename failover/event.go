@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package failover
+
+import (
+	"fmt"
+
+	"github.com/SAP/go-dblib/netlib"
+)
+
+// EventType is the type of an Event.
+type EventType int
+
+// Types of events emitted by a Manager.
+const (
+	// EventConnected is emitted when a connection to an endpoint was
+	// established.
+	EventConnected EventType = iota
+	// EventDialFailed is emitted when connecting to an endpoint
+	// failed.
+	EventDialFailed
+	// EventLivenessFailed is emitted when a liveness check of the
+	// active connection failed.
+	EventLivenessFailed
+	// EventFailover is emitted when the active connection was
+	// replaced by a connection to another endpoint.
+	EventFailover
+	// EventUnavailable is emitted when no endpoint could be
+	// connected to.
+	EventUnavailable
+)
+
+var eventTypeNames = map[EventType]string{
+	EventConnected:      "connected",
+	EventDialFailed:     "dial failed",
+	EventLivenessFailed: "liveness failed",
+	EventFailover:       "failover",
+	EventUnavailable:    "unavailable",
+}
+
+func (typ EventType) String() string {
+	if name, ok := eventTypeNames[typ]; ok {
+		return name
+	}
+	return fmt.Sprintf("EventType(%d)", int(typ))
+}
+
+// Event describes a change of the connection of a Manager.
+type Event struct {
+	Type EventType
+	// Endpoint is the endpoint the event refers to. It is empty for
+	// EventUnavailable.
+	Endpoint netlib.Endpoint
+	// Previous is the endpoint that failed for EventFailover.
+	Previous netlib.Endpoint
+	// Err is the error that caused the event, if any.
+	Err error
+}
+
+func (event Event) String() string {
+	s := event.Type.String()
+
+	switch event.Type {
+	case EventFailover:
+		s += fmt.Sprintf(" from %s to %s", event.Previous, event.Endpoint)
+	case EventUnavailable:
+	default:
+		s += " " + event.Endpoint.String()
+	}
+
+	if event.Err != nil {
+		s += fmt.Sprintf(": %v", event.Err)
+	}
+
+	return s
+}
@@ -0,0 +1,300 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/netlib"
+	"github.com/SAP/go-dblib/pool"
+	"github.com/SAP/go-dblib/trace"
+	"github.com/hashicorp/go-multierror"
+)
+
+// Defaults of Config.
+const (
+	DefaultCheckInterval = 10 * time.Second
+	DefaultCheckTimeout  = 5 * time.Second
+)
+
+// Config configures a Manager.
+type Config struct {
+	DSN *dsn.Info
+	// Endpoints are the servers in order of their priority. Defaults
+	// to the endpoints of DSN, see netlib.Endpoints.
+	Endpoints []netlib.Endpoint
+	// Dial establishes connections. Defaults to pool.DialTDS.
+	Dial pool.DialFunc
+	// CheckInterval is the interval of liveness checks in Run.
+	// Defaults to DefaultCheckInterval.
+	CheckInterval time.Duration
+	// CheckTimeout limits each liveness check. Defaults to
+	// DefaultCheckTimeout.
+	CheckTimeout time.Duration
+	// FailureThreshold is the number of consecutive failed liveness
+	// checks after which the Manager fails over. Defaults to one.
+	FailureThreshold int
+	// OnEvent is called for each event. It is called synchronously
+	// and must not call methods of the Manager.
+	OnEvent func(Event)
+}
+
+// ErrClosed is returned when using a closed Manager.
+var ErrClosed = errors.New("failover manager is closed")
+
+// Manager maintains a connection to one of multiple servers and fails
+// over to another server when the active server stops responding.
+type Manager struct {
+	config Config
+
+	lock   *sync.Mutex
+	closed bool
+	conn   pool.Conn
+	// active is the index of the endpoint of conn.
+	active   int
+	failures int
+}
+
+// New returns a Manager for config. Connections are established on
+// the first call to Conn or Check.
+func New(config Config) (*Manager, error) {
+	if config.DSN == nil {
+		return nil, errors.New("config has no DSN")
+	}
+
+	if len(config.Endpoints) == 0 {
+		endpoints, err := netlib.Endpoints(config.DSN)
+		if err != nil {
+			return nil, err
+		}
+		config.Endpoints = endpoints
+	}
+
+	if config.Dial == nil {
+		config.Dial = pool.DialTDS
+	}
+
+	if config.CheckInterval == 0 {
+		config.CheckInterval = DefaultCheckInterval
+	}
+
+	if config.CheckTimeout == 0 {
+		config.CheckTimeout = DefaultCheckTimeout
+	}
+
+	if config.FailureThreshold < 1 {
+		config.FailureThreshold = 1
+	}
+
+	return &Manager{
+		config: config,
+		lock:   &sync.Mutex{},
+		active: -1,
+	}, nil
+}
+
+// Conn returns the active connection and establishes it if there is
+// none.
+//
+// The connection is closed by the Manager when it fails over, hence
+// Conn should be called again after errors on the connection.
+func (m *Manager) Conn(ctx context.Context) (pool.Conn, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.closed {
+		return nil, ErrClosed
+	}
+
+	if m.conn == nil {
+		if err := m.connectLocked(ctx, -1); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.conn, nil
+}
+
+// Active returns the endpoint of the active connection. The returned
+// boolean is false if there is no active connection.
+func (m *Manager) Active() (netlib.Endpoint, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.conn == nil {
+		return netlib.Endpoint{}, false
+	}
+	return m.config.Endpoints[m.active], true
+}
+
+// Check checks the liveness of the active connection by pinging it.
+//
+// If config.FailureThreshold consecutive checks failed the connection
+// is closed and a connection to another endpoint is established. If
+// there is no active connection a connection is established.
+//
+// An error is returned if no connection could be established.
+func (m *Manager) Check(ctx context.Context) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	if m.conn == nil {
+		return m.connectLocked(ctx, -1)
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, m.config.CheckTimeout)
+	err := m.conn.Ping(pingCtx)
+	cancel()
+
+	if err == nil {
+		m.failures = 0
+		return nil
+	}
+
+	m.failures++
+	m.emit(ctx, Event{Type: EventLivenessFailed, Endpoint: m.config.Endpoints[m.active], Err: err})
+
+	if m.failures < m.config.FailureThreshold {
+		return nil
+	}
+
+	failed := m.active
+	m.closeLocked()
+
+	return m.connectLocked(ctx, failed)
+}
+
+// Run calls Check every config.CheckInterval until ctx is done.
+// Errors are reported as events.
+func (m *Manager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := m.Check(ctx); errors.Is(err, ErrClosed) {
+				return err
+			}
+		}
+	}
+}
+
+// Close closes the active connection. Subsequent calls to the Manager
+// return ErrClosed.
+func (m *Manager) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+
+	return m.closeLocked()
+}
+
+// closeLocked closes the active connection.
+//
+// The caller must hold m.lock.
+func (m *Manager) closeLocked() error {
+	if m.conn == nil {
+		return nil
+	}
+
+	err := m.conn.Close()
+	m.conn = nil
+	m.active = -1
+	m.failures = 0
+
+	if err != nil {
+		return fmt.Errorf("error closing connection: %w", err)
+	}
+	return nil
+}
+
+// order returns the indices of the endpoints in the order they are
+// tried. The endpoint at index failed is tried last.
+func (m *Manager) order(failed int) []int {
+	order := make([]int, 0, len(m.config.Endpoints))
+	for i := range m.config.Endpoints {
+		if i != failed {
+			order = append(order, i)
+		}
+	}
+
+	if failed >= 0 {
+		order = append(order, failed)
+	}
+
+	return order
+}
+
+// connectLocked establishes a connection to the first reachable
+// endpoint. failed is the index of the endpoint whose connection
+// failed or -1.
+//
+// The caller must hold m.lock.
+func (m *Manager) connectLocked(ctx context.Context, failed int) error {
+	var me error
+
+	// The servers may have been moved by DNS changes.
+	if failed >= 0 {
+		ctx = netlib.WithFreshResolution(ctx)
+	}
+
+	for _, i := range m.order(failed) {
+		endpoint := m.config.Endpoints[i]
+
+		conn, err := m.config.Dial(ctx, netlib.WithEndpoint(m.config.DSN, endpoint))
+		if err != nil {
+			me = multierror.Append(me, fmt.Errorf("error connecting to %s: %w", endpoint, err))
+			m.emit(ctx, Event{Type: EventDialFailed, Endpoint: endpoint, Err: err})
+
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		m.conn = conn
+		m.active = i
+		m.failures = 0
+
+		m.emit(ctx, Event{Type: EventConnected, Endpoint: endpoint})
+		if failed >= 0 && i != failed {
+			m.emit(ctx, Event{Type: EventFailover, Endpoint: endpoint, Previous: m.config.Endpoints[failed]})
+		}
+
+		return nil
+	}
+
+	m.emit(ctx, Event{Type: EventUnavailable, Err: me})
+	return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("no endpoint available: %w", me))
+}
+
+// emit passes event to config.OnEvent and the tracer.
+func (m *Manager) emit(ctx context.Context, event Event) {
+	trace.Emit(ctx, trace.KindConnect, "failover",
+		trace.Attr{Key: "event", Value: event.Type.String()},
+		trace.Attr{Key: "endpoint", Value: event.Endpoint.String()},
+	)
+
+	if m.config.OnEvent != nil {
+		m.config.OnEvent(event)
+	}
+}
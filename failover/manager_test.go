@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package failover
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/netlib"
+	"github.com/SAP/go-dblib/pool"
+)
+
+type testConn struct {
+	host    string
+	servers *testServers
+	closed  bool
+}
+
+func (conn *testConn) Ping(ctx context.Context) error {
+	if conn.servers.isDown(conn.host) || conn.servers.isHung(conn.host) {
+		return errors.New("server not responding")
+	}
+	return nil
+}
+
+func (conn *testConn) State() pool.SessionState {
+	return pool.SessionState{}
+}
+
+func (conn *testConn) Close() error {
+	conn.closed = true
+	return nil
+}
+
+// testServers simulates the servers of the endpoints. Servers that are
+// down refuse connections, hung servers accept connections but do not
+// respond.
+type testServers struct {
+	lock *sync.Mutex
+	down map[string]bool
+	hung map[string]bool
+}
+
+func (servers *testServers) isDown(host string) bool {
+	servers.lock.Lock()
+	defer servers.lock.Unlock()
+	return servers.down[host]
+}
+
+func (servers *testServers) setDown(host string, down bool) {
+	servers.lock.Lock()
+	defer servers.lock.Unlock()
+	servers.down[host] = down
+}
+
+func (servers *testServers) isHung(host string) bool {
+	servers.lock.Lock()
+	defer servers.lock.Unlock()
+	return servers.hung[host]
+}
+
+func (servers *testServers) setHung(host string, hung bool) {
+	servers.lock.Lock()
+	defer servers.lock.Unlock()
+	servers.hung[host] = hung
+}
+
+func (servers *testServers) dial(ctx context.Context, info *dsn.Info) (pool.Conn, error) {
+	if servers.isDown(info.Host) {
+		return nil, errors.New("connection refused")
+	}
+	return &testConn{host: info.Host, servers: servers}, nil
+}
+
+func newTestManager(t *testing.T, config Config) (*Manager, *testServers, *[]Event) {
+	servers := &testServers{lock: &sync.Mutex{}, down: map[string]bool{}, hung: map[string]bool{}}
+	events := &[]Event{}
+
+	config.DSN = dsn.NewInfo()
+	if err := config.DSN.SetField("hosts", "primary:4901,secondary:4901,tertiary:4901"); err != nil {
+		t.Fatalf("Unexpected error setting hosts: %v", err)
+	}
+	config.Dial = servers.dial
+	config.OnEvent = func(event Event) {
+		*events = append(*events, event)
+	}
+
+	m, err := New(config)
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	return m, servers, events
+}
+
+func eventTypes(events []Event) []EventType {
+	types := []EventType{}
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func assertActive(t *testing.T, m *Manager, host string) {
+	endpoint, ok := m.Active()
+	if !ok {
+		t.Errorf("Expected active endpoint %s, got none", host)
+		return
+	}
+
+	if endpoint.Host != host {
+		t.Errorf("Expected active endpoint %s, got %s", host, endpoint)
+	}
+}
+
+func TestManager_Conn_Order(t *testing.T) {
+	m, servers, events := newTestManager(t, Config{})
+	defer m.Close()
+
+	servers.setDown("primary", true)
+
+	if _, err := m.Conn(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertActive(t, m, "secondary")
+
+	expected := []EventType{EventDialFailed, EventConnected}
+	if types := eventTypes(*events); !reflect.DeepEqual(types, expected) {
+		t.Errorf("Expected events %v, got %v", expected, types)
+	}
+}
+
+func TestManager_Check_Failover(t *testing.T) {
+	m, servers, events := newTestManager(t, Config{FailureThreshold: 2})
+	defer m.Close()
+
+	conn, err := m.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	servers.setDown("primary", true)
+
+	// The first failure is below the threshold.
+	if err := m.Check(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertActive(t, m, "primary")
+
+	if err := m.Check(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertActive(t, m, "secondary")
+
+	if !conn.(*testConn).closed {
+		t.Errorf("Expected failed connection to be closed")
+	}
+
+	expected := []EventType{EventConnected, EventLivenessFailed, EventLivenessFailed, EventConnected, EventFailover}
+	if types := eventTypes(*events); !reflect.DeepEqual(types, expected) {
+		t.Errorf("Expected events %v, got %v", expected, types)
+	}
+
+	failover := (*events)[len(*events)-1]
+	if failover.Previous.Host != "primary" || failover.Endpoint.Host != "secondary" {
+		t.Errorf("Unexpected failover event: %s", failover)
+	}
+}
+
+func TestManager_Check_FailedEndpointTriedLast(t *testing.T) {
+	m, servers, _ := newTestManager(t, Config{
+		Endpoints: []netlib.Endpoint{{Host: "primary", Port: "4901"}, {Host: "secondary", Port: "4901"}},
+	})
+	defer m.Close()
+
+	if err := m.Check(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertActive(t, m, "primary")
+
+	// The hung primary still accepts connections but is tried after
+	// the secondary.
+	servers.setHung("primary", true)
+	if err := m.Check(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertActive(t, m, "secondary")
+}
+
+func TestManager_Unavailable(t *testing.T) {
+	m, servers, events := newTestManager(t, Config{})
+	defer m.Close()
+
+	for _, host := range []string{"primary", "secondary", "tertiary"} {
+		servers.setDown(host, true)
+	}
+
+	if _, err := m.Conn(context.Background()); err == nil {
+		t.Fatalf("Expected error")
+	}
+
+	if _, ok := m.Active(); ok {
+		t.Errorf("Expected no active endpoint")
+	}
+
+	if last := (*events)[len(*events)-1]; last.Type != EventUnavailable {
+		t.Errorf("Expected last event %s, got %s", EventUnavailable, last.Type)
+	}
+}
+
+func TestManager_Closed(t *testing.T) {
+	m, _, _ := newTestManager(t, Config{CheckInterval: time.Millisecond})
+
+	if _, err := m.Conn(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Unexpected error closing: %v", err)
+	}
+
+	if _, err := m.Conn(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+
+	if err := m.Run(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected Run to return ErrClosed, got %v", err)
+	}
+}
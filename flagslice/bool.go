@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flagslice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FlagBoolSlice implements the flags.Value interface.
+// Each occurrence of a flag of this type will append the given
+// parameter parsed with strconv.ParseBool to the flags' value.
+//
+// Like boolean flags of the flag package a flag of this type can be
+// passed without a value, which appends true.
+type FlagBoolSlice []bool
+
+// String implements the Stringer interface.
+func (fbs FlagBoolSlice) String() string {
+	s := make([]string, len(fbs))
+	for i, value := range fbs {
+		s[i] = strconv.FormatBool(value)
+	}
+	return strings.Join(s, " ")
+}
+
+// Slice returns the FlagBoolSlice as a bool slice.
+func (fbs FlagBoolSlice) Slice() []bool {
+	return ([]bool)(fbs)
+}
+
+// Set parses the given value as bool and appends it to the
+// FlagBoolSlice.
+func (fbs *FlagBoolSlice) Set(value string) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("flagslice: error parsing '%s' as bool: %w", value, err)
+	}
+
+	*fbs = append(*fbs, b)
+	return nil
+}
+
+// IsBoolFlag allows passing the flag without a value.
+func (fbs FlagBoolSlice) IsBoolFlag() bool {
+	return true
+}
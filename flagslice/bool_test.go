@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flagslice
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestFlagBoolSlice(t *testing.T) {
+	cases := map[string]struct {
+		args     []string
+		expected []bool
+		s        string
+	}{
+		"single": {
+			args:     []string{"true"},
+			expected: []bool{true},
+			s:        "true",
+		},
+		"repeated": {
+			args:     []string{"true", "0", "F", "1"},
+			expected: []bool{true, false, false, true},
+			s:        "true false false true",
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				fs := &FlagBoolSlice{}
+				for _, arg := range cas.args {
+					if err := fs.Set(arg); err != nil {
+						t.Fatalf("Unexpected error setting %q: %v", arg, err)
+					}
+				}
+
+				if !reflect.DeepEqual(fs.Slice(), cas.expected) {
+					t.Errorf("Received unexpected slice")
+					t.Errorf("Expected: %v", cas.expected)
+					t.Errorf("Received: %v", fs.Slice())
+				}
+
+				if s := fs.String(); s != cas.s {
+					t.Errorf("Received unexpected string")
+					t.Errorf("Expected: %q", cas.s)
+					t.Errorf("Received: %q", s)
+				}
+			},
+		)
+	}
+}
+
+func TestFlagBoolSlice_Invalid(t *testing.T) {
+	for _, arg := range []string{"", "yes", "2", "true,false"} {
+		fs := &FlagBoolSlice{}
+		if err := fs.Set(arg); err == nil {
+			t.Errorf("Expected error setting %q", arg)
+		}
+
+		if len(fs.Slice()) != 0 {
+			t.Errorf("Expected no values after setting %q, got %v", arg, fs.Slice())
+		}
+	}
+}
+
+func TestFlagBoolSlice_NoValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fbs := &FlagBoolSlice{}
+	fs.Var(fbs, "b", "bools")
+
+	if err := fs.Parse([]string{"-b", "-b=false", "-b"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []bool{true, false, true}
+	if !reflect.DeepEqual(fbs.Slice(), expected) {
+		t.Errorf("Expected: %v", expected)
+		t.Errorf("Received: %v", fbs.Slice())
+	}
+}
@@ -17,5 +17,19 @@ times, e.g. to accept multiple options.
 		log.Printf("Passed value no. %d: %s", i, fOpt)
 	}
 
+Besides FlagStringSlice the types FlagIntSlice, FlagDurationSlice and
+FlagBoolSlice parse each passed value before appending it.
+
+FlagMap parses values in the form key=value, e.g. to accept arbitrary
+properties:
+
+	var fProps = &flagslice.FlagMap{}
+
+	flag.Var(fProps, "prop", "Properties in key=value form, can be passed multiple times")
+	flag.Parse()
+
+	for key, value := range fProps.Map() {
+		log.Printf("Passed property %s: %s", key, value)
+	}
 */
 package flagslice
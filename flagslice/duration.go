@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flagslice
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FlagDurationSlice implements the flags.Value interface.
+// Each occurrence of a flag of this type will append the given
+// parameter parsed with time.ParseDuration to the flags' value.
+type FlagDurationSlice []time.Duration
+
+// String implements the Stringer interface.
+func (fds FlagDurationSlice) String() string {
+	s := make([]string, len(fds))
+	for i, value := range fds {
+		s[i] = value.String()
+	}
+	return strings.Join(s, " ")
+}
+
+// Slice returns the FlagDurationSlice as a time.Duration slice.
+func (fds FlagDurationSlice) Slice() []time.Duration {
+	return ([]time.Duration)(fds)
+}
+
+// Set parses the given value as time.Duration and appends it to the
+// FlagDurationSlice.
+func (fds *FlagDurationSlice) Set(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("flagslice: error parsing '%s' as duration: %w", value, err)
+	}
+
+	*fds = append(*fds, d)
+	return nil
+}
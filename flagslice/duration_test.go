@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flagslice
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFlagDurationSlice(t *testing.T) {
+	cases := map[string]struct {
+		args     []string
+		expected []time.Duration
+		s        string
+	}{
+		"single": {
+			args:     []string{"1s"},
+			expected: []time.Duration{time.Second},
+			s:        "1s",
+		},
+		"repeated": {
+			args:     []string{"1s", "1m30s", "100ms"},
+			expected: []time.Duration{time.Second, 90 * time.Second, 100 * time.Millisecond},
+			s:        "1s 1m30s 100ms",
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				fs := &FlagDurationSlice{}
+				for _, arg := range cas.args {
+					if err := fs.Set(arg); err != nil {
+						t.Fatalf("Unexpected error setting %q: %v", arg, err)
+					}
+				}
+
+				if !reflect.DeepEqual(fs.Slice(), cas.expected) {
+					t.Errorf("Received unexpected slice")
+					t.Errorf("Expected: %v", cas.expected)
+					t.Errorf("Received: %v", fs.Slice())
+				}
+
+				if s := fs.String(); s != cas.s {
+					t.Errorf("Received unexpected string")
+					t.Errorf("Expected: %q", cas.s)
+					t.Errorf("Received: %q", s)
+				}
+			},
+		)
+	}
+}
+
+func TestFlagDurationSlice_Invalid(t *testing.T) {
+	for _, arg := range []string{"", "1", "a", "1s,2s"} {
+		fs := &FlagDurationSlice{}
+		if err := fs.Set(arg); err == nil {
+			t.Errorf("Expected error setting %q", arg)
+		}
+
+		if len(fs.Slice()) != 0 {
+			t.Errorf("Expected no values after setting %q, got %v", arg, fs.Slice())
+		}
+	}
+}
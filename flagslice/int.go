@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flagslice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FlagIntSlice implements the flags.Value interface.
+// Each occurrence of a flag of this type will append the given
+// parameter parsed as int to the flags' value.
+type FlagIntSlice []int
+
+// String implements the Stringer interface.
+func (fis FlagIntSlice) String() string {
+	s := make([]string, len(fis))
+	for i, value := range fis {
+		s[i] = strconv.Itoa(value)
+	}
+	return strings.Join(s, " ")
+}
+
+// Slice returns the FlagIntSlice as an int slice.
+func (fis FlagIntSlice) Slice() []int {
+	return ([]int)(fis)
+}
+
+// Set parses the given value as int and appends it to the
+// FlagIntSlice.
+func (fis *FlagIntSlice) Set(value string) error {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("flagslice: error parsing '%s' as int: %w", value, err)
+	}
+
+	*fis = append(*fis, i)
+	return nil
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flagslice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlagIntSlice(t *testing.T) {
+	cases := map[string]struct {
+		args     []string
+		expected []int
+		s        string
+	}{
+		"single": {
+			args:     []string{"1"},
+			expected: []int{1},
+			s:        "1",
+		},
+		"repeated": {
+			args:     []string{"1", "-2", "1"},
+			expected: []int{1, -2, 1},
+			s:        "1 -2 1",
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				fs := &FlagIntSlice{}
+				for _, arg := range cas.args {
+					if err := fs.Set(arg); err != nil {
+						t.Fatalf("Unexpected error setting %q: %v", arg, err)
+					}
+				}
+
+				if !reflect.DeepEqual(fs.Slice(), cas.expected) {
+					t.Errorf("Received unexpected slice")
+					t.Errorf("Expected: %v", cas.expected)
+					t.Errorf("Received: %v", fs.Slice())
+				}
+
+				if s := fs.String(); s != cas.s {
+					t.Errorf("Received unexpected string")
+					t.Errorf("Expected: %q", cas.s)
+					t.Errorf("Received: %q", s)
+				}
+			},
+		)
+	}
+}
+
+func TestFlagIntSlice_Invalid(t *testing.T) {
+	for _, arg := range []string{"", "a", "1.5", "1,2"} {
+		fs := &FlagIntSlice{}
+		if err := fs.Set(arg); err == nil {
+			t.Errorf("Expected error setting %q", arg)
+		}
+
+		if len(fs.Slice()) != 0 {
+			t.Errorf("Expected no values after setting %q, got %v", arg, fs.Slice())
+		}
+	}
+}
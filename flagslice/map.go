@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flagslice
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// FlagMapEntry is a key and value passed to a FlagMap.
+type FlagMapEntry struct {
+	Key, Value string
+}
+
+// FlagMap implements the flags.Value interface.
+// Each occurrence of a flag of this type will parse the given
+// parameter in the form of key=value and add the value to the key.
+//
+// A parameter without an equal sign is added as key with an empty
+// value.
+//
+// The entries are kept in the order they were passed.
+type FlagMap struct {
+	entries []FlagMapEntry
+}
+
+// String implements the Stringer interface.
+func (fm FlagMap) String() string {
+	s := make([]string, 0, len(fm.entries))
+	for _, entry := range fm.entries {
+		s = append(s, entry.Key+"="+entry.Value)
+	}
+	return strings.Join(s, " ")
+}
+
+// Entries returns the entries of the FlagMap in the order they were
+// passed.
+func (fm FlagMap) Entries() []FlagMapEntry {
+	return append([]FlagMapEntry{}, fm.entries...)
+}
+
+// Keys returns the keys of the FlagMap in alphabetical order.
+func (fm FlagMap) Keys() []string {
+	values := fm.Values()
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Values returns the FlagMap as url.Values. The values of each key
+// are in the order they were passed.
+func (fm FlagMap) Values() url.Values {
+	values := url.Values{}
+	for _, entry := range fm.entries {
+		values[entry.Key] = append(values[entry.Key], entry.Value)
+	}
+	return values
+}
+
+// Map returns the FlagMap as a string map. If a key was passed
+// multiple times the last value is used.
+func (fm FlagMap) Map() map[string]string {
+	m := make(map[string]string, len(fm.entries))
+	for _, entry := range fm.entries {
+		m[entry.Key] = entry.Value
+	}
+	return m
+}
+
+// Add adds value to key.
+func (fm *FlagMap) Add(key, value string) {
+	fm.entries = append(fm.entries, FlagMapEntry{Key: key, Value: value})
+}
+
+// Set parses the given value as key=value and adds it to the FlagMap.
+func (fm *FlagMap) Set(value string) error {
+	split := strings.SplitN(value, "=", 2)
+
+	key := strings.TrimSpace(split[0])
+	if key == "" {
+		return fmt.Errorf("flagslice: missing key in '%s'", value)
+	}
+
+	val := ""
+	if len(split) > 1 {
+		val = split[1]
+	}
+
+	fm.Add(key, val)
+	return nil
+}
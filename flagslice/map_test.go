@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flagslice
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestFlagMap(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		entries []FlagMapEntry
+		values  url.Values
+		m       map[string]string
+		s       string
+	}{
+		"single": {
+			args:    []string{"a=1"},
+			entries: []FlagMapEntry{{"a", "1"}},
+			values:  url.Values{"a": {"1"}},
+			m:       map[string]string{"a": "1"},
+			s:       "a=1",
+		},
+		"missing equal sign": {
+			args:    []string{"a"},
+			entries: []FlagMapEntry{{"a", ""}},
+			values:  url.Values{"a": {""}},
+			m:       map[string]string{"a": ""},
+			s:       "a=",
+		},
+		"value with equal sign": {
+			args:    []string{"a=b=c"},
+			entries: []FlagMapEntry{{"a", "b=c"}},
+			values:  url.Values{"a": {"b=c"}},
+			m:       map[string]string{"a": "b=c"},
+			s:       "a=b=c",
+		},
+		"repeated keys": {
+			args:    []string{"b=1", "a=2", "b=3"},
+			entries: []FlagMapEntry{{"b", "1"}, {"a", "2"}, {"b", "3"}},
+			values:  url.Values{"a": {"2"}, "b": {"1", "3"}},
+			m:       map[string]string{"a": "2", "b": "3"},
+			s:       "b=1 a=2 b=3",
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				fm := &FlagMap{}
+				for _, arg := range cas.args {
+					if err := fm.Set(arg); err != nil {
+						t.Fatalf("Unexpected error setting %q: %v", arg, err)
+					}
+				}
+
+				if entries := fm.Entries(); !reflect.DeepEqual(entries, cas.entries) {
+					t.Errorf("Received unexpected entries")
+					t.Errorf("Expected: %v", cas.entries)
+					t.Errorf("Received: %v", entries)
+				}
+
+				if values := fm.Values(); !reflect.DeepEqual(values, cas.values) {
+					t.Errorf("Received unexpected values")
+					t.Errorf("Expected: %v", cas.values)
+					t.Errorf("Received: %v", values)
+				}
+
+				if m := fm.Map(); !reflect.DeepEqual(m, cas.m) {
+					t.Errorf("Received unexpected map")
+					t.Errorf("Expected: %v", cas.m)
+					t.Errorf("Received: %v", m)
+				}
+
+				if s := fm.String(); s != cas.s {
+					t.Errorf("Received unexpected string")
+					t.Errorf("Expected: %q", cas.s)
+					t.Errorf("Received: %q", s)
+				}
+			},
+		)
+	}
+}
+
+func TestFlagMap_Keys(t *testing.T) {
+	fm := &FlagMap{}
+	for _, arg := range []string{"c=1", "a=2", "c=3", "b=4"} {
+		if err := fm.Set(arg); err != nil {
+			t.Fatalf("Unexpected error setting %q: %v", arg, err)
+		}
+	}
+
+	expected := []string{"a", "b", "c"}
+	if keys := fm.Keys(); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected: %v", expected)
+		t.Errorf("Received: %v", keys)
+	}
+}
+
+func TestFlagMap_EmptyKey(t *testing.T) {
+	for _, arg := range []string{"=value", " =value", ""} {
+		fm := &FlagMap{}
+		if err := fm.Set(arg); err == nil {
+			t.Errorf("Expected error setting %q", arg)
+		}
+
+		if len(fm.Entries()) != 0 {
+			t.Errorf("Expected no entries after setting %q, got %v", arg, fm.Entries())
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package flagslice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlagStringSlice(t *testing.T) {
+	cases := map[string]struct {
+		args     []string
+		expected []string
+		s        string
+	}{
+		"single": {
+			args:     []string{"a"},
+			expected: []string{"a"},
+			s:        "a",
+		},
+		"repeated": {
+			args:     []string{"a", "b", "a"},
+			expected: []string{"a", "b", "a"},
+			s:        "a b a",
+		},
+		"comma-separated": {
+			args:     []string{"a,b", "c"},
+			expected: []string{"a,b", "c"},
+			s:        "a,b c",
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				fs := &FlagStringSlice{}
+				for _, arg := range cas.args {
+					if err := fs.Set(arg); err != nil {
+						t.Fatalf("Unexpected error setting %q: %v", arg, err)
+					}
+				}
+
+				if !reflect.DeepEqual(fs.Slice(), cas.expected) {
+					t.Errorf("Received unexpected slice")
+					t.Errorf("Expected: %v", cas.expected)
+					t.Errorf("Received: %v", fs.Slice())
+				}
+
+				if s := fs.String(); s != cas.s {
+					t.Errorf("Received unexpected string")
+					t.Errorf("Expected: %q", cas.s)
+					t.Errorf("Received: %q", s)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package health provides health checks for TDS connections.
+
+Ping exercises the protocol by executing a cheap language command on
+a channel and reading the complete response, unlike checks of the TCP
+connection, which succeed even if the server no longer processes
+requests.
+
+Drivers can use Ping to implement driver.Pinger. Errors after which the
+connection must not be reused are reported by IsBadConn:
+
+	func (c *Conn) Ping(ctx context.Context) error {
+		if err := health.Ping(ctx, c.Channel); err != nil {
+			if health.IsBadConn(err) {
+				return driver.ErrBadConn
+			}
+			return err
+		}
+		return nil
+	}
+
+The connections of package pool are validated with Ping on checkout.
+*/
+package health
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"fmt"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/tds"
+	"github.com/SAP/go-dblib/trace"
+)
+
+// Command is the language command executed by Ping.
+const Command = "select 1"
+
+// Ping checks whether the server processes requests on channel by
+// executing Command and reading the complete response.
+//
+// If the response could not be read completely, e.g. because ctx was
+// cancelled, the state of the channel is unknown and the returned
+// error is reported as bad connection by IsBadConn.
+func Ping(ctx context.Context, channel *tds.Channel) error {
+	ctx, span := trace.Start(ctx, trace.KindQuery, "ping")
+	err := ping(ctx, channel)
+	span.End(err)
+	return err
+}
+
+func ping(ctx context.Context, channel *tds.Channel) error {
+	defer channel.Reset()
+
+	if err := channel.SendPackage(ctx, &tds.LanguagePackage{Cmd: Command}); err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error sending ping: %w", err))
+	}
+
+	failed := false
+	_, err := channel.NextPackageUntil(ctx, true,
+		func(pkg tds.Package) (bool, error) {
+			done, ok := pkg.(*tds.DonePackage)
+			if !ok {
+				return false, nil
+			}
+
+			if done.Status&tds.TDS_DONE_ERROR == tds.TDS_DONE_ERROR {
+				failed = true
+			}
+			return done.Status&tds.TDS_DONE_MORE != tds.TDS_DONE_MORE, nil
+		},
+	)
+	if err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error reading ping response: %w", err))
+	}
+
+	if failed {
+		return dberrors.New(dberrors.CategoryServer, "server reported an error executing ping")
+	}
+
+	return nil
+}
+
+// IsBadConn reports whether err signals that the connection is no
+// longer usable and must be discarded.
+//
+// Errors of the network and protocol categories are bad connections,
+// while errors reported by the server are not.
+func IsBadConn(err error) bool {
+	switch dberrors.CategoryOf(err) {
+	case dberrors.CategoryNetwork, dberrors.CategoryProtocol:
+		return true
+	default:
+		return false
+	}
+}
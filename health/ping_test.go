@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// serve accepts a single connection on l and calls respond for each
+// request after reading it completely.
+func serve(l net.Listener, respond func(net.Conn) error) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	header := make([]byte, tds.PacketHeaderSize)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+
+		length := binary.BigEndian.Uint16(header[2:4])
+		if _, err := io.CopyN(ioutil.Discard, conn, int64(length)-tds.PacketHeaderSize); err != nil {
+			return
+		}
+
+		if tds.PacketHeaderStatus(header[1])&tds.TDS_BUFSTAT_EOM != tds.TDS_BUFSTAT_EOM {
+			continue
+		}
+
+		if err := respond(conn); err != nil {
+			return
+		}
+	}
+}
+
+// writeDone writes a response with a single done package.
+func writeDone(w io.Writer, status tds.DoneState) error {
+	bs := make([]byte, tds.PacketHeaderSize+9)
+	bs[0] = byte(tds.TDS_BUF_RESPONSE)
+	bs[1] = byte(tds.TDS_BUFSTAT_EOM)
+	binary.BigEndian.PutUint16(bs[2:4], uint16(len(bs)))
+
+	bs[tds.PacketHeaderSize] = byte(tds.TDS_DONE)
+	binary.LittleEndian.PutUint16(bs[tds.PacketHeaderSize+1:], uint16(status))
+
+	_, err := w.Write(bs)
+	return err
+}
+
+func newTestChannel(t *testing.T, respond func(net.Conn) error) (*tds.Conn, *tds.Channel) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go serve(l, respond)
+
+	info := dsn.NewInfo()
+	info.Host, info.Port, _ = net.SplitHostPort(l.Addr().String())
+	info.Username = "user"
+	info.Password = "pass"
+
+	conn, err := tds.NewConn(context.Background(), info)
+	if err != nil {
+		t.Fatalf("Failed to open connection: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		conn.CloseContext(ctx)
+	})
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to open channel: %v", err)
+	}
+
+	return conn, channel
+}
+
+func TestPing(t *testing.T) {
+	_, channel := newTestChannel(t, func(conn net.Conn) error {
+		return writeDone(conn, tds.TDS_DONE_FINAL)
+	})
+
+	if err := Ping(context.Background(), channel); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// The channel must be usable after a successful ping.
+	if err := Ping(context.Background(), channel); err != nil {
+		t.Errorf("Unexpected error in second ping: %v", err)
+	}
+}
+
+func TestPing_ServerError(t *testing.T) {
+	_, channel := newTestChannel(t, func(conn net.Conn) error {
+		return writeDone(conn, tds.TDS_DONE_ERROR)
+	})
+
+	err := Ping(context.Background(), channel)
+	if err == nil {
+		t.Fatalf("Expected error")
+	}
+
+	if IsBadConn(err) {
+		t.Errorf("Expected server error not to be a bad connection: %v", err)
+	}
+}
+
+func TestPing_Unresponsive(t *testing.T) {
+	_, channel := newTestChannel(t, func(conn net.Conn) error {
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := Ping(ctx, channel)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+
+	if !IsBadConn(err) {
+		t.Errorf("Expected bad connection: %v", err)
+	}
+}
+
+func TestPing_ServerClosed(t *testing.T) {
+	_, channel := newTestChannel(t, func(conn net.Conn) error {
+		return io.EOF
+	})
+
+	if err := Ping(context.Background(), channel); !IsBadConn(err) {
+		t.Errorf("Expected bad connection, got: %v", err)
+	}
+}
+
+func TestIsBadConn(t *testing.T) {
+	cases := map[string]struct {
+		err    error
+		expect bool
+	}{
+		"nil":      {nil, false},
+		"network":  {dberrors.New(dberrors.CategoryNetwork, "network"), true},
+		"protocol": {dberrors.New(dberrors.CategoryProtocol, "protocol"), true},
+		"server":   {dberrors.New(dberrors.CategoryServer, "server"), false},
+		"unknown":  {errors.New("unknown"), false},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsBadConn(cas.err); got != cas.expect {
+				t.Errorf("Expected %t, got %t", cas.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// DBBenchFunc is the interface for benchmarks accepting a pre-connected
+// sql.DB.
+type DBBenchFunc func(b *testing.B, db *sql.DB, tableName string)
+
+// BenchmarkForEachDB runs the given DBBenchFunc against all registered
+// connection types.
+//
+// The connection types are run sequentially in a stable order to keep
+// the results of the cgo and go implementation comparable.
+func BenchmarkForEachDB(benchName string, b *testing.B, benchFn DBBenchFunc) {
+	connectNames := make([]string, 0, len(sqlDBMap))
+	for connectName := range sqlDBMap {
+		connectNames = append(connectNames, connectName)
+	}
+	sort.Strings(connectNames)
+
+	for _, connectName := range connectNames {
+		entry := sqlDBMap[connectName]
+
+		b.Run(connectName,
+			func(b *testing.B) {
+				db, err := entry.fn(entry.info)
+				if err != nil {
+					b.Errorf("Connection failed for '%s': %v", connectName, err)
+					return
+				}
+				defer db.Close()
+
+				benchFn(b, db, strings.Replace(benchName+connectName, " ", "_", -1))
+			},
+		)
+	}
+}
+
+// DoBenchmarkResultSet benchmarks draining a result set of rowCount
+// rows without scanning the values.
+func DoBenchmarkResultSet(b *testing.B, rowCount int) {
+	BenchmarkForEachDB(fmt.Sprintf("BenchmarkResultSet%d", rowCount), b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			samples := make([]interface{}, rowCount)
+			for i := range samples {
+				samples[i] = int32(i)
+			}
+
+			benchmarkDrain(b, db, tableName, samples)
+		},
+	)
+}
+
+// benchmarkType runs sub-benchmarks measuring the insert and scan
+// throughput of the passed column type. newRecv must return a pointer
+// to scan a value of the column into.
+func benchmarkType(b *testing.B, db *sql.DB, tableName, columnDef string, newRecv func() interface{}, samples []interface{}) {
+	if len(samples) == 0 {
+		b.Errorf("No samples to benchmark")
+		return
+	}
+
+	b.Run("Insert",
+		func(b *testing.B) {
+			benchmarkInsert(b, db, tableName, columnDef, samples)
+		},
+	)
+
+	b.Run("Scan",
+		func(b *testing.B) {
+			benchmarkScan(b, db, tableName, columnDef, newRecv, samples)
+		},
+	)
+}
+
+// benchmarkInsert measures the time to insert a single sample using
+// a prepared statement.
+func benchmarkInsert(b *testing.B, db *sql.DB, tableName, columnDef string, samples []interface{}) {
+	if err := createTableInsert(context.Background(), db, tableName, columnDef); err != nil {
+		b.Errorf("Error preparing table: %v", err)
+		return
+	}
+	defer dropBenchTable(b, db, tableName)
+
+	stmt, err := db.Prepare(fmt.Sprintf("insert into %s (a) values (?)", tableName))
+	if err != nil {
+		b.Errorf("Error preparing statement: %v", err)
+		return
+	}
+	defer stmt.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := stmt.Exec(samples[i%len(samples)]); err != nil {
+			b.Errorf("Failed to insert sample %v: %v", samples[i%len(samples)], err)
+			return
+		}
+	}
+	b.StopTimer()
+}
+
+// benchmarkScan measures the time to select and scan all samples.
+func benchmarkScan(b *testing.B, db *sql.DB, tableName, columnDef string, newRecv func() interface{}, samples []interface{}) {
+	if err := createTableInsert(context.Background(), db, tableName, columnDef, samples...); err != nil {
+		b.Errorf("Error preparing table: %v", err)
+		return
+	}
+	defer dropBenchTable(b, db, tableName)
+
+	recv := newRecv()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := db.Query("select a from " + tableName)
+		if err != nil {
+			b.Errorf("Error selecting from %s: %v", tableName, err)
+			return
+		}
+
+		for rows.Next() {
+			if err := rows.Scan(recv); err != nil {
+				rows.Close()
+				b.Errorf("Scan failed: %v", err)
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			b.Errorf("Error reading rows: %v", err)
+		}
+		rows.Close()
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(len(samples)), "rows/op")
+}
+
+// benchmarkDrain measures the time to select and read all rows without
+// scanning the values.
+func benchmarkDrain(b *testing.B, db *sql.DB, tableName string, samples []interface{}) {
+	if err := createTableInsert(context.Background(), db, tableName, "int", samples...); err != nil {
+		b.Errorf("Error preparing table: %v", err)
+		return
+	}
+	defer dropBenchTable(b, db, tableName)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := db.Query("select a from " + tableName)
+		if err != nil {
+			b.Errorf("Error selecting from %s: %v", tableName, err)
+			return
+		}
+
+		n := 0
+		for rows.Next() {
+			n++
+		}
+
+		if err := rows.Err(); err != nil {
+			b.Errorf("Error reading rows: %v", err)
+		}
+		rows.Close()
+
+		if n != len(samples) {
+			b.Errorf("Read %d rows, expected %d", n, len(samples))
+			return
+		}
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(len(samples)), "rows/op")
+}
+
+// dropBenchTable drops the table of a benchmark, as benchmark
+// functions are run multiple times with the same table name.
+func dropBenchTable(b *testing.B, db *sql.DB, tableName string) {
+	if _, err := db.Exec("drop table " + tableName); err != nil {
+		b.Errorf("Error dropping table %s: %v", tableName, err)
+	}
+}
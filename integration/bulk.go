@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// BulkLoadFunc loads rows into the columns of a table, sending
+// batchSize rows per batch.
+type BulkLoadFunc func(ctx context.Context, db *sql.DB, tableName string, columns []string, batchSize int, rows [][]interface{}) error
+
+// bulkLoader is the BulkLoadFunc used by DoTestBulkInsert.
+var bulkLoader BulkLoadFunc = PreparedBulkLoad
+
+// RegisterBulkLoader sets the BulkLoadFunc used by DoTestBulkInsert,
+// e.g. a function using the bulk copy protocol of a driver.
+//
+// RegisterBulkLoader must be called before DoTestBulkInsert is run.
+func RegisterBulkLoader(fn BulkLoadFunc) {
+	bulkLoader = fn
+}
+
+// PreparedBulkLoad is a BulkLoadFunc inserting rows with a prepared
+// statement, committing a transaction after each batch.
+//
+// It is used until the bulk copy protocol is implemented in tds.
+func PreparedBulkLoad(ctx context.Context, db *sql.DB, tableName string, columns []string, batchSize int, rows [][]interface{}) error {
+	if batchSize < 1 {
+		return fmt.Errorf("invalid batch size %d", batchSize)
+	}
+
+	query := fmt.Sprintf("insert into %s (%s) values (%s)", tableName, strings.Join(columns, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		if err := preparedBulkBatch(ctx, db, query, rows[start:end]); err != nil {
+			return fmt.Errorf("error loading rows %d to %d: %w", start, end, err)
+		}
+	}
+
+	return nil
+}
+
+// preparedBulkBatch inserts rows in a single transaction.
+func preparedBulkBatch(ctx context.Context, db *sql.DB, query string, rows [][]interface{}) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error inserting row %v: %w", row, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// bulkRow returns the values of the row with the passed index.
+func bulkRow(i int) []interface{} {
+	return []interface{}{int32(i), fmt.Sprintf("row %d", i), float64(i) / 4}
+}
+
+// bulkSampleCount is the number of rows whose content is verified
+// after a bulk load.
+const bulkSampleCount = 50
+
+// DoTestBulkInsert loads rowCount rows using the registered
+// BulkLoadFunc for each of the passed batch sizes and verifies the
+// number of rows and the contents of randomly sampled rows.
+func DoTestBulkInsert(t *testing.T, rowCount int, batchSizes ...int) {
+	for _, batchSize := range batchSizes {
+		batchSize := batchSize
+
+		t.Run(fmt.Sprintf("batch size %d", batchSize),
+			func(t *testing.T) {
+				TestForEachDB(fmt.Sprintf("TestBulkInsert%d", batchSize), t,
+					func(t *testing.T, db *sql.DB, tableName string) {
+						testBulkInsert(t, db, tableName, rowCount, batchSize, TestRand(t))
+					},
+				)
+			},
+		)
+	}
+}
+
+func testBulkInsert(t *testing.T, db *sql.DB, tableName string, rowCount, batchSize int, r *rand.Rand) {
+	ctx := context.Background()
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("create table %s (a int, b varchar(30), c float)", tableName)); err != nil {
+		t.Errorf("Error creating table %s: %v", tableName, err)
+		return
+	}
+	defer db.ExecContext(ctx, "drop table "+tableName)
+
+	rows := make([][]interface{}, rowCount)
+	for i := range rows {
+		rows[i] = bulkRow(i)
+	}
+
+	if err := bulkLoader(ctx, db, tableName, []string{"a", "b", "c"}, batchSize, rows); err != nil {
+		t.Errorf("Bulk load failed: %v", err)
+		return
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, "select count(*) from "+tableName).Scan(&count); err != nil {
+		t.Errorf("Error counting rows: %v", err)
+		return
+	}
+
+	if count != rowCount {
+		t.Errorf("Expected %d rows, found %d", rowCount, count)
+		return
+	}
+
+	if rowCount == 0 {
+		return
+	}
+
+	stmt, err := db.PrepareContext(ctx, fmt.Sprintf("select a, b, c from %s where a = ?", tableName))
+	if err != nil {
+		t.Errorf("Error preparing statement: %v", err)
+		return
+	}
+	defer stmt.Close()
+
+	for i := 0; i < bulkSampleCount; i++ {
+		index := r.Intn(rowCount)
+		expect := bulkRow(index)
+
+		var a int32
+		var b string
+		var c float64
+		if err := stmt.QueryRowContext(ctx, index).Scan(&a, &b, &c); err != nil {
+			t.Errorf("Error selecting row %d: %v", index, err)
+			continue
+		}
+
+		if a != expect[0] || b != expect[1] || c != expect[2] {
+			t.Errorf("Received row does not match loaded row")
+			t.Errorf("Expected: %v", expect)
+			t.Errorf("Received: %v", []interface{}{a, b, c})
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// cleanupEntry is a named teardown function.
+type cleanupEntry struct {
+	name string
+	fn   func() error
+}
+
+// Cleanup is a registry of teardown functions, which are run in
+// reverse order of their registration.
+//
+// All teardown functions are run even if one fails, so that a failing
+// teardown does not mask failures of the teardowns registered before
+// it.
+type Cleanup struct {
+	lock    *sync.Mutex
+	entries []cleanupEntry
+}
+
+// NewCleanup returns an empty Cleanup.
+func NewCleanup() *Cleanup {
+	return &Cleanup{lock: &sync.Mutex{}}
+}
+
+// TestCleanup returns a Cleanup that is run when the test and all its
+// subtests have finished. Each failing teardown is reported as test
+// error.
+func TestCleanup(t *testing.T) *Cleanup {
+	cleanup := NewCleanup()
+
+	t.Cleanup(func() {
+		if err := cleanup.Run(); err != nil {
+			if merr, ok := err.(*multierror.Error); ok {
+				for _, err := range merr.Errors {
+					t.Errorf("%v", err)
+				}
+				return
+			}
+			t.Errorf("%v", err)
+		}
+	})
+
+	return cleanup
+}
+
+// Add registers a teardown function. The name is used to report
+// failures of the function.
+func (cleanup *Cleanup) Add(name string, fn func() error) {
+	cleanup.lock.Lock()
+	defer cleanup.lock.Unlock()
+
+	cleanup.entries = append(cleanup.entries, cleanupEntry{name: name, fn: fn})
+}
+
+// Run runs all registered teardown functions in reverse order and
+// removes them from the registry.
+//
+// The errors of failing teardown functions are aggregated in
+// a *multierror.Error.
+func (cleanup *Cleanup) Run() error {
+	cleanup.lock.Lock()
+	entries := cleanup.entries
+	cleanup.entries = nil
+	cleanup.lock.Unlock()
+
+	var me error
+	for i := len(entries) - 1; i >= 0; i-- {
+		if err := entries[i].fn(); err != nil {
+			me = multierror.Append(me, fmt.Errorf("teardown '%s' failed: %w", entries[i].name, err))
+		}
+	}
+
+	return me
+}
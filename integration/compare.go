@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+// The comparison functions in this file follow the convention of the
+// -compare flag of gen_type.go and return true if the received value
+// does not match the expected value.
+
+// DateTimeResolution is the resolution of the datetime and time types.
+const DateTimeResolution = time.Second / 300
+
+// CompareTime returns a comparison function for time.Time treating
+// values as equal if they differ by at most tolerance.
+func CompareTime(tolerance time.Duration) func(recv, expect time.Time) bool {
+	return func(recv, expect time.Time) bool {
+		diff := recv.Sub(expect)
+		if diff < 0 {
+			diff = -diff
+		}
+
+		return diff > tolerance
+	}
+}
+
+// CompareFloat64 returns a comparison function for float64 treating
+// values as equal if their relative difference is at most epsilon.
+func CompareFloat64(epsilon float64) func(recv, expect float64) bool {
+	return func(recv, expect float64) bool {
+		if recv == expect {
+			return false
+		}
+
+		diff := math.Abs(recv - expect)
+		largest := math.Max(math.Abs(recv), math.Abs(expect))
+
+		// The difference of values at the ends of the range
+		// overflows, compare their halves instead.
+		if math.IsInf(diff, 0) {
+			diff = math.Abs(recv/2 - expect/2)
+			largest /= 2
+		}
+
+		return diff > largest*epsilon
+	}
+}
+
+// CompareFloat32 returns a comparison function for float32 treating
+// values as equal if their relative difference is at most epsilon.
+func CompareFloat32(epsilon float32) func(recv, expect float32) bool {
+	compare := CompareFloat64(float64(epsilon))
+	return func(recv, expect float32) bool {
+		return compare(float64(recv), float64(expect))
+	}
+}
+
+var (
+	// compareDateTime accounts for the rounding of datetime and time
+	// values to 1/300 second.
+	compareDateTime = CompareTime(DateTimeResolution)
+	compareFloat    = CompareFloat64(1e-15)
+	compareReal     = CompareFloat32(1e-6)
+)
+
+func compareDecimal(recv, expect *asetypes.Decimal) bool {
+	return !expect.Cmp(*recv)
+}
+
+func compareChar(recv, expect string) bool {
+	return strings.Compare(strings.TrimSpace(recv), expect) != 0
+}
+
+func compareBinary(recv, expect []byte) bool {
+	return !bytes.Equal(bytes.Trim(recv, "\x00"), expect)
+}
+
+// compareTimeValues adapts a comparison function for time.Time to the
+// signature of RandomSampleGenerator.Compare.
+func compareTimeValues(compare func(recv, expect time.Time) bool) func(recv, expect interface{}) bool {
+	return func(recv, expect interface{}) bool {
+		return compare(recv.(time.Time), expect.(time.Time))
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+)
+
+// ContainerConfig configures the ASE container started by
+// StartContainer.
+type ContainerConfig struct {
+	// Image is the image including the tag to start. If empty the
+	// value of the environment variable INTEGRATION_ASE_IMAGE is used.
+	Image string
+	// Port is the port ASE listens on inside the container.
+	// Defaults to 5000.
+	Port string
+	// Username and Password are the credentials the image configures
+	// for ASE. Username defaults to sa.
+	Username, Password string
+	// Env is passed as environment to the container.
+	Env map[string]string
+	// ReadyTimeout is the maximum duration to wait for ASE to accept
+	// connections. Defaults to five minutes.
+	ReadyTimeout time.Duration
+}
+
+// StartContainer starts an ASE container using the docker executable,
+// waits until the server accepts connections and returns a dsn.Info
+// pointing to the server.
+//
+// The returned function stops and removes the container and should be
+// called once all tests have finished, e.g. at the end of TestMain.
+func StartContainer(ctx context.Context, config ContainerConfig) (*dsn.Info, func() error, error) {
+	if config.Image == "" {
+		config.Image = os.Getenv("INTEGRATION_ASE_IMAGE")
+	}
+	if config.Image == "" {
+		return nil, nil, errors.New("no image configured, set ContainerConfig.Image or INTEGRATION_ASE_IMAGE")
+	}
+
+	if config.Port == "" {
+		config.Port = "5000"
+	}
+
+	if config.Username == "" {
+		config.Username = "sa"
+	}
+
+	if config.ReadyTimeout == 0 {
+		config.ReadyTimeout = 5 * time.Minute
+	}
+
+	args := []string{"run", "--detach", "--publish", config.Port}
+	for key, value := range config.Env {
+		args = append(args, "--env", key+"="+value)
+	}
+	args = append(args, config.Image)
+
+	id, err := docker(ctx, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start container from image %s: %w", config.Image, err)
+	}
+
+	teardownFn := func() error {
+		if _, err := docker(context.Background(), "rm", "--force", "--volumes", id); err != nil {
+			return fmt.Errorf("failed to remove container %s: %w", id, err)
+		}
+		return nil
+	}
+
+	info, err := containerInfo(ctx, id, config)
+	if err != nil {
+		if teardownErr := teardownFn(); teardownErr != nil {
+			err = fmt.Errorf("%v; %w", err, teardownErr)
+		}
+		return nil, nil, err
+	}
+
+	if err := waitForServer(ctx, info, config.ReadyTimeout); err != nil {
+		if teardownErr := teardownFn(); teardownErr != nil {
+			err = fmt.Errorf("%v; %w", err, teardownErr)
+		}
+		return nil, nil, fmt.Errorf("server in container %s did not become ready: %w", id, err)
+	}
+
+	return info, teardownFn, nil
+}
+
+// containerInfo returns a dsn.Info with the address the port of the
+// container with the passed id is published on.
+func containerInfo(ctx context.Context, id string, config ContainerConfig) (*dsn.Info, error) {
+	out, err := docker(ctx, "port", id, config.Port+"/tcp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve published port of container %s: %w", id, err)
+	}
+
+	// docker port may return one line per address family, the first
+	// is sufficient.
+	host, port, err := net.SplitHostPort(strings.Split(out, "\n")[0])
+	if err != nil {
+		return nil, fmt.Errorf("error parsing published address '%s': %w", out, err)
+	}
+
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+
+	info := dsn.NewInfo()
+	info.Host = host
+	info.Port = port
+	info.Username = config.Username
+	info.Password = config.Password
+
+	return info, nil
+}
+
+// waitForServer pings the server described by info until it responds
+// or timeout is exceeded.
+func waitForServer(ctx context.Context, info *dsn.Info, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	db, err := sql.Open("ase", info.AsSimple())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("last error: %v: %w", err, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// docker executes the docker executable with the passed arguments and
+// returns the trimmed output.
+func docker(ctx context.Context, args ...string) (string, error) {
+	stderr := &bytes.Buffer{}
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/hashicorp/go-multierror"
+)
+
+var (
+	dbPoolOnce  sync.Once
+	dbPoolLimit int
+
+	dbPoolLock = &sync.Mutex{}
+	// dbPoolIdle holds the names of databases that are not in use.
+	dbPoolIdle chan string
+	// dbPoolCreated holds the infos used to create the pooled
+	// databases, which are used to drop them.
+	dbPoolCreated []*dsn.Info
+	// dbPoolFreed is closed and replaced when a slot of the pool is
+	// freed, so waiting callers can create a database in its place.
+	dbPoolFreed = make(chan struct{})
+)
+
+// DBPoolSize returns the maximum number of databases kept in the pool
+// of isolated databases.
+//
+// The size is read once from the environment variable
+// INTEGRATION_DB_POOL. If the variable is unset, not a number or less
+// than one isolated databases are created and dropped for each test.
+//
+// Pooled databases are created on demand, cleaned after each test and
+// must be dropped by calling TeardownDBPool at the end of the suite.
+func DBPoolSize() int {
+	dbPoolOnce.Do(func() {
+		val, ok := os.LookupEnv("INTEGRATION_DB_POOL")
+		if !ok {
+			return
+		}
+
+		limit, err := strconv.Atoi(val)
+		if err != nil || limit < 1 {
+			return
+		}
+
+		dbPoolLimit = limit
+		dbPoolIdle = make(chan string, limit)
+	})
+
+	return dbPoolLimit
+}
+
+// acquirePooledDB returns a copy of info pointing to a pooled database
+// and a function that cleans the database and returns it to the pool.
+//
+// If all databases are in use and the pool is not full a new database
+// is created, otherwise acquirePooledDB blocks until a database is
+// returned or a slot is freed.
+func acquirePooledDB(info *dsn.Info) (*dsn.Info, func() error, error) {
+	pooled := copyInfo(info)
+
+acquire:
+	for {
+		select {
+		case name := <-dbPoolIdle:
+			pooled.Database = name
+			break acquire
+		default:
+		}
+
+		dbPoolLock.Lock()
+		create := len(dbPoolCreated) < DBPoolSize()
+		if create {
+			// Reserve the slot before creating the database.
+			dbPoolCreated = append(dbPoolCreated, pooled)
+		}
+		freed := dbPoolFreed
+		dbPoolLock.Unlock()
+
+		if create {
+			if err := SetupDB(pooled); err != nil {
+				forgetPooledDB(pooled)
+				return nil, nil, err
+			}
+			break acquire
+		}
+
+		// Wait for a database to be returned or for a slot to be
+		// freed.
+		select {
+		case name := <-dbPoolIdle:
+			pooled.Database = name
+			break acquire
+		case <-freed:
+		}
+	}
+
+	releaseFn := func() error {
+		if err := cleanDB(pooled); err != nil {
+			// The database is not returned to the pool as its
+			// state is unknown. It is dropped instead and its slot
+			// freed.
+			err = fmt.Errorf("failed to clean pooled database %s: %w", pooled.Database, err)
+			if dropErr := TeardownDB(copyInfo(pooled)); dropErr != nil {
+				err = multierror.Append(err, fmt.Errorf("failed to drop pooled database %s: %w", pooled.Database, dropErr))
+			}
+
+			forgetPooledDB(pooled)
+			return err
+		}
+
+		dbPoolIdle <- pooled.Database
+		return nil
+	}
+
+	return pooled, releaseFn, nil
+}
+
+// forgetPooledDB removes the database of pooled from the pool and frees
+// its slot.
+func forgetPooledDB(pooled *dsn.Info) {
+	dbPoolLock.Lock()
+	defer dbPoolLock.Unlock()
+
+	// Databases taken from dbPoolIdle are only known by their name,
+	// reservations whose creation failed only by their info.
+	index := -1
+	for i, created := range dbPoolCreated {
+		if created == pooled {
+			index = i
+			break
+		}
+
+		if index < 0 && created.Database == pooled.Database {
+			index = i
+		}
+	}
+
+	if index >= 0 {
+		dbPoolCreated = append(dbPoolCreated[:index], dbPoolCreated[index+1:]...)
+	}
+
+	close(dbPoolFreed)
+	dbPoolFreed = make(chan struct{})
+}
+
+// cleanDB drops all user objects in the database of info.
+func cleanDB(info *dsn.Info) error {
+	db, err := sql.Open("ase", info.AsSimple())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := withDefaultDeadline(context.Background())
+	defer cancel()
+
+	// Objects depending on tables are dropped first.
+	objectTypes := []struct {
+		sysType, drop string
+	}{
+		{"TR", "drop trigger"},
+		{"P", "drop procedure"},
+		{"V", "drop view"},
+		{"U", "drop table"},
+	}
+
+	for _, objectType := range objectTypes {
+		rows, err := db.QueryContext(ctx, "select name from sysobjects where type = ?", objectType.sysType)
+		if err != nil {
+			return fmt.Errorf("error listing objects of type %s: %w", objectType.sysType, err)
+		}
+
+		names := []string{}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning object name: %w", err)
+			}
+			names = append(names, name)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error listing objects of type %s: %w", objectType.sysType, err)
+		}
+
+		for _, name := range names {
+			if _, err := db.ExecContext(ctx, objectType.drop+" "+name); err != nil {
+				return fmt.Errorf("error executing '%s %s': %w", objectType.drop, name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// TeardownDBPool drops all pooled databases. It should be called once
+// all tests have finished, e.g. at the end of TestMain.
+func TeardownDBPool() error {
+	dbPoolLock.Lock()
+	defer dbPoolLock.Unlock()
+
+	var me error
+	for _, info := range dbPoolCreated {
+		if err := TeardownDB(copyInfo(info)); err != nil {
+			me = multierror.Append(me, fmt.Errorf("failed to drop pooled database %s: %w", info.Database, err))
+		}
+	}
+
+	dbPoolCreated = nil
+	return me
+}
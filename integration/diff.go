@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// diffContext is the number of bytes or characters shown around the
+// first difference of long values.
+const diffContext = 32
+
+// DescribeMismatch returns a structured description of the difference
+// between the expected and received value of the sample with the
+// passed index.
+//
+// Strings and byte slices are described by their length and the
+// position of the first difference with a window of the surrounding
+// content, byte slices are printed as hex.
+func DescribeMismatch(index int, expect, recv interface{}) string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "sample %d does not match (%T)\n", index, expect)
+
+	switch e := expect.(type) {
+	case []byte:
+		r, ok := recv.([]byte)
+		if !ok {
+			break
+		}
+		describeBytes(sb, e, r)
+		return sb.String()
+	case string:
+		r, ok := recv.(string)
+		if !ok {
+			break
+		}
+		describeString(sb, e, r)
+		return sb.String()
+	case time.Time:
+		r, ok := recv.(time.Time)
+		if !ok {
+			break
+		}
+		fmt.Fprintf(sb, "  expected: %s\n", e.Format(time.RFC3339Nano))
+		fmt.Fprintf(sb, "  received: %s\n", r.Format(time.RFC3339Nano))
+		fmt.Fprintf(sb, "  difference: %s\n", r.Sub(e))
+		return sb.String()
+	}
+
+	fmt.Fprintf(sb, "  expected: %v\n", expect)
+	fmt.Fprintf(sb, "  received: %v (%T)\n", recv, recv)
+	return sb.String()
+}
+
+// ReportMismatch reports the result of DescribeMismatch as test error.
+func ReportMismatch(t *testing.T, index int, expect, recv interface{}) {
+	t.Helper()
+	t.Errorf("Received value does not match passed parameter: %s", DescribeMismatch(index, expect, recv))
+}
+
+// firstDifference returns the index of the first element differing
+// between slices of length a and b as determined by equal.
+func firstDifference(a, b int, equal func(i int) bool) int {
+	n := a
+	if b < n {
+		n = b
+	}
+
+	for i := 0; i < n; i++ {
+		if !equal(i) {
+			return i
+		}
+	}
+
+	return n
+}
+
+// window returns the bounds of the context window around pos.
+func window(pos, length int) (int, int) {
+	start := pos - diffContext
+	if start < 0 {
+		start = 0
+	}
+
+	end := pos + diffContext
+	if end > length {
+		end = length
+	}
+
+	return start, end
+}
+
+func describeBytes(sb *strings.Builder, expect, recv []byte) {
+	fmt.Fprintf(sb, "  length: expected %d, received %d\n", len(expect), len(recv))
+
+	pos := firstDifference(len(expect), len(recv), func(i int) bool { return expect[i] == recv[i] })
+	fmt.Fprintf(sb, "  first difference at byte %d\n", pos)
+
+	start, end := window(pos, len(expect))
+	fmt.Fprintf(sb, "  expected[%d:%d]: %s\n", start, end, hex.EncodeToString(expect[start:end]))
+
+	start, end = window(pos, len(recv))
+	fmt.Fprintf(sb, "  received[%d:%d]: %s\n", start, end, hex.EncodeToString(recv[start:end]))
+}
+
+func describeString(sb *strings.Builder, expect, recv string) {
+	e, r := []rune(expect), []rune(recv)
+
+	fmt.Fprintf(sb, "  length: expected %d characters (%d bytes), received %d characters (%d bytes)\n",
+		len(e), len(expect), len(r), len(recv))
+
+	if !utf8.ValidString(recv) {
+		sb.WriteString("  received string is not valid UTF-8\n")
+	}
+
+	pos := firstDifference(len(e), len(r), func(i int) bool { return e[i] == r[i] })
+	fmt.Fprintf(sb, "  first difference at character %d\n", pos)
+
+	start, end := window(pos, len(e))
+	fmt.Fprintf(sb, "  expected[%d:%d]: %q\n", start, end, string(e[start:end]))
+
+	start, end = window(pos, len(r))
+	fmt.Fprintf(sb, "  received[%d:%d]: %q\n", start, end, string(r[start:end]))
+}
@@ -22,5 +22,38 @@ These tests should follow these constraints:
 		E.g. .Begin returns a transaction - which can be commmited or rolled back.
 		In that case both the .Commit and the .Rollback must be tested.
 
+Teardowns should be registered with TestCleanup instead of being
+deferred, so that they run in reverse order after the test and all
+failing teardowns are reported.
+
+Tests run through TestForEachDB can be executed in parallel by setting
+the environment variable INTEGRATION_PARALLEL to the maximum number of
+concurrently running tests. Each test then receives its own database.
+Setting INTEGRATION_DB_POOL reuses a pool of databases, which are
+cleaned between tests, instead of creating a database per test. The
+pooled databases are dropped by TeardownDBPool.
+
+TestForEachDB fails tests leaking connections, goroutines or temporary
+tables. The check can be disabled by setting the environment variable
+INTEGRATION_LEAK_CHECK to 'no'. Goroutines and temporary tables are
+only checked if tests are not run in parallel.
+
+All randomness of the helpers, e.g. names of databases and random
+samples, is derived from the seed in the environment variable
+INTEGRATION_SEED, which is logged when a test fails. See SuiteSeed and
+TestSeed.
+
+Slow environments can configure timeouts and retries of the helpers
+through environment variables, see CurrentSettings.
+
+TestForEachDB runs each test against a matrix of DSN variants, e.g.
+with TLS enabled or different packet sizes, if variants are registered
+with RegisterDSNVariant or passed in the environment variable
+INTEGRATION_DSN_MATRIX.
+
+Benchmarks are run through BenchmarkForEachDB. Each type provides
+DoBenchmark<Type> to measure the insert and scan throughput, while
+DoBenchmarkResultSet measures the speed of draining result sets.
+
 */
 package integration
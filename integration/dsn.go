@@ -8,10 +8,10 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
-	"log"
 	"os"
 
 	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/logging"
 )
 
 // doCallbacks return true if CGO_CALLBACKS is set to 'yes', signaling
@@ -53,7 +53,7 @@ func DSN(userstore bool) (*dsn.Info, func(), error) {
 
 	fn := func() {
 		if err := TeardownDB(info); err != nil {
-			log.Printf("failed to drop database: %s", info.Database)
+			logging.Default().Error("failed to drop database", "database", info.Database, "error", err)
 		}
 	}
 
@@ -61,11 +61,19 @@ func DSN(userstore bool) (*dsn.Info, func(), error) {
 }
 
 // genSQLDBFn is the signature of functions stored in the genSQLDBMap.
-type genSQLDBFn func() (*sql.DB, error)
+// The passed dsn.Info is either the registered one or a copy pointing
+// to an isolated database.
+type genSQLDBFn func(*dsn.Info) (*sql.DB, error)
+
+// sqlDBEntry is a registered connection type.
+type sqlDBEntry struct {
+	info *dsn.Info
+	fn   genSQLDBFn
+}
 
-// genSQLDBMap maps abstract names to functions, which are expected to
-// return unique sql.DBs.
-type genSQLDBMap map[string]genSQLDBFn
+// genSQLDBMap maps abstract names to registered connection types, whose
+// functions are expected to return unique sql.DBs.
+type genSQLDBMap map[string]sqlDBEntry
 
 var sqlDBMap = make(genSQLDBMap)
 
@@ -78,22 +86,28 @@ type ConnectorCreator func(*dsn.Info) (driver.Connector, error)
 // If connectorFn is non-nil a second genSQLDBFn is stored with the
 // suffix `connector`.
 func RegisterDSN(name string, info *dsn.Info, connectorFn ConnectorCreator) error {
-	sqlDBMap[name] = func() (*sql.DB, error) {
-		db, err := sql.Open("ase", info.AsSimple())
-		if err != nil {
-			return nil, err
-		}
-		return db, nil
-	}
-
-	if connectorFn != nil {
-		sqlDBMap[name+" connector"] = func() (*sql.DB, error) {
-			connector, err := connectorFn(info)
+	sqlDBMap[name] = sqlDBEntry{
+		info: info,
+		fn: func(info *dsn.Info) (*sql.DB, error) {
+			db, err := sql.Open("ase", info.AsSimple())
 			if err != nil {
 				return nil, err
 			}
+			return db, nil
+		},
+	}
 
-			return sql.OpenDB(connector), nil
+	if connectorFn != nil {
+		sqlDBMap[name+" connector"] = sqlDBEntry{
+			info: info,
+			fn: func(info *dsn.Info) (*sql.DB, error) {
+				connector, err := connectorFn(info)
+				if err != nil {
+					return nil, err
+				}
+
+				return sql.OpenDB(connector), nil
+			},
 		}
 	}
 
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// SplitBatches splits a script into batches separated by lines only
+// containing the isql batch terminator "go".
+func SplitBatches(script string) []string {
+	batches := []string{}
+	current := &strings.Builder{}
+
+	flush := func() {
+		if batch := strings.TrimSpace(current.String()); batch != "" {
+			batches = append(batches, batch)
+		}
+		current.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(script))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(script)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.EqualFold(strings.TrimSpace(line), "go") {
+			flush()
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	flush()
+
+	return batches
+}
+
+// LoadFixture executes the batches of sqlScript in order.
+//
+// Batches are separated by lines only containing "go", which allows
+// scripts to create procedures and triggers, which must be the only
+// statement in a batch.
+//
+// LoadFixture is LoadFixtureContext with the default deadline, see
+// DefaultSetupTimeout.
+func LoadFixture(db *sql.DB, sqlScript string) error {
+	return LoadFixtureContext(context.Background(), db, sqlScript)
+}
+
+// LoadFixtureContext executes the batches of sqlScript in order, see
+// LoadFixture.
+//
+// If ctx has no deadline DefaultSetupTimeout is applied.
+func LoadFixtureContext(ctx context.Context, db *sql.DB, sqlScript string) error {
+	ctx, cancel := withDefaultDeadline(ctx)
+	defer cancel()
+
+	for i, batch := range SplitBatches(sqlScript) {
+		if _, err := db.ExecContext(ctx, batch); err != nil {
+			return fmt.Errorf("error executing batch %d of fixture: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Migration is a named script applied by Migrate.
+type Migration struct {
+	ID     string
+	Script string
+}
+
+// checksum returns the hex-encoded SHA256 checksum of the script.
+func (migration Migration) checksum() string {
+	sum := sha256.Sum256([]byte(migration.Script))
+	return hex.EncodeToString(sum[:])
+}
+
+// migrationsTable records the applied migrations.
+const migrationsTable = "integration_migrations"
+
+// Migrate applies the passed migrations in order using LoadFixture.
+//
+// Applied migrations are recorded with the checksum of their script.
+// Migrations that have been applied before are skipped unless their
+// script changed, in which case they are applied again. Scripts of
+// migrations must therefore be idempotent.
+//
+// Migrate is MigrateContext with the default deadline, see
+// DefaultSetupTimeout.
+func Migrate(db *sql.DB, migrations ...Migration) error {
+	return MigrateContext(context.Background(), db, migrations...)
+}
+
+// MigrateContext applies the passed migrations in order using
+// LoadFixtureContext, see Migrate.
+//
+// If ctx has no deadline DefaultSetupTimeout is applied.
+func MigrateContext(ctx context.Context, db *sql.DB, migrations ...Migration) error {
+	ctx, cancel := withDefaultDeadline(ctx)
+	defer cancel()
+
+	createTable := fmt.Sprintf(`if object_id('%s') is null
+		create table %s (id varchar(255) not null, checksum varchar(64) not null)`,
+		migrationsTable, migrationsTable)
+	if _, err := db.ExecContext(ctx, createTable); err != nil {
+		return fmt.Errorf("error creating migrations table: %w", err)
+	}
+
+	for _, migration := range migrations {
+		if err := applyMigration(ctx, db, migration); err != nil {
+			return fmt.Errorf("error applying migration %s: %w", migration.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// applyMigration applies a single migration if it has not been applied
+// with the same checksum.
+func applyMigration(ctx context.Context, db *sql.DB, migration Migration) error {
+	checksum := migration.checksum()
+
+	var applied string
+	err := db.QueryRowContext(ctx, fmt.Sprintf("select checksum from %s where id = ?", migrationsTable),
+		migration.ID).Scan(&applied)
+
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return fmt.Errorf("error reading applied checksum: %w", err)
+	case applied == checksum:
+		return nil
+	}
+
+	if err := LoadFixtureContext(ctx, db, migration.Script); err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("delete from %s where id = ?", migrationsTable), migration.ID); err != nil {
+		return fmt.Errorf("error removing previous checksum: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("insert into %s (id, checksum) values (?, ?)", migrationsTable),
+		migration.ID, checksum); err != nil {
+		return fmt.Errorf("error recording checksum: %w", err)
+	}
+
+	return nil
+}
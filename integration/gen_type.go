@@ -38,11 +38,59 @@ func DoTest{{.ASEType}}(t *testing.T) {
 	// {{ end }}
 }
 
-func test{{.ASEType}}(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samples{{.ASEType}}))
-	mySamples := make([]{{.GoType}}, len(samples{{.ASEType}}))
+// DoTestPrepared{{.ASEType}} tests the handling of the {{.ASEType}}
+// when selected through prepared statements.
+func DoTestPrepared{{.ASEType}}(t *testing.T) {
+	TestForEachDB("TestPrepared{{.ASEType}}", t, testPrepared{{.ASEType}})
+}
 
+// DoBenchmark{{.ASEType}} benchmarks inserting and scanning
+// {{.ASEType}}.
+func DoBenchmark{{.ASEType}}(b *testing.B) {
+	pass := make([]interface{}, len(samples{{.ASEType}}))
 	for i, sample := range samples{{.ASEType}} {
+		{{ if .Convert }}
+		mySample, err := {{.Convert}}(sample)
+		if err != nil {
+			b.Errorf("Failed to convert sample %v: %v", sample, err)
+			return
+		}
+		pass[i] = mySample
+		{{ else }}
+		pass[i] = sample
+		{{ end }}
+	}
+
+	BenchmarkForEachDB("Benchmark{{.ASEType}}", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"{{if .ColumnDef}}{{.ColumnDef}}{{else}}{{.ASETypeLower}}{{end}}",
+				func() interface{} { return new({{.GoType}}) },
+				pass,
+			)
+		},
+	)
+}
+
+func test{{.ASEType}}(t *testing.T, db *sql.DB, tableName string) {
+	test{{.ASEType}}With(t, db, tableName, SetupTableInsert)
+}
+
+func testPrepared{{.ASEType}}(t *testing.T, db *sql.DB, tableName string) {
+	test{{.ASEType}}With(t, db, tableName, SetupTablePrepared)
+}
+
+func test{{.ASEType}}With(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samples{{.ASEType}}
+	if err := applyCustomSamples("{{.ASETypeLower}}", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]{{.GoType}}, len(samples))
+
+	for i, sample := range samples {
 		{{ if .Convert }}
 		// Convert sample with passed function before proceeding
 		mySample, err := {{.Convert}}(sample)
@@ -59,13 +107,14 @@ func test{{.ASEType}}(t *testing.T, db *sql.DB, tableName string) {
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "{{if .ColumnDef}}{{.ColumnDef}}{{else}}{{.ASETypeLower}}{{end}}", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "{{if .ColumnDef}}{{.ColumnDef}}{{else}}{{.ASETypeLower}}{{end}}", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv {{.GoType}}
@@ -80,9 +129,7 @@ func test{{.ASEType}}(t *testing.T, db *sql.DB, tableName string) {
 		{{ else }}
 		if recv != mySamples[i] {
 		{{ end }}
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	updateGoldenOnce    = &sync.Once{}
+	updateGoldenEnabled bool
+)
+
+// UpdateGolden returns true if AssertGolden writes the golden files
+// instead of comparing against them.
+//
+// Golden files are updated if the environment variable
+// INTEGRATION_GOLDEN_UPDATE is set to 'yes'.
+func UpdateGolden() bool {
+	updateGoldenOnce.Do(func() {
+		updateGoldenEnabled = os.Getenv("INTEGRATION_GOLDEN_UPDATE") == "yes"
+	})
+
+	return updateGoldenEnabled
+}
+
+// SerializeRows reads all result sets of rows and returns a
+// deterministic representation of the column names, types and values.
+func SerializeRows(rows *sql.Rows) (string, error) {
+	sb := &strings.Builder{}
+
+	for resultSet := 0; ; resultSet++ {
+		columnTypes, err := rows.ColumnTypes()
+		if err != nil {
+			return "", fmt.Errorf("error reading column types: %w", err)
+		}
+
+		fmt.Fprintf(sb, "result set %d\n", resultSet)
+		for _, columnType := range columnTypes {
+			fmt.Fprintf(sb, "column %s %s", columnType.Name(), columnType.DatabaseTypeName())
+			if nullable, ok := columnType.Nullable(); ok && nullable {
+				sb.WriteString(" null")
+			}
+			sb.WriteString("\n")
+		}
+
+		for rows.Next() {
+			values := make([]interface{}, len(columnTypes))
+			dest := make([]interface{}, len(columnTypes))
+			for i := range values {
+				dest[i] = &values[i]
+			}
+
+			if err := rows.Scan(dest...); err != nil {
+				return "", fmt.Errorf("error scanning row: %w", err)
+			}
+
+			formatted := make([]string, len(values))
+			for i, value := range values {
+				formatted[i] = formatGoldenValue(value)
+			}
+
+			fmt.Fprintf(sb, "row %s\n", strings.Join(formatted, " | "))
+		}
+
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("error reading rows: %w", err)
+	}
+
+	return sb.String(), nil
+}
+
+// formatGoldenValue returns a representation of a scanned value that
+// does not depend on the local timezone or float formatting.
+func formatGoldenValue(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return "0x" + hex.EncodeToString(typed)
+	case string:
+		return strconv.Quote(typed)
+	case time.Time:
+		return typed.UTC().Format(time.RFC3339Nano)
+	case float32:
+		return strconv.FormatFloat(float64(typed), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(typed, 'g', -1, 64)
+	default:
+		return fmt.Sprintf("%v", typed)
+	}
+}
+
+// AssertGolden compares the serialized rows with the golden file
+// testdata/<name>.golden.
+//
+// If INTEGRATION_GOLDEN_UPDATE is set to 'yes' the golden file is
+// written instead, see UpdateGolden.
+func AssertGolden(t *testing.T, rows *sql.Rows, name string) {
+	recv, err := SerializeRows(rows)
+	if err != nil {
+		t.Errorf("Failed to serialize rows: %v", err)
+		return
+	}
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if UpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Errorf("Failed to create directory for golden file: %v", err)
+			return
+		}
+
+		if err := ioutil.WriteFile(path, []byte(recv), 0644); err != nil {
+			t.Errorf("Failed to write golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("Failed to read golden file %s, set INTEGRATION_GOLDEN_UPDATE=yes to create it: %v", path, err)
+		return
+	}
+
+	if recv != string(expected) {
+		t.Errorf("Result does not match golden file %s", path)
+		t.Errorf("Expected:\n%s", expected)
+		t.Errorf("Received:\n%s", recv)
+	}
+}
@@ -6,7 +6,6 @@ package integration
 
 import (
 	"database/sql"
-	"math/rand"
 	"strconv"
 	"strings"
 	"testing"
@@ -18,27 +17,97 @@ type DBTestFunc func(t *testing.T, db *sql.DB, tableName string)
 
 // TestForEachDB runs the given DBTestFunc against all registered
 // connection types.
+//
+// If Parallel returns a limit above zero each test is marked as
+// parallel and runs against its own database, which is created before
+// and dropped after the test.
+//
+// If LeakCheck returns true tests leaking connections, goroutines or
+// temporary tables fail.
+//
+// Each test is additionally run against all DSN variants, see
+// RegisterDSNVariant.
 func TestForEachDB(testName string, t *testing.T, testFn DBTestFunc) {
-	for connectName, dbFn := range sqlDBMap {
-		db, err := dbFn()
-		if err != nil {
-			t.Errorf("Connection failed for '%s': %v", connectName, err)
-			continue
-		}
-		defer db.Close()
+	for connectName, entry := range sqlDBMap {
+		for _, variant := range getDSNVariants() {
+			connectName, entry, variant := variant.testName(connectName), entry, variant
+
+			t.Run(connectName,
+				func(t *testing.T) {
+					info, err := variant.apply(entry.info)
+					if err != nil {
+						t.Errorf("%v", err)
+						return
+					}
+
+					cleanup := TestCleanup(t)
+
+					if Parallel() > 0 {
+						t.Parallel()
+
+						cleanup.Add("release parallel slot", acquireParallelSlot())
+
+						isolated, teardownFn, err := isolatedInfo(info)
+						if err != nil {
+							t.Errorf("Failed to setup isolated database for '%s': %v", connectName, err)
+							return
+						}
+						cleanup.Add("release isolated database "+isolated.Database, teardownFn)
 
-		t.Run(connectName,
-			func(t *testing.T) {
-				testFn(t, db, strings.Replace(testName+connectName, " ", "_", -1))
-			},
-		)
+						info = isolated
+					}
+
+					db, err := entry.fn(info)
+					if err != nil {
+						t.Errorf("Connection failed for '%s': %v", connectName, err)
+						return
+					}
+
+					if err := withConnectRetries(db.Ping); err != nil {
+						db.Close()
+						t.Errorf("Connection failed for '%s': %v", connectName, err)
+						return
+					}
+
+					var snapshot *leakSnapshot
+					if LeakCheck() {
+						snapshot, err = takeLeakSnapshot(db)
+						if err != nil {
+							db.Close()
+							t.Errorf("Failed to record resources before test: %v", err)
+							return
+						}
+					}
+
+					// Teardowns registered by the test are run before
+					// the database is closed.
+					cleanup.Add("close database", func() error {
+						if snapshot != nil {
+							snapshot.checkConnLeaks(t, db)
+						}
+
+						if err := db.Close(); err != nil {
+							return err
+						}
+
+						if snapshot != nil {
+							snapshot.checkGoroutineLeaks(t)
+						}
+						return nil
+					})
+
+					testFn(t, db, strings.Replace(testName+connectName, " ", "_", -1))
+				},
+			)
+		}
 	}
 }
 
 // RandomNumber returns an unsecure random number as a string.
 //
 // This method is used to ensure random names for similar objects being
-// created for testing purposes in databases.
+// created for testing purposes in databases. The numbers are derived
+// from SuiteSeed.
 func RandomNumber() string {
-	return strconv.Itoa(rand.Int())
+	return strconv.Itoa(suiteInt())
 }
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// leakSettleTimeout is the maximum duration to wait for goroutines to
+// exit after the sql.DB was closed.
+const leakSettleTimeout = 5 * time.Second
+
+var (
+	leakCheckOnce    = &sync.Once{}
+	leakCheckEnabled bool
+)
+
+// LeakCheck returns true if TestForEachDB checks for leaked
+// connections, goroutines and temporary tables.
+//
+// The check is enabled unless the environment variable
+// INTEGRATION_LEAK_CHECK is set to 'no'.
+func LeakCheck() bool {
+	leakCheckOnce.Do(func() {
+		leakCheckEnabled = os.Getenv("INTEGRATION_LEAK_CHECK") != "no"
+	})
+
+	return leakCheckEnabled
+}
+
+// leakSnapshot records resources before a test is run.
+type leakSnapshot struct {
+	// global is true if process-wide and server-wide resources are
+	// compared, which is only reliable if tests are not run in
+	// parallel.
+	global     bool
+	goroutines int
+	tempTables int
+}
+
+// takeLeakSnapshot records the resources before the test is run. It
+// must be called before the sql.DB of the test is used.
+func takeLeakSnapshot(db *sql.DB) (*leakSnapshot, error) {
+	snapshot := &leakSnapshot{global: Parallel() == 0}
+	if !snapshot.global {
+		return snapshot, nil
+	}
+
+	snapshot.goroutines = runtime.NumGoroutine()
+
+	tempTables, err := countTempTables(db)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.tempTables = tempTables
+
+	return snapshot, nil
+}
+
+// checkConnLeaks reports connections of the db that are still in use
+// after the test returned, e.g. due to unclosed sql.Rows, sql.Conn or
+// sql.Tx, as well as temporary tables that have not been dropped.
+func (snapshot *leakSnapshot) checkConnLeaks(t *testing.T, db *sql.DB) {
+	if inUse := db.Stats().InUse; inUse > 0 {
+		t.Errorf("Leaked %d connections still in use after the test returned", inUse)
+	}
+
+	if !snapshot.global {
+		return
+	}
+
+	tempTables, err := countTempTables(db)
+	if err != nil {
+		t.Errorf("Failed to count temporary tables: %v", err)
+		return
+	}
+
+	if tempTables > snapshot.tempTables {
+		t.Errorf("Leaked %d temporary tables", tempTables-snapshot.tempTables)
+	}
+}
+
+// checkGoroutineLeaks reports goroutines that are still running after
+// the sql.DB of the test was closed.
+func (snapshot *leakSnapshot) checkGoroutineLeaks(t *testing.T) {
+	if !snapshot.global {
+		return
+	}
+
+	deadline := time.Now().Add(leakSettleTimeout)
+
+	for {
+		goroutines := runtime.NumGoroutine()
+		if goroutines <= snapshot.goroutines {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Errorf("Leaked %d goroutines after closing the database", goroutines-snapshot.goroutines)
+			return
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// countTempTables returns the number of temporary tables in tempdb.
+func countTempTables(db *sql.DB) (int, error) {
+	var count int
+	if err := db.QueryRow("select count(*) from tempdb..sysobjects where name like '#%'").Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting temporary tables: %w", err)
+	}
+
+	return count, nil
+}
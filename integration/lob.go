@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// lobTextSize is the @@textsize set for LOB tests.
+const lobTextSize = 64 * 1024
+
+// LOBBoundarySizes returns the sizes in bytes at which chunking bugs of
+// large objects are likely: around the packet size, multiple packets,
+// 16 KiB and textSize.
+//
+// Sizes are returned sorted and without duplicates.
+func LOBBoundarySizes(packetSize, textSize int) []int {
+	candidates := []int{1}
+
+	for _, boundary := range []int{
+		packetSize,
+		2 * packetSize,
+		10 * packetSize,
+		16 * 1024,
+		textSize,
+	} {
+		candidates = append(candidates, boundary-1, boundary, boundary+1)
+	}
+
+	seen := map[int]bool{}
+	sizes := []int{}
+	for _, size := range candidates {
+		if size < 1 || seen[size] {
+			continue
+		}
+		seen[size] = true
+		sizes = append(sizes, size)
+	}
+
+	sort.Ints(sizes)
+	return sizes
+}
+
+// LOBText returns a string of size bytes with a repeating pattern,
+// which allows to recognize shifted or duplicated chunks.
+func LOBText(size int) string {
+	sb := &strings.Builder{}
+	sb.Grow(size)
+
+	for i := 0; sb.Len() < size; i++ {
+		sb.WriteByte(byte('a' + i%26))
+	}
+
+	return sb.String()
+}
+
+// LOBBytes returns a byte slice of size bytes with a repeating pattern
+// without null bytes.
+func LOBBytes(size int) []byte {
+	bs := make([]byte, size)
+	for i := range bs {
+		bs[i] = byte(1 + i%255)
+	}
+
+	return bs
+}
+
+// LOBUniText returns a string of size/2 characters, which occupy size
+// bytes when encoded in UTF-16 as used by unitext.
+func LOBUniText(size int) string {
+	sb := &strings.Builder{}
+
+	for i := 0; i < size/2; i++ {
+		sb.WriteRune(rune(0x0400 + i%256))
+	}
+
+	return sb.String()
+}
+
+// lobKind describes how to generate and truncate values of a LOB type.
+type lobKind struct {
+	columnDef string
+	generate  func(size int) interface{}
+	// truncate returns value truncated to textSize bytes.
+	truncate func(value interface{}, textSize int) interface{}
+	newRecv  func() interface{}
+	compare  func(recv, expect interface{}) bool
+}
+
+var lobKinds = map[string]lobKind{
+	"text": {
+		columnDef: "text null",
+		generate:  func(size int) interface{} { return LOBText(size) },
+		truncate: func(value interface{}, textSize int) interface{} {
+			s := value.(string)
+			if len(s) > textSize {
+				s = s[:textSize]
+			}
+			return s
+		},
+		newRecv: func() interface{} { return new(string) },
+		compare: func(recv, expect interface{}) bool {
+			return compareChar(recv.(string), expect.(string))
+		},
+	},
+	"image": {
+		columnDef: "image null",
+		generate:  func(size int) interface{} { return LOBBytes(size) },
+		truncate: func(value interface{}, textSize int) interface{} {
+			bs := value.([]byte)
+			if len(bs) > textSize {
+				bs = bs[:textSize]
+			}
+			return bs
+		},
+		newRecv: func() interface{} { return new([]byte) },
+		compare: func(recv, expect interface{}) bool {
+			return compareBinary(recv.([]byte), expect.([]byte))
+		},
+	},
+	"unitext": {
+		columnDef: "unitext null",
+		generate:  func(size int) interface{} { return LOBUniText(size) },
+		truncate: func(value interface{}, textSize int) interface{} {
+			runes := []rune(value.(string))
+			if len(runes) > textSize/2 {
+				runes = runes[:textSize/2]
+			}
+			return string(runes)
+		},
+		newRecv: func() interface{} { return new(string) },
+		compare: func(recv, expect interface{}) bool {
+			return compareChar(recv.(string), expect.(string))
+		},
+	},
+}
+
+// DoTestLOB inserts and selects values of the LOB type aseType, which
+// is one of text, image and unitext, at the sizes returned by
+// LOBBoundarySizes for the negotiated packet size and a @@textsize of
+// 64 KiB.
+func DoTestLOB(t *testing.T, aseType string) {
+	kind, ok := lobKinds[strings.ToLower(aseType)]
+	if !ok {
+		t.Errorf("No LOB generator for %s", aseType)
+		return
+	}
+
+	TestForEachDB("TestLOB"+aseType, t,
+		func(t *testing.T, db *sql.DB, tableName string) {
+			testLOB(t, db, tableName, kind)
+		},
+	)
+}
+
+func testLOB(t *testing.T, db *sql.DB, tableName string, kind lobKind) {
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Errorf("Failed to open connection: %v", err)
+		return
+	}
+	cleanup := TestCleanup(t)
+	cleanup.Add("close connection", conn.Close)
+
+	var packetSize int
+	if err := conn.QueryRowContext(ctx,
+		"select network_pktsz from master..sysprocesses where spid = @@spid").Scan(&packetSize); err != nil {
+		t.Errorf("Failed to query packet size: %v", err)
+		return
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("set textsize %d", lobTextSize)); err != nil {
+		t.Errorf("Failed to set textsize: %v", err)
+		return
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("create table %s (a int, b %s)", tableName, kind.columnDef)); err != nil {
+		t.Errorf("Error creating table %s: %v", tableName, err)
+		return
+	}
+	cleanup.Add("drop table "+tableName, func() error {
+		_, err := db.Exec("drop table " + tableName)
+		return err
+	})
+
+	sizes := LOBBoundarySizes(packetSize, lobTextSize)
+
+	samples := make([]interface{}, len(sizes))
+	for i, size := range sizes {
+		samples[i] = kind.generate(size)
+
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("insert into %s (a, b) values (?, ?)", tableName),
+			i, samples[i]); err != nil {
+			t.Errorf("Failed to insert sample of %d bytes: %v", size, err)
+			return
+		}
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("select b from %s order by a", tableName))
+	if err != nil {
+		t.Errorf("Error selecting from %s: %v", tableName, err)
+		return
+	}
+	cleanup.Add("close rows", rows.Close)
+
+	i := 0
+	for rows.Next() {
+		if i >= len(samples) {
+			t.Errorf("Received more rows than samples were inserted")
+			return
+		}
+
+		recv := kind.newRecv()
+		if err := rows.Scan(recv); err != nil {
+			t.Errorf("Scan failed for sample of %d bytes: %v", sizes[i], err)
+			i++
+			continue
+		}
+
+		// Values exceeding @@textsize are truncated by the server.
+		expect := kind.truncate(samples[i], lobTextSize)
+		received := dereference(recv)
+		if kind.compare(received, expect) {
+			ReportMismatch(t, i, expect, received)
+		}
+
+		i++
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Errorf("Error reading rows: %v", err)
+	}
+
+	if i != len(samples) {
+		t.Errorf("Only read %d values from database, expected to read %d", i, len(samples))
+	}
+}
+
+// dereference returns the value the pointer recv points to.
+func dereference(recv interface{}) interface{} {
+	switch typed := recv.(type) {
+	case *string:
+		return *typed
+	case *[]byte:
+		return *typed
+	default:
+		return recv
+	}
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/logging"
+)
+
+// DSNVariantFn modifies a copy of a registered dsn.Info.
+type DSNVariantFn func(*dsn.Info) error
+
+// dsnVariant is a named modification of registered dsn.Infos.
+type dsnVariant struct {
+	name string
+	fn   DSNVariantFn
+}
+
+var (
+	dsnVariantsOnce sync.Once
+	dsnVariantsLock = &sync.Mutex{}
+	// dsnVariants always contains the unmodified default variant as
+	// first element.
+	dsnVariants = []dsnVariant{{}}
+)
+
+// RegisterDSNVariant registers a variant TestForEachDB runs each test
+// against in addition to the unmodified dsn.Info, e.g. with TLS enabled
+// or a different charset.
+//
+// RegisterDSNVariant must be called before the tests are run.
+func RegisterDSNVariant(name string, fn DSNVariantFn) {
+	dsnVariantsLock.Lock()
+	defer dsnVariantsLock.Unlock()
+
+	dsnVariants = append(dsnVariants, dsnVariant{name: name, fn: fn})
+}
+
+// getDSNVariants returns the registered variants including the
+// variants from the environment variable INTEGRATION_DSN_MATRIX.
+//
+// The variable is a semicolon separated list of variants in the form
+// <name>:<key>=<value>,<key>=<value>. The key/value pairs are set on
+// the dsn.Info using .SetField, e.g.:
+//	INTEGRATION_DSN_MATRIX="tls:tls=true,tls-skip-validation=true;iso1:charset=iso_1"
+func getDSNVariants() []dsnVariant {
+	dsnVariantsOnce.Do(func() {
+		val, ok := os.LookupEnv("INTEGRATION_DSN_MATRIX")
+		if !ok {
+			return
+		}
+
+		variants, err := parseDSNMatrix(val)
+		if err != nil {
+			logging.Default().Warn("ignoring invalid INTEGRATION_DSN_MATRIX", "error", err)
+			return
+		}
+
+		dsnVariantsLock.Lock()
+		defer dsnVariantsLock.Unlock()
+		dsnVariants = append(dsnVariants, variants...)
+	})
+
+	dsnVariantsLock.Lock()
+	defer dsnVariantsLock.Unlock()
+	return append([]dsnVariant{}, dsnVariants...)
+}
+
+// parseDSNMatrix parses the variants of INTEGRATION_DSN_MATRIX.
+func parseDSNMatrix(matrix string) ([]dsnVariant, error) {
+	variants := []dsnVariant{}
+
+	for _, entry := range strings.Split(matrix, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		split := strings.SplitN(entry, ":", 2)
+		if len(split) != 2 || split[0] == "" {
+			return nil, fmt.Errorf("variant '%s' is not in the form <name>:<key>=<value>", entry)
+		}
+
+		name, settings := split[0], map[string]string{}
+		for _, pair := range strings.Split(split[1], ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				return nil, fmt.Errorf("invalid key/value pair '%s' in variant %s", pair, name)
+			}
+			settings[kv[0]] = kv[1]
+		}
+
+		variants = append(variants, dsnVariant{
+			name: name,
+			fn: func(info *dsn.Info) error {
+				for key, value := range settings {
+					if err := info.SetField(key, value); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		})
+	}
+
+	return variants, nil
+}
+
+// apply returns a copy of info modified by the variant.
+func (variant dsnVariant) apply(info *dsn.Info) (*dsn.Info, error) {
+	if variant.fn == nil {
+		return info, nil
+	}
+
+	cp := copyInfo(info)
+	if err := variant.fn(cp); err != nil {
+		return nil, fmt.Errorf("error applying DSN variant %s: %w", variant.name, err)
+	}
+
+	return cp, nil
+}
+
+// testName returns the name of the subtest of the connection type for
+// the variant.
+func (variant dsnVariant) testName(connectName string) string {
+	if variant.name == "" {
+		return connectName
+	}
+
+	return connectName + " " + variant.name
+}
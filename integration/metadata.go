@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+// ColumnExpectation describes the expected metadata of a column as
+// reported by sql.ColumnType.
+type ColumnExpectation struct {
+	// Name is the expected name of the column.
+	Name string
+	// Def is the column definition used by DoTestColumnTypes to
+	// create the column, e.g. "varchar(30) null".
+	Def string
+	// DatabaseTypeName is compared case-insensitively. An empty value
+	// is not compared.
+	DatabaseTypeName string
+
+	// Length is only compared if HasLength is true.
+	HasLength bool
+	Length    int64
+
+	// Precision and Scale are only compared if HasPrecisionScale is
+	// true.
+	HasPrecisionScale bool
+	Precision, Scale  int64
+
+	// Nullable is only compared if HasNullable is true.
+	HasNullable bool
+	Nullable    bool
+}
+
+// AssertColumnTypes compares the column types of rows with the passed
+// expectations.
+func AssertColumnTypes(t *testing.T, rows *sql.Rows, expectations ...ColumnExpectation) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Errorf("Failed to retrieve column types: %v", err)
+		return
+	}
+
+	if len(columnTypes) != len(expectations) {
+		t.Errorf("Received %d columns, expected %d", len(columnTypes), len(expectations))
+		return
+	}
+
+	for i, columnType := range columnTypes {
+		assertColumnType(t, columnType, expectations[i])
+	}
+}
+
+func assertColumnType(t *testing.T, columnType *sql.ColumnType, expect ColumnExpectation) {
+	if !strings.EqualFold(columnType.Name(), expect.Name) {
+		t.Errorf("Column name is '%s', expected '%s'", columnType.Name(), expect.Name)
+	}
+
+	if expect.DatabaseTypeName != "" && !strings.EqualFold(columnType.DatabaseTypeName(), expect.DatabaseTypeName) {
+		t.Errorf("Column %s: database type name is '%s', expected '%s'",
+			expect.Name, columnType.DatabaseTypeName(), expect.DatabaseTypeName)
+	}
+
+	if expect.HasLength {
+		length, ok := columnType.Length()
+		if !ok {
+			t.Errorf("Column %s: length not reported, expected %d", expect.Name, expect.Length)
+		} else if length != expect.Length {
+			t.Errorf("Column %s: length is %d, expected %d", expect.Name, length, expect.Length)
+		}
+	}
+
+	if expect.HasPrecisionScale {
+		precision, scale, ok := columnType.DecimalSize()
+		if !ok {
+			t.Errorf("Column %s: precision and scale not reported, expected (%d,%d)",
+				expect.Name, expect.Precision, expect.Scale)
+		} else if precision != expect.Precision || scale != expect.Scale {
+			t.Errorf("Column %s: precision and scale are (%d,%d), expected (%d,%d)",
+				expect.Name, precision, scale, expect.Precision, expect.Scale)
+		}
+	}
+
+	if expect.HasNullable {
+		nullable, ok := columnType.Nullable()
+		if !ok {
+			t.Errorf("Column %s: nullability not reported, expected %t", expect.Name, expect.Nullable)
+		} else if nullable != expect.Nullable {
+			t.Errorf("Column %s: nullable is %t, expected %t", expect.Name, nullable, expect.Nullable)
+		}
+	}
+}
+
+// DoTestColumnTypes creates a table with the columns described by the
+// expectations and asserts that the column types of a select on the
+// table match the expectations.
+//
+// The expected database type names depend on the driver and must be
+// passed by the caller.
+func DoTestColumnTypes(t *testing.T, expectations ...ColumnExpectation) {
+	TestForEachDB("TestColumnTypes", t,
+		func(t *testing.T, db *sql.DB, tableName string) {
+			testColumnTypes(t, db, tableName, expectations)
+		},
+	)
+}
+
+func testColumnTypes(t *testing.T, db *sql.DB, tableName string, expectations []ColumnExpectation) {
+	table := NewTable(tableName)
+	for _, expect := range expectations {
+		table.Column(expect.Name, expect.Def)
+	}
+
+	teardownFn, err := table.Setup(db)
+	if err != nil {
+		t.Errorf("Error preparing table: %v", err)
+		return
+	}
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+
+	rows, err := table.Select(db)
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+	cleanup.Add("close rows", rows.Close)
+
+	AssertColumnTypes(t, rows, expectations...)
+}
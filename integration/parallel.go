@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/SAP/go-dblib/dsn"
+)
+
+var (
+	parallelOnce  sync.Once
+	parallelLimit int
+	parallelSlots chan struct{}
+)
+
+// Parallel returns the maximum number of tests TestForEachDB executes
+// in parallel.
+//
+// The limit is read once from the environment variable
+// INTEGRATION_PARALLEL. If the variable is unset, not a number or less
+// than one tests are executed sequentially against the shared database.
+func Parallel() int {
+	parallelOnce.Do(func() {
+		val, ok := os.LookupEnv("INTEGRATION_PARALLEL")
+		if !ok {
+			return
+		}
+
+		limit, err := strconv.Atoi(val)
+		if err != nil || limit < 1 {
+			return
+		}
+
+		parallelLimit = limit
+		parallelSlots = make(chan struct{}, limit)
+	})
+
+	return parallelLimit
+}
+
+// acquireParallelSlot blocks until less than Parallel() tests are
+// running and returns a function to release the acquired slot.
+func acquireParallelSlot() func() error {
+	parallelSlots <- struct{}{}
+	return func() error {
+		<-parallelSlots
+		return nil
+	}
+}
+
+// isolatedInfo returns a copy of info with a newly created database
+// and a function to drop that database.
+//
+// If DBPoolSize returns a size above zero the database is taken from
+// the pool instead and the returned function returns it to the pool.
+func isolatedInfo(info *dsn.Info) (*dsn.Info, func() error, error) {
+	if DBPoolSize() > 0 {
+		return acquirePooledDB(info)
+	}
+
+	isolated := copyInfo(info)
+
+	if err := SetupDB(isolated); err != nil {
+		return nil, nil, err
+	}
+
+	return isolated, func() error {
+		return TeardownDB(isolated)
+	}, nil
+}
+
+// copyInfo returns a copy of info that can be modified without
+// affecting info.
+func copyInfo(info *dsn.Info) *dsn.Info {
+	return info.Clone()
+}
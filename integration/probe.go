@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EnvironmentReport describes the capabilities of the server tests are
+// run against.
+type EnvironmentReport struct {
+	Version     string
+	PageSize    int
+	TextSize    int
+	Charset     string
+	Charsets    []string
+	Roles       string
+	Unicode     bool
+	ProbeErrors []error
+}
+
+// ProbeEnvironment queries the capabilities of the server.
+//
+// Failing probes do not abort probing and are recorded in
+// .ProbeErrors, as they may be caused by missing permissions.
+func ProbeEnvironment(db *sql.DB) *EnvironmentReport {
+	report := &EnvironmentReport{}
+
+	probes := []struct {
+		name  string
+		query string
+		dest  interface{}
+	}{
+		{"version", "select @@version", &report.Version},
+		{"page size", "select @@maxpagesize", &report.PageSize},
+		{"text size", "select @@textsize", &report.TextSize},
+		{"client charset", "select @@client_csname", &report.Charset},
+		{"roles", "select show_role()", &report.Roles},
+	}
+
+	for _, probe := range probes {
+		if err := db.QueryRow(probe.query).Scan(probe.dest); err != nil {
+			report.ProbeErrors = append(report.ProbeErrors,
+				fmt.Errorf("error probing %s: %w", probe.name, err))
+		}
+	}
+
+	charsets, err := probeCharsets(db)
+	if err != nil {
+		report.ProbeErrors = append(report.ProbeErrors, err)
+	}
+	report.Charsets = charsets
+
+	unicode, err := HasFeature(db, "enable unicode conversions")
+	if err != nil {
+		report.ProbeErrors = append(report.ProbeErrors, err)
+	}
+	report.Unicode = unicode
+
+	return report
+}
+
+// probeCharsets returns the names of the installed character sets.
+func probeCharsets(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("select name from master..syscharsets where type between 1000 and 1999 order by name")
+	if err != nil {
+		return nil, fmt.Errorf("error probing charsets: %w", err)
+	}
+	defer rows.Close()
+
+	charsets := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("error scanning charset: %w", err)
+		}
+		charsets = append(charsets, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading charsets: %w", err)
+	}
+
+	return charsets, nil
+}
+
+// String returns a human readable report.
+func (report EnvironmentReport) String() string {
+	sb := &strings.Builder{}
+
+	fmt.Fprintf(sb, "Server environment:\n")
+	fmt.Fprintf(sb, "  version:            %s\n", report.Version)
+	fmt.Fprintf(sb, "  page size:          %d\n", report.PageSize)
+	fmt.Fprintf(sb, "  text size:          %d\n", report.TextSize)
+	fmt.Fprintf(sb, "  client charset:     %s\n", report.Charset)
+	fmt.Fprintf(sb, "  installed charsets: %s\n", strings.Join(report.Charsets, ", "))
+	fmt.Fprintf(sb, "  unicode conversion: %t\n", report.Unicode)
+	fmt.Fprintf(sb, "  roles:              %s\n", report.Roles)
+
+	if len(report.ProbeErrors) > 0 {
+		fmt.Fprintf(sb, "  failed probes:\n")
+		for _, err := range report.ProbeErrors {
+			fmt.Fprintf(sb, "    %v\n", err)
+		}
+	}
+
+	return sb.String()
+}
+
+// WriteEnvironmentReport probes the server of the registered DSNs and
+// writes the report to w. It is intended to be called at the start of
+// the suite, e.g. in TestMain after the DSNs have been registered.
+func WriteEnvironmentReport(w io.Writer) error {
+	return withSuiteDB(func(db *sql.DB) error {
+		_, err := io.WriteString(w, ProbeEnvironment(db).String())
+		return err
+	})
+}
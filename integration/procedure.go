@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SetupProcedure creates a stored procedure with the passed name,
+// parameter declarations and body.
+//
+// params is the comma separated list of parameter declarations, e.g.
+// "@a int, @b int output", and may be empty.
+//
+// The returned function drops the procedure.
+//
+// SetupProcedure is SetupProcedureContext with the default deadline,
+// see DefaultSetupTimeout.
+func SetupProcedure(db *sql.DB, name, params, body string) (func() error, error) {
+	return SetupProcedureContext(context.Background(), db, name, params, body)
+}
+
+// SetupProcedureContext creates a stored procedure, see SetupProcedure.
+//
+// If ctx has no deadline DefaultSetupTimeout is applied. The returned
+// function drops the procedure with its own deadline, so that it
+// succeeds even if ctx is done.
+func SetupProcedureContext(ctx context.Context, db *sql.DB, name, params, body string) (func() error, error) {
+	ctx, cancel := withDefaultDeadline(ctx)
+	defer cancel()
+
+	query := "create procedure " + name
+	if params != "" {
+		query += " " + params
+	}
+	query += " as " + body
+
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to create procedure %s: %w", name, err)
+	}
+
+	teardownFn := func() error {
+		ctx, cancel := withDefaultDeadline(context.Background())
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, "drop procedure "+name)
+		return err
+	}
+
+	return teardownFn, nil
+}
+
+// procedureCall returns the statement executing the procedure with
+// a placeholder for each argument.
+func procedureCall(name string, argc int) string {
+	if argc == 0 {
+		return "exec " + name
+	}
+
+	return "exec " + name + " " + strings.TrimSuffix(strings.Repeat("?, ", argc), ", ")
+}
+
+// isOutputUnsupported reports whether err was returned by database/sql
+// because the driver does not accept sql.Out arguments, which it
+// converts with the default converter in that case.
+func isOutputUnsupported(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "unsupported type sql.Out")
+}
+
+// ExecProcedure executes the procedure through sql.DB.Exec.
+func ExecProcedure(db *sql.DB, name string, args ...interface{}) (sql.Result, error) {
+	result, err := db.Exec(procedureCall(name, len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing procedure %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// QueryProcedure executes the procedure through sql.DB.Query and
+// returns the result set of the procedure.
+func QueryProcedure(db *sql.DB, name string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := db.Query(procedureCall(name, len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying procedure %s: %w", name, err)
+	}
+
+	return rows, nil
+}
+
+// ProcedureReturnStatus executes the procedure in a language batch and
+// returns its return status.
+//
+// The arguments are inserted literally into the batch and must be
+// valid T-SQL expressions. Result sets of the procedure are discarded.
+func ProcedureReturnStatus(db *sql.DB, name string, args ...string) (int, error) {
+	query := "declare @status int exec @status = " + name
+	if len(args) > 0 {
+		query += " " + strings.Join(args, ", ")
+	}
+	query += " select @status"
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return 0, fmt.Errorf("error executing procedure %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// The return status is the last result set, preceding result
+	// sets are returned by the procedure and are discarded.
+	var last []interface{}
+	for {
+		last = nil
+
+		columns, err := rows.Columns()
+		if err != nil {
+			return 0, fmt.Errorf("error reading columns of %s: %w", name, err)
+		}
+
+		for rows.Next() {
+			values := make([]interface{}, len(columns))
+			dest := make([]interface{}, len(columns))
+			for i := range values {
+				dest[i] = &values[i]
+			}
+
+			if err := rows.Scan(dest...); err != nil {
+				return 0, fmt.Errorf("error scanning result of %s: %w", name, err)
+			}
+			last = values
+		}
+
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error reading return status of %s: %w", name, err)
+	}
+
+	if len(last) != 1 {
+		return 0, fmt.Errorf("no return status received from %s", name)
+	}
+
+	switch status := last[0].(type) {
+	case int64:
+		return int(status), nil
+	case int32:
+		return int(status), nil
+	case int:
+		return status, nil
+	default:
+		return 0, fmt.Errorf("return status of %s is of unexpected type %T", name, last[0])
+	}
+}
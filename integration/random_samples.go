@@ -0,0 +1,338 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+// RandomSampleGenerator produces random values that are valid for
+// a column type.
+type RandomSampleGenerator struct {
+	// ColumnDef is the column definition used to create the test
+	// table.
+	ColumnDef string
+	// Generate returns a random sample using the passed source of
+	// randomness.
+	Generate func(r *rand.Rand) interface{}
+	// Compare returns true if the received value does not match the
+	// expected value. If nil reflect.DeepEqual is used.
+	Compare func(recv, expect interface{}) bool
+}
+
+var randomSampleGenerators = map[string]RandomSampleGenerator{
+	"bigint": {
+		ColumnDef: "bigint",
+		Generate:  func(r *rand.Rand) interface{} { return int64(r.Uint64()) },
+	},
+	"int": {
+		ColumnDef: "int",
+		Generate:  func(r *rand.Rand) interface{} { return int32(r.Uint32()) },
+	},
+	"smallint": {
+		ColumnDef: "smallint",
+		Generate:  func(r *rand.Rand) interface{} { return int16(r.Uint32()) },
+	},
+	"tinyint": {
+		ColumnDef: "tinyint",
+		Generate:  func(r *rand.Rand) interface{} { return uint8(r.Uint32()) },
+	},
+	"unsignedbigint": {
+		ColumnDef: "unsigned bigint",
+		Generate:  func(r *rand.Rand) interface{} { return r.Uint64() },
+	},
+	"unsignedint": {
+		ColumnDef: "unsigned int",
+		Generate:  func(r *rand.Rand) interface{} { return r.Uint32() },
+	},
+	"unsignedsmallint": {
+		ColumnDef: "unsigned smallint",
+		Generate:  func(r *rand.Rand) interface{} { return uint16(r.Uint32()) },
+	},
+	"float": {
+		ColumnDef: "float",
+		Generate: func(r *rand.Rand) interface{} {
+			return randomFloat(r, 64)
+		},
+	},
+	"real": {
+		ColumnDef: "real",
+		Generate: func(r *rand.Rand) interface{} {
+			return float32(randomFloat(r, 32))
+		},
+	},
+	"bit": {
+		ColumnDef: "bit",
+		Generate:  func(r *rand.Rand) interface{} { return r.Intn(2) == 1 },
+	},
+	"decimal": {
+		ColumnDef: "decimal(38,19)",
+		Generate: func(r *rand.Rand) interface{} {
+			return randomDecimal(r, 38, 19, nil)
+		},
+		Compare: func(recv, expect interface{}) bool {
+			return compareDecimal(recv.(*asetypes.Decimal), expect.(*asetypes.Decimal))
+		},
+	},
+	"money": {
+		ColumnDef: "money",
+		Generate: func(r *rand.Rand) interface{} {
+			return randomDecimal(r, asetypes.ASEMoneyPrecision, asetypes.ASEMoneyScale,
+				big.NewInt(math.MaxInt64))
+		},
+		Compare: func(recv, expect interface{}) bool {
+			return compareDecimal(recv.(*asetypes.Decimal), expect.(*asetypes.Decimal))
+		},
+	},
+	"varchar": {
+		ColumnDef: "varchar(255) null",
+		Generate: func(r *rand.Rand) interface{} {
+			return randomString(r, 255)
+		},
+		Compare: func(recv, expect interface{}) bool {
+			return compareChar(recv.(string), expect.(string))
+		},
+	},
+	"varbinary": {
+		ColumnDef: "varbinary(255)",
+		Generate: func(r *rand.Rand) interface{} {
+			return randomBytes(r, 255)
+		},
+		Compare: func(recv, expect interface{}) bool {
+			return compareBinary(recv.([]byte), expect.([]byte))
+		},
+	},
+	"date": {
+		ColumnDef: "date",
+		Generate: func(r *rand.Rand) interface{} {
+			return randomDate(r, 1, 9999)
+		},
+		Compare: compareTimeValues(CompareTime(0)),
+	},
+	"time": {
+		ColumnDef: "time",
+		Generate: func(r *rand.Rand) interface{} {
+			return time.Time{}.Add(randomDateTimeFraction(r))
+		},
+		Compare: compareTimeValues(compareDateTime),
+	},
+	"smalldatetime": {
+		ColumnDef: "smalldatetime",
+		Generate: func(r *rand.Rand) interface{} {
+			date := randomDate(r, 1900, 2078)
+			return date.Add(time.Duration(r.Intn(24*60)) * time.Minute)
+		},
+		Compare: compareTimeValues(CompareTime(0)),
+	},
+	"datetime": {
+		ColumnDef: "datetime",
+		Generate: func(r *rand.Rand) interface{} {
+			return randomDate(r, 1753, 9999).Add(randomDateTimeFraction(r))
+		},
+		Compare: compareTimeValues(compareDateTime),
+	},
+	"bigdatetime": {
+		ColumnDef: "bigdatetime",
+		Generate: func(r *rand.Rand) interface{} {
+			date := randomDate(r, 1, 9999)
+			return date.Add(time.Duration(r.Int63n(int64(24*time.Hour/time.Microsecond))) * time.Microsecond)
+		},
+		Compare: compareTimeValues(CompareTime(0)),
+	},
+	"bigtime": {
+		ColumnDef: "bigtime",
+		Generate: func(r *rand.Rand) interface{} {
+			return time.Time{}.Add(time.Duration(r.Int63n(int64(24*time.Hour/time.Microsecond))) * time.Microsecond)
+		},
+		Compare: compareTimeValues(CompareTime(0)),
+	},
+}
+
+// RegisterRandomSampleGenerator registers a generator for aseType,
+// replacing any existing generator for the type.
+//
+// RegisterRandomSampleGenerator must be called before DoTestRandom is
+// run.
+func RegisterRandomSampleGenerator(aseType string, gen RandomSampleGenerator) {
+	randomSampleGenerators[strings.ToLower(aseType)] = gen
+}
+
+// DoTestRandom inserts iterations randomly generated samples of the
+// type into a table and compares the retrieved values with the
+// inserted samples.
+//
+// The seed is logged if the test fails, see TestSeed.
+func DoTestRandom(t *testing.T, aseType string, iterations int) {
+	gen, ok := randomSampleGenerators[strings.ToLower(aseType)]
+	if !ok {
+		t.Errorf("No random sample generator registered for %s", aseType)
+		return
+	}
+
+	TestForEachDB("TestRandom"+aseType, t,
+		func(t *testing.T, db *sql.DB, tableName string) {
+			testRandom(t, db, tableName, gen, TestRand(t), iterations)
+		},
+	)
+}
+
+func testRandom(t *testing.T, db *sql.DB, tableName string, gen RandomSampleGenerator, r *rand.Rand, iterations int) {
+	samples := make([]interface{}, iterations)
+	for i := range samples {
+		samples[i] = gen.Generate(r)
+	}
+
+	rows, teardownFn, err := SetupTableInsert(db, tableName, gen.ColumnDef, samples...)
+	if err != nil {
+		t.Errorf("Error preparing table: %v", err)
+		return
+	}
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
+
+	compare := gen.Compare
+	if compare == nil {
+		compare = func(recv, expect interface{}) bool {
+			return !reflect.DeepEqual(recv, expect)
+		}
+	}
+
+	i := 0
+	for rows.Next() {
+		if i >= len(samples) {
+			t.Errorf("Received more rows than samples were inserted")
+			return
+		}
+
+		recv := reflect.New(reflect.TypeOf(samples[i]))
+		if err := rows.Scan(recv.Interface()); err != nil {
+			t.Errorf("Scan failed on %dth scan: %v", i, err)
+			i++
+			continue
+		}
+
+		if compare(recv.Elem().Interface(), samples[i]) {
+			ReportMismatch(t, i, samples[i], recv.Elem().Interface())
+		}
+
+		i++
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Errorf("Error preparing rows: %v", err)
+	}
+
+	if i != len(samples) {
+		t.Errorf("Only read %d values from database, expected to read %d", i, len(samples))
+	}
+}
+
+// randomFloat returns a finite float with a random exponent that can
+// be represented with bitSize bits.
+func randomFloat(r *rand.Rand, bitSize int) float64 {
+	maxExp := 1023
+	if bitSize == 32 {
+		maxExp = 127
+	}
+
+	f := math.Ldexp(r.Float64(), r.Intn(2*maxExp)-maxExp)
+	if bitSize == 32 {
+		f = float64(float32(f))
+	}
+
+	if r.Intn(2) == 1 {
+		f = -f
+	}
+
+	return f
+}
+
+// randomDecimal returns a decimal with random digits that fits into
+// the passed precision and scale. If max is non-nil the absolute
+// integer representation of the decimal is less than max.
+func randomDecimal(r *rand.Rand, precision, scale int, max *big.Int) *asetypes.Decimal {
+	if max == nil {
+		max = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	}
+
+	digits := new(big.Int).Rand(r, max).String()
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	s := digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	if r.Intn(2) == 1 {
+		s = "-" + s
+	}
+
+	dec, err := asetypes.NewDecimalString(precision, scale, s)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create decimal from generated string '%s': %v", s, err))
+	}
+
+	return dec
+}
+
+// randomString returns a string of printable ASCII characters with
+// a random length up to maxLength.
+func randomString(r *rand.Rand, maxLength int) string {
+	bs := make([]byte, r.Intn(maxLength+1))
+	for i := range bs {
+		bs[i] = byte(' ' + r.Intn('~'-' '+1))
+	}
+
+	// Trailing whitespaces are trimmed by the server.
+	return strings.TrimSpace(string(bs))
+}
+
+// randomBytes returns a byte slice of random length between one and
+// maxLength. Trailing null bytes are replaced as they are trimmed by
+// the server.
+func randomBytes(r *rand.Rand, maxLength int) []byte {
+	bs := make([]byte, r.Intn(maxLength)+1)
+	r.Read(bs)
+
+	for i := len(bs) - 1; i >= 0 && bs[i] == 0; i-- {
+		bs[i] = 1
+	}
+	for i := 0; i < len(bs) && bs[i] == 0; i++ {
+		bs[i] = 1
+	}
+
+	return bs
+}
+
+// randomDate returns a date at midnight between the first day of
+// minYear and the last day of maxYear.
+func randomDate(r *rand.Rand, minYear, maxYear int) time.Time {
+	lower := time.Date(minYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	upper := time.Date(maxYear, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	// time.Time.Sub saturates at about 292 years, the difference is
+	// computed from the days since the Unix epoch instead.
+	const secondsPerDay = 60 * 60 * 24
+	days := int((upper.Unix()-lower.Unix())/secondsPerDay) + 1
+	return lower.AddDate(0, 0, r.Intn(days))
+}
+
+// randomDateTimeFraction returns a random time of day that can be
+// represented with the 1/300 second resolution of datetime and time.
+func randomDateTimeFraction(r *rand.Rand) time.Duration {
+	ticks := r.Intn(300 * 60 * 60 * 24)
+	seconds := ticks / 300
+	millis := (ticks % 300) * 10 / 3
+	return time.Duration(seconds)*time.Second + time.Duration(millis)*time.Millisecond
+}
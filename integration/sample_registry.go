@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// sampleRegistration stores samples registered for a type.
+type sampleRegistration struct {
+	replace bool
+	samples []interface{}
+}
+
+var (
+	customSamples     = map[string]*sampleRegistration{}
+	customSamplesLock = &sync.RWMutex{}
+)
+
+// RegisterSamples adds samples to the built-in samples of the type
+// tested by DoTest<aseType>. The aseType is case-insensitive, e.g.
+// "unitext" for DoTestUniText.
+//
+// The samples must have the same type as the built-in samples of the
+// type. Mismatching samples are reported as test errors when the tests
+// are run.
+//
+// RegisterSamples must be called before the DoTest functions are run.
+func RegisterSamples(aseType string, samples ...interface{}) {
+	customSamplesLock.Lock()
+	defer customSamplesLock.Unlock()
+
+	aseType = strings.ToLower(aseType)
+
+	reg, ok := customSamples[aseType]
+	if !ok {
+		reg = &sampleRegistration{}
+		customSamples[aseType] = reg
+	}
+
+	reg.samples = append(reg.samples, samples...)
+}
+
+// ReplaceSamples replaces the built-in samples and all previously
+// registered samples of the type tested by DoTest<aseType>.
+//
+// The restrictions of RegisterSamples apply.
+func ReplaceSamples(aseType string, samples ...interface{}) {
+	customSamplesLock.Lock()
+	defer customSamplesLock.Unlock()
+
+	customSamples[strings.ToLower(aseType)] = &sampleRegistration{
+		replace: true,
+		samples: samples,
+	}
+}
+
+// applyCustomSamples applies the samples registered for aseType to the
+// slice samplesPtr points to.
+//
+// The slice is replaced with a new slice, the built-in samples are not
+// modified.
+func applyCustomSamples(aseType string, samplesPtr interface{}) error {
+	customSamplesLock.RLock()
+	defer customSamplesLock.RUnlock()
+
+	reg, ok := customSamples[strings.ToLower(aseType)]
+	if !ok {
+		return nil
+	}
+
+	ptr := reflect.ValueOf(samplesPtr)
+	if ptr.Kind() != reflect.Ptr || ptr.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected pointer to slice, received %T", samplesPtr)
+	}
+
+	sliceType := ptr.Elem().Type()
+	samples := reflect.MakeSlice(sliceType, 0, ptr.Elem().Len()+len(reg.samples))
+	if !reg.replace {
+		samples = reflect.AppendSlice(samples, ptr.Elem())
+	}
+
+	for i, sample := range reg.samples {
+		value := reflect.ValueOf(sample)
+		if !value.IsValid() || !value.Type().AssignableTo(sliceType.Elem()) {
+			return fmt.Errorf("registered sample %d for %s is of type %T, expected %s",
+				i, aseType, sample, sliceType.Elem())
+		}
+		samples = reflect.Append(samples, value)
+	}
+
+	ptr.Elem().Set(samples)
+	return nil
+}
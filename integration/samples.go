@@ -5,9 +5,7 @@
 package integration
 
 import (
-	"bytes"
 	"math"
-	"strings"
 	"time"
 
 	"github.com/SAP/go-dblib/asetypes"
@@ -84,11 +82,7 @@ var samplesDecimal = []string{
 	"1234.5678",
 }
 
-func compareDecimal(recv, expect *asetypes.Decimal) bool {
-	return !expect.Cmp(*recv)
-}
-
-//go:generate go run ./gen_type.go Float float64
+//go:generate go run ./gen_type.go Float float64 -compare compareFloat
 // TODO: -null database/sql.NullFloat64
 var samplesFloat = []float64{
 	-math.SmallestNonzeroFloat64,
@@ -99,7 +93,7 @@ var samplesFloat = []float64{
 	math.MaxFloat64,
 }
 
-//go:generate go run ./gen_type.go Real float32
+//go:generate go run ./gen_type.go Real float32 -compare compareReal
 // TODO: -null database/sql.NullFloat32
 var samplesReal = []float32{
 	-math.SmallestNonzeroFloat32,
@@ -150,7 +144,7 @@ var samplesDate = []time.Time{
 	time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC),
 }
 
-//go:generate go run ./gen_type.go Time time.Time
+//go:generate go run ./gen_type.go Time time.Time -compare compareDateTime
 var samplesTime = []time.Time{
 	// Sybase & Golang zero-value; 00:00:00.00
 	time.Time{},
@@ -168,7 +162,7 @@ var samplesSmallDateTime = []time.Time{
 	time.Date(2079, time.June, 6, 23, 59, 0, 0, time.UTC),
 }
 
-//go:generate go run ./gen_type.go DateTime time.Time
+//go:generate go run ./gen_type.go DateTime time.Time -compare compareDateTime
 var samplesDateTime = []time.Time{
 	// Sybase min: January 1, 1753 Midnight
 	time.Date(1753, time.January, 1, 0, 0, 0, 0, time.UTC),
@@ -212,10 +206,6 @@ var samplesNChar = samplesChar
 // TODO: -null database/sql.NullString
 var samplesNVarChar = samplesChar
 
-func compareChar(recv, expect string) bool {
-	return strings.Compare(strings.TrimSpace(recv), expect) != 0
-}
-
 //go:generate go run ./gen_type.go Binary []byte -columndef binary(13) -compare compareBinary
 // TODO: -null github.com/SAP/go-dblib/asetypes.NullBinary
 var samplesBinary = [][]byte{
@@ -227,10 +217,6 @@ var samplesBinary = [][]byte{
 // TODO: -null github.com/SAP/go-dblib/asetypes.NullBinary
 var samplesVarBinary = samplesBinary
 
-func compareBinary(recv, expect []byte) bool {
-	return !bytes.Equal(bytes.Trim(recv, "\x00"), expect)
-}
-
 //go:generate go run ./gen_type.go Bit bool
 // Cannot be nulled
 var samplesBit = []bool{true, false}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/logging"
+)
+
+var (
+	suiteSeedOnce  sync.Once
+	suiteSeedValue int64
+
+	suiteRand     *rand.Rand
+	suiteRandLock = &sync.Mutex{}
+)
+
+// SuiteSeed returns the seed all randomness of the helpers is derived
+// from.
+//
+// The seed is read once from the environment variable INTEGRATION_SEED
+// to allow replaying failed tests and defaults to the current time.
+func SuiteSeed() int64 {
+	suiteSeedOnce.Do(func() {
+		suiteSeedValue = time.Now().UnixNano()
+
+		if val, ok := os.LookupEnv("INTEGRATION_SEED"); ok {
+			seed, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				logging.Default().Warn("ignoring invalid INTEGRATION_SEED", "value", val, "error", err)
+			} else {
+				suiteSeedValue = seed
+			}
+		}
+
+		suiteRand = rand.New(rand.NewSource(suiteSeedValue))
+	})
+
+	return suiteSeedValue
+}
+
+// TestSeed returns a seed for the test derived from SuiteSeed and the
+// name of the test, so that replaying a test with the same
+// INTEGRATION_SEED yields the same random values independent of the
+// order tests are run in.
+//
+// The suite seed is logged if the test fails.
+func TestSeed(t *testing.T) int64 {
+	seed := SuiteSeed()
+
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("Random values were derived from INTEGRATION_SEED=%d", seed)
+		}
+	})
+
+	h := fnv.New64a()
+	h.Write([]byte(t.Name()))
+	return seed ^ int64(h.Sum64())
+}
+
+// TestRand returns a source of randomness seeded with TestSeed.
+func TestRand(t *testing.T) *rand.Rand {
+	return rand.New(rand.NewSource(TestSeed(t)))
+}
+
+// suiteInt returns a non-negative random int derived from SuiteSeed.
+func suiteInt() int {
+	SuiteSeed()
+
+	suiteRandLock.Lock()
+	defer suiteRandLock.Unlock()
+
+	return suiteRand.Int()
+}
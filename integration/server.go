@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/SAP/go-dblib/version"
+)
+
+// ServerVersion is the version of an ASE server, e.g. 16.0 SP03 PL02.
+type ServerVersion = version.Version
+
+// ParseServerVersion parses a version in the form
+// "<major>.<minor>[ SP<sp>][ PL<pl>]". Additional text, e.g. the full
+// @@version string, is ignored. See version.Parse.
+func ParseServerVersion(s string) (ServerVersion, error) {
+	return version.Parse(s)
+}
+
+var (
+	serverVersionOnce sync.Once
+	serverVersion     ServerVersion
+	serverVersionErr  error
+
+	featureCache     = map[string]bool{}
+	featureCacheLock = &sync.Mutex{}
+)
+
+// CurrentServerVersion returns the version of the server of the
+// registered DSNs. The version is queried once and cached.
+func CurrentServerVersion() (ServerVersion, error) {
+	serverVersionOnce.Do(func() {
+		serverVersionErr = withSuiteDB(func(db *sql.DB) error {
+			var versionString string
+			if err := db.QueryRow("select @@version").Scan(&versionString); err != nil {
+				return fmt.Errorf("error querying @@version: %w", err)
+			}
+
+			parsed, err := ParseServerVersion(versionString)
+			if err != nil {
+				return err
+			}
+
+			serverVersion = parsed
+			return nil
+		})
+	})
+
+	return serverVersion, serverVersionErr
+}
+
+// withSuiteDB calls fn with a database opened from any registered DSN.
+func withSuiteDB(fn func(*sql.DB) error) error {
+	for _, entry := range sqlDBMap {
+		db, err := entry.fn(entry.info)
+		if err != nil {
+			return fmt.Errorf("error opening database: %w", err)
+		}
+		defer db.Close()
+
+		return fn(db)
+	}
+
+	return fmt.Errorf("no DSN registered")
+}
+
+// SkipIfVersionBelow skips the test if the server version is older
+// than minVersion, e.g. "16.0 SP03".
+func SkipIfVersionBelow(t *testing.T, minVersion string) {
+	required, err := ParseServerVersion(minVersion)
+	if err != nil {
+		t.Fatalf("Invalid required version: %v", err)
+	}
+
+	current, err := CurrentServerVersion()
+	if err != nil {
+		t.Fatalf("Failed to determine server version: %v", err)
+	}
+
+	if current.Less(required) {
+		t.Skipf("Server version %s is older than required version %s", current, required)
+	}
+}
+
+// HasFeature returns true if the configuration option feature, e.g.
+// "enable functionality group", is set to a non-zero value.
+//
+// The result is cached per feature.
+func HasFeature(db *sql.DB, feature string) (bool, error) {
+	featureCacheLock.Lock()
+	defer featureCacheLock.Unlock()
+
+	if enabled, ok := featureCache[feature]; ok {
+		return enabled, nil
+	}
+
+	var value int
+	err := db.QueryRow("select value from master..sysconfigures where name = ?", feature).Scan(&value)
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("error querying configuration option '%s': %w", feature, err)
+	}
+
+	featureCache[feature] = value != 0
+	return value != 0, nil
+}
+
+// SkipIfFeatureDisabled skips the test if the configuration option
+// feature is not enabled.
+func SkipIfFeatureDisabled(t *testing.T, db *sql.DB, feature string) {
+	enabled, err := HasFeature(db, feature)
+	if err != nil {
+		t.Fatalf("Failed to check feature: %v", err)
+	}
+
+	if !enabled {
+		t.Skipf("Configuration option '%s' is not enabled", feature)
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SAP/go-dblib/logging"
+)
+
+// Settings configures the timeouts and retries of the helpers.
+type Settings struct {
+	// StatementTimeout is the maximum duration of statements executed
+	// by the setup and teardown helpers. Zero disables the timeout.
+	StatementTimeout time.Duration
+	// ConnectRetries is the number of times establishing a connection
+	// is retried.
+	ConnectRetries int
+	// RetryBackoff is the duration to wait before the first retry.
+	// The duration is doubled for each further retry.
+	RetryBackoff time.Duration
+}
+
+var (
+	settingsOnce sync.Once
+	settings     = Settings{
+		StatementTimeout: 0,
+		ConnectRetries:   0,
+		RetryBackoff:     time.Second,
+	}
+)
+
+// CurrentSettings returns the settings used by the helpers.
+//
+// The settings are read once from the environment variables
+// INTEGRATION_STATEMENT_TIMEOUT, INTEGRATION_CONNECT_RETRIES and
+// INTEGRATION_RETRY_BACKOFF. Durations are parsed with
+// time.ParseDuration. Invalid values are logged and ignored.
+func CurrentSettings() Settings {
+	settingsOnce.Do(func() {
+		if d, ok := durationFromEnv("INTEGRATION_STATEMENT_TIMEOUT"); ok {
+			settings.StatementTimeout = d
+		}
+
+		if val, ok := os.LookupEnv("INTEGRATION_CONNECT_RETRIES"); ok {
+			retries, err := strconv.Atoi(val)
+			if err != nil || retries < 0 {
+				logging.Default().Warn("ignoring invalid INTEGRATION_CONNECT_RETRIES", "value", val)
+			} else {
+				settings.ConnectRetries = retries
+			}
+		}
+
+		if d, ok := durationFromEnv("INTEGRATION_RETRY_BACKOFF"); ok {
+			settings.RetryBackoff = d
+		}
+	})
+
+	return settings
+}
+
+// durationFromEnv parses the environment variable name as duration.
+func durationFromEnv(name string) (time.Duration, bool) {
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return 0, false
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil || d < 0 {
+		logging.Default().Warn("ignoring invalid "+name, "value", val)
+		return 0, false
+	}
+
+	return d, true
+}
+
+// DefaultSetupTimeout is the deadline applied to setup and teardown
+// helpers if neither the passed context has a deadline nor a statement
+// timeout is configured.
+const DefaultSetupTimeout = 5 * time.Minute
+
+// withDefaultDeadline returns a context derived from ctx, which is
+// limited by the statement timeout if configured and otherwise by
+// DefaultSetupTimeout if ctx has no deadline.
+func withDefaultDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout := CurrentSettings().StatementTimeout; timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, DefaultSetupTimeout)
+}
+
+// withConnectRetries calls fn until it succeeds or the configured
+// number of retries is exhausted, waiting with an exponential backoff
+// between attempts.
+func withConnectRetries(fn func() error) error {
+	s := CurrentSettings()
+	backoff := s.RetryBackoff
+
+	err := fn()
+	for retry := 0; err != nil && retry < s.ConnectRetries; retry++ {
+		time.Sleep(backoff)
+		backoff *= 2
+
+		err = fn()
+	}
+
+	if err != nil && s.ConnectRetries > 0 {
+		return fmt.Errorf("failed after %d retries: %w", s.ConnectRetries, err)
+	}
+
+	return err
+}
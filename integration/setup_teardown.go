@@ -13,34 +13,38 @@ import (
 )
 
 // SetupDB creates a database and sets .Database on the passed testDsn.
+//
+// SetupDB is SetupDBContext with the default deadline, see
+// DefaultSetupTimeout.
 func SetupDB(testDsn *dsn.Info) error {
-	db, err := sql.Open("ase", testDsn.AsSimple())
-	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
-	}
-	defer db.Close()
+	return SetupDBContext(context.Background(), testDsn)
+}
 
-	conn, err := db.Conn(context.Background())
-	if err != nil {
-		return fmt.Errorf("failed to open connection: %w", err)
-	}
-	defer conn.Close()
+// SetupDBContext creates a database and sets .Database on the passed
+// testDsn.
+//
+// If ctx has no deadline DefaultSetupTimeout is applied.
+func SetupDBContext(ctx context.Context, testDsn *dsn.Info) error {
+	ctx, cancel := withDefaultDeadline(ctx)
+	defer cancel()
 
-	if _, err := conn.ExecContext(context.Background(), "use master"); err != nil {
-		return fmt.Errorf("failed to switch context to master: %w", err)
+	conn, closeFn, err := masterConn(ctx, testDsn)
+	if err != nil {
+		return err
 	}
+	defer closeFn()
 
 	testDatabase := "test" + RandomNumber()
 
-	if _, err := conn.ExecContext(context.Background(), fmt.Sprintf("if db_id('%s') is not null drop database %s", testDatabase, testDatabase)); err != nil {
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("if db_id('%s') is not null drop database %s", testDatabase, testDatabase)); err != nil {
 		return fmt.Errorf("error on conditional drop of database: %w", err)
 	}
 
-	if _, err := conn.ExecContext(context.Background(), "create database "+testDatabase); err != nil {
+	if _, err := conn.ExecContext(ctx, "create database "+testDatabase); err != nil {
 		return fmt.Errorf("failed to create database: %w", err)
 	}
 
-	if _, err := conn.ExecContext(context.Background(), "use "+testDatabase); err != nil {
+	if _, err := conn.ExecContext(ctx, "use "+testDatabase); err != nil {
 		return fmt.Errorf("failed to switch context to %s: %w", testDatabase, err)
 	}
 
@@ -50,59 +54,190 @@ func SetupDB(testDsn *dsn.Info) error {
 
 // TeardownDB deletes the database indicated by .Database of the passed
 // testDsn and unsets the member.
+//
+// TeardownDB is TeardownDBContext with the default deadline, see
+// DefaultSetupTimeout.
 func TeardownDB(testDsn *dsn.Info) error {
-	db, err := sql.Open("ase", testDsn.AsSimple())
+	return TeardownDBContext(context.Background(), testDsn)
+}
+
+// TeardownDBContext deletes the database indicated by .Database of the
+// passed testDsn and unsets the member.
+//
+// If ctx has no deadline DefaultSetupTimeout is applied.
+func TeardownDBContext(ctx context.Context, testDsn *dsn.Info) error {
+	ctx, cancel := withDefaultDeadline(ctx)
+	defer cancel()
+
+	conn, closeFn, err := masterConn(ctx, testDsn)
 	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
+		return err
 	}
-	defer db.Close()
+	defer closeFn()
 
-	conn, err := db.Conn(context.Background())
+	if _, err := conn.ExecContext(ctx, "drop database "+testDsn.Database); err != nil {
+		return fmt.Errorf("failed to drop database: %w", err)
+	}
+
+	testDsn.Database = ""
+	return nil
+}
+
+// masterConn opens a connection to the server of info and switches to
+// the master database. The returned function closes the connection.
+func masterConn(ctx context.Context, info *dsn.Info) (*sql.Conn, func(), error) {
+	db, err := sql.Open("ase", info.AsSimple())
 	if err != nil {
-		return fmt.Errorf("failed to open connection: %w", err)
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
 	}
-	defer conn.Close()
 
-	if _, err := conn.ExecContext(context.Background(), "use master"); err != nil {
-		return fmt.Errorf("failed to switch context to master: %w", err)
+	var conn *sql.Conn
+	err = withConnectRetries(func() error {
+		var err error
+		conn, err = db.Conn(ctx)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to open connection: %w", err)
 	}
 
-	if _, err := conn.ExecContext(context.Background(), "drop database "+testDsn.Database); err != nil {
-		return fmt.Errorf("failed to drop database: %w", err)
+	closeFn := func() {
+		conn.Close()
+		db.Close()
 	}
 
-	testDsn.Database = ""
-	return nil
+	if _, err := conn.ExecContext(ctx, "use master"); err != nil {
+		closeFn()
+		return nil, nil, fmt.Errorf("failed to switch context to master: %w", err)
+	}
+
+	return conn, closeFn, nil
 }
 
+// SetupTableFunc is the signature of functions creating a table with
+// a single column of the passed type, inserting all passed samples and
+// returning the rows of the table as well as a function to drop the
+// table.
+type SetupTableFunc func(db *sql.DB, tableName, aseType string, samples ...interface{}) (*sql.Rows, func() error, error)
+
+var (
+	_ SetupTableFunc = SetupTableInsert
+	_ SetupTableFunc = SetupTablePrepared
+)
+
 // SetupTableInsert creates a table with the passed type and inserts all
 // passed samples as rows.
+//
+// SetupTableInsert is SetupTableInsertContext with the default
+// deadline, see DefaultSetupTimeout.
 func SetupTableInsert(db *sql.DB, tableName, aseType string, samples ...interface{}) (*sql.Rows, func() error, error) {
-	if _, err := db.Exec(fmt.Sprintf("create table %s (a %s)", tableName, aseType)); err != nil {
-		return nil, nil, fmt.Errorf("failed to create table: %w", err)
+	return SetupTableInsertContext(context.Background(), db, tableName, aseType, samples...)
+}
+
+// SetupTableInsertContext creates a table with the passed type and
+// inserts all passed samples as rows.
+//
+// If ctx has no deadline DefaultSetupTimeout is applied, which includes
+// reading the returned rows. The returned function drops the table
+// with its own deadline, so that it succeeds even if ctx is done.
+func SetupTableInsertContext(ctx context.Context, db *sql.DB, tableName, aseType string, samples ...interface{}) (*sql.Rows, func() error, error) {
+	ctx, cancel := withDefaultDeadline(ctx)
+
+	if err := createTableInsert(ctx, db, tableName, aseType, samples...); err != nil {
+		cancel()
+		return nil, nil, err
 	}
 
-	stmt, err := db.Prepare(fmt.Sprintf("insert into %s (a) values (?)", tableName))
+	rows, err := db.QueryContext(ctx, "select * from "+tableName)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error preparing statement: %w", err)
+		cancel()
+		return nil, nil, fmt.Errorf("error selecting from %s: %w", tableName, err)
 	}
-	defer stmt.Close()
 
-	for _, sample := range samples {
-		if _, err := stmt.Exec(sample); err != nil {
-			return nil, nil, fmt.Errorf("failed to execute prepared statement with %v: %w", sample, err)
-		}
+	teardownFn := func() error {
+		defer cancel()
+		return dropTable(db, tableName)
 	}
 
-	rows, err := db.Query("select * from " + tableName)
+	return rows, teardownFn, nil
+}
+
+// SetupTablePrepared creates a table with the passed type and inserts
+// all passed samples as rows like SetupTableInsert. The rows are
+// selected using a prepared statement, which is closed by the returned
+// function.
+//
+// SetupTablePrepared is SetupTablePreparedContext with the default
+// deadline, see DefaultSetupTimeout.
+func SetupTablePrepared(db *sql.DB, tableName, aseType string, samples ...interface{}) (*sql.Rows, func() error, error) {
+	return SetupTablePreparedContext(context.Background(), db, tableName, aseType, samples...)
+}
+
+// SetupTablePreparedContext is the context-aware variant of
+// SetupTablePrepared. The deadlines are applied like in
+// SetupTableInsertContext.
+func SetupTablePreparedContext(ctx context.Context, db *sql.DB, tableName, aseType string, samples ...interface{}) (*sql.Rows, func() error, error) {
+	ctx, cancel := withDefaultDeadline(ctx)
+
+	if err := createTableInsert(ctx, db, tableName, aseType, samples...); err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	stmt, err := db.PrepareContext(ctx, "select * from "+tableName)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error selecting from %s: %w", tableName, err)
+		cancel()
+		return nil, nil, fmt.Errorf("error preparing select from %s: %w", tableName, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		stmt.Close()
+		cancel()
+		return nil, nil, fmt.Errorf("error executing prepared select from %s: %w", tableName, err)
 	}
 
 	teardownFn := func() error {
-		_, err := db.Exec("drop table " + tableName)
-		return err
+		defer cancel()
+
+		if err := stmt.Close(); err != nil {
+			return fmt.Errorf("error closing prepared statement: %w", err)
+		}
+
+		return dropTable(db, tableName)
 	}
 
 	return rows, teardownFn, nil
 }
+
+// dropTable drops the table with the default deadline.
+func dropTable(db *sql.DB, tableName string) error {
+	ctx, cancel := withDefaultDeadline(context.Background())
+	defer cancel()
+
+	_, err := db.ExecContext(ctx, "drop table "+tableName)
+	return err
+}
+
+// createTableInsert creates a table with the passed type and inserts
+// all passed samples using a prepared statement.
+func createTableInsert(ctx context.Context, db *sql.DB, tableName, aseType string, samples ...interface{}) error {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("create table %s (a %s)", tableName, aseType)); err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	stmt, err := db.PrepareContext(ctx, fmt.Sprintf("insert into %s (a) values (?)", tableName))
+	if err != nil {
+		return fmt.Errorf("error preparing statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, sample := range samples {
+		if _, err := stmt.ExecContext(ctx, sample); err != nil {
+			return fmt.Errorf("failed to execute prepared statement with %v: %w", sample, err)
+		}
+	}
+
+	return nil
+}
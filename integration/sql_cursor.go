@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+// cursorRowCount is the number of rows in the result set fetched
+// through cursors.
+const cursorRowCount = 2500
+
+// cursorFetchSizes are the number of rows fetched per fetch.
+var cursorFetchSizes = []int{1, 7, 100, 1000, cursorRowCount + 1}
+
+// DoTestCursor runs tests for fetching result sets through cursors.
+//
+// The cursors are declared through language commands until cursor
+// support is implemented in tds, at which point the same tests cover
+// the TDS cursor protocol.
+func DoTestCursor(t *testing.T) {
+	for _, fetchSize := range cursorFetchSizes {
+		fetchSize := fetchSize
+
+		t.Run(fmt.Sprintf("fetch size %d", fetchSize),
+			func(t *testing.T) {
+				TestForEachDB(fmt.Sprintf("TestCursorFetch%d", fetchSize), t,
+					func(t *testing.T, db *sql.DB, tableName string) {
+						testCursorFetch(t, db, tableName, fetchSize)
+					},
+				)
+			},
+		)
+	}
+
+	t.Run("early close",
+		func(t *testing.T) {
+			TestForEachDB("TestCursorEarlyClose", t, testCursorEarlyClose)
+		},
+	)
+}
+
+// setupCursor creates a table with cursorRowCount rows and declares and
+// opens a cursor on the passed connection selecting all rows in order.
+//
+// The returned function closes and deallocates the cursor and drops
+// the table.
+func setupCursor(ctx context.Context, db *sql.DB, conn *sql.Conn, tableName string, fetchSize int) (string, func() error, error) {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("create table %s (a int)", tableName)); err != nil {
+		return "", nil, fmt.Errorf("failed to create table: %w", err)
+	}
+
+	dropTable := func() error {
+		_, err := db.ExecContext(ctx, "drop table "+tableName)
+		return err
+	}
+
+	fill := fmt.Sprintf(`declare @i int
+		select @i = 0
+		while @i < %d
+		begin
+			insert into %s (a) values (@i)
+			select @i = @i + 1
+		end`, cursorRowCount, tableName)
+	if _, err := db.ExecContext(ctx, fill); err != nil {
+		dropTable()
+		return "", nil, fmt.Errorf("failed to fill table: %w", err)
+	}
+
+	cursorName := "cur" + RandomNumber()
+
+	stmts := []string{
+		fmt.Sprintf("declare %s cursor for select a from %s order by a for read only", cursorName, tableName),
+		"open " + cursorName,
+		fmt.Sprintf("set cursor rows %d for %s", fetchSize, cursorName),
+	}
+
+	for _, stmt := range stmts {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			conn.ExecContext(ctx, "deallocate cursor "+cursorName)
+			dropTable()
+			return "", nil, fmt.Errorf("error executing '%s': %w", stmt, err)
+		}
+	}
+
+	teardownFn := func() error {
+		if _, err := conn.ExecContext(ctx, "close "+cursorName); err != nil {
+			return fmt.Errorf("failed to close cursor: %w", err)
+		}
+
+		if _, err := conn.ExecContext(ctx, "deallocate cursor "+cursorName); err != nil {
+			return fmt.Errorf("failed to deallocate cursor: %w", err)
+		}
+
+		return dropTable()
+	}
+
+	return cursorName, teardownFn, nil
+}
+
+// fetchCursor fetches the next chunk of rows from the cursor.
+func fetchCursor(ctx context.Context, conn *sql.Conn, cursorName string) ([]int, error) {
+	rows, err := conn.QueryContext(ctx, "fetch "+cursorName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching from cursor: %w", err)
+	}
+	defer rows.Close()
+
+	values := []int{}
+	for rows.Next() {
+		var value int
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("error scanning fetched row: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading fetched rows: %w", err)
+	}
+
+	return values, nil
+}
+
+func testCursorFetch(t *testing.T, db *sql.DB, tableName string, fetchSize int) {
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Errorf("Failed to open connection: %v", err)
+		return
+	}
+	cleanup := TestCleanup(t)
+	cleanup.Add("close connection", conn.Close)
+
+	cursorName, teardownFn, err := setupCursor(ctx, db, conn, tableName, fetchSize)
+	if err != nil {
+		t.Errorf("Error preparing cursor: %v", err)
+		return
+	}
+	cleanup.Add("close cursor", teardownFn)
+
+	expected := 0
+	for fetches := 0; ; fetches++ {
+		if fetches > cursorRowCount {
+			t.Errorf("Cursor did not finish after %d fetches", fetches)
+			return
+		}
+
+		values, err := fetchCursor(ctx, conn, cursorName)
+		if err != nil {
+			t.Errorf("%v", err)
+			return
+		}
+
+		if len(values) == 0 {
+			break
+		}
+
+		if len(values) > fetchSize {
+			t.Errorf("Received %d rows in a single fetch, expected at most %d", len(values), fetchSize)
+		}
+
+		remaining := cursorRowCount - expected
+		if len(values) < fetchSize && len(values) != remaining {
+			t.Errorf("Received %d rows in a single fetch, expected %d", len(values), fetchSize)
+		}
+
+		for _, value := range values {
+			if value != expected {
+				t.Errorf("Received value %d, expected %d", value, expected)
+				return
+			}
+			expected++
+		}
+	}
+
+	if expected != cursorRowCount {
+		t.Errorf("Only fetched %d rows, expected %d", expected, cursorRowCount)
+	}
+}
+
+func testCursorEarlyClose(t *testing.T, db *sql.DB, tableName string) {
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Errorf("Failed to open connection: %v", err)
+		return
+	}
+	TestCleanup(t).Add("close connection", conn.Close)
+
+	cursorName, teardownFn, err := setupCursor(ctx, db, conn, tableName, 10)
+	if err != nil {
+		t.Errorf("Error preparing cursor: %v", err)
+		return
+	}
+
+	values, err := fetchCursor(ctx, conn, cursorName)
+	if err != nil {
+		t.Errorf("%v", err)
+		teardownFn()
+		return
+	}
+
+	if len(values) != 10 {
+		t.Errorf("Received %d rows, expected 10", len(values))
+	}
+
+	if err := teardownFn(); err != nil {
+		t.Errorf("Failed to close cursor after partial fetch: %v", err)
+		return
+	}
+
+	// The connection must be usable after closing the cursor early.
+	var one int
+	if err := conn.QueryRowContext(ctx, "select 1").Scan(&one); err != nil {
+		t.Errorf("Connection unusable after closing cursor: %v", err)
+	}
+}
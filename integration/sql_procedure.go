@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+// DoTestSQLProcedure runs tests for calling stored procedures.
+func DoTestSQLProcedure(t *testing.T) {
+	t.Run("result set",
+		func(t *testing.T) {
+			TestForEachDB("TestSQLProcedureResultSet", t, testSQLProcedureResultSet)
+		},
+	)
+
+	t.Run("exec",
+		func(t *testing.T) {
+			TestForEachDB("TestSQLProcedureExec", t, testSQLProcedureExec)
+		},
+	)
+
+	t.Run("return status",
+		func(t *testing.T) {
+			TestForEachDB("TestSQLProcedureReturnStatus", t, testSQLProcedureReturnStatus)
+		},
+	)
+
+	t.Run("output parameter",
+		func(t *testing.T) {
+			TestForEachDB("TestSQLProcedureOutput", t, testSQLProcedureOutput)
+		},
+	)
+}
+
+func testSQLProcedureResultSet(t *testing.T, db *sql.DB, tableName string) {
+	procName := "proc" + tableName
+
+	teardownFn, err := SetupProcedure(db, procName, "@a int, @b varchar(30)",
+		"select @a * 2, @b + 'suffix'")
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+	TestCleanup(t).Add("drop procedure "+procName, teardownFn)
+
+	rows, err := QueryProcedure(db, procName, 21, "prefix")
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		t.Errorf("Procedure did not return a row: %v", rows.Err())
+		return
+	}
+
+	var a int
+	var b string
+	if err := rows.Scan(&a, &b); err != nil {
+		t.Errorf("Scan failed: %v", err)
+		return
+	}
+
+	if a != 42 || b != "prefixsuffix" {
+		t.Errorf("Received unexpected values: %d, '%s'", a, b)
+	}
+
+	if rows.Next() {
+		t.Errorf("Procedure returned more than one row")
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Errorf("Error reading rows: %v", err)
+	}
+}
+
+func testSQLProcedureExec(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int)", tableName)); err != nil {
+		t.Errorf("Error creating table %s: %v", tableName, err)
+		return
+	}
+	TestCleanup(t).Add("drop table "+tableName, func() error {
+		_, err := db.Exec("drop table " + tableName)
+		return err
+	})
+
+	procName := "proc" + tableName
+
+	teardownFn, err := SetupProcedure(db, procName, "@a int",
+		fmt.Sprintf("insert into %s (a) values (@a)", tableName))
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+	TestCleanup(t).Add("drop procedure "+procName, teardownFn)
+
+	for i := 0; i < 3; i++ {
+		if _, err := ExecProcedure(db, procName, i); err != nil {
+			t.Errorf("%v", err)
+			return
+		}
+	}
+
+	var count int
+	if err := db.QueryRow("select count(*) from " + tableName).Scan(&count); err != nil {
+		t.Errorf("Error counting rows: %v", err)
+		return
+	}
+
+	if count != 3 {
+		t.Errorf("Expected 3 rows to be inserted by procedure, found %d", count)
+	}
+}
+
+func testSQLProcedureReturnStatus(t *testing.T, db *sql.DB, tableName string) {
+	procName := "proc" + tableName
+
+	teardownFn, err := SetupProcedure(db, procName, "@a int",
+		"select 'discarded' return @a + 1")
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+	TestCleanup(t).Add("drop procedure "+procName, teardownFn)
+
+	status, err := ProcedureReturnStatus(db, procName, "41")
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	if status != 42 {
+		t.Errorf("Expected return status 42, received %d", status)
+	}
+}
+
+func testSQLProcedureOutput(t *testing.T, db *sql.DB, tableName string) {
+	procName := "proc" + tableName
+
+	teardownFn, err := SetupProcedure(db, procName, "@a int, @b int output",
+		"select @b = @a * 2")
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+	TestCleanup(t).Add("drop procedure "+procName, teardownFn)
+
+	t.Run("batch",
+		func(t *testing.T) {
+			var out int
+			query := fmt.Sprintf("declare @b int exec %s 21, @b output select @b", procName)
+			if err := db.QueryRow(query).Scan(&out); err != nil {
+				t.Errorf("Error executing procedure: %v", err)
+				return
+			}
+
+			if out != 42 {
+				t.Errorf("Expected output parameter 42, received %d", out)
+			}
+		},
+	)
+
+	t.Run("rpc",
+		func(t *testing.T) {
+			var out int
+			_, err := ExecProcedure(db, procName, 21, sql.Out{Dest: &out})
+			if isOutputUnsupported(err) {
+				t.Skipf("Output parameters are not supported by the driver: %v", err)
+			}
+
+			if err != nil {
+				t.Errorf("Error executing procedure: %v", err)
+				return
+			}
+
+			if out != 42 {
+				t.Errorf("Expected output parameter 42, received %d", out)
+			}
+		},
+	)
+}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// DoTestSQLRows runs tests for sql.Rows.
+func DoTestSQLRows(t *testing.T) {
+	t.Run("multiple columns",
+		func(t *testing.T) {
+			TestForEachDB("TestSQLRowsMultiColumn", t, testSQLRowsMultiColumn)
+		},
+	)
+}
+
+func testSQLRowsMultiColumn(t *testing.T, db *sql.DB, tableName string) {
+	date := time.Date(2020, time.April, 1, 12, 30, 0, 0, time.UTC)
+
+	table := NewTable(tableName).
+		Column("a", "int").
+		Column("b", "varchar(30) null").
+		Column("c", "float").
+		Column("d", "datetime").
+		Column("e", "varbinary(10) null").
+		Row(int32(1), "one", 1.5, date, []byte{0x1}).
+		Row(int32(2), nil, -2.25, date.AddDate(1, 0, 0), nil).
+		Row(int32(3), "three", 0.0, date.AddDate(0, 1, 0), []byte{0x1, 0x2, 0x3})
+
+	teardownFn, err := table.Setup(db)
+	if err != nil {
+		t.Errorf("Error preparing table: %v", err)
+		return
+	}
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+
+	rows, err := table.Select(db)
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+	cleanup.Add("close rows", rows.Close)
+
+	i := 0
+	for rows.Next() {
+		if i >= len(table.Rows()) {
+			t.Errorf("Received more rows than were inserted")
+			return
+		}
+
+		var a int32
+		var b sql.NullString
+		var c float64
+		var d time.Time
+		var e []byte
+
+		if err := rows.Scan(&a, &b, &c, &d, &e); err != nil {
+			t.Errorf("Scan failed on %dth scan: %v", i, err)
+			i++
+			continue
+		}
+
+		expect := table.Rows()[i]
+
+		var recvB interface{}
+		if b.Valid {
+			recvB = b.String
+		}
+
+		var recvE interface{}
+		if e != nil {
+			recvE = e
+		}
+
+		recv := []interface{}{a, recvB, c, d, recvE}
+		if !reflect.DeepEqual(recv[:3], expect[:3]) || !d.Equal(expect[3].(time.Time)) ||
+			!reflect.DeepEqual(recv[4], expect[4]) {
+			t.Errorf("Received row does not match inserted row")
+			t.Errorf("Expected: %v", expect)
+			t.Errorf("Received: %v", recv)
+		}
+
+		i++
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Errorf("Error preparing rows: %v", err)
+	}
+
+	if i != len(table.Rows()) {
+		t.Errorf("Only read %d rows from database, expected to read %d", i, len(table.Rows()))
+	}
+}
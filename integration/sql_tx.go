@@ -5,6 +5,7 @@
 package integration
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"testing"
@@ -23,6 +24,24 @@ func DoTestSQLTx(t *testing.T) {
 			TestForEachDB("TestSQLTxRollback", t, testSQLTxRollback)
 		},
 	)
+
+	t.Run("IsolationLevel",
+		func(t *testing.T) {
+			TestForEachDB("TestSQLTxIsolationLevel", t, testSQLTxIsolationLevel)
+		},
+	)
+
+	t.Run("Visibility",
+		func(t *testing.T) {
+			TestForEachDB("TestSQLTxVisibility", t, testSQLTxVisibility)
+		},
+	)
+
+	t.Run("Chained",
+		func(t *testing.T) {
+			TestForEachDB("TestSQLTxChained", t, testSQLTxChained)
+		},
+	)
 }
 
 func testSQLTxCommit(t *testing.T, db *sql.DB, tableName string) {
@@ -114,3 +133,181 @@ func testSQLTxRollback(t *testing.T, db *sql.DB, tableName string) {
 		t.Errorf("Error preparing rows: %v", err)
 	}
 }
+
+// txIsolationLevels maps the isolation levels supported by ASE to the
+// value of @@isolation in a transaction with that level.
+var txIsolationLevels = map[sql.IsolationLevel]int{
+	sql.LevelReadUncommitted: 0,
+	sql.LevelReadCommitted:   1,
+	sql.LevelRepeatableRead:  2,
+	sql.LevelSerializable:    3,
+}
+
+func testSQLTxIsolationLevel(t *testing.T, db *sql.DB, tableName string) {
+	for level, expected := range txIsolationLevels {
+		tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: level})
+		if err != nil {
+			t.Errorf("Failed to initialize transaction with isolation level %s: %v", level, err)
+			continue
+		}
+
+		var recv int
+		if err := tx.QueryRow("select @@isolation").Scan(&recv); err != nil {
+			t.Errorf("Error selecting @@isolation in transaction with isolation level %s: %v", level, err)
+		} else if recv != expected {
+			t.Errorf("Transaction with isolation level %s has unexpected @@isolation", level)
+			t.Errorf("Expected: %d", expected)
+			t.Errorf("Received: %d", recv)
+		}
+
+		if err := tx.Rollback(); err != nil {
+			t.Errorf("Error while rolling back transaction: %v", err)
+		}
+	}
+
+	if _, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelLinearizable}); err == nil {
+		t.Errorf("Expected error initializing transaction with unsupported isolation level %s",
+			sql.LevelLinearizable)
+	}
+}
+
+// countRowsReadUncommitted returns the number of rows in tableName
+// using a dedicated connection with isolation level read uncommitted,
+// so the count includes uncommitted rows without blocking.
+func countRowsReadUncommitted(db *sql.DB, tableName string) (int, error) {
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelReadUncommitted})
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow(fmt.Sprintf("select count(*) from %s", tableName)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting rows in %s: %w", tableName, err)
+	}
+
+	return count, nil
+}
+
+func testSQLTxVisibility(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int)", tableName)); err != nil {
+		t.Errorf("Error creating table %s: %v", tableName, err)
+		return
+	}
+	TestCleanup(t).Add("drop table "+tableName, func() error {
+		return dropTable(db, tableName)
+	})
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Errorf("Failed to initialize transaction: %v", err)
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(fmt.Sprintf("insert into %s (a) values (?)", tableName), 5); err != nil {
+		t.Errorf("Error inserting value in transaction: %v", err)
+		return
+	}
+
+	var inTx int
+	if err := tx.QueryRow(fmt.Sprintf("select count(*) from %s", tableName)).Scan(&inTx); err != nil {
+		t.Errorf("Error counting rows in transaction: %v", err)
+		return
+	}
+
+	if inTx != 1 {
+		t.Errorf("Inserted row is not visible in its own transaction, counted %d rows", inTx)
+	}
+
+	count, err := countRowsReadUncommitted(db, tableName)
+	if err != nil {
+		t.Errorf("Error counting uncommitted rows: %v", err)
+		return
+	}
+
+	if count != 1 {
+		t.Errorf("Uncommitted row is not visible with isolation level read uncommitted, counted %d rows", count)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Errorf("Error while rolling back transaction: %v", err)
+		return
+	}
+
+	count, err = countRowsReadUncommitted(db, tableName)
+	if err != nil {
+		t.Errorf("Error counting rows after rollback: %v", err)
+		return
+	}
+
+	if count != 0 {
+		t.Errorf("Rolled back row is still visible, counted %d rows", count)
+	}
+}
+
+func testSQLTxChained(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int)", tableName)); err != nil {
+		t.Errorf("Error creating table %s: %v", tableName, err)
+		return
+	}
+	TestCleanup(t).Add("drop table "+tableName, func() error {
+		return dropTable(db, tableName)
+	})
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Failed to open connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(context.Background(), "set chained on"); err != nil {
+		t.Errorf("Error enabling chained mode: %v", err)
+		return
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "set chained off"); err != nil {
+			t.Errorf("Error disabling chained mode: %v", err)
+		}
+	}()
+
+	var chained int
+	if err := conn.QueryRowContext(context.Background(), "select @@tranchained").Scan(&chained); err != nil {
+		t.Errorf("Error selecting @@tranchained: %v", err)
+		return
+	}
+
+	if chained != 1 {
+		t.Errorf("Connection is not in chained mode, @@tranchained is %d", chained)
+		return
+	}
+
+	// In chained mode the insert implicitly opens a transaction, which
+	// is discarded by the rollback.
+	if _, err := conn.ExecContext(context.Background(), fmt.Sprintf("insert into %s (a) values (5)", tableName)); err != nil {
+		t.Errorf("Error inserting value in chained mode: %v", err)
+		return
+	}
+
+	if _, err := conn.ExecContext(context.Background(), "rollback transaction"); err != nil {
+		t.Errorf("Error rolling back implicit transaction: %v", err)
+		return
+	}
+
+	var count int
+	if err := conn.QueryRowContext(context.Background(), fmt.Sprintf("select count(*) from %s", tableName)).Scan(&count); err != nil {
+		t.Errorf("Error counting rows: %v", err)
+		return
+	}
+
+	if count != 0 {
+		t.Errorf("Implicit transaction in chained mode was not rolled back, counted %d rows", count)
+	}
+
+	// Close the implicit transaction opened by the select before
+	// leaving chained mode.
+	if _, err := conn.ExecContext(context.Background(), "commit transaction"); err != nil {
+		t.Errorf("Error committing implicit transaction: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// StressConfig configures DoTestStress.
+type StressConfig struct {
+	// Duration is the time the goroutines issue statements.
+	// Defaults to ten seconds.
+	Duration time.Duration
+	// Parallelism is the number of goroutines sharing the sql.DB.
+	// Defaults to four times GOMAXPROCS.
+	Parallelism int
+	// WriteRatio is the ratio of writing operations between 0 and 1.
+	// Defaults to 0.5.
+	WriteRatio float64
+}
+
+// DoTestStress runs mixed reads and writes from many goroutines on the
+// same sql.DB to uncover data races in the connection and buffer
+// handling. It should be run with the race detector enabled.
+func DoTestStress(t *testing.T, config StressConfig) {
+	if config.Duration == 0 {
+		config.Duration = 10 * time.Second
+	}
+
+	if config.Parallelism == 0 {
+		config.Parallelism = 4 * runtime.GOMAXPROCS(0)
+	}
+
+	if config.WriteRatio == 0 {
+		config.WriteRatio = 0.5
+	}
+
+	TestForEachDB("TestStress", t,
+		func(t *testing.T, db *sql.DB, tableName string) {
+			testStress(t, db, tableName, config, TestSeed(t))
+		},
+	)
+}
+
+func testStress(t *testing.T, db *sql.DB, tableName string, config StressConfig, seed int64) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int, b varchar(255))", tableName)); err != nil {
+		t.Errorf("Error creating table %s: %v", tableName, err)
+		return
+	}
+	defer db.Exec("drop table " + tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Duration)
+	defer cancel()
+
+	var inserted, operations int64
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < config.Parallelism; i++ {
+		wg.Add(1)
+
+		go func(r *rand.Rand) {
+			defer wg.Done()
+
+			for ctx.Err() == nil {
+				write := r.Float64() < config.WriteRatio
+
+				n, err := stressOperation(db, tableName, r, write)
+				if err != nil {
+					t.Errorf("Operation failed: %v", err)
+					return
+				}
+
+				atomic.AddInt64(&inserted, n)
+				atomic.AddInt64(&operations, 1)
+			}
+		}(rand.New(rand.NewSource(seed + int64(i))))
+	}
+	wg.Wait()
+
+	t.Logf("Executed %d operations with %d goroutines", operations, config.Parallelism)
+
+	var count int64
+	if err := db.QueryRow("select count(*) from " + tableName).Scan(&count); err != nil {
+		t.Errorf("Error counting rows: %v", err)
+		return
+	}
+
+	if count != inserted {
+		t.Errorf("Expected %d rows, found %d", inserted, count)
+	}
+}
+
+// stressOperation executes a random reading or writing operation and
+// returns the number of rows it inserted.
+func stressOperation(db *sql.DB, tableName string, r *rand.Rand, write bool) (int64, error) {
+	if write {
+		switch r.Intn(2) {
+		case 0:
+			if _, err := db.Exec(fmt.Sprintf("insert into %s (a, b) values (?, ?)", tableName),
+				r.Int31(), randomString(r, 255)); err != nil {
+				return 0, fmt.Errorf("error inserting: %w", err)
+			}
+			return 1, nil
+		default:
+			return stressTx(db, tableName, r)
+		}
+	}
+
+	switch r.Intn(2) {
+	case 0:
+		var count int
+		if err := db.QueryRow("select count(*) from " + tableName).Scan(&count); err != nil {
+			return 0, fmt.Errorf("error counting: %w", err)
+		}
+	default:
+		rows, err := db.Query("select a, b from " + tableName)
+		if err != nil {
+			return 0, fmt.Errorf("error selecting: %w", err)
+		}
+		defer rows.Close()
+
+		// Stop reading early on occasion to test closing rows
+		// that have not been drained.
+		limit := r.Intn(100)
+		for i := 0; i < limit && rows.Next(); i++ {
+			var a int32
+			var b string
+			if err := rows.Scan(&a, &b); err != nil {
+				return 0, fmt.Errorf("error scanning: %w", err)
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("error reading rows: %w", err)
+		}
+	}
+
+	return 0, nil
+}
+
+// stressTx inserts rows in a transaction, which is either committed or
+// rolled back.
+func stressTx(db *sql.DB, tableName string, r *rand.Rand) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	n := int64(r.Intn(5) + 1)
+	for i := int64(0); i < n; i++ {
+		if _, err := tx.Exec(fmt.Sprintf("insert into %s (a, b) values (?, ?)", tableName),
+			r.Int31(), randomString(r, 255)); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("error inserting in transaction: %w", err)
+		}
+	}
+
+	if r.Intn(2) == 0 {
+		if err := tx.Rollback(); err != nil {
+			return 0, fmt.Errorf("error rolling back transaction: %w", err)
+		}
+		return 0, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return n, nil
+}
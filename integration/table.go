@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// tableOrderColumn is the name of the column added to each table
+// created by a TableBuilder to retrieve rows in insertion order.
+const tableOrderColumn = "row_order"
+
+// TableColumn is a column of a table created by a TableBuilder.
+type TableColumn struct {
+	Name string
+	// Def is the column definition, e.g. "varchar(30) null".
+	Def string
+}
+
+// TableBuilder builds tables with multiple typed columns.
+//
+// Example:
+//	table := NewTable(tableName).
+//		Column("a", "int").
+//		Column("b", "varchar(30) null").
+//		Row(1, "one").
+//		Row(2, nil)
+//
+//	teardownFn, err := table.Setup(db)
+//	...
+//	rows, err := table.Select(db)
+type TableBuilder struct {
+	name    string
+	columns []TableColumn
+	rows    [][]interface{}
+}
+
+// NewTable returns a TableBuilder for a table with the passed name.
+func NewTable(name string) *TableBuilder {
+	return &TableBuilder{name: name}
+}
+
+// Name returns the name of the table.
+func (table *TableBuilder) Name() string {
+	return table.name
+}
+
+// Columns returns the columns of the table in the order they were
+// added.
+func (table *TableBuilder) Columns() []TableColumn {
+	return table.columns
+}
+
+// Rows returns the rows added to the table.
+func (table *TableBuilder) Rows() [][]interface{} {
+	return table.rows
+}
+
+// Column adds a column with the passed name and definition.
+func (table *TableBuilder) Column(name, def string) *TableBuilder {
+	table.columns = append(table.columns, TableColumn{Name: name, Def: def})
+	return table
+}
+
+// Row adds a row. The values must be passed in the order the columns
+// were added.
+func (table *TableBuilder) Row(values ...interface{}) *TableBuilder {
+	table.rows = append(table.rows, values)
+	return table
+}
+
+// validate checks that the table has columns and that each row has
+// a value for each column.
+func (table *TableBuilder) validate() error {
+	if len(table.columns) == 0 {
+		return fmt.Errorf("table %s has no columns", table.name)
+	}
+
+	for _, column := range table.columns {
+		if strings.EqualFold(column.Name, tableOrderColumn) {
+			return fmt.Errorf("column name %s is reserved", tableOrderColumn)
+		}
+	}
+
+	for i, row := range table.rows {
+		if len(row) != len(table.columns) {
+			return fmt.Errorf("row %d has %d values, expected %d", i, len(row), len(table.columns))
+		}
+	}
+
+	return nil
+}
+
+// Setup creates the table and inserts all rows using a prepared
+// statement.
+//
+// The returned function drops the table.
+//
+// Setup is SetupContext with the default deadline, see
+// DefaultSetupTimeout.
+func (table *TableBuilder) Setup(db *sql.DB) (func() error, error) {
+	return table.SetupContext(context.Background(), db)
+}
+
+// SetupContext creates the table and inserts all rows, see Setup.
+//
+// If ctx has no deadline DefaultSetupTimeout is applied. The returned
+// function drops the table with its own deadline, so that it succeeds
+// even if ctx is done.
+func (table *TableBuilder) SetupContext(ctx context.Context, db *sql.DB) (func() error, error) {
+	ctx, cancel := withDefaultDeadline(ctx)
+	defer cancel()
+
+	if err := table.validate(); err != nil {
+		return nil, err
+	}
+
+	defs := make([]string, 0, len(table.columns)+1)
+	names := make([]string, 0, len(table.columns)+1)
+	placeholders := make([]string, 0, len(table.columns)+1)
+
+	defs = append(defs, tableOrderColumn+" int")
+	names = append(names, tableOrderColumn)
+	placeholders = append(placeholders, "?")
+
+	for _, column := range table.columns {
+		defs = append(defs, column.Name+" "+column.Def)
+		names = append(names, column.Name)
+		placeholders = append(placeholders, "?")
+	}
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("create table %s (%s)", table.name, strings.Join(defs, ", "))); err != nil {
+		return nil, fmt.Errorf("failed to create table: %w", err)
+	}
+
+	teardownFn := func() error {
+		return dropTable(db, table.name)
+	}
+
+	stmt, err := db.PrepareContext(ctx, fmt.Sprintf("insert into %s (%s) values (%s)",
+		table.name, strings.Join(names, ", "), strings.Join(placeholders, ", ")))
+	if err != nil {
+		teardownFn()
+		return nil, fmt.Errorf("error preparing statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, row := range table.rows {
+		args := make([]interface{}, 0, len(row)+1)
+		args = append(args, i)
+		args = append(args, row...)
+
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			teardownFn()
+			return nil, fmt.Errorf("failed to insert row %d %v: %w", i, row, err)
+		}
+	}
+
+	return teardownFn, nil
+}
+
+// Select returns the rows of the table in the order they were added.
+// The columns are returned in the order they were added, the ordering
+// column is not returned.
+func (table *TableBuilder) Select(db *sql.DB) (*sql.Rows, error) {
+	names := make([]string, len(table.columns))
+	for i, column := range table.columns {
+		names[i] = column.Name
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select %s from %s order by %s",
+		strings.Join(names, ", "), table.name, tableOrderColumn))
+	if err != nil {
+		return nil, fmt.Errorf("error selecting from %s: %w", table.name, err)
+	}
+
+	return rows, nil
+}
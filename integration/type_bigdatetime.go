@@ -20,25 +20,67 @@ func DoTestBigDateTime(t *testing.T) {
 	//
 }
 
-func testBigDateTime(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesBigDateTime))
-	mySamples := make([]time.Time, len(samplesBigDateTime))
+// DoTestPreparedBigDateTime tests the handling of the BigDateTime
+// when selected through prepared statements.
+func DoTestPreparedBigDateTime(t *testing.T) {
+	TestForEachDB("TestPreparedBigDateTime", t, testPreparedBigDateTime)
+}
 
+// DoBenchmarkBigDateTime benchmarks inserting and scanning
+// BigDateTime.
+func DoBenchmarkBigDateTime(b *testing.B) {
+	pass := make([]interface{}, len(samplesBigDateTime))
 	for i, sample := range samplesBigDateTime {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkBigDateTime", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"bigdatetime",
+				func() interface{} { return new(time.Time) },
+				pass,
+			)
+		},
+	)
+}
+
+func testBigDateTime(t *testing.T, db *sql.DB, tableName string) {
+	testBigDateTimeWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedBigDateTime(t *testing.T, db *sql.DB, tableName string) {
+	testBigDateTimeWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testBigDateTimeWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesBigDateTime
+	if err := applyCustomSamples("bigdatetime", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]time.Time, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "bigdatetime", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "bigdatetime", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv time.Time
@@ -50,9 +92,7 @@ func testBigDateTime(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -18,25 +18,67 @@ func DoTestBigInt(t *testing.T) {
 	//
 }
 
-func testBigInt(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesBigInt))
-	mySamples := make([]int64, len(samplesBigInt))
+// DoTestPreparedBigInt tests the handling of the BigInt
+// when selected through prepared statements.
+func DoTestPreparedBigInt(t *testing.T) {
+	TestForEachDB("TestPreparedBigInt", t, testPreparedBigInt)
+}
 
+// DoBenchmarkBigInt benchmarks inserting and scanning
+// BigInt.
+func DoBenchmarkBigInt(b *testing.B) {
+	pass := make([]interface{}, len(samplesBigInt))
 	for i, sample := range samplesBigInt {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkBigInt", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"bigint",
+				func() interface{} { return new(int64) },
+				pass,
+			)
+		},
+	)
+}
+
+func testBigInt(t *testing.T, db *sql.DB, tableName string) {
+	testBigIntWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedBigInt(t *testing.T, db *sql.DB, tableName string) {
+	testBigIntWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testBigIntWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesBigInt
+	if err := applyCustomSamples("bigint", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]int64, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "bigint", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "bigint", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv int64
@@ -48,9 +90,7 @@ func testBigInt(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -20,25 +20,67 @@ func DoTestBigTime(t *testing.T) {
 	//
 }
 
-func testBigTime(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesBigTime))
-	mySamples := make([]time.Time, len(samplesBigTime))
+// DoTestPreparedBigTime tests the handling of the BigTime
+// when selected through prepared statements.
+func DoTestPreparedBigTime(t *testing.T) {
+	TestForEachDB("TestPreparedBigTime", t, testPreparedBigTime)
+}
 
+// DoBenchmarkBigTime benchmarks inserting and scanning
+// BigTime.
+func DoBenchmarkBigTime(b *testing.B) {
+	pass := make([]interface{}, len(samplesBigTime))
 	for i, sample := range samplesBigTime {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkBigTime", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"bigtime",
+				func() interface{} { return new(time.Time) },
+				pass,
+			)
+		},
+	)
+}
+
+func testBigTime(t *testing.T, db *sql.DB, tableName string) {
+	testBigTimeWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedBigTime(t *testing.T, db *sql.DB, tableName string) {
+	testBigTimeWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testBigTimeWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesBigTime
+	if err := applyCustomSamples("bigtime", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]time.Time, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "bigtime", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "bigtime", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv time.Time
@@ -50,9 +92,7 @@ func testBigTime(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -18,25 +18,67 @@ func DoTestBinary(t *testing.T) {
 	//
 }
 
-func testBinary(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesBinary))
-	mySamples := make([][]byte, len(samplesBinary))
+// DoTestPreparedBinary tests the handling of the Binary
+// when selected through prepared statements.
+func DoTestPreparedBinary(t *testing.T) {
+	TestForEachDB("TestPreparedBinary", t, testPreparedBinary)
+}
 
+// DoBenchmarkBinary benchmarks inserting and scanning
+// Binary.
+func DoBenchmarkBinary(b *testing.B) {
+	pass := make([]interface{}, len(samplesBinary))
 	for i, sample := range samplesBinary {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkBinary", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"binary(13)",
+				func() interface{} { return new([]byte) },
+				pass,
+			)
+		},
+	)
+}
+
+func testBinary(t *testing.T, db *sql.DB, tableName string) {
+	testBinaryWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedBinary(t *testing.T, db *sql.DB, tableName string) {
+	testBinaryWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testBinaryWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesBinary
+	if err := applyCustomSamples("binary", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([][]byte, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "binary(13)", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "binary(13)", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv []byte
@@ -48,9 +90,7 @@ func testBinary(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareBinary(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -18,25 +18,67 @@ func DoTestBit(t *testing.T) {
 	//
 }
 
-func testBit(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesBit))
-	mySamples := make([]bool, len(samplesBit))
+// DoTestPreparedBit tests the handling of the Bit
+// when selected through prepared statements.
+func DoTestPreparedBit(t *testing.T) {
+	TestForEachDB("TestPreparedBit", t, testPreparedBit)
+}
 
+// DoBenchmarkBit benchmarks inserting and scanning
+// Bit.
+func DoBenchmarkBit(b *testing.B) {
+	pass := make([]interface{}, len(samplesBit))
 	for i, sample := range samplesBit {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkBit", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"bit",
+				func() interface{} { return new(bool) },
+				pass,
+			)
+		},
+	)
+}
+
+func testBit(t *testing.T, db *sql.DB, tableName string) {
+	testBitWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedBit(t *testing.T, db *sql.DB, tableName string) {
+	testBitWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testBitWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesBit
+	if err := applyCustomSamples("bit", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]bool, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "bit", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "bit", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv bool
@@ -48,9 +90,7 @@ func testBit(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
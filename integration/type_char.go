@@ -18,25 +18,67 @@ func DoTestChar(t *testing.T) {
 	//
 }
 
-func testChar(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesChar))
-	mySamples := make([]string, len(samplesChar))
+// DoTestPreparedChar tests the handling of the Char
+// when selected through prepared statements.
+func DoTestPreparedChar(t *testing.T) {
+	TestForEachDB("TestPreparedChar", t, testPreparedChar)
+}
 
+// DoBenchmarkChar benchmarks inserting and scanning
+// Char.
+func DoBenchmarkChar(b *testing.B) {
+	pass := make([]interface{}, len(samplesChar))
 	for i, sample := range samplesChar {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkChar", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"char(13) null",
+				func() interface{} { return new(string) },
+				pass,
+			)
+		},
+	)
+}
+
+func testChar(t *testing.T, db *sql.DB, tableName string) {
+	testCharWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedChar(t *testing.T, db *sql.DB, tableName string) {
+	testCharWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testCharWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesChar
+	if err := applyCustomSamples("char", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]string, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "char(13) null", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "char(13) null", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv string
@@ -48,9 +90,7 @@ func testChar(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareChar(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -20,25 +20,67 @@ func DoTestDate(t *testing.T) {
 	//
 }
 
-func testDate(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesDate))
-	mySamples := make([]time.Time, len(samplesDate))
+// DoTestPreparedDate tests the handling of the Date
+// when selected through prepared statements.
+func DoTestPreparedDate(t *testing.T) {
+	TestForEachDB("TestPreparedDate", t, testPreparedDate)
+}
 
+// DoBenchmarkDate benchmarks inserting and scanning
+// Date.
+func DoBenchmarkDate(b *testing.B) {
+	pass := make([]interface{}, len(samplesDate))
 	for i, sample := range samplesDate {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkDate", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"date",
+				func() interface{} { return new(time.Time) },
+				pass,
+			)
+		},
+	)
+}
+
+func testDate(t *testing.T, db *sql.DB, tableName string) {
+	testDateWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedDate(t *testing.T, db *sql.DB, tableName string) {
+	testDateWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testDateWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesDate
+	if err := applyCustomSamples("date", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]time.Time, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "date", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "date", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv time.Time
@@ -50,9 +92,7 @@ func testDate(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -20,12 +20,58 @@ func DoTestDecimal(t *testing.T) {
 	//
 }
 
-func testDecimal(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesDecimal))
-	mySamples := make([]*asetypes.Decimal, len(samplesDecimal))
+// DoTestPreparedDecimal tests the handling of the Decimal
+// when selected through prepared statements.
+func DoTestPreparedDecimal(t *testing.T) {
+	TestForEachDB("TestPreparedDecimal", t, testPreparedDecimal)
+}
 
+// DoBenchmarkDecimal benchmarks inserting and scanning
+// Decimal.
+func DoBenchmarkDecimal(b *testing.B) {
+	pass := make([]interface{}, len(samplesDecimal))
 	for i, sample := range samplesDecimal {
 
+		mySample, err := convertDecimal3819(sample)
+		if err != nil {
+			b.Errorf("Failed to convert sample %v: %v", sample, err)
+			return
+		}
+		pass[i] = mySample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkDecimal", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"decimal(38,19)",
+				func() interface{} { return new(*asetypes.Decimal) },
+				pass,
+			)
+		},
+	)
+}
+
+func testDecimal(t *testing.T, db *sql.DB, tableName string) {
+	testDecimalWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedDecimal(t *testing.T, db *sql.DB, tableName string) {
+	testDecimalWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testDecimalWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesDecimal
+	if err := applyCustomSamples("decimal", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]*asetypes.Decimal, len(samples))
+
+	for i, sample := range samples {
+
 		// Convert sample with passed function before proceeding
 		mySample, err := convertDecimal3819(sample)
 		if err != nil {
@@ -37,13 +83,14 @@ func testDecimal(t *testing.T, db *sql.DB, tableName string) {
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "decimal(38,19)", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "decimal(38,19)", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv *asetypes.Decimal
@@ -55,9 +102,7 @@ func testDecimal(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareDecimal(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -20,12 +20,58 @@ func DoTestDecimal10(t *testing.T) {
 	//
 }
 
-func testDecimal10(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesDecimal10))
-	mySamples := make([]*asetypes.Decimal, len(samplesDecimal10))
+// DoTestPreparedDecimal10 tests the handling of the Decimal10
+// when selected through prepared statements.
+func DoTestPreparedDecimal10(t *testing.T) {
+	TestForEachDB("TestPreparedDecimal10", t, testPreparedDecimal10)
+}
 
+// DoBenchmarkDecimal10 benchmarks inserting and scanning
+// Decimal10.
+func DoBenchmarkDecimal10(b *testing.B) {
+	pass := make([]interface{}, len(samplesDecimal10))
 	for i, sample := range samplesDecimal10 {
 
+		mySample, err := convertDecimal10(sample)
+		if err != nil {
+			b.Errorf("Failed to convert sample %v: %v", sample, err)
+			return
+		}
+		pass[i] = mySample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkDecimal10", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"decimal(1,0)",
+				func() interface{} { return new(*asetypes.Decimal) },
+				pass,
+			)
+		},
+	)
+}
+
+func testDecimal10(t *testing.T, db *sql.DB, tableName string) {
+	testDecimal10With(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedDecimal10(t *testing.T, db *sql.DB, tableName string) {
+	testDecimal10With(t, db, tableName, SetupTablePrepared)
+}
+
+func testDecimal10With(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesDecimal10
+	if err := applyCustomSamples("decimal10", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]*asetypes.Decimal, len(samples))
+
+	for i, sample := range samples {
+
 		// Convert sample with passed function before proceeding
 		mySample, err := convertDecimal10(sample)
 		if err != nil {
@@ -37,13 +83,14 @@ func testDecimal10(t *testing.T, db *sql.DB, tableName string) {
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "decimal(1,0)", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "decimal(1,0)", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv *asetypes.Decimal
@@ -55,9 +102,7 @@ func testDecimal10(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareDecimal(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
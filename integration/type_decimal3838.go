@@ -20,12 +20,58 @@ func DoTestDecimal3838(t *testing.T) {
 	//
 }
 
-func testDecimal3838(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesDecimal3838))
-	mySamples := make([]*asetypes.Decimal, len(samplesDecimal3838))
+// DoTestPreparedDecimal3838 tests the handling of the Decimal3838
+// when selected through prepared statements.
+func DoTestPreparedDecimal3838(t *testing.T) {
+	TestForEachDB("TestPreparedDecimal3838", t, testPreparedDecimal3838)
+}
 
+// DoBenchmarkDecimal3838 benchmarks inserting and scanning
+// Decimal3838.
+func DoBenchmarkDecimal3838(b *testing.B) {
+	pass := make([]interface{}, len(samplesDecimal3838))
 	for i, sample := range samplesDecimal3838 {
 
+		mySample, err := convertDecimal3838(sample)
+		if err != nil {
+			b.Errorf("Failed to convert sample %v: %v", sample, err)
+			return
+		}
+		pass[i] = mySample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkDecimal3838", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"decimal(38,38)",
+				func() interface{} { return new(*asetypes.Decimal) },
+				pass,
+			)
+		},
+	)
+}
+
+func testDecimal3838(t *testing.T, db *sql.DB, tableName string) {
+	testDecimal3838With(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedDecimal3838(t *testing.T, db *sql.DB, tableName string) {
+	testDecimal3838With(t, db, tableName, SetupTablePrepared)
+}
+
+func testDecimal3838With(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesDecimal3838
+	if err := applyCustomSamples("decimal3838", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]*asetypes.Decimal, len(samples))
+
+	for i, sample := range samples {
+
 		// Convert sample with passed function before proceeding
 		mySample, err := convertDecimal3838(sample)
 		if err != nil {
@@ -37,13 +83,14 @@ func testDecimal3838(t *testing.T, db *sql.DB, tableName string) {
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "decimal(38,38)", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "decimal(38,38)", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv *asetypes.Decimal
@@ -55,9 +102,7 @@ func testDecimal3838(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareDecimal(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
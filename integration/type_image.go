@@ -18,25 +18,67 @@ func DoTestImage(t *testing.T) {
 	//
 }
 
-func testImage(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesImage))
-	mySamples := make([][]byte, len(samplesImage))
+// DoTestPreparedImage tests the handling of the Image
+// when selected through prepared statements.
+func DoTestPreparedImage(t *testing.T) {
+	TestForEachDB("TestPreparedImage", t, testPreparedImage)
+}
 
+// DoBenchmarkImage benchmarks inserting and scanning
+// Image.
+func DoBenchmarkImage(b *testing.B) {
+	pass := make([]interface{}, len(samplesImage))
 	for i, sample := range samplesImage {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkImage", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"image",
+				func() interface{} { return new([]byte) },
+				pass,
+			)
+		},
+	)
+}
+
+func testImage(t *testing.T, db *sql.DB, tableName string) {
+	testImageWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedImage(t *testing.T, db *sql.DB, tableName string) {
+	testImageWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testImageWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesImage
+	if err := applyCustomSamples("image", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([][]byte, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "image", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "image", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv []byte
@@ -48,9 +90,7 @@ func testImage(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareBinary(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
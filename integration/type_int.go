@@ -18,25 +18,67 @@ func DoTestInt(t *testing.T) {
 	//
 }
 
-func testInt(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesInt))
-	mySamples := make([]int32, len(samplesInt))
+// DoTestPreparedInt tests the handling of the Int
+// when selected through prepared statements.
+func DoTestPreparedInt(t *testing.T) {
+	TestForEachDB("TestPreparedInt", t, testPreparedInt)
+}
 
+// DoBenchmarkInt benchmarks inserting and scanning
+// Int.
+func DoBenchmarkInt(b *testing.B) {
+	pass := make([]interface{}, len(samplesInt))
 	for i, sample := range samplesInt {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkInt", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"int",
+				func() interface{} { return new(int32) },
+				pass,
+			)
+		},
+	)
+}
+
+func testInt(t *testing.T, db *sql.DB, tableName string) {
+	testIntWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedInt(t *testing.T, db *sql.DB, tableName string) {
+	testIntWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testIntWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesInt
+	if err := applyCustomSamples("int", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]int32, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "int", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "int", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv int32
@@ -48,9 +90,7 @@ func testInt(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
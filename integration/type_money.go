@@ -20,12 +20,58 @@ func DoTestMoney(t *testing.T) {
 	//
 }
 
-func testMoney(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesMoney))
-	mySamples := make([]*asetypes.Decimal, len(samplesMoney))
+// DoTestPreparedMoney tests the handling of the Money
+// when selected through prepared statements.
+func DoTestPreparedMoney(t *testing.T) {
+	TestForEachDB("TestPreparedMoney", t, testPreparedMoney)
+}
 
+// DoBenchmarkMoney benchmarks inserting and scanning
+// Money.
+func DoBenchmarkMoney(b *testing.B) {
+	pass := make([]interface{}, len(samplesMoney))
 	for i, sample := range samplesMoney {
 
+		mySample, err := convertMoney(sample)
+		if err != nil {
+			b.Errorf("Failed to convert sample %v: %v", sample, err)
+			return
+		}
+		pass[i] = mySample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkMoney", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"money",
+				func() interface{} { return new(*asetypes.Decimal) },
+				pass,
+			)
+		},
+	)
+}
+
+func testMoney(t *testing.T, db *sql.DB, tableName string) {
+	testMoneyWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedMoney(t *testing.T, db *sql.DB, tableName string) {
+	testMoneyWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testMoneyWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesMoney
+	if err := applyCustomSamples("money", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]*asetypes.Decimal, len(samples))
+
+	for i, sample := range samples {
+
 		// Convert sample with passed function before proceeding
 		mySample, err := convertMoney(sample)
 		if err != nil {
@@ -37,13 +83,14 @@ func testMoney(t *testing.T, db *sql.DB, tableName string) {
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "money", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "money", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv *asetypes.Decimal
@@ -55,9 +102,7 @@ func testMoney(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareDecimal(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -20,12 +20,58 @@ func DoTestMoney4(t *testing.T) {
 	//
 }
 
-func testMoney4(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesMoney4))
-	mySamples := make([]*asetypes.Decimal, len(samplesMoney4))
+// DoTestPreparedMoney4 tests the handling of the Money4
+// when selected through prepared statements.
+func DoTestPreparedMoney4(t *testing.T) {
+	TestForEachDB("TestPreparedMoney4", t, testPreparedMoney4)
+}
 
+// DoBenchmarkMoney4 benchmarks inserting and scanning
+// Money4.
+func DoBenchmarkMoney4(b *testing.B) {
+	pass := make([]interface{}, len(samplesMoney4))
 	for i, sample := range samplesMoney4 {
 
+		mySample, err := convertSmallMoney(sample)
+		if err != nil {
+			b.Errorf("Failed to convert sample %v: %v", sample, err)
+			return
+		}
+		pass[i] = mySample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkMoney4", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"smallmoney",
+				func() interface{} { return new(*asetypes.Decimal) },
+				pass,
+			)
+		},
+	)
+}
+
+func testMoney4(t *testing.T, db *sql.DB, tableName string) {
+	testMoney4With(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedMoney4(t *testing.T, db *sql.DB, tableName string) {
+	testMoney4With(t, db, tableName, SetupTablePrepared)
+}
+
+func testMoney4With(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesMoney4
+	if err := applyCustomSamples("money4", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]*asetypes.Decimal, len(samples))
+
+	for i, sample := range samples {
+
 		// Convert sample with passed function before proceeding
 		mySample, err := convertSmallMoney(sample)
 		if err != nil {
@@ -37,13 +83,14 @@ func testMoney4(t *testing.T, db *sql.DB, tableName string) {
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "smallmoney", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "smallmoney", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv *asetypes.Decimal
@@ -55,9 +102,7 @@ func testMoney4(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareDecimal(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -18,25 +18,67 @@ func DoTestNChar(t *testing.T) {
 	//
 }
 
-func testNChar(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesNChar))
-	mySamples := make([]string, len(samplesNChar))
+// DoTestPreparedNChar tests the handling of the NChar
+// when selected through prepared statements.
+func DoTestPreparedNChar(t *testing.T) {
+	TestForEachDB("TestPreparedNChar", t, testPreparedNChar)
+}
 
+// DoBenchmarkNChar benchmarks inserting and scanning
+// NChar.
+func DoBenchmarkNChar(b *testing.B) {
+	pass := make([]interface{}, len(samplesNChar))
 	for i, sample := range samplesNChar {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkNChar", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"nchar(13) null",
+				func() interface{} { return new(string) },
+				pass,
+			)
+		},
+	)
+}
+
+func testNChar(t *testing.T, db *sql.DB, tableName string) {
+	testNCharWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedNChar(t *testing.T, db *sql.DB, tableName string) {
+	testNCharWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testNCharWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesNChar
+	if err := applyCustomSamples("nchar", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]string, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "nchar(13) null", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "nchar(13) null", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv string
@@ -48,9 +90,7 @@ func testNChar(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareChar(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -18,25 +18,67 @@ func DoTestNVarChar(t *testing.T) {
 	//
 }
 
-func testNVarChar(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesNVarChar))
-	mySamples := make([]string, len(samplesNVarChar))
+// DoTestPreparedNVarChar tests the handling of the NVarChar
+// when selected through prepared statements.
+func DoTestPreparedNVarChar(t *testing.T) {
+	TestForEachDB("TestPreparedNVarChar", t, testPreparedNVarChar)
+}
 
+// DoBenchmarkNVarChar benchmarks inserting and scanning
+// NVarChar.
+func DoBenchmarkNVarChar(b *testing.B) {
+	pass := make([]interface{}, len(samplesNVarChar))
 	for i, sample := range samplesNVarChar {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkNVarChar", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"nvarchar(13) null",
+				func() interface{} { return new(string) },
+				pass,
+			)
+		},
+	)
+}
+
+func testNVarChar(t *testing.T, db *sql.DB, tableName string) {
+	testNVarCharWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedNVarChar(t *testing.T, db *sql.DB, tableName string) {
+	testNVarCharWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testNVarCharWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesNVarChar
+	if err := applyCustomSamples("nvarchar", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]string, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "nvarchar(13) null", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "nvarchar(13) null", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv string
@@ -48,9 +90,7 @@ func testNVarChar(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareChar(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -20,25 +20,67 @@ func DoTestSmallDateTime(t *testing.T) {
 	//
 }
 
-func testSmallDateTime(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesSmallDateTime))
-	mySamples := make([]time.Time, len(samplesSmallDateTime))
+// DoTestPreparedSmallDateTime tests the handling of the SmallDateTime
+// when selected through prepared statements.
+func DoTestPreparedSmallDateTime(t *testing.T) {
+	TestForEachDB("TestPreparedSmallDateTime", t, testPreparedSmallDateTime)
+}
 
+// DoBenchmarkSmallDateTime benchmarks inserting and scanning
+// SmallDateTime.
+func DoBenchmarkSmallDateTime(b *testing.B) {
+	pass := make([]interface{}, len(samplesSmallDateTime))
 	for i, sample := range samplesSmallDateTime {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkSmallDateTime", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"smalldatetime",
+				func() interface{} { return new(time.Time) },
+				pass,
+			)
+		},
+	)
+}
+
+func testSmallDateTime(t *testing.T, db *sql.DB, tableName string) {
+	testSmallDateTimeWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedSmallDateTime(t *testing.T, db *sql.DB, tableName string) {
+	testSmallDateTimeWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testSmallDateTimeWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesSmallDateTime
+	if err := applyCustomSamples("smalldatetime", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]time.Time, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "smalldatetime", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "smalldatetime", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv time.Time
@@ -50,9 +92,7 @@ func testSmallDateTime(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
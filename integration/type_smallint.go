@@ -18,25 +18,67 @@ func DoTestSmallInt(t *testing.T) {
 	//
 }
 
-func testSmallInt(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesSmallInt))
-	mySamples := make([]int16, len(samplesSmallInt))
+// DoTestPreparedSmallInt tests the handling of the SmallInt
+// when selected through prepared statements.
+func DoTestPreparedSmallInt(t *testing.T) {
+	TestForEachDB("TestPreparedSmallInt", t, testPreparedSmallInt)
+}
 
+// DoBenchmarkSmallInt benchmarks inserting and scanning
+// SmallInt.
+func DoBenchmarkSmallInt(b *testing.B) {
+	pass := make([]interface{}, len(samplesSmallInt))
 	for i, sample := range samplesSmallInt {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkSmallInt", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"smallint",
+				func() interface{} { return new(int16) },
+				pass,
+			)
+		},
+	)
+}
+
+func testSmallInt(t *testing.T, db *sql.DB, tableName string) {
+	testSmallIntWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedSmallInt(t *testing.T, db *sql.DB, tableName string) {
+	testSmallIntWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testSmallIntWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesSmallInt
+	if err := applyCustomSamples("smallint", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]int16, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "smallint", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "smallint", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv int16
@@ -48,9 +90,7 @@ func testSmallInt(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -18,25 +18,67 @@ func DoTestText(t *testing.T) {
 	//
 }
 
-func testText(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesText))
-	mySamples := make([]string, len(samplesText))
+// DoTestPreparedText tests the handling of the Text
+// when selected through prepared statements.
+func DoTestPreparedText(t *testing.T) {
+	TestForEachDB("TestPreparedText", t, testPreparedText)
+}
 
+// DoBenchmarkText benchmarks inserting and scanning
+// Text.
+func DoBenchmarkText(b *testing.B) {
+	pass := make([]interface{}, len(samplesText))
 	for i, sample := range samplesText {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkText", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"text null",
+				func() interface{} { return new(string) },
+				pass,
+			)
+		},
+	)
+}
+
+func testText(t *testing.T, db *sql.DB, tableName string) {
+	testTextWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedText(t *testing.T, db *sql.DB, tableName string) {
+	testTextWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testTextWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesText
+	if err := applyCustomSamples("text", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]string, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "text null", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "text null", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv string
@@ -48,9 +90,7 @@ func testText(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareChar(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
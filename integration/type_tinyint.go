@@ -18,25 +18,67 @@ func DoTestTinyInt(t *testing.T) {
 	//
 }
 
-func testTinyInt(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesTinyInt))
-	mySamples := make([]uint8, len(samplesTinyInt))
+// DoTestPreparedTinyInt tests the handling of the TinyInt
+// when selected through prepared statements.
+func DoTestPreparedTinyInt(t *testing.T) {
+	TestForEachDB("TestPreparedTinyInt", t, testPreparedTinyInt)
+}
 
+// DoBenchmarkTinyInt benchmarks inserting and scanning
+// TinyInt.
+func DoBenchmarkTinyInt(b *testing.B) {
+	pass := make([]interface{}, len(samplesTinyInt))
 	for i, sample := range samplesTinyInt {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkTinyInt", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"tinyint",
+				func() interface{} { return new(uint8) },
+				pass,
+			)
+		},
+	)
+}
+
+func testTinyInt(t *testing.T, db *sql.DB, tableName string) {
+	testTinyIntWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedTinyInt(t *testing.T, db *sql.DB, tableName string) {
+	testTinyIntWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testTinyIntWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesTinyInt
+	if err := applyCustomSamples("tinyint", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]uint8, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "tinyint", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "tinyint", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv uint8
@@ -48,9 +90,7 @@ func testTinyInt(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -18,25 +18,67 @@ func DoTestUniChar(t *testing.T) {
 	//
 }
 
-func testUniChar(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesUniChar))
-	mySamples := make([]string, len(samplesUniChar))
+// DoTestPreparedUniChar tests the handling of the UniChar
+// when selected through prepared statements.
+func DoTestPreparedUniChar(t *testing.T) {
+	TestForEachDB("TestPreparedUniChar", t, testPreparedUniChar)
+}
 
+// DoBenchmarkUniChar benchmarks inserting and scanning
+// UniChar.
+func DoBenchmarkUniChar(b *testing.B) {
+	pass := make([]interface{}, len(samplesUniChar))
 	for i, sample := range samplesUniChar {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkUniChar", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"unichar(30) null",
+				func() interface{} { return new(string) },
+				pass,
+			)
+		},
+	)
+}
+
+func testUniChar(t *testing.T, db *sql.DB, tableName string) {
+	testUniCharWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedUniChar(t *testing.T, db *sql.DB, tableName string) {
+	testUniCharWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testUniCharWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesUniChar
+	if err := applyCustomSamples("unichar", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]string, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "unichar(30) null", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "unichar(30) null", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv string
@@ -48,9 +90,7 @@ func testUniChar(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareChar(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
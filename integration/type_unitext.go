@@ -18,25 +18,67 @@ func DoTestUniText(t *testing.T) {
 	//
 }
 
-func testUniText(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesUniText))
-	mySamples := make([]string, len(samplesUniText))
+// DoTestPreparedUniText tests the handling of the UniText
+// when selected through prepared statements.
+func DoTestPreparedUniText(t *testing.T) {
+	TestForEachDB("TestPreparedUniText", t, testPreparedUniText)
+}
 
+// DoBenchmarkUniText benchmarks inserting and scanning
+// UniText.
+func DoBenchmarkUniText(b *testing.B) {
+	pass := make([]interface{}, len(samplesUniText))
 	for i, sample := range samplesUniText {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkUniText", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"unitext",
+				func() interface{} { return new(string) },
+				pass,
+			)
+		},
+	)
+}
+
+func testUniText(t *testing.T, db *sql.DB, tableName string) {
+	testUniTextWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedUniText(t *testing.T, db *sql.DB, tableName string) {
+	testUniTextWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testUniTextWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesUniText
+	if err := applyCustomSamples("unitext", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]string, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "unitext", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "unitext", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv string
@@ -48,9 +90,7 @@ func testUniText(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareChar(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
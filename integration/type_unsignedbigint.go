@@ -18,25 +18,67 @@ func DoTestUnsignedBigInt(t *testing.T) {
 	//
 }
 
-func testUnsignedBigInt(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesUnsignedBigInt))
-	mySamples := make([]uint64, len(samplesUnsignedBigInt))
+// DoTestPreparedUnsignedBigInt tests the handling of the UnsignedBigInt
+// when selected through prepared statements.
+func DoTestPreparedUnsignedBigInt(t *testing.T) {
+	TestForEachDB("TestPreparedUnsignedBigInt", t, testPreparedUnsignedBigInt)
+}
 
+// DoBenchmarkUnsignedBigInt benchmarks inserting and scanning
+// UnsignedBigInt.
+func DoBenchmarkUnsignedBigInt(b *testing.B) {
+	pass := make([]interface{}, len(samplesUnsignedBigInt))
 	for i, sample := range samplesUnsignedBigInt {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkUnsignedBigInt", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"unsigned bigint",
+				func() interface{} { return new(uint64) },
+				pass,
+			)
+		},
+	)
+}
+
+func testUnsignedBigInt(t *testing.T, db *sql.DB, tableName string) {
+	testUnsignedBigIntWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedUnsignedBigInt(t *testing.T, db *sql.DB, tableName string) {
+	testUnsignedBigIntWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testUnsignedBigIntWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesUnsignedBigInt
+	if err := applyCustomSamples("unsignedbigint", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]uint64, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "unsigned bigint", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "unsigned bigint", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv uint64
@@ -48,9 +90,7 @@ func testUnsignedBigInt(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
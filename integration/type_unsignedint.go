@@ -18,25 +18,67 @@ func DoTestUnsignedInt(t *testing.T) {
 	//
 }
 
-func testUnsignedInt(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesUnsignedInt))
-	mySamples := make([]uint32, len(samplesUnsignedInt))
+// DoTestPreparedUnsignedInt tests the handling of the UnsignedInt
+// when selected through prepared statements.
+func DoTestPreparedUnsignedInt(t *testing.T) {
+	TestForEachDB("TestPreparedUnsignedInt", t, testPreparedUnsignedInt)
+}
 
+// DoBenchmarkUnsignedInt benchmarks inserting and scanning
+// UnsignedInt.
+func DoBenchmarkUnsignedInt(b *testing.B) {
+	pass := make([]interface{}, len(samplesUnsignedInt))
 	for i, sample := range samplesUnsignedInt {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkUnsignedInt", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"unsigned int",
+				func() interface{} { return new(uint32) },
+				pass,
+			)
+		},
+	)
+}
+
+func testUnsignedInt(t *testing.T, db *sql.DB, tableName string) {
+	testUnsignedIntWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedUnsignedInt(t *testing.T, db *sql.DB, tableName string) {
+	testUnsignedIntWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testUnsignedIntWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesUnsignedInt
+	if err := applyCustomSamples("unsignedint", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]uint32, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "unsigned int", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "unsigned int", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv uint32
@@ -48,9 +90,7 @@ func testUnsignedInt(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
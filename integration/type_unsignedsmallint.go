@@ -18,25 +18,67 @@ func DoTestUnsignedSmallInt(t *testing.T) {
 	//
 }
 
-func testUnsignedSmallInt(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesUnsignedSmallInt))
-	mySamples := make([]uint16, len(samplesUnsignedSmallInt))
+// DoTestPreparedUnsignedSmallInt tests the handling of the UnsignedSmallInt
+// when selected through prepared statements.
+func DoTestPreparedUnsignedSmallInt(t *testing.T) {
+	TestForEachDB("TestPreparedUnsignedSmallInt", t, testPreparedUnsignedSmallInt)
+}
 
+// DoBenchmarkUnsignedSmallInt benchmarks inserting and scanning
+// UnsignedSmallInt.
+func DoBenchmarkUnsignedSmallInt(b *testing.B) {
+	pass := make([]interface{}, len(samplesUnsignedSmallInt))
 	for i, sample := range samplesUnsignedSmallInt {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkUnsignedSmallInt", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"unsigned smallint",
+				func() interface{} { return new(uint16) },
+				pass,
+			)
+		},
+	)
+}
+
+func testUnsignedSmallInt(t *testing.T, db *sql.DB, tableName string) {
+	testUnsignedSmallIntWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedUnsignedSmallInt(t *testing.T, db *sql.DB, tableName string) {
+	testUnsignedSmallIntWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testUnsignedSmallIntWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesUnsignedSmallInt
+	if err := applyCustomSamples("unsignedsmallint", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]uint16, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "unsigned smallint", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "unsigned smallint", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv uint16
@@ -48,9 +90,7 @@ func testUnsignedSmallInt(t *testing.T, db *sql.DB, tableName string) {
 
 		if recv != mySamples[i] {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
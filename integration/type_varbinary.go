@@ -18,25 +18,67 @@ func DoTestVarBinary(t *testing.T) {
 	//
 }
 
-func testVarBinary(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesVarBinary))
-	mySamples := make([][]byte, len(samplesVarBinary))
+// DoTestPreparedVarBinary tests the handling of the VarBinary
+// when selected through prepared statements.
+func DoTestPreparedVarBinary(t *testing.T) {
+	TestForEachDB("TestPreparedVarBinary", t, testPreparedVarBinary)
+}
 
+// DoBenchmarkVarBinary benchmarks inserting and scanning
+// VarBinary.
+func DoBenchmarkVarBinary(b *testing.B) {
+	pass := make([]interface{}, len(samplesVarBinary))
 	for i, sample := range samplesVarBinary {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkVarBinary", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"varbinary(13)",
+				func() interface{} { return new([]byte) },
+				pass,
+			)
+		},
+	)
+}
+
+func testVarBinary(t *testing.T, db *sql.DB, tableName string) {
+	testVarBinaryWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedVarBinary(t *testing.T, db *sql.DB, tableName string) {
+	testVarBinaryWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testVarBinaryWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesVarBinary
+	if err := applyCustomSamples("varbinary", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([][]byte, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "varbinary(13)", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "varbinary(13)", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv []byte
@@ -48,9 +90,7 @@ func testVarBinary(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareBinary(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
@@ -18,25 +18,67 @@ func DoTestVarChar(t *testing.T) {
 	//
 }
 
-func testVarChar(t *testing.T, db *sql.DB, tableName string) {
-	pass := make([]interface{}, len(samplesVarChar))
-	mySamples := make([]string, len(samplesVarChar))
+// DoTestPreparedVarChar tests the handling of the VarChar
+// when selected through prepared statements.
+func DoTestPreparedVarChar(t *testing.T) {
+	TestForEachDB("TestPreparedVarChar", t, testPreparedVarChar)
+}
 
+// DoBenchmarkVarChar benchmarks inserting and scanning
+// VarChar.
+func DoBenchmarkVarChar(b *testing.B) {
+	pass := make([]interface{}, len(samplesVarChar))
 	for i, sample := range samplesVarChar {
 
+		pass[i] = sample
+
+	}
+
+	BenchmarkForEachDB("BenchmarkVarChar", b,
+		func(b *testing.B, db *sql.DB, tableName string) {
+			benchmarkType(b, db, tableName,
+				"varchar(13) null",
+				func() interface{} { return new(string) },
+				pass,
+			)
+		},
+	)
+}
+
+func testVarChar(t *testing.T, db *sql.DB, tableName string) {
+	testVarCharWith(t, db, tableName, SetupTableInsert)
+}
+
+func testPreparedVarChar(t *testing.T, db *sql.DB, tableName string) {
+	testVarCharWith(t, db, tableName, SetupTablePrepared)
+}
+
+func testVarCharWith(t *testing.T, db *sql.DB, tableName string, setupFn SetupTableFunc) {
+	samples := samplesVarChar
+	if err := applyCustomSamples("varchar", &samples); err != nil {
+		t.Errorf("Failed to apply registered samples: %v", err)
+		return
+	}
+
+	pass := make([]interface{}, len(samples))
+	mySamples := make([]string, len(samples))
+
+	for i, sample := range samples {
+
 		mySample := sample
 
 		pass[i] = mySample
 		mySamples[i] = mySample
 	}
 
-	rows, teardownFn, err := SetupTableInsert(db, tableName, "varchar(13) null", pass...)
+	rows, teardownFn, err := setupFn(db, tableName, "varchar(13) null", pass...)
 	if err != nil {
 		t.Errorf("Error preparing table: %v", err)
 		return
 	}
-	defer rows.Close()
-	defer teardownFn()
+	cleanup := TestCleanup(t)
+	cleanup.Add("drop table "+tableName, teardownFn)
+	cleanup.Add("close rows", rows.Close)
 
 	i := 0
 	var recv string
@@ -48,9 +90,7 @@ func testVarChar(t *testing.T, db *sql.DB, tableName string) {
 
 		if compareChar(recv, mySamples[i]) {
 
-			t.Errorf("Received value does not match passed parameter")
-			t.Errorf("Expected: %v", mySamples[i])
-			t.Errorf("Received: %v", recv)
+			ReportMismatch(t, i, mySamples[i], recv)
 		}
 
 		i++
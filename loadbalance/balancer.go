@@ -0,0 +1,359 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/health"
+	"github.com/SAP/go-dblib/netlib"
+	"github.com/SAP/go-dblib/pool"
+	"github.com/hashicorp/go-multierror"
+)
+
+// Strategy selects a server among the eligible servers.
+type Strategy int
+
+// Strategies of a Balancer.
+const (
+	// RoundRobin selects the eligible servers in turn.
+	RoundRobin Strategy = iota
+	// LeastConnections selects the eligible server with the fewest
+	// open connections established by the Balancer.
+	LeastConnections
+)
+
+func (strategy Strategy) String() string {
+	if strategy == LeastConnections {
+		return "least-connections"
+	}
+	return "round-robin"
+}
+
+// DefaultExcludeFor is the default of Config.ExcludeFor.
+const DefaultExcludeFor = 30 * time.Second
+
+// ErrNoServer is returned if no server is configured for an intent.
+var ErrNoServer = dberrors.New(dberrors.CategoryConfig, "no server configured")
+
+// Server is a server connections are distributed to.
+type Server struct {
+	Endpoint netlib.Endpoint
+	// ReadOnly marks servers that only accept read-only connections,
+	// e.g. replicas.
+	ReadOnly bool
+}
+
+// ServersFromDSN returns the servers described by info.
+//
+// The writable servers are read with netlib.Endpoints. The property
+// "read-only-hosts" lists read-only servers as comma separated
+// host:port pairs.
+func ServersFromDSN(info *dsn.Info) ([]Server, error) {
+	endpoints, err := netlib.Endpoints(info)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []Server{}
+	for _, endpoint := range endpoints {
+		servers = append(servers, Server{Endpoint: endpoint})
+	}
+
+	if prop := info.Prop("read-only-hosts"); prop != "" {
+		endpoints, err := netlib.ParseEndpoints(prop)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing read-only-hosts: %w", err)
+		}
+
+		for _, endpoint := range endpoints {
+			servers = append(servers, Server{Endpoint: endpoint, ReadOnly: true})
+		}
+	}
+
+	return servers, nil
+}
+
+// Config configures a Balancer.
+type Config struct {
+	Servers  []Server
+	Strategy Strategy
+	// Dial establishes connections. Defaults to pool.DialTDS.
+	Dial pool.DialFunc
+	// ExcludeFor is the duration a server is excluded from selection
+	// after it failed. Defaults to DefaultExcludeFor.
+	ExcludeFor time.Duration
+}
+
+// ServerStats contains the statistics of a server.
+type ServerStats struct {
+	Server Server
+	// Open is the number of open connections established by the
+	// Balancer.
+	Open int
+	// Excluded reports whether the server is excluded from selection.
+	Excluded bool
+}
+
+type serverState struct {
+	server        Server
+	open          int
+	excludedUntil time.Time
+}
+
+// Balancer distributes connections over servers.
+type Balancer struct {
+	config Config
+	now    func() time.Time
+
+	lock    *sync.Mutex
+	servers []*serverState
+	// next is the position of the next read-only and writable server
+	// for RoundRobin.
+	next map[bool]int
+}
+
+// New returns a Balancer for config.
+func New(config Config) (*Balancer, error) {
+	if len(config.Servers) == 0 {
+		return nil, ErrNoServer
+	}
+
+	if config.Dial == nil {
+		config.Dial = pool.DialTDS
+	}
+
+	if config.ExcludeFor == 0 {
+		config.ExcludeFor = DefaultExcludeFor
+	}
+
+	b := &Balancer{
+		config: config,
+		now:    time.Now,
+		lock:   &sync.Mutex{},
+		next:   map[bool]int{},
+	}
+
+	for _, server := range config.Servers {
+		b.servers = append(b.servers, &serverState{server: server})
+	}
+
+	return b, nil
+}
+
+// Dial establishes a connection to a server selected for the intent
+// of info, see IntentFromDSN. .Host and .Port of info are replaced by
+// the endpoint of the selected server.
+//
+// If the connection fails the server is excluded and the next server
+// is tried. Closing the returned connection updates the number of
+// open connections of the server.
+//
+// Dial implements pool.DialFunc.
+func (b *Balancer) Dial(ctx context.Context, info *dsn.Info) (pool.Conn, error) {
+	intent, err := IntentFromDSN(info)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := b.candidates(intent)
+	if err != nil {
+		return nil, err
+	}
+
+	var me error
+	for _, state := range candidates {
+		conn, err := b.config.Dial(ctx, netlib.WithEndpoint(info, state.server.Endpoint))
+		if err != nil {
+			me = multierror.Append(me, fmt.Errorf("error connecting to %s: %w", state.server.Endpoint, err))
+			b.exclude(state)
+
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		b.lock.Lock()
+		state.open++
+		state.excludedUntil = time.Time{}
+		b.lock.Unlock()
+
+		return &balancedConn{Conn: conn, balancer: b, state: state, closeOnce: &sync.Once{}}, nil
+	}
+
+	return nil, dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("no %s server available: %w", intent, me))
+}
+
+// Pick returns the server a connection with intent would be
+// established to.
+func (b *Balancer) Pick(intent Intent) (Server, error) {
+	candidates, err := b.candidates(intent)
+	if err != nil {
+		return Server{}, err
+	}
+
+	return candidates[0].server, nil
+}
+
+// Exclude excludes the server of endpoint from selection for
+// config.ExcludeFor, e.g. after its connections reported errors.
+func (b *Balancer) Exclude(endpoint netlib.Endpoint) {
+	for _, state := range b.servers {
+		if state.server.Endpoint == endpoint {
+			b.exclude(state)
+		}
+	}
+}
+
+func (b *Balancer) exclude(state *serverState) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	state.excludedUntil = b.now().Add(b.config.ExcludeFor)
+}
+
+// Stats returns the statistics of the servers.
+func (b *Balancer) Stats() []ServerStats {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.now()
+	stats := make([]ServerStats, 0, len(b.servers))
+	for _, state := range b.servers {
+		stats = append(stats, ServerStats{
+			Server:   state.server,
+			Open:     state.open,
+			Excluded: now.Before(state.excludedUntil),
+		})
+	}
+
+	return stats
+}
+
+// candidates returns the servers eligible for intent in the order they
+// are tried. Read-only connections fall back to the writable servers.
+//
+// Excluded servers are only returned if all eligible servers are
+// excluded, as they may have recovered.
+func (b *Balancer) candidates(intent Intent) ([]*serverState, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	groups := [][]*serverState{b.filterLocked(intent == ReadOnly)}
+	if intent == ReadOnly {
+		groups = append(groups, b.filterLocked(false))
+	}
+
+	now := b.now()
+	candidates := []*serverState{}
+	for i, group := range groups {
+		candidates = append(candidates, b.orderLocked(included(group, now), i == 0)...)
+	}
+
+	if len(candidates) == 0 {
+		for i, group := range groups {
+			candidates = append(candidates, b.orderLocked(group, i == 0)...)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("error selecting %s server: %w", intent, ErrNoServer)
+	}
+
+	return candidates, nil
+}
+
+// filterLocked returns the servers with the passed read-only flag.
+//
+// The caller must hold b.lock.
+func (b *Balancer) filterLocked(readOnly bool) []*serverState {
+	filtered := []*serverState{}
+	for _, state := range b.servers {
+		if state.server.ReadOnly == readOnly {
+			filtered = append(filtered, state)
+		}
+	}
+	return filtered
+}
+
+// orderLocked orders states by the configured strategy. If advance is
+// true the next round-robin position of the group is advanced.
+//
+// The caller must hold b.lock.
+func (b *Balancer) orderLocked(states []*serverState, advance bool) []*serverState {
+	if len(states) == 0 {
+		return states
+	}
+
+	if b.config.Strategy == LeastConnections {
+		return orderByOpen(states)
+	}
+
+	readOnly := states[0].server.ReadOnly
+	start := b.next[readOnly] % len(states)
+	if advance {
+		b.next[readOnly] = start + 1
+	}
+
+	rotated := make([]*serverState, 0, len(states))
+	rotated = append(rotated, states[start:]...)
+	return append(rotated, states[:start]...)
+}
+
+// included returns the states that are not excluded at now.
+func included(states []*serverState, now time.Time) []*serverState {
+	filtered := []*serverState{}
+	for _, state := range states {
+		if !now.Before(state.excludedUntil) {
+			filtered = append(filtered, state)
+		}
+	}
+	return filtered
+}
+
+// orderByOpen orders states by the number of open connections.
+// Servers with the same number retain their order.
+func orderByOpen(states []*serverState) []*serverState {
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].open < states[j].open
+	})
+	return states
+}
+
+// balancedConn tracks the open connections of a server.
+type balancedConn struct {
+	pool.Conn
+	balancer  *Balancer
+	state     *serverState
+	closeOnce *sync.Once
+}
+
+// Ping implements the pool.Conn interface. The server is excluded if
+// the connection is no longer usable, see health.IsBadConn.
+func (conn *balancedConn) Ping(ctx context.Context) error {
+	err := conn.Conn.Ping(ctx)
+	if health.IsBadConn(err) {
+		conn.balancer.exclude(conn.state)
+	}
+	return err
+}
+
+// Close implements the pool.Conn interface.
+func (conn *balancedConn) Close() error {
+	conn.closeOnce.Do(func() {
+		conn.balancer.lock.Lock()
+		conn.state.open--
+		conn.balancer.lock.Unlock()
+	})
+
+	return conn.Conn.Close()
+}
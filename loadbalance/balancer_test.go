@@ -0,0 +1,229 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalance
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/netlib"
+	"github.com/SAP/go-dblib/pool"
+)
+
+type testConn struct {
+	host    string
+	pingErr error
+}
+
+func (conn *testConn) Ping(ctx context.Context) error { return conn.pingErr }
+func (conn *testConn) State() pool.SessionState       { return pool.SessionState{} }
+func (conn *testConn) Close() error                   { return nil }
+
+type testDialer struct {
+	lock *sync.Mutex
+	down map[string]bool
+}
+
+func (dialer *testDialer) dial(ctx context.Context, info *dsn.Info) (pool.Conn, error) {
+	dialer.lock.Lock()
+	defer dialer.lock.Unlock()
+
+	if dialer.down[info.Host] {
+		return nil, errors.New("connection refused")
+	}
+	return &testConn{host: info.Host}, nil
+}
+
+func newTestBalancer(t *testing.T, strategy Strategy) (*Balancer, *testDialer) {
+	info := dsn.NewInfo()
+	info.Hosts = []dsn.Endpoint{{Host: "rw1", Port: "4901"}, {Host: "rw2", Port: "4901"}}
+	info.ConnectProps.Set("read-only-hosts", "ro1:4901,ro2:4901")
+
+	servers, err := ServersFromDSN(info)
+	if err != nil {
+		t.Fatalf("Unexpected error reading servers: %v", err)
+	}
+
+	dialer := &testDialer{lock: &sync.Mutex{}, down: map[string]bool{}}
+
+	b, err := New(Config{Servers: servers, Strategy: strategy, Dial: dialer.dial})
+	if err != nil {
+		t.Fatalf("Unexpected error creating balancer: %v", err)
+	}
+
+	return b, dialer
+}
+
+// dialHosts dials n connections with intent and returns the hosts the
+// connections were established to.
+func dialHosts(t *testing.T, b *Balancer, intent Intent, n int) ([]string, []pool.Conn) {
+	info := dsn.NewInfo()
+	if intent == ReadOnly {
+		info.ConnectProps.Set("read-only", "true")
+	}
+
+	hosts := []string{}
+	conns := []pool.Conn{}
+	for i := 0; i < n; i++ {
+		conn, err := b.Dial(context.Background(), info)
+		if err != nil {
+			t.Fatalf("Unexpected error dialing: %v", err)
+		}
+
+		hosts = append(hosts, conn.(*balancedConn).Conn.(*testConn).host)
+		conns = append(conns, conn)
+	}
+
+	return hosts, conns
+}
+
+func TestServersFromDSN(t *testing.T) {
+	info := dsn.NewInfo()
+	info.Hosts = []dsn.Endpoint{{Host: "rw1", Port: "4901"}}
+	info.ConnectProps.Set("read-only-hosts", "ro1:4901")
+
+	servers, err := ServersFromDSN(info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Server{
+		{Endpoint: netlib.Endpoint{Host: "rw1", Port: "4901"}},
+		{Endpoint: netlib.Endpoint{Host: "ro1", Port: "4901"}, ReadOnly: true},
+	}
+
+	if !reflect.DeepEqual(servers, expected) {
+		t.Errorf("Expected %v, got %v", expected, servers)
+	}
+}
+
+func TestIntentFromDSN(t *testing.T) {
+	info := dsn.NewInfo()
+
+	if intent, err := IntentFromDSN(info); err != nil || intent != ReadWrite {
+		t.Errorf("Expected %s, got %s (%v)", ReadWrite, intent, err)
+	}
+
+	info.ConnectProps.Set("read-only", "yes")
+	if _, err := IntentFromDSN(info); !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error, got %v", err)
+	}
+}
+
+func TestBalancer_RoundRobin(t *testing.T) {
+	b, _ := newTestBalancer(t, RoundRobin)
+
+	hosts, _ := dialHosts(t, b, ReadWrite, 3)
+	if expected := []string{"rw1", "rw2", "rw1"}; !reflect.DeepEqual(hosts, expected) {
+		t.Errorf("Expected read-write hosts %v, got %v", expected, hosts)
+	}
+
+	hosts, _ = dialHosts(t, b, ReadOnly, 3)
+	if expected := []string{"ro1", "ro2", "ro1"}; !reflect.DeepEqual(hosts, expected) {
+		t.Errorf("Expected read-only hosts %v, got %v", expected, hosts)
+	}
+}
+
+func TestBalancer_LeastConnections(t *testing.T) {
+	b, _ := newTestBalancer(t, LeastConnections)
+
+	hosts, conns := dialHosts(t, b, ReadWrite, 3)
+	if expected := []string{"rw1", "rw2", "rw1"}; !reflect.DeepEqual(hosts, expected) {
+		t.Errorf("Expected hosts %v, got %v", expected, hosts)
+	}
+
+	// Closing both connections to rw1 makes it the least used server.
+	conns[0].Close()
+	conns[2].Close()
+	conns[2].Close()
+
+	hosts, _ = dialHosts(t, b, ReadWrite, 2)
+	if expected := []string{"rw1", "rw1"}; !reflect.DeepEqual(hosts, expected) {
+		t.Errorf("Expected hosts %v, got %v", expected, hosts)
+	}
+
+	for _, stats := range b.Stats() {
+		if stats.Server.Endpoint.Host == "rw1" && stats.Open != 2 {
+			t.Errorf("Expected 2 open connections to rw1, got %d", stats.Open)
+		}
+	}
+}
+
+func TestBalancer_HealthExclusion(t *testing.T) {
+	b, dialer := newTestBalancer(t, RoundRobin)
+
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	dialer.down["rw1"] = true
+
+	hosts, _ := dialHosts(t, b, ReadWrite, 2)
+	if expected := []string{"rw2", "rw2"}; !reflect.DeepEqual(hosts, expected) {
+		t.Errorf("Expected hosts %v, got %v", expected, hosts)
+	}
+
+	// The excluded server is selected again once the exclusion
+	// expired.
+	dialer.down["rw1"] = false
+	now = now.Add(DefaultExcludeFor)
+
+	hosts, _ = dialHosts(t, b, ReadWrite, 2)
+	if hosts[0] != "rw1" && hosts[1] != "rw1" {
+		t.Errorf("Expected rw1 to be selected after exclusion expired, got %v", hosts)
+	}
+}
+
+func TestBalancer_ReadOnlyFallback(t *testing.T) {
+	b, dialer := newTestBalancer(t, RoundRobin)
+
+	dialer.down["ro1"] = true
+	dialer.down["ro2"] = true
+
+	hosts, _ := dialHosts(t, b, ReadOnly, 1)
+	if hosts[0] != "rw1" && hosts[0] != "rw2" {
+		t.Errorf("Expected fallback to a read-write server, got %s", hosts[0])
+	}
+
+	for _, stats := range b.Stats() {
+		if stats.Server.ReadOnly && !stats.Excluded {
+			t.Errorf("Expected failed read-only server %s to be excluded", stats.Server.Endpoint)
+		}
+	}
+}
+
+func TestBalancer_Unavailable(t *testing.T) {
+	b, dialer := newTestBalancer(t, RoundRobin)
+
+	dialer.down["rw1"] = true
+	dialer.down["rw2"] = true
+
+	_, err := b.Dial(context.Background(), dsn.NewInfo())
+	if !errors.Is(err, dberrors.CategoryNetwork) {
+		t.Errorf("Expected network error, got %v", err)
+	}
+}
+
+func TestBalancer_PingExclusion(t *testing.T) {
+	b, _ := newTestBalancer(t, RoundRobin)
+
+	_, conns := dialHosts(t, b, ReadWrite, 1)
+	conns[0].(*balancedConn).Conn.(*testConn).pingErr = dberrors.New(dberrors.CategoryNetwork, "broken pipe")
+
+	if err := conns[0].Ping(context.Background()); err == nil {
+		t.Fatalf("Expected ping error")
+	}
+
+	for _, stats := range b.Stats() {
+		if stats.Server.Endpoint.Host == "rw1" && !stats.Excluded {
+			t.Errorf("Expected rw1 to be excluded after failed ping")
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package loadbalance distributes connections over multiple servers,
+e.g. to scale out reads against replicated servers.
+
+The servers are read from .Hosts and the property "read-only-hosts" of
+the DSN, see ServersFromDSN. Connections are routed by their intent,
+which is set by the property "read-only":
+
+  - Read-write connections are routed to servers of .Hosts.
+  - Read-only connections are routed to servers of "read-only-hosts"
+    and to servers of .Hosts if no read-only server is available.
+
+Among the eligible servers a server is selected by the configured
+Strategy. Servers that fail to accept connections or whose connections
+fail pings are excluded from the selection for Config.ExcludeFor.
+
+Balancer.Dial implements pool.DialFunc, so a Balancer can be used to
+establish the connections of a pool:
+
+	servers, err := loadbalance.ServersFromDSN(info)
+	if err != nil {
+		return err
+	}
+
+	b, err := loadbalance.New(loadbalance.Config{
+		Servers:  servers,
+		Strategy: loadbalance.LeastConnections,
+	})
+	if err != nil {
+		return err
+	}
+
+	p, err := pool.New(ctx, pool.Config{DSN: info, Dial: b.Dial})
+*/
+package loadbalance
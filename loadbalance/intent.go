@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalance
+
+import (
+	"github.com/SAP/go-dblib/dsn"
+)
+
+func init() {
+	dsn.RegisterProps("read-only", "read-only-hosts")
+}
+
+// Intent is the intended use of a connection.
+type Intent int
+
+// Intents of connections.
+const (
+	// ReadWrite connections are routed to writable servers.
+	ReadWrite Intent = iota
+	// ReadOnly connections are routed to read-only servers if
+	// available.
+	ReadOnly
+)
+
+func (intent Intent) String() string {
+	if intent == ReadOnly {
+		return "read-only"
+	}
+	return "read-write"
+}
+
+// IntentFromDSN returns the intent set by the property "read-only" of
+// info. The intent defaults to ReadWrite.
+func IntentFromDSN(info *dsn.Info) (Intent, error) {
+	readOnly, err := info.PropBool("read-only", false)
+	if err != nil {
+		return ReadWrite, err
+	}
+
+	if readOnly {
+		return ReadOnly, nil
+	}
+	return ReadWrite, nil
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package logging provides the Logger used by all packages of go-dblib.
+
+The Logger interface is compatible with *slog.Logger of log/slog,
+which allows to inject a structured logger:
+
+	logging.SetDefault(slog.Default())
+
+Arguments following the message are alternating keys and values, as
+with log/slog.
+
+Without an injected logger messages of at least LevelInfo are written
+to the standard logger of package log.
+
+A Logger can be passed to a single connection through the context
+passed to tds.NewConn:
+
+	ctx = logging.NewContext(ctx, logger)
+*/
+package logging
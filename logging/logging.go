@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+// Logger is the interface of structured loggers. It is implemented by
+// *slog.Logger.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// Level is the severity of a message. The values match the levels of
+// log/slog.
+type Level int
+
+// Levels of messages.
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+func (level Level) String() string {
+	switch level {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("Level(%d)", int(level))
+	}
+}
+
+// StdLogger is a Logger writing to a *log.Logger.
+type StdLogger struct {
+	// level is accessed atomically and must be 64-bit aligned.
+	level int64
+
+	// Logger is the logger to write to. Defaults to the standard
+	// logger.
+	Logger *log.Logger
+}
+
+// NewStdLogger returns a StdLogger writing messages of at least level
+// to logger.
+func NewStdLogger(logger *log.Logger, level Level) *StdLogger {
+	return &StdLogger{Logger: logger, level: int64(level)}
+}
+
+// Level returns the minimum level of written messages.
+func (logger *StdLogger) Level() Level {
+	return Level(atomic.LoadInt64(&logger.level))
+}
+
+// SetLevel sets the minimum level of written messages.
+func (logger *StdLogger) SetLevel(level Level) {
+	atomic.StoreInt64(&logger.level, int64(level))
+}
+
+// Debug implements the Logger interface.
+func (logger *StdLogger) Debug(msg string, args ...interface{}) {
+	logger.log(LevelDebug, msg, args)
+}
+
+// Info implements the Logger interface.
+func (logger *StdLogger) Info(msg string, args ...interface{}) {
+	logger.log(LevelInfo, msg, args)
+}
+
+// Warn implements the Logger interface.
+func (logger *StdLogger) Warn(msg string, args ...interface{}) {
+	logger.log(LevelWarn, msg, args)
+}
+
+// Error implements the Logger interface.
+func (logger *StdLogger) Error(msg string, args ...interface{}) {
+	logger.log(LevelError, msg, args)
+}
+
+func (logger *StdLogger) log(level Level, msg string, args []interface{}) {
+	if level < logger.Level() {
+		return
+	}
+
+	line := level.String() + " " + msg + formatArgs(args)
+	if logger.Logger == nil {
+		log.Print(line)
+		return
+	}
+
+	logger.Logger.Print(line)
+}
+
+// formatArgs formats alternating keys and values as key=value pairs.
+// A trailing key without value is formatted with the key !BADKEY, as
+// in log/slog.
+func formatArgs(args []interface{}) string {
+	sb := &strings.Builder{}
+
+	for i := 0; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			fmt.Fprintf(sb, " !BADKEY=%v", args[i])
+			break
+		}
+
+		fmt.Fprintf(sb, " %v=%v", args[i], args[i+1])
+	}
+
+	return sb.String()
+}
+
+// discard is a Logger dropping all messages.
+type discard struct{}
+
+func (discard) Debug(msg string, args ...interface{}) {}
+func (discard) Info(msg string, args ...interface{})  {}
+func (discard) Warn(msg string, args ...interface{})  {}
+func (discard) Error(msg string, args ...interface{}) {}
+
+// Discard is a Logger dropping all messages.
+var Discard Logger = discard{}
+
+// withArgs is a Logger adding args to all messages.
+type withArgs struct {
+	logger Logger
+	args   []interface{}
+}
+
+// With returns a Logger adding the alternating keys and values args to
+// all messages written to logger.
+func With(logger Logger, args ...interface{}) Logger {
+	if len(args) == 0 {
+		return logger
+	}
+
+	if parent, ok := logger.(*withArgs); ok {
+		return &withArgs{
+			logger: parent.logger,
+			args:   append(append([]interface{}{}, parent.args...), args...),
+		}
+	}
+
+	return &withArgs{logger: logger, args: args}
+}
+
+func (logger *withArgs) join(args []interface{}) []interface{} {
+	return append(append([]interface{}{}, logger.args...), args...)
+}
+
+func (logger *withArgs) Debug(msg string, args ...interface{}) {
+	logger.logger.Debug(msg, logger.join(args)...)
+}
+
+func (logger *withArgs) Info(msg string, args ...interface{}) {
+	logger.logger.Info(msg, logger.join(args)...)
+}
+
+func (logger *withArgs) Warn(msg string, args ...interface{}) {
+	logger.logger.Warn(msg, logger.join(args)...)
+}
+
+func (logger *withArgs) Error(msg string, args ...interface{}) {
+	logger.logger.Error(msg, logger.join(args)...)
+}
+
+// loggerHolder allows to store different Logger implementations in an
+// atomic.Value.
+type loggerHolder struct {
+	logger Logger
+}
+
+var defaultLogger atomic.Value
+
+func init() {
+	defaultLogger.Store(loggerHolder{logger: NewStdLogger(nil, LevelInfo)})
+}
+
+// Default returns the default Logger.
+func Default() Logger {
+	return defaultLogger.Load().(loggerHolder).logger
+}
+
+// SetDefault sets the default Logger. Passing nil discards all
+// messages.
+func SetDefault(logger Logger) {
+	if logger == nil {
+		logger = Discard
+	}
+
+	defaultLogger.Store(loggerHolder{logger: logger})
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying logger.
+func NewContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the Logger carried by ctx or the default
+// Logger.
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(Logger); ok && logger != nil {
+			return logger
+		}
+	}
+
+	return Default()
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewStdLogger(log.New(buf, "", 0), LevelInfo)
+
+	logger.Debug("skipped")
+	logger.Info("message", "key", "value", "count", 2)
+	logger.Error("odd", "trailing")
+
+	expected := "INFO message key=value count=2\nERROR odd !BADKEY=trailing\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, received %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	logger.SetLevel(LevelDebug)
+	logger.Debug("written")
+
+	if buf.String() != "DEBUG written\n" {
+		t.Errorf("Expected debug message after lowering level, received %q", buf.String())
+	}
+}
+
+func TestWith(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := With(With(NewStdLogger(log.New(buf, "", 0), LevelDebug), "conn", 1), "channel", 2)
+
+	logger.Warn("message", "key", "value")
+
+	expected := "WARN message conn=1 channel=2 key=value\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, received %q", expected, buf.String())
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != Default() {
+		t.Errorf("Expected default logger without logger in context")
+	}
+
+	logger := NewStdLogger(nil, LevelError)
+	if FromContext(NewContext(context.Background(), logger)) != logger {
+		t.Errorf("Expected logger from context")
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	prev := Default()
+	defer SetDefault(prev)
+
+	SetDefault(nil)
+	if Default() != Discard {
+		t.Errorf("Expected Discard after setting nil")
+	}
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package metrics collects connection, packet and query metrics.
+
+Metrics implements trace.Tracer and records the spans and events
+reported by the tds package and the packages built on it:
+
+	m := metrics.New()
+	trace.SetTracer(m)
+
+	stats := m.Snapshot()
+
+To keep existing tracers use trace.Multi:
+
+	trace.SetTracer(trace.Multi(m, tracer))
+
+The module github.com/SAP/go-dblib/metrics/prometheus exposes the
+metrics as prometheus.Collector. It is a separate module, so go-dblib
+does not depend on github.com/prometheus/client_golang:
+
+	collector := prometheus.NewCollector(m, "ase")
+	registry.MustRegister(collector)
+*/
+package metrics
@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/SAP/go-dblib/trace"
+)
+
+// DefaultLatencyBuckets are the upper bounds in seconds of the query
+// latency histogram used by New.
+var DefaultLatencyBuckets = []float64{.001, .005, .01, .05, .1, .5, 1, 5, 10}
+
+// Metrics records metrics from trace spans and events.
+type Metrics struct {
+	// Counters are accessed atomically and must be 64-bit aligned.
+	connsOpened, connsClosed, connErrors      int64
+	logins, loginErrors                       int64
+	packetsSent, packetsReceived              int64
+	bytesSent, bytesReceived                  int64
+	queries, queryErrors, commands, cmdErrors int64
+
+	latencyLock   *sync.Mutex
+	latencyBounds []float64
+	latencyCounts []uint64
+	latencySum    float64
+}
+
+// New returns Metrics using DefaultLatencyBuckets.
+func New() *Metrics {
+	return NewWithBuckets(DefaultLatencyBuckets)
+}
+
+// NewWithBuckets returns Metrics recording query latencies in
+// a histogram with the passed upper bounds in seconds.
+func NewWithBuckets(bounds []float64) *Metrics {
+	sorted := append([]float64{}, bounds...)
+	sort.Float64s(sorted)
+
+	return &Metrics{
+		latencyLock:   &sync.Mutex{},
+		latencyBounds: sorted,
+		latencyCounts: make([]uint64, len(sorted)),
+	}
+}
+
+// SpanStart implements the trace.Tracer interface.
+func (m *Metrics) SpanStart(ctx context.Context, span *trace.Span) {}
+
+// SpanEnd implements the trace.Tracer interface.
+func (m *Metrics) SpanEnd(ctx context.Context, span *trace.Span) {
+	switch span.Kind {
+	case trace.KindConnect:
+		if span.Err != nil {
+			atomic.AddInt64(&m.connErrors, 1)
+		} else {
+			atomic.AddInt64(&m.connsOpened, 1)
+		}
+	case trace.KindLogin:
+		atomic.AddInt64(&m.logins, 1)
+		if span.Err != nil {
+			atomic.AddInt64(&m.loginErrors, 1)
+		}
+	case trace.KindQuery:
+		atomic.AddInt64(&m.queries, 1)
+		if span.Err != nil {
+			atomic.AddInt64(&m.queryErrors, 1)
+		}
+		m.observeLatency(span.Duration().Seconds())
+	case trace.KindCommand:
+		atomic.AddInt64(&m.commands, 1)
+		if span.Err != nil {
+			atomic.AddInt64(&m.cmdErrors, 1)
+		}
+	}
+}
+
+// Event implements the trace.Tracer interface.
+func (m *Metrics) Event(ctx context.Context, event *trace.Event) {
+	switch event.Kind {
+	case trace.KindConnect:
+		if event.Name == "close" {
+			atomic.AddInt64(&m.connsClosed, 1)
+		}
+	case trace.KindPacket:
+		length := packetLength(event.Attrs)
+		switch event.Name {
+		case "send":
+			atomic.AddInt64(&m.packetsSent, 1)
+			atomic.AddInt64(&m.bytesSent, length)
+		case "receive":
+			atomic.AddInt64(&m.packetsReceived, 1)
+			atomic.AddInt64(&m.bytesReceived, length)
+		}
+	}
+}
+
+// packetLength returns the value of the attribute "length".
+func packetLength(attrs []trace.Attr) int64 {
+	for _, attr := range attrs {
+		if attr.Key != "length" {
+			continue
+		}
+
+		switch length := attr.Value.(type) {
+		case uint16:
+			return int64(length)
+		case int:
+			return int64(length)
+		case int64:
+			return length
+		}
+	}
+
+	return 0
+}
+
+func (m *Metrics) observeLatency(seconds float64) {
+	m.latencyLock.Lock()
+	defer m.latencyLock.Unlock()
+
+	m.latencySum += seconds
+	for i, bound := range m.latencyBounds {
+		if seconds <= bound {
+			m.latencyCounts[i]++
+			break
+		}
+	}
+}
+
+// Bucket is a bucket of a histogram.
+type Bucket struct {
+	UpperBound float64
+	// CumulativeCount is the number of observations less than or
+	// equal to UpperBound.
+	CumulativeCount uint64
+}
+
+// Snapshot contains the values of Metrics at a point in time.
+type Snapshot struct {
+	ConnsOpened, ConnsClosed, ConnErrors int64
+	Logins, LoginErrors                  int64
+	PacketsSent, PacketsReceived         int64
+	BytesSent, BytesReceived             int64
+	Queries, QueryErrors                 int64
+	Commands, CommandErrors              int64
+
+	LatencyBuckets []Bucket
+	LatencySum     float64
+}
+
+// OpenConns returns the number of open connections.
+func (snapshot Snapshot) OpenConns() int64 {
+	return snapshot.ConnsOpened - snapshot.ConnsClosed
+}
+
+// Snapshot returns the current values.
+func (m *Metrics) Snapshot() Snapshot {
+	snapshot := Snapshot{
+		ConnsOpened:     atomic.LoadInt64(&m.connsOpened),
+		ConnsClosed:     atomic.LoadInt64(&m.connsClosed),
+		ConnErrors:      atomic.LoadInt64(&m.connErrors),
+		Logins:          atomic.LoadInt64(&m.logins),
+		LoginErrors:     atomic.LoadInt64(&m.loginErrors),
+		PacketsSent:     atomic.LoadInt64(&m.packetsSent),
+		PacketsReceived: atomic.LoadInt64(&m.packetsReceived),
+		BytesSent:       atomic.LoadInt64(&m.bytesSent),
+		BytesReceived:   atomic.LoadInt64(&m.bytesReceived),
+		Queries:         atomic.LoadInt64(&m.queries),
+		QueryErrors:     atomic.LoadInt64(&m.queryErrors),
+		Commands:        atomic.LoadInt64(&m.commands),
+		CommandErrors:   atomic.LoadInt64(&m.cmdErrors),
+	}
+
+	m.latencyLock.Lock()
+	defer m.latencyLock.Unlock()
+
+	snapshot.LatencySum = m.latencySum
+	snapshot.LatencyBuckets = make([]Bucket, len(m.latencyBounds))
+
+	var cumulative uint64
+	for i, bound := range m.latencyBounds {
+		cumulative += m.latencyCounts[i]
+		snapshot.LatencyBuckets[i] = Bucket{UpperBound: bound, CumulativeCount: cumulative}
+	}
+
+	return snapshot
+}
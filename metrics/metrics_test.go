@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/trace"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewWithBuckets([]float64{1000, 0})
+	ctx := trace.WithTracer(context.Background(), m)
+
+	_, span := trace.Start(ctx, trace.KindConnect, "dial")
+	span.End(nil)
+	_, span = trace.Start(ctx, trace.KindConnect, "dial")
+	span.End(errors.New("connection refused"))
+
+	trace.Emit(ctx, trace.KindPacket, "send", trace.Attr{Key: "length", Value: uint16(512)})
+	trace.Emit(ctx, trace.KindPacket, "receive", trace.Attr{Key: "length", Value: uint16(100)})
+	trace.Emit(ctx, trace.KindPacket, "receive", trace.Attr{Key: "length", Value: uint16(28)})
+
+	for _, err := range []error{nil, errors.New("syntax error")} {
+		_, span = trace.Start(ctx, trace.KindQuery, "query")
+		span.End(err)
+	}
+
+	trace.Emit(ctx, trace.KindConnect, "close")
+
+	snapshot := m.Snapshot()
+
+	expected := Snapshot{
+		ConnsOpened:     1,
+		ConnsClosed:     1,
+		ConnErrors:      1,
+		PacketsSent:     1,
+		PacketsReceived: 2,
+		BytesSent:       512,
+		BytesReceived:   128,
+		Queries:         2,
+		QueryErrors:     1,
+	}
+	snapshot.LatencyBuckets, snapshot.LatencySum = nil, 0
+
+	if !reflect.DeepEqual(snapshot, expected) {
+		t.Errorf("Expected %+v, received %+v", expected, snapshot)
+	}
+
+	if snapshot.OpenConns() != 0 {
+		t.Errorf("Expected no open connections, received %d", snapshot.OpenConns())
+	}
+}
+
+func TestMetrics_Latency(t *testing.T) {
+	m := NewWithBuckets([]float64{10, 1})
+
+	m.observeLatency(0.5)
+	m.observeLatency(5)
+	m.observeLatency(50)
+
+	snapshot := m.Snapshot()
+
+	expected := []Bucket{{1, 1}, {10, 2}}
+	if len(snapshot.LatencyBuckets) != len(expected) {
+		t.Fatalf("Expected %d buckets, received %v", len(expected), snapshot.LatencyBuckets)
+	}
+
+	for i, bucket := range expected {
+		if snapshot.LatencyBuckets[i] != bucket {
+			t.Errorf("Expected bucket %v, received %v", bucket, snapshot.LatencyBuckets[i])
+		}
+	}
+
+	if snapshot.LatencySum != 55.5 {
+		t.Errorf("Expected sum 55.5, received %f", snapshot.LatencySum)
+	}
+}
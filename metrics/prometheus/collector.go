@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"github.com/SAP/go-dblib/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector exposes metrics.Metrics as prometheus.Collector.
+type Collector struct {
+	metrics *metrics.Metrics
+
+	connsOpened, connsClosed, connErrors, openConns *prometheus.Desc
+	logins, loginErrors                             *prometheus.Desc
+	packets, bytes                                  *prometheus.Desc
+	queries, queryErrors, queryLatency              *prometheus.Desc
+	commands, commandErrors                         *prometheus.Desc
+}
+
+// NewCollector returns a Collector for m. The names of the metrics
+// are prefixed with namespace.
+func NewCollector(m *metrics.Metrics, namespace string) *Collector {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, labels, nil)
+	}
+
+	return &Collector{
+		metrics:       m,
+		connsOpened:   desc("connections_opened_total", "Number of established connections."),
+		connsClosed:   desc("connections_closed_total", "Number of closed connections."),
+		connErrors:    desc("connection_errors_total", "Number of failed connection attempts."),
+		openConns:     desc("connections_open", "Number of open connections."),
+		logins:        desc("logins_total", "Number of login attempts."),
+		loginErrors:   desc("login_errors_total", "Number of failed login attempts."),
+		packets:       desc("packets_total", "Number of packets.", "direction"),
+		bytes:         desc("packet_bytes_total", "Number of bytes in packets.", "direction"),
+		queries:       desc("queries_total", "Number of executed queries."),
+		queryErrors:   desc("query_errors_total", "Number of failed queries."),
+		queryLatency:  desc("query_duration_seconds", "Duration of queries in seconds."),
+		commands:      desc("commands_total", "Number of executed REPL commands."),
+		commandErrors: desc("command_errors_total", "Number of failed REPL commands."),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		collector.connsOpened, collector.connsClosed, collector.connErrors, collector.openConns,
+		collector.logins, collector.loginErrors,
+		collector.packets, collector.bytes,
+		collector.queries, collector.queryErrors, collector.queryLatency,
+		collector.commands, collector.commandErrors,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements the prometheus.Collector interface.
+func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := collector.metrics.Snapshot()
+
+	counter := func(desc *prometheus.Desc, value int64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(value), labels...)
+	}
+
+	counter(collector.connsOpened, snapshot.ConnsOpened)
+	counter(collector.connsClosed, snapshot.ConnsClosed)
+	counter(collector.connErrors, snapshot.ConnErrors)
+	ch <- prometheus.MustNewConstMetric(collector.openConns, prometheus.GaugeValue, float64(snapshot.OpenConns()))
+	counter(collector.logins, snapshot.Logins)
+	counter(collector.loginErrors, snapshot.LoginErrors)
+	counter(collector.packets, snapshot.PacketsSent, "sent")
+	counter(collector.packets, snapshot.PacketsReceived, "received")
+	counter(collector.bytes, snapshot.BytesSent, "sent")
+	counter(collector.bytes, snapshot.BytesReceived, "received")
+	counter(collector.queries, snapshot.Queries)
+	counter(collector.queryErrors, snapshot.QueryErrors)
+	counter(collector.commands, snapshot.Commands)
+	counter(collector.commandErrors, snapshot.CommandErrors)
+
+	buckets := make(map[float64]uint64, len(snapshot.LatencyBuckets))
+	for _, bucket := range snapshot.LatencyBuckets {
+		buckets[bucket.UpperBound] = bucket.CumulativeCount
+	}
+	ch <- prometheus.MustNewConstHistogram(collector.queryLatency,
+		uint64(snapshot.Queries), snapshot.LatencySum, buckets)
+}
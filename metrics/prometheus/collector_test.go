@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SAP/go-dblib/metrics"
+	"github.com/SAP/go-dblib/trace"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCollector(t *testing.T) {
+	m := metrics.New()
+	ctx := trace.WithTracer(context.Background(), m)
+
+	_, span := trace.Start(ctx, trace.KindQuery, "query")
+	span.End(nil)
+
+	// The pedantic registry verifies that the collected metrics match
+	// their descriptions.
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(NewCollector(m, "ase")); err != nil {
+		t.Fatalf("Failed to register collector: %v", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != "ase_queries_total" {
+			continue
+		}
+
+		if value := family.GetMetric()[0].GetCounter().GetValue(); value != 1 {
+			t.Errorf("Expected 1 query, received %v", value)
+		}
+		return
+	}
+
+	t.Errorf("Expected metric ase_queries_total, received %v", families)
+}
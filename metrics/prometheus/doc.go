@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package prometheus exposes the metrics collected by the metrics package
+as prometheus.Collector:
+
+	m := metrics.New()
+	trace.SetTracer(m)
+
+	registry.MustRegister(prometheus.NewCollector(m, "ase"))
+
+The package is a separate module, so go-dblib itself does not depend
+on github.com/prometheus/client_golang.
+*/
+package prometheus
@@ -0,0 +1,12 @@
+module github.com/SAP/go-dblib/metrics/prometheus
+
+go 1.15
+
+require (
+	github.com/SAP/go-dblib v0.0.0
+	github.com/prometheus/client_golang v1.11.0
+)
+
+// The exporter is developed and tested against the go-dblib in this
+// repository.
+replace github.com/SAP/go-dblib => ../..
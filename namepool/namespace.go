@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package namepool
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ExhaustedError is returned when a limited pool has no Names left.
+type ExhaustedError struct {
+	Namespace string
+	Limit     int
+}
+
+func (err ExhaustedError) Error() string {
+	if err.Namespace == "" {
+		return fmt.Sprintf("namepool: all %d names acquired", err.Limit)
+	}
+	return fmt.Sprintf("namepool: all %d names of namespace %s acquired", err.Limit, err.Namespace)
+}
+
+// Namespaces is a set of name pools, which are identified by their
+// namespace. This allows e.g. cursors and dynamic statements to draw
+// names from separate pools with distinct formats and limits.
+type Namespaces struct {
+	lock  *sync.RWMutex
+	pools map[string]*pool
+}
+
+// NewNamespaces returns an empty set of namespaces.
+func NewNamespaces() *Namespaces {
+	return &Namespaces{
+		lock:  &sync.RWMutex{},
+		pools: map[string]*pool{},
+	}
+}
+
+// Register creates a pool for namespace with the passed format and
+// limit. See LimitedPool for the semantics of format and limit.
+//
+// An error is returned if namespace is already registered.
+func (ns *Namespaces) Register(namespace, format string, limit int) (*pool, error) {
+	ns.lock.Lock()
+	defer ns.lock.Unlock()
+
+	if _, ok := ns.pools[namespace]; ok {
+		return nil, fmt.Errorf("namepool: namespace %s is already registered", namespace)
+	}
+
+	pool := LimitedPool(format, limit)
+	pool.namespace = namespace
+	ns.pools[namespace] = pool
+
+	return pool, nil
+}
+
+// Pool returns the pool of namespace.
+func (ns *Namespaces) Pool(namespace string) (*pool, error) {
+	ns.lock.RLock()
+	defer ns.lock.RUnlock()
+
+	pool, ok := ns.pools[namespace]
+	if !ok {
+		return nil, fmt.Errorf("namepool: namespace %s is not registered", namespace)
+	}
+
+	return pool, nil
+}
+
+// Acquire returns a Name from the pool of namespace or an
+// *ExhaustedError if all Names of the namespace are acquired.
+func (ns *Namespaces) Acquire(namespace string) (*Name, error) {
+	pool, err := ns.Pool(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return pool.TryAcquire()
+}
+
+// Namespaces returns the registered namespaces in alphabetical order.
+func (ns *Namespaces) Namespaces() []string {
+	ns.lock.RLock()
+	defer ns.lock.RUnlock()
+
+	namespaces := make([]string, 0, len(ns.pools))
+	for namespace := range ns.pools {
+		namespaces = append(namespaces, namespace)
+	}
+
+	sort.Strings(namespaces)
+	return namespaces
+}
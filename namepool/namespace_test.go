@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package namepool
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNamespaces(t *testing.T) {
+	ns := NewNamespaces()
+
+	if _, err := ns.Register("stmt", "stmt%d", 0); err != nil {
+		t.Errorf("Error registering namespace: %v", err)
+		return
+	}
+
+	if _, err := ns.Register("cur", "cur%d", 1); err != nil {
+		t.Errorf("Error registering namespace: %v", err)
+		return
+	}
+
+	if _, err := ns.Register("cur", "cursor%d", 1); err == nil {
+		t.Errorf("Expected error registering namespace twice")
+	}
+
+	stmt, err := ns.Acquire("stmt")
+	if err != nil {
+		t.Errorf("Error acquiring name: %v", err)
+		return
+	}
+
+	if stmt.Name() != "stmt1" {
+		t.Errorf("Expected name stmt1, received %s", stmt.Name())
+	}
+
+	cur, err := ns.Acquire("cur")
+	if err != nil {
+		t.Errorf("Error acquiring name: %v", err)
+		return
+	}
+
+	if cur.Name() != "cur1" {
+		t.Errorf("Expected name cur1, received %s", cur.Name())
+	}
+
+	_, err = ns.Acquire("cur")
+	var exhaustedErr *ExhaustedError
+	if !errors.As(err, &exhaustedErr) {
+		t.Errorf("Expected *ExhaustedError, received %v", err)
+	} else if exhaustedErr.Namespace != "cur" || exhaustedErr.Limit != 1 {
+		t.Errorf("Unexpected error details: %v", exhaustedErr)
+	}
+
+	cur.Release()
+
+	if _, err := ns.Acquire("cur"); err != nil {
+		t.Errorf("Expected released name to be available: %v", err)
+	}
+
+	if _, err := ns.Acquire("unknown"); err == nil {
+		t.Errorf("Expected error acquiring from unknown namespace")
+	}
+}
+
+func TestLimitedPool_AcquireBlocks(t *testing.T) {
+	pool := LimitedPool("%d", 1)
+
+	name := pool.Acquire()
+
+	acquired := make(chan *Name)
+	go func() {
+		acquired <- pool.Acquire()
+	}()
+
+	name.Release()
+
+	if recv := <-acquired; recv.Name() != "1" {
+		t.Errorf("Expected released name 1 to be reused, received %s", recv.Name())
+	}
+}
@@ -7,51 +7,156 @@ package namepool
 import (
 	"fmt"
 	"sync"
-	"sync/atomic"
 )
 
 type pool struct {
+	namespace string
 	format    string
+	// limit is the maximum number of simultaneously acquired Names.
+	// Zero means no limit.
+	limit int
+
+	lock *sync.Mutex
+	// released is signaled when a Name is released.
+	released  *sync.Cond
 	idCounter uint64
-	idPool    *sync.Pool
+	// freeIds holds the IDs of released names, which are reused
+	// before new IDs are generated.
+	freeIds  []uint64
+	acquired int
 }
 
-// Pool is a wrapper around a sync.Pool utilizing sync/atomic to provide
-// simple name pools that are safe to use by multiple goroutines.
+// Pool returns a simple name pool that is safe to use by multiple
+// goroutines.
+//
+// IDs of released Names are reused, hence the number of distinct names
+// is bound by the maximum number of simultaneously acquired Names.
 //
 // The argument format should include exactly one format verb for base
 // 10 integers (%d).
 // It is not an error if format doesn't include any verbs. The ID will
 // still be stored in acquired Names.
 func Pool(format string) *pool {
+	return LimitedPool(format, 0)
+}
+
+// LimitedPool returns a name pool like Pool, which hands out at most
+// limit Names simultaneously. A limit of zero or less means no limit.
+func LimitedPool(format string, limit int) *pool {
+	if limit < 0 {
+		limit = 0
+	}
+
 	pool := &pool{
 		format:    format,
+		limit:     limit,
+		lock:      &sync.Mutex{},
 		idCounter: 0,
+		freeIds:   []uint64{},
 	}
-
-	pool.idPool = &sync.Pool{
-		New: func() interface{} {
-			newId := atomic.AddUint64(&pool.idCounter, 1)
-			return &newId
-		},
-	}
+	pool.released = sync.NewCond(pool.lock)
 
 	return pool
 }
 
 // Acquire returns a Name from the name pool.
+//
+// If the pool is limited and all Names are acquired Acquire blocks
+// until a Name is released.
 func (pool *pool) Acquire() *Name {
-	id := pool.idPool.Get().(*uint64)
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	for pool.exhausted() {
+		pool.released.Wait()
+	}
+
+	return pool.acquire()
+}
+
+// TryAcquire returns a Name from the name pool or an *ExhaustedError
+// if the pool is limited and all Names are acquired.
+func (pool *pool) TryAcquire() (*Name, error) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	if pool.exhausted() {
+		return nil, &ExhaustedError{Namespace: pool.namespace, Limit: pool.limit}
+	}
+
+	return pool.acquire(), nil
+}
+
+// exhausted returns whether all Names are acquired. The caller must
+// hold the lock.
+func (pool *pool) exhausted() bool {
+	return pool.limit > 0 && pool.acquired >= pool.limit
+}
+
+// acquire returns a Name with a reused or new ID. The caller must hold
+// the lock.
+func (pool *pool) acquire() *Name {
+	var id uint64
+	if n := len(pool.freeIds); n > 0 {
+		id = pool.freeIds[n-1]
+		pool.freeIds = pool.freeIds[:n-1]
+	} else {
+		pool.idCounter++
+		id = pool.idCounter
+	}
+	pool.acquired++
 
 	return &Name{
-		name: fmt.Sprintf(pool.format, *id),
-		id:   id,
+		name: fmt.Sprintf(pool.format, id),
+		id:   &id,
+		pool: pool,
 	}
 }
 
 // Release returns a name to the name pool. The value name points to
 // will be reset to a default Name.
+//
+// Releasing a Name that has already been released is a no-op.
 func (pool *pool) Release(name *Name) {
-	pool.idPool.Put(name.id)
+	if name.id == nil {
+		return
+	}
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	pool.freeIds = append(pool.freeIds, *name.id)
+	pool.acquired--
 	*name = Name{}
+
+	pool.released.Signal()
+}
+
+// Acquired returns the number of Names that are currently acquired.
+func (pool *pool) Acquired() int {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	return pool.acquired
+}
+
+// Namespace returns the namespace of the pool, which is empty for
+// pools not created by Namespaces.
+func (pool *pool) Namespace() string {
+	return pool.namespace
+}
+
+// Limit returns the maximum number of simultaneously acquired Names.
+// Zero means no limit.
+func (pool *pool) Limit() int {
+	return pool.limit
+}
+
+// Free returns the number of released Names that are available for
+// reuse.
+func (pool *pool) Free() int {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	return len(pool.freeIds)
 }
@@ -5,6 +5,7 @@
 package namepool
 
 import (
+	"sync"
 	"testing"
 )
 
@@ -65,3 +66,60 @@ func TestPool_Release(t *testing.T) {
 		t.Errorf("Released Name has non-empty name")
 	}
 }
+
+func TestPool_Reuse(t *testing.T) {
+	pool := Pool("%d")
+
+	first := pool.Acquire()
+	second := pool.Acquire()
+
+	if first.Name() == second.Name() {
+		t.Errorf("Acquired Names share the name %s", first.Name())
+	}
+
+	firstName := first.Name()
+	first.Release()
+
+	if pool.Acquired() != 1 || pool.Free() != 1 {
+		t.Errorf("Expected 1 acquired and 1 free name, got %d and %d", pool.Acquired(), pool.Free())
+	}
+
+	reused := pool.Acquire()
+	if reused.Name() != firstName {
+		t.Errorf("Expected released name %s to be reused, received %s", firstName, reused.Name())
+	}
+
+	// Releasing twice must not add the ID twice.
+	reused.Release()
+	reused.Release()
+
+	if pool.Free() != 1 {
+		t.Errorf("Expected 1 free name after double release, got %d", pool.Free())
+	}
+}
+
+func TestPool_Concurrent(t *testing.T) {
+	pool := Pool("%d")
+
+	const goroutines, iterations = 8, 100
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				pool.Acquire().Release()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if pool.Acquired() != 0 {
+		t.Errorf("Expected no acquired names, got %d", pool.Acquired())
+	}
+
+	if pool.Free() > goroutines {
+		t.Errorf("Expected at most %d distinct names, got %d", goroutines, pool.Free())
+	}
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// WithRetry returns a Dialer that retries failed dials of dialer up to
+// retries times. The backoff between attempts starts at backoff and is
+// doubled for each retry.
+//
+// Retrying stops when the context passed to DialContext is done.
+func WithRetry(dialer Dialer, retries int, backoff time.Duration) Dialer {
+	return DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, address)
+
+		wait := backoff
+		for retry := 0; err != nil && retry < retries; retry++ {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("aborted retrying after %d retries: %w", retry, err)
+			case <-time.After(wait):
+			}
+			wait *= 2
+
+			conn, err = dialer.DialContext(ctx, network, address)
+		}
+
+		if err != nil && retries > 0 {
+			return nil, fmt.Errorf("failed after %d retries: %w", retries, err)
+		}
+
+		return conn, err
+	})
+}
+
+// WithConnWrapper returns a Dialer that passes the connections of
+// dialer to wrap, e.g. to record or throttle traffic.
+//
+// If wrap returns an error the connection is closed.
+func WithConnWrapper(dialer Dialer, wrap func(net.Conn) (net.Conn, error)) Dialer {
+	return DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		wrapped, err := wrap(conn)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error wrapping connection: %w", err)
+		}
+
+		return wrapped, nil
+	})
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/logging"
+	"github.com/SAP/go-dblib/replay"
+)
+
+func init() {
+	dsn.RegisterProps("network", "proxy", "record-dir", "dns-max-ttl", "tls-cert", "tls-key")
+}
+
+// Dialer is the interface of transports establishing connections to
+// a server. It is implemented by *net.Dialer.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// DialerFunc is an adapter to use functions as Dialer.
+type DialerFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// DialContext calls fn.
+func (fn DialerFunc) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return fn(ctx, network, address)
+}
+
+// UnixDialer dials unix domain sockets. The address is the path of the
+// socket.
+type UnixDialer struct {
+	Dialer net.Dialer
+}
+
+// DialContext implements the Dialer interface. The passed network is
+// ignored.
+func (dialer *UnixDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return dialer.Dialer.DialContext(ctx, "unix", address)
+}
+
+// Network returns the network of info, which is set by .Network or the
+// property "network". It defaults to "unix" for unix domain sockets,
+// see dsn.Info.IsUnixSocket, and "tcp" otherwise.
+func Network(info *dsn.Info) string {
+	if info.Network != "" {
+		return info.Network
+	}
+
+	if network := info.Prop("network"); network != "" {
+		return network
+	}
+
+	if info.IsUnixSocket() {
+		return "unix"
+	}
+
+	return "tcp"
+}
+
+// Address returns the address to dial for info, see dsn.Info.Address.
+// For unix domain sockets the address is the .Host of info.
+func Address(info *dsn.Info) string {
+	if strings.HasPrefix(Network(info), "unix") {
+		return info.Host
+	}
+
+	return info.Address()
+}
+
+// DialerFromDSN returns the Dialer for the transport described by
+// info:
+//
+//   - .Network selects the network, see Network.
+//   - TCP connections use the keepalive period .TCPKeepAlive and the
+//     socket options of info, see SocketOptionsDialer.
+//   - Host names are resolved with the DNSCache configured by the
+//     property "dns-max-ttl", see DNSCacheFromDSN.
+//   - .Proxy sets the SOCKS5 or HTTP proxy used to connect to the
+//     server, see ProxyDialer.
+//   - TLS is used if enabled, see TLSEnabled and TLSConfigFromDSN.
+//     The configuration is reloaded when its files change, see
+//     TLSReloaderFromDSN.
+//   - The property "record-dir" records the sessions to files in the
+//     directory, see replay.RecordToDir.
+func DialerFromDSN(info *dsn.Info) (Dialer, error) {
+	var dialer Dialer
+
+	network := Network(info)
+	if strings.HasPrefix(network, "unix") {
+		dialer = &UnixDialer{}
+	} else {
+		dialer = tcpDialer(info)
+
+		cache, err := DNSCacheFromDSN(info)
+		if err != nil {
+			return nil, err
+		}
+
+		if cache != nil {
+			dialer = &ResolvingDialer{Dialer: dialer, Cache: cache}
+		}
+	}
+
+	if proxy := proxyURL(info); proxy != "" {
+		if strings.HasPrefix(network, "unix") {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "proxy %s cannot be used with network %s", proxy, network)
+		}
+
+		var err error
+		dialer, err = ProxyDialer(dialer, proxy, info.ProxyUsername, info.ProxyPassword)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if TLSEnabled(info) {
+		reloader, err := TLSReloaderFromDSN(info)
+		if err != nil {
+			return nil, err
+		}
+
+		// A failed reload, e.g. of partially written files during
+		// rotation, keeps the previous configuration.
+		if _, err := reloader.ReloadIfChanged(); err != nil {
+			logging.Default().Warn("error reloading TLS configuration, using previous configuration", "error", err)
+		}
+
+		dialer = &TLSDialer{Dialer: dialer, Config: reloader.Config()}
+	}
+
+	// Sessions are recorded above TLS, so the recording contains the
+	// packets in plain text.
+	if dir := info.Prop("record-dir"); dir != "" {
+		dialer = WithConnWrapper(dialer, replay.RecordToDir(dir))
+	}
+
+	return dialer, nil
+}
@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package netlib provides the transports used to connect to a server.
+
+Transports implement the Dialer interface and can be composed, e.g.
+to dial through an HTTP proxy, wrap the connection in TLS and retry
+failed attempts:
+
+	var dialer netlib.Dialer = &net.Dialer{}
+	dialer = &netlib.HTTPProxyDialer{Dialer: dialer, ProxyAddress: "proxy:3128"}
+	dialer = &netlib.TLSDialer{Dialer: dialer, Config: tlsConfig}
+	dialer = netlib.WithRetry(dialer, 3, time.Second)
+
+	conn, err := dialer.DialContext(ctx, "tcp", "host:4901")
+
+DialerFromDSN composes the transport described by a dsn.Info. Its TLS
+configuration is reloaded when the CA, certificate or key files change,
+see TLSReloader.
+*/
+package netlib
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"github.com/SAP/go-dblib/dsn"
+)
+
+// Endpoint is the host and port of a server.
+type Endpoint = dsn.Endpoint
+
+// Endpoints returns the servers described by info, see
+// dsn.Info.Endpoints.
+func Endpoints(info *dsn.Info) ([]Endpoint, error) {
+	return info.Endpoints()
+}
+
+// ParseEndpoints parses comma separated host:port pairs, see
+// dsn.ParseEndpoints.
+func ParseEndpoints(s string) ([]Endpoint, error) {
+	return dsn.ParseEndpoints(s)
+}
+
+// WithEndpoint returns a copy of info with .Host and .Port set to
+// endpoint.
+//
+// The servers listed in .Hosts are removed from the copy, so only
+// endpoint is dialed.
+func WithEndpoint(info *dsn.Info, endpoint Endpoint) *dsn.Info {
+	copied := info.Clone()
+	copied.Host = endpoint.Host
+	copied.Port = endpoint.Port
+	copied.Hosts = nil
+
+	return copied
+}
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// proxyURL returns the proxy of info. The property "proxy" is used if
+// .Proxy is not set for backwards compatibility.
+func proxyURL(info *dsn.Info) string {
+	if info.Proxy != "" {
+		return info.Proxy
+	}
+
+	return info.Prop("proxy")
+}
+
+// ProxyDialer returns a Dialer connecting through the proxy at
+// proxyURL with dialer, which is either a SOCKS5Dialer for the scheme
+// "socks5" or a HTTPProxyDialer for the scheme "http" or addresses
+// without scheme.
+//
+// Credentials in proxyURL take precedence over username and password.
+// HTTP proxies receive the credentials with basic authentication.
+func ProxyDialer(dialer Dialer, proxyURL, username, password string) (Dialer, error) {
+	scheme, address := "http", proxyURL
+	if strings.Contains(proxyURL, "://") {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "error parsing proxy URL: %w", err)
+		}
+
+		scheme, address = u.Scheme, u.Host
+		if u.User != nil {
+			username = u.User.Username()
+			password, _ = u.User.Password()
+		}
+	}
+
+	if address == "" {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "proxy URL %s has no address", proxyURL)
+	}
+
+	switch scheme {
+	case "socks5", "socks5h":
+		return &SOCKS5Dialer{Dialer: dialer, ProxyAddress: address, Username: username, Password: password}, nil
+	case "http":
+		proxyDialer := &HTTPProxyDialer{Dialer: dialer, ProxyAddress: address}
+		if username != "" {
+			credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+			proxyDialer.Header = http.Header{"Proxy-Authorization": []string{"Basic " + credentials}}
+		}
+		return proxyDialer, nil
+	default:
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "unsupported proxy scheme %s", scheme)
+	}
+}
+
+// HTTPProxyDialer connects to the server through an HTTP proxy using
+// the CONNECT method.
+type HTTPProxyDialer struct {
+	// Dialer is used to connect to the proxy.
+	Dialer Dialer
+	// ProxyAddress is the address of the proxy.
+	ProxyAddress string
+	// Header is sent with the CONNECT request, e.g. to authenticate
+	// with the proxy.
+	Header http.Header
+}
+
+// DialContext implements the Dialer interface.
+func (dialer *HTTPProxyDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := dialer.Dialer.DialContext(ctx, network, dialer.ProxyAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to proxy %s: %w", dialer.ProxyAddress, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if err := dialer.connect(conn, address); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+func (dialer *HTTPProxyDialer) connect(conn net.Conn, address string) error {
+	header := dialer.Header
+	if header == nil {
+		header = http.Header{}
+	}
+
+	// http.Request.Write cannot be used as it writes the URL instead
+	// of the address for CONNECT requests.
+	if _, err := fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n", address, address); err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error writing CONNECT request: %w", err))
+	}
+
+	if err := header.Write(conn); err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error writing CONNECT headers: %w", err))
+	}
+
+	if _, err := fmt.Fprint(conn, "\r\n"); err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error writing CONNECT request: %w", err))
+	}
+
+	// The server does not send data before the client sent the login,
+	// hence the reader does not buffer data after the response.
+	resp, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error reading CONNECT response: %w", err))
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return dberrors.Errorf(dberrors.CategoryNetwork, "proxy %s refused connection to %s: %s",
+			dialer.ProxyAddress, address, resp.Status)
+	}
+
+	return nil
+}
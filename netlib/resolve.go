@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/hashicorp/go-multierror"
+)
+
+// DefaultDNSMaxTTL is the default duration resolved addresses are
+// cached.
+const DefaultDNSMaxTTL = 30 * time.Second
+
+// Resolver resolves host names. It is implemented by *net.Resolver.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// resolved are the cached addresses of a host.
+type resolved struct {
+	addrs   []string
+	expires time.Time
+}
+
+// DNSCache caches the addresses of host names.
+//
+// Addresses are cached for at most the max TTL. The address a
+// connection was last established to is tried first on subsequent
+// dials.
+type DNSCache struct {
+	resolver Resolver
+	maxTTL   time.Duration
+
+	lock  *sync.Mutex
+	hosts map[string]resolved
+}
+
+// NewDNSCache returns a DNSCache resolving with resolver and caching
+// addresses for at most maxTTL. If resolver is nil net.DefaultResolver
+// is used. If maxTTL is zero addresses are not cached.
+func NewDNSCache(resolver Resolver, maxTTL time.Duration) *DNSCache {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return &DNSCache{
+		resolver: resolver,
+		maxTTL:   maxTTL,
+		lock:     &sync.Mutex{},
+		hosts:    map[string]resolved{},
+	}
+}
+
+// Lookup returns the addresses of host. Cached addresses are returned
+// unless they expired or fresh is true.
+func (cache *DNSCache) Lookup(ctx context.Context, host string, fresh bool) ([]string, error) {
+	if !fresh {
+		cache.lock.Lock()
+		entry, ok := cache.hosts[host]
+		cache.lock.Unlock()
+
+		if ok && time.Now().Before(entry.expires) {
+			return entry.addrs, nil
+		}
+	}
+
+	addrs, err := cache.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error resolving %s: %w", host, err))
+	}
+
+	if cache.maxTTL > 0 {
+		cache.lock.Lock()
+		cache.hosts[host] = resolved{addrs: addrs, expires: time.Now().Add(cache.maxTTL)}
+		cache.lock.Unlock()
+	}
+
+	return addrs, nil
+}
+
+// prefer moves addr to the front of the cached addresses of host.
+func (cache *DNSCache) prefer(host, addr string) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	entry, ok := cache.hosts[host]
+	if !ok || len(entry.addrs) == 0 || entry.addrs[0] == addr {
+		return
+	}
+
+	// The cached slice may be in use by concurrent dials and is not
+	// modified in place.
+	addrs := make([]string, 0, len(entry.addrs))
+	addrs = append(addrs, addr)
+	for _, a := range entry.addrs {
+		if a != addr {
+			addrs = append(addrs, a)
+		}
+	}
+
+	// The address is not part of the current resolution.
+	if len(addrs) != len(entry.addrs) {
+		return
+	}
+
+	entry.addrs = addrs
+	cache.hosts[host] = entry
+}
+
+// Invalidate removes the cached addresses of host.
+func (cache *DNSCache) Invalidate(host string) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	delete(cache.hosts, host)
+}
+
+type contextKey int
+
+const freshResolutionKey contextKey = iota
+
+// WithFreshResolution returns a context whose dials through a
+// ResolvingDialer resolve host names instead of using cached
+// addresses, e.g. for reconnects and failovers driven by DNS changes.
+func WithFreshResolution(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshResolutionKey, true)
+}
+
+// freshResolution reports whether ctx requests fresh resolution.
+func freshResolution(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshResolutionKey).(bool)
+	return fresh
+}
+
+// ResolvingDialer resolves host names with a DNSCache and dials the
+// addresses in order until a connection is established.
+//
+// If no cached address could be dialed the host name is resolved again
+// and the new addresses are dialed, so servers moved by DNS changes are
+// reached.
+type ResolvingDialer struct {
+	Dialer Dialer
+	Cache  *DNSCache
+}
+
+// DialContext implements the Dialer interface.
+func (dialer *ResolvingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || dsn.IsIPLiteral(host) {
+		return dialer.Dialer.DialContext(ctx, network, address)
+	}
+
+	fresh := freshResolution(ctx)
+
+	addrs, err := dialer.Cache.Lookup(ctx, host, fresh)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, me := dialer.dialAddrs(ctx, network, host, port, addrs)
+	if conn != nil || fresh || ctx.Err() != nil {
+		return conn, me
+	}
+
+	// The cached addresses may be stale.
+	dialer.Cache.Invalidate(host)
+
+	addrs, err = dialer.Cache.Lookup(ctx, host, true)
+	if err != nil {
+		return nil, multierror.Append(me, err)
+	}
+
+	return dialer.dialAddrs(ctx, network, host, port, addrs)
+}
+
+// dialAddrs dials addrs in order and returns the first established
+// connection.
+func (dialer *ResolvingDialer) dialAddrs(ctx context.Context, network, host, port string, addrs []string) (net.Conn, error) {
+	var me error
+	for _, addr := range addrs {
+		conn, err := dialer.Dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			dialer.Cache.prefer(host, addr)
+			return conn, nil
+		}
+
+		me = multierror.Append(me, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if me == nil {
+		return nil, dberrors.Errorf(dberrors.CategoryNetwork, "no addresses for %s", host)
+	}
+
+	return nil, me
+}
+
+var (
+	dnsCachesLock = &sync.Mutex{}
+	dnsCaches     = map[time.Duration]*DNSCache{}
+)
+
+// DNSCacheFromDSN returns the DNSCache shared by all dsn.Infos with
+// the max TTL of info. The property "dns-max-ttl" sets the max TTL
+// and defaults to DefaultDNSMaxTTL. A max TTL of zero disables caching
+// and nil is returned.
+func DNSCacheFromDSN(info *dsn.Info) (*DNSCache, error) {
+	maxTTL, err := info.PropDuration("dns-max-ttl", DefaultDNSMaxTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxTTL <= 0 {
+		return nil, nil
+	}
+
+	dnsCachesLock.Lock()
+	defer dnsCachesLock.Unlock()
+
+	cache, ok := dnsCaches[maxTTL]
+	if !ok {
+		cache = NewDNSCache(nil, maxTTL)
+		dnsCaches[maxTTL] = cache
+	}
+
+	return cache, nil
+}
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testResolver resolves hosts from a map and counts lookups.
+type testResolver struct {
+	lock    *sync.Mutex
+	hosts   map[string][]string
+	lookups int
+}
+
+func newTestResolver(hosts map[string][]string) *testResolver {
+	return &testResolver{lock: &sync.Mutex{}, hosts: hosts}
+}
+
+func (resolver *testResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	resolver.lock.Lock()
+	defer resolver.lock.Unlock()
+
+	resolver.lookups++
+	addrs, ok := resolver.hosts[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+func (resolver *testResolver) set(host string, addrs ...string) {
+	resolver.lock.Lock()
+	defer resolver.lock.Unlock()
+
+	resolver.hosts[host] = addrs
+}
+
+// recordingDialer records the dialed addresses and only connects to
+// reachable addresses.
+type recordingDialer struct {
+	reachable map[string]bool
+	dialed    []string
+}
+
+func (dialer *recordingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer.dialed = append(dialer.dialed, address)
+	if !dialer.reachable[address] {
+		return nil, errors.New("connection refused")
+	}
+
+	client, server := net.Pipe()
+	server.Close()
+	return client, nil
+}
+
+func TestDNSCache_Lookup(t *testing.T) {
+	resolver := newTestResolver(map[string][]string{"db": {"10.0.0.1"}})
+	cache := NewDNSCache(resolver, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Lookup(context.Background(), "db", false); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if resolver.lookups != 1 {
+		t.Errorf("Expected cached addresses to be used, got %d lookups", resolver.lookups)
+	}
+
+	resolver.set("db", "10.0.0.2")
+	addrs, err := cache.Lookup(context.Background(), "db", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(addrs, []string{"10.0.0.2"}) {
+		t.Errorf("Expected fresh addresses, got %v", addrs)
+	}
+}
+
+func TestDNSCache_Lookup_Expired(t *testing.T) {
+	resolver := newTestResolver(map[string][]string{"db": {"10.0.0.1"}})
+	cache := NewDNSCache(resolver, time.Millisecond)
+
+	cache.Lookup(context.Background(), "db", false)
+	time.Sleep(5 * time.Millisecond)
+	cache.Lookup(context.Background(), "db", false)
+
+	if resolver.lookups != 2 {
+		t.Errorf("Expected expired addresses to be resolved again, got %d lookups", resolver.lookups)
+	}
+}
+
+func TestResolvingDialer(t *testing.T) {
+	resolver := newTestResolver(map[string][]string{"db": {"10.0.0.1", "10.0.0.2"}})
+	recorder := &recordingDialer{reachable: map[string]bool{"10.0.0.2:4901": true}}
+	dialer := &ResolvingDialer{Dialer: recorder, Cache: NewDNSCache(resolver, time.Hour)}
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", "db:4901")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+
+	// The reachable address is tried first.
+	recorder.dialed = nil
+	conn, err = dialer.DialContext(context.Background(), "tcp", "db:4901")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+
+	if !reflect.DeepEqual(recorder.dialed, []string{"10.0.0.2:4901"}) {
+		t.Errorf("Expected last established address to be dialed first, dialed %v", recorder.dialed)
+	}
+}
+
+func TestResolvingDialer_StaleCache(t *testing.T) {
+	resolver := newTestResolver(map[string][]string{"db": {"10.0.0.1"}})
+	recorder := &recordingDialer{reachable: map[string]bool{"10.0.0.1:4901": true}}
+	dialer := &ResolvingDialer{Dialer: recorder, Cache: NewDNSCache(resolver, time.Hour)}
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", "db:4901")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+
+	// The server moved to another address.
+	resolver.set("db", "10.0.0.9")
+	recorder.reachable = map[string]bool{"10.0.0.9:4901": true}
+
+	conn, err = dialer.DialContext(context.Background(), "tcp", "db:4901")
+	if err != nil {
+		t.Fatalf("Expected stale addresses to be resolved again, got: %v", err)
+	}
+	conn.Close()
+}
+
+func TestResolvingDialer_FreshResolution(t *testing.T) {
+	resolver := newTestResolver(map[string][]string{"db": {"10.0.0.1"}})
+	recorder := &recordingDialer{reachable: map[string]bool{"10.0.0.1:4901": true, "10.0.0.9:4901": true}}
+	dialer := &ResolvingDialer{Dialer: recorder, Cache: NewDNSCache(resolver, time.Hour)}
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", "db:4901")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+
+	// The old server is still reachable, but DNS points to the new one.
+	resolver.set("db", "10.0.0.9")
+	recorder.dialed = nil
+
+	conn, err = dialer.DialContext(WithFreshResolution(context.Background()), "tcp", "db:4901")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+
+	if !reflect.DeepEqual(recorder.dialed, []string{"10.0.0.9:4901"}) {
+		t.Errorf("Expected new address to be dialed, dialed %v", recorder.dialed)
+	}
+}
+
+func TestResolvingDialer_IP(t *testing.T) {
+	resolver := newTestResolver(map[string][]string{})
+	recorder := &recordingDialer{reachable: map[string]bool{"127.0.0.1:4901": true}}
+	dialer := &ResolvingDialer{Dialer: recorder, Cache: NewDNSCache(resolver, time.Hour)}
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", "127.0.0.1:4901")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+
+	if resolver.lookups != 0 {
+		t.Errorf("Expected IP addresses not to be resolved, got %d lookups", resolver.lookups)
+	}
+}
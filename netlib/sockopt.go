@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// SocketOptionsDialer sets socket options on the TCP connections
+// established by Dialer. Connections other than *net.TCPConn are
+// returned unchanged.
+type SocketOptionsDialer struct {
+	Dialer Dialer
+	// Delay disables TCP_NODELAY, which is set by default, so small
+	// packets are coalesced with Nagle's algorithm.
+	Delay bool
+	// ReadBuffer and WriteBuffer set SO_RCVBUF and SO_SNDBUF if they
+	// are greater than zero.
+	ReadBuffer, WriteBuffer int
+}
+
+// DialContext implements the Dialer interface.
+func (dialer *SocketOptionsDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := dialer.Dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return conn, nil
+	}
+
+	if err := dialer.apply(tcpConn); err != nil {
+		conn.Close()
+		return nil, dberrors.Wrap(dberrors.CategoryNetwork, err)
+	}
+
+	return conn, nil
+}
+
+func (dialer *SocketOptionsDialer) apply(conn *net.TCPConn) error {
+	if dialer.Delay {
+		if err := conn.SetNoDelay(false); err != nil {
+			return fmt.Errorf("error disabling TCP_NODELAY: %w", err)
+		}
+	}
+
+	if dialer.ReadBuffer > 0 {
+		if err := conn.SetReadBuffer(dialer.ReadBuffer); err != nil {
+			return fmt.Errorf("error setting read buffer to %d bytes: %w", dialer.ReadBuffer, err)
+		}
+	}
+
+	if dialer.WriteBuffer > 0 {
+		if err := conn.SetWriteBuffer(dialer.WriteBuffer); err != nil {
+			return fmt.Errorf("error setting write buffer to %d bytes: %w", dialer.WriteBuffer, err)
+		}
+	}
+
+	return nil
+}
+
+// tcpDialer returns the Dialer establishing TCP connections with the
+// keepalive and socket options of info.
+func tcpDialer(info *dsn.Info) Dialer {
+	var dialer Dialer = &net.Dialer{KeepAlive: info.TCPKeepAlive}
+
+	if info.TCPDelay || info.TCPReadBuffer > 0 || info.TCPWriteBuffer > 0 {
+		dialer = &SocketOptionsDialer{
+			Dialer:      dialer,
+			Delay:       info.TCPDelay,
+			ReadBuffer:  info.TCPReadBuffer,
+			WriteBuffer: info.TCPWriteBuffer,
+		}
+	}
+
+	return dialer
+}
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// SOCKS5 protocol constants, see RFC 1928 and RFC 1929.
+const (
+	socks5Version = 0x05
+
+	socks5AuthNone     = 0x00
+	socks5AuthPassword = 0x02
+
+	socks5CmdConnect = 0x01
+
+	socks5AddrIPv4   = 0x01
+	socks5AddrDomain = 0x03
+	socks5AddrIPv6   = 0x04
+)
+
+var socks5Replies = map[byte]string{
+	0x01: "general SOCKS server failure",
+	0x02: "connection not allowed by ruleset",
+	0x03: "network unreachable",
+	0x04: "host unreachable",
+	0x05: "connection refused",
+	0x06: "TTL expired",
+	0x07: "command not supported",
+	0x08: "address type not supported",
+}
+
+// SOCKS5Dialer connects to the server through a SOCKS5 proxy. Host
+// names are resolved by the proxy.
+type SOCKS5Dialer struct {
+	// Dialer is used to connect to the proxy.
+	Dialer Dialer
+	// ProxyAddress is the address of the proxy.
+	ProxyAddress string
+	// Username and Password authenticate with the proxy if Username is
+	// set.
+	Username, Password string
+}
+
+// DialContext implements the Dialer interface.
+func (dialer *SOCKS5Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := dialer.Dialer.DialContext(ctx, network, dialer.ProxyAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to proxy %s: %w", dialer.ProxyAddress, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if err := dialer.connect(conn, address); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+func (dialer *SOCKS5Dialer) connect(conn net.Conn, address string) error {
+	if err := dialer.authenticate(conn); err != nil {
+		return err
+	}
+
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return dberrors.Errorf(dberrors.CategoryConfig, "error parsing address %s: %w", address, err)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return dberrors.Errorf(dberrors.CategoryConfig, "error parsing port of address %s: %w", address, err)
+	}
+
+	req := []byte{socks5Version, socks5CmdConnect, 0x00}
+	host, _ = dsn.SplitZone(host)
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			req = append(append(req, socks5AddrIPv4), ip4...)
+		} else {
+			req = append(append(req, socks5AddrIPv6), ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return dberrors.Errorf(dberrors.CategoryConfig, "host name %s is too long for SOCKS5", host)
+		}
+		req = append(append(req, socks5AddrDomain, byte(len(host))), host...)
+	}
+	req = append(req, byte(port>>8), byte(port))
+
+	if _, err := conn.Write(req); err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error writing SOCKS5 connect request: %w", err))
+	}
+
+	// VER, REP, RSV, ATYP
+	resp := make([]byte, 4)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error reading SOCKS5 connect response: %w", err))
+	}
+
+	if resp[1] != 0x00 {
+		reason, ok := socks5Replies[resp[1]]
+		if !ok {
+			reason = fmt.Sprintf("reply(%d)", resp[1])
+		}
+		return dberrors.Errorf(dberrors.CategoryNetwork, "proxy %s refused connection to %s: %s",
+			dialer.ProxyAddress, address, reason)
+	}
+
+	// Discard the bound address and port.
+	var skip int
+	switch resp[3] {
+	case socks5AddrIPv4:
+		skip = net.IPv4len + 2
+	case socks5AddrIPv6:
+		skip = net.IPv6len + 2
+	case socks5AddrDomain:
+		var length [1]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error reading SOCKS5 bound address: %w", err))
+		}
+		skip = int(length[0]) + 2
+	default:
+		return dberrors.Errorf(dberrors.CategoryNetwork, "unknown SOCKS5 address type %d", resp[3])
+	}
+
+	if _, err := io.CopyN(ioutil.Discard, conn, int64(skip)); err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error reading SOCKS5 bound address: %w", err))
+	}
+
+	return nil
+}
+
+// authenticate negotiates the authentication method and authenticates
+// with username and password if the proxy requests it.
+func (dialer *SOCKS5Dialer) authenticate(conn net.Conn) error {
+	methods := []byte{socks5AuthNone}
+	if dialer.Username != "" {
+		methods = append(methods, socks5AuthPassword)
+	}
+
+	if _, err := conn.Write(append([]byte{socks5Version, byte(len(methods))}, methods...)); err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error writing SOCKS5 greeting: %w", err))
+	}
+
+	var resp [2]byte
+	if _, err := io.ReadFull(conn, resp[:]); err != nil {
+		return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error reading SOCKS5 greeting: %w", err))
+	}
+
+	if resp[0] != socks5Version {
+		return dberrors.Errorf(dberrors.CategoryNetwork, "proxy %s is not a SOCKS5 proxy", dialer.ProxyAddress)
+	}
+
+	switch resp[1] {
+	case socks5AuthNone:
+		return nil
+	case socks5AuthPassword:
+		if dialer.Username == "" {
+			break
+		}
+
+		if len(dialer.Username) > 255 || len(dialer.Password) > 255 {
+			return dberrors.New(dberrors.CategoryConfig, "SOCKS5 username and password are limited to 255 bytes")
+		}
+
+		req := []byte{0x01, byte(len(dialer.Username))}
+		req = append(req, dialer.Username...)
+		req = append(req, byte(len(dialer.Password)))
+		req = append(req, dialer.Password...)
+		if _, err := conn.Write(req); err != nil {
+			return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error writing SOCKS5 authentication: %w", err))
+		}
+
+		if _, err := io.ReadFull(conn, resp[:]); err != nil {
+			return dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error reading SOCKS5 authentication: %w", err))
+		}
+
+		if resp[1] != 0x00 {
+			return dberrors.Errorf(dberrors.CategoryNetwork, "proxy %s rejected the credentials", dialer.ProxyAddress)
+		}
+
+		return nil
+	}
+
+	return dberrors.Errorf(dberrors.CategoryNetwork, "proxy %s accepts none of the offered authentication methods", dialer.ProxyAddress)
+}
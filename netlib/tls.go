@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// TLSDialer wraps the connections of Dialer in TLS.
+type TLSDialer struct {
+	Dialer Dialer
+	Config *tls.Config
+}
+
+// DialContext implements the Dialer interface. The TLS handshake is
+// aborted if ctx is done.
+func (dialer *TLSDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := dialer.Dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	tlsConn := tls.Client(conn, dialer.Config)
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error during TLS handshake with server: %w", dberrors.Wrap(dberrors.CategoryNetwork, err))
+	}
+
+	conn.SetDeadline(time.Time{})
+	return tlsConn, nil
+}
+
+// TLSEnabled returns whether TLS is used to connect to the server of
+// info. TLS is used if .TLSEnable is set or the port is 443.
+func TLSEnabled(info *dsn.Info) bool {
+	return info.TLSEnable || strings.TrimSpace(strings.Replace(info.Port, "0", "", -1)) == "443"
+}
+
+// TLSConfigFromDSN returns the TLS configuration of info.
+//
+// .TLSCertFile sets the PEM encoded client certificate and .TLSKeyFile
+// its key, which defaults to the certificate file. Encrypted keys are
+// decrypted with .TLSKeyPassword.
+//
+// .TLSMinVersion and .TLSMaxVersion limit the negotiated TLS versions
+// and .TLSCipherSuites the cipher suites of TLS 1.2 and earlier. The
+// cipher suites of TLS 1.3 are not configurable.
+func TLSConfigFromDSN(info *dsn.Info) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	tlsConfig.ServerName = serverName(info.Host)
+	tlsConfig.InsecureSkipVerify = info.TLSSkipValidation
+
+	if info.TLSHostname != "" {
+		hostname := info.TLSHostname
+		if strings.HasPrefix(hostname, "CN=") {
+			hostname = strings.TrimPrefix(hostname, "CN=")
+		}
+
+		tlsConfig.ServerName = serverName(hostname)
+	}
+
+	if info.TLSMinVersion != "" {
+		version, err := dsn.ParseTLSVersion(info.TLSMinVersion)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if info.TLSMaxVersion != "" {
+		version, err := dsn.ParseTLSVersion(info.TLSMaxVersion)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.MaxVersion = version
+	}
+
+	if info.TLSCipherSuites != "" {
+		suites, err := dsn.ParseCipherSuites(info.TLSCipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.CipherSuites = suites
+	}
+
+	if info.TLSCAFile != "" {
+		bs, err := ioutil.ReadFile(info.TLSCAFile)
+		if err != nil {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "error reading file at ssl-ca path '%s': %w",
+				info.TLSCAFile, err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+
+		for {
+			var block *pem.Block
+			block, bs = pem.Decode(bs)
+			if block == nil {
+				break
+			}
+
+			caCert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, dberrors.Errorf(dberrors.CategoryConfig, "error parsing CA PEM at ssl-ca path '%s': %w",
+					info.TLSCAFile, err)
+			}
+
+			tlsConfig.RootCAs.AddCert(caCert)
+
+			if len(bs) == 0 {
+				break
+			}
+		}
+
+		if len(tlsConfig.RootCAs.Subjects()) == 0 {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "could not parse any valid CA certificate from file '%s'", info.TLSCAFile)
+		}
+	}
+
+	if certFile, keyFile := clientCertFiles(info); certFile != "" {
+		cert, err := loadClientCert(certFile, keyFile, info.TLSKeyPassword)
+		if err != nil {
+			return nil, dberrors.Errorf(dberrors.CategoryConfig, "error loading client certificate '%s' with key '%s': %w",
+				certFile, keyFile, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// clientCertFiles returns the files of the client certificate and its
+// key of info. The properties "tls-cert" and "tls-key" are used if the
+// fields are not set for backwards compatibility.
+func clientCertFiles(info *dsn.Info) (certFile, keyFile string) {
+	certFile = info.TLSCertFile
+	if certFile == "" {
+		certFile = info.Prop("tls-cert")
+	}
+
+	keyFile = info.TLSKeyFile
+	if keyFile == "" {
+		keyFile = info.PropDefault("tls-key", certFile)
+	}
+
+	return certFile, keyFile
+}
+
+// loadClientCert loads the client certificate from certFile and its
+// key from keyFile. If password is set encrypted PEM blocks of the key
+// are decrypted with it.
+func loadClientCert(certFile, keyFile, password string) (tls.Certificate, error) {
+	if password == "" {
+		return tls.LoadX509KeyPair(certFile, keyFile)
+	}
+
+	certPEM, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	keyPEM, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	var decrypted []byte
+	for {
+		var block *pem.Block
+		block, keyPEM = pem.Decode(keyPEM)
+		if block == nil {
+			break
+		}
+
+		// Keys encrypted by OpenSSL with e.g. -aes256 use the legacy
+		// PEM encryption.
+		if x509.IsEncryptedPEMBlock(block) {
+			der, err := x509.DecryptPEMBlock(block, []byte(password))
+			if err != nil {
+				return tls.Certificate{}, fmt.Errorf("error decrypting key: %w", err)
+			}
+			block = &pem.Block{Type: block.Type, Bytes: der}
+		}
+
+		decrypted = append(decrypted, pem.EncodeToMemory(block)...)
+	}
+
+	return tls.X509KeyPair(certPEM, decrypted)
+}
+
+// serverName returns the name of host to verify the certificate of the
+// server against. Brackets and zone identifiers of IPv6 literals are
+// removed, as they are not part of the certificate.
+func serverName(host string) string {
+	name, _ := dsn.SplitZone(host)
+	return name
+}
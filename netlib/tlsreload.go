@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+)
+
+// fileStamp identifies the version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// TLSReloader holds the TLS configuration of a dsn.Info and reloads it
+// when the CA, certificate or key files change, so new connections use
+// rotated certificates without restarting the application.
+//
+// Connections established with a previous configuration are not
+// affected by reloading.
+type TLSReloader struct {
+	info *dsn.Info
+	// config holds the current *tls.Config.
+	config atomic.Value
+
+	lock *sync.Mutex
+	// stamps are the versions of the files the current configuration
+	// was loaded from.
+	stamps map[string]fileStamp
+}
+
+// NewTLSReloader returns a TLSReloader with the TLS configuration of
+// info, see TLSConfigFromDSN.
+func NewTLSReloader(info *dsn.Info) (*TLSReloader, error) {
+	reloader := &TLSReloader{
+		info: info.Clone(),
+		lock: &sync.Mutex{},
+	}
+
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// Config returns the current TLS configuration. The returned
+// configuration must not be modified.
+func (reloader *TLSReloader) Config() *tls.Config {
+	return reloader.config.Load().(*tls.Config)
+}
+
+// Reload loads the TLS configuration from the files. If an error is
+// returned the previous configuration is kept.
+func (reloader *TLSReloader) Reload() error {
+	reloader.lock.Lock()
+	defer reloader.lock.Unlock()
+
+	return reloader.reloadLocked(reloader.statLocked())
+}
+
+// ReloadIfChanged reloads the TLS configuration if any of the files
+// changed since the last reload and reports whether the configuration
+// was reloaded. If an error is returned the previous configuration is
+// kept.
+func (reloader *TLSReloader) ReloadIfChanged() (bool, error) {
+	reloader.lock.Lock()
+	defer reloader.lock.Unlock()
+
+	stamps := reloader.statLocked()
+	if len(stamps) == len(reloader.stamps) {
+		changed := false
+		for file, stamp := range stamps {
+			if previous, ok := reloader.stamps[file]; !ok || !previous.modTime.Equal(stamp.modTime) || previous.size != stamp.size {
+				changed = true
+				break
+			}
+		}
+
+		if !changed {
+			return false, nil
+		}
+	}
+
+	if err := reloader.reloadLocked(stamps); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// reloadLocked loads the TLS configuration and records stamps as the
+// versions of the files.
+//
+// The caller must hold reloader.lock.
+func (reloader *TLSReloader) reloadLocked(stamps map[string]fileStamp) error {
+	config, err := TLSConfigFromDSN(reloader.info)
+	if err != nil {
+		return err
+	}
+
+	reloader.config.Store(config)
+	reloader.stamps = stamps
+	return nil
+}
+
+// statLocked returns the current versions of the files of the TLS
+// configuration. Files that cannot be read have a zero stamp.
+//
+// The caller must hold reloader.lock.
+func (reloader *TLSReloader) statLocked() map[string]fileStamp {
+	files := []string{reloader.info.TLSCAFile}
+	if certFile, keyFile := clientCertFiles(reloader.info); certFile != "" {
+		files = append(files, certFile, keyFile)
+	}
+
+	stamps := map[string]fileStamp{}
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+
+		stamp := fileStamp{}
+		if fileInfo, err := os.Stat(file); err == nil {
+			stamp = fileStamp{modTime: fileInfo.ModTime(), size: fileInfo.Size()}
+		}
+		stamps[file] = stamp
+	}
+
+	return stamps
+}
+
+var (
+	tlsReloadersLock = &sync.Mutex{}
+	tlsReloaders     = map[string]*TLSReloader{}
+)
+
+// TLSReloaderFromDSN returns the TLSReloader shared by all dsn.Infos
+// with the TLS configuration of info. The reloader is created on the
+// first call.
+//
+// Reload can be called on the returned TLSReloader to apply rotated
+// certificates explicitly.
+func TLSReloaderFromDSN(info *dsn.Info) (*TLSReloader, error) {
+	certFile, keyFile := clientCertFiles(info)
+	key := fmt.Sprintf("%s|%s|%t|%s|%s|%s|%s|%s|%s|%s", info.Host, info.TLSHostname, info.TLSSkipValidation,
+		info.TLSCAFile, certFile, keyFile, info.TLSKeyPassword,
+		info.TLSMinVersion, info.TLSMaxVersion, info.TLSCipherSuites)
+
+	tlsReloadersLock.Lock()
+	defer tlsReloadersLock.Unlock()
+
+	if reloader, ok := tlsReloaders[key]; ok {
+		return reloader, nil
+	}
+
+	reloader, err := NewTLSReloader(info)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsReloaders[key] = reloader
+	return reloader, nil
+}
@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package netlib
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+)
+
+// writeCert writes a self-signed certificate with the common name cn
+// and its key as PEM to certFile and keyFile.
+func writeCert(t *testing.T, cn, certFile, keyFile string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Error creating certificate: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatalf("Error writing certificate: %v", err)
+	}
+
+	if keyFile != "" {
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatalf("Error marshaling key: %v", err)
+		}
+
+		keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+		if err := ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+			t.Fatalf("Error writing key: %v", err)
+		}
+	}
+
+	return certPEM
+}
+
+// touch sets the modification time of file to a distinct time, as
+// writes within the resolution of the file system are not detected.
+func touch(t *testing.T, file string, offset time.Duration) {
+	mtime := time.Now().Add(offset)
+	if err := os.Chtimes(file, mtime, mtime); err != nil {
+		t.Fatalf("Error setting modification time: %v", err)
+	}
+}
+
+func TestTLSReloader(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	writeCert(t, "first", caFile, "")
+
+	info := dsn.NewInfo()
+	info.Host = "localhost"
+	info.TLSCAFile = caFile
+
+	reloader, err := NewTLSReloader(info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	first := reloader.Config()
+
+	if reloaded, err := reloader.ReloadIfChanged(); reloaded || err != nil {
+		t.Errorf("Expected no reload of unchanged files, got %t, %v", reloaded, err)
+	}
+
+	// Rotated CA bundle
+	writeCert(t, "second", caFile, "")
+	touch(t, caFile, time.Minute)
+
+	reloaded, err := reloader.ReloadIfChanged()
+	if !reloaded || err != nil {
+		t.Fatalf("Expected reload of changed file, got %t, %v", reloaded, err)
+	}
+
+	if reloader.Config() == first {
+		t.Errorf("Expected new configuration after reload")
+	}
+
+	// Partially written file during rotation
+	second := reloader.Config()
+	if err := ioutil.WriteFile(caFile, []byte("garbage"), 0600); err != nil {
+		t.Fatalf("Error writing file: %v", err)
+	}
+	touch(t, caFile, 2*time.Minute)
+
+	if _, err := reloader.ReloadIfChanged(); err == nil {
+		t.Errorf("Expected error reloading invalid file")
+	}
+
+	if reloader.Config() != second {
+		t.Errorf("Expected previous configuration to be kept after failed reload")
+	}
+}
+
+func TestTLSReloader_ClientCert(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	writeCert(t, "client", certFile, keyFile)
+
+	info := dsn.NewInfo()
+	info.Host = "localhost"
+	info.ConnectProps.Set("tls-cert", certFile)
+	info.ConnectProps.Set("tls-key", keyFile)
+
+	reloader, err := TLSReloaderFromDSN(info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if n := len(reloader.Config().Certificates); n != 1 {
+		t.Errorf("Expected client certificate, got %d certificates", n)
+	}
+
+	if same, err := TLSReloaderFromDSN(info); err != nil || same != reloader {
+		t.Errorf("Expected shared reloader, got %p, %v", same, err)
+	}
+}
+
+func TestTLSConfigFromDSN_EncryptedKey(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	writeCert(t, "client", certFile, keyFile)
+
+	keyPEM, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		t.Fatalf("Error reading key: %v", err)
+	}
+
+	block, _ := pem.Decode(keyPEM)
+	encrypted, err := x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte("secret"), x509.PEMCipherAES256)
+	if err != nil {
+		t.Fatalf("Error encrypting key: %v", err)
+	}
+
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(encrypted), 0600); err != nil {
+		t.Fatalf("Error writing key: %v", err)
+	}
+
+	info := dsn.NewInfo()
+	info.Host = "localhost"
+	info.TLSCertFile = certFile
+	info.TLSKeyFile = keyFile
+
+	if _, err := TLSConfigFromDSN(info); err == nil {
+		t.Errorf("Expected error loading encrypted key without password")
+	}
+
+	info.TLSKeyPassword = "wrong"
+	if _, err := TLSConfigFromDSN(info); err == nil {
+		t.Errorf("Expected error loading encrypted key with wrong password")
+	}
+
+	info.TLSKeyPassword = "secret"
+	config, err := TLSConfigFromDSN(info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if n := len(config.Certificates); n != 1 {
+		t.Errorf("Expected client certificate, got %d certificates", n)
+	}
+}
+
+func TestTLSConfigFromDSN_Versions(t *testing.T) {
+	info := dsn.NewInfo()
+	info.Host = "localhost"
+	info.TLSMinVersion = "TLS1.2"
+	info.TLSMaxVersion = "TLS1.3"
+	info.TLSCipherSuites = "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"
+
+	config, err := TLSConfigFromDSN(info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.MinVersion != tls.VersionTLS12 || config.MaxVersion != tls.VersionTLS13 {
+		t.Errorf("Unexpected versions %x-%x", config.MinVersion, config.MaxVersion)
+	}
+
+	if len(config.CipherSuites) != 1 || config.CipherSuites[0] != tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("Unexpected cipher suites: %v", config.CipherSuites)
+	}
+
+	info.TLSMaxVersion = "TLS2.0"
+	if _, err := TLSConfigFromDSN(info); err == nil {
+		t.Errorf("Expected error with unknown TLS version")
+	}
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package pool provides a session-aware connection pool for drivers that
+require more control than the pool of database/sql offers.
+
+The pool tracks the session state of each connection, such as the
+current database, charset and options. Connections are checked out
+with Get or GetSession, which prefers idle connections whose session
+state matches the requested state:
+
+	p, err := pool.New(ctx, pool.Config{
+		DSN:         info,
+		MaxOpen:     10,
+		MaxLifetime: time.Hour,
+		WarmUp:      2,
+	})
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	pc, err := p.GetSession(ctx, pool.SessionState{Database: "mydb"})
+	if err != nil {
+		return err
+	}
+	defer pc.Release()
+
+Idle connections are checked for liveness on checkout. Connections
+exceeding the maximum lifetime or idle time are closed when they are
+encountered on checkout or release.
+
+Shutdown closes the pool gracefully for service rollouts: it rejects
+new checkouts, waits until the connections in use are released or the
+context is done and reports the connections that had to be aborted.
+
+By default connections are established with DialTDS.
+*/
+package pool
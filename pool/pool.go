@@ -0,0 +1,462 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/logging"
+	"github.com/SAP/go-dblib/throttle"
+	"github.com/hashicorp/go-multierror"
+)
+
+// Conn is the interface of connections managed by a Pool.
+type Conn interface {
+	// Ping checks whether the connection is alive.
+	Ping(ctx context.Context) error
+	// State returns the current session state.
+	State() SessionState
+	Close() error
+}
+
+// Limited is implemented by connections whose rate of requests can be
+// limited.
+type Limited interface {
+	SetLimiter(limiter *throttle.Limiter)
+}
+
+// DialFunc establishes a connection to the server of info.
+//
+// info is a copy of Config.DSN and may be modified.
+type DialFunc func(ctx context.Context, info *dsn.Info) (Conn, error)
+
+// DefaultMaxIdle is the number of idle connections kept if
+// Config.MaxIdle is zero.
+const DefaultMaxIdle = 2
+
+// Config configures a Pool.
+type Config struct {
+	DSN *dsn.Info
+	// Dial establishes new connections. Defaults to DialTDS.
+	Dial DialFunc
+
+	// MaxOpen is the maximum number of open connections. Zero means
+	// unlimited.
+	MaxOpen int
+	// MaxIdle is the maximum number of idle connections. Zero means
+	// DefaultMaxIdle, negative values disable keeping idle
+	// connections.
+	MaxIdle int
+	// MaxLifetime is the maximum duration a connection is reused.
+	// Zero means unlimited.
+	MaxLifetime time.Duration
+	// MaxIdleTime is the maximum duration a connection is kept idle.
+	// Zero means .IdleTimeout of DSN, which defaults to unlimited.
+	MaxIdleTime time.Duration
+	// PingAfter is the duration after which idle connections are
+	// pinged on checkout. Zero pings on every checkout.
+	PingAfter time.Duration
+	// WarmUp is the number of connections established by New.
+	WarmUp int
+	// Limiter limits the rate of requests of all connections
+	// implementing Limited. Nil disables the limit.
+	Limiter *throttle.Limiter
+}
+
+// ErrClosed is returned when using a closed Pool.
+var ErrClosed = errors.New("pool is closed")
+
+// Stats contains the statistics of a Pool.
+type Stats struct {
+	Open, Idle, InUse int
+}
+
+// Pool manages connections to a server.
+type Pool struct {
+	config Config
+	now    func() time.Time
+
+	lock    *sync.Mutex
+	closed  bool
+	numOpen int
+	idle    []*PooledConn
+	// active are the checked out connections.
+	active map[*PooledConn]struct{}
+	// notify is closed and replaced whenever a connection is
+	// returned or closed to wake up waiting checkouts.
+	notify chan struct{}
+}
+
+// New returns a Pool and establishes config.WarmUp connections.
+func New(ctx context.Context, config Config) (*Pool, error) {
+	if config.DSN == nil {
+		return nil, errors.New("config has no DSN")
+	}
+
+	if config.Dial == nil {
+		config.Dial = DialTDS
+	}
+
+	if config.MaxIdle == 0 {
+		config.MaxIdle = DefaultMaxIdle
+	}
+
+	if config.MaxIdleTime == 0 {
+		config.MaxIdleTime = config.DSN.IdleTimeout
+	}
+
+	pool := &Pool{
+		config: config,
+		now:    time.Now,
+		lock:   &sync.Mutex{},
+		active: map[*PooledConn]struct{}{},
+		notify: make(chan struct{}),
+	}
+
+	if err := pool.warmUp(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// warmUp establishes config.WarmUp connections, limited by the
+// maximum number of open and idle connections.
+func (pool *Pool) warmUp(ctx context.Context) error {
+	n := pool.config.WarmUp
+	if pool.config.MaxOpen > 0 && n > pool.config.MaxOpen {
+		n = pool.config.MaxOpen
+	}
+	if n > pool.config.MaxIdle {
+		n = pool.config.MaxIdle
+	}
+
+	for i := 0; i < n; i++ {
+		pool.lock.Lock()
+		pool.numOpen++
+		pool.lock.Unlock()
+
+		pc, err := pool.open(ctx)
+		if err != nil {
+			return fmt.Errorf("error warming up connection %d: %w", i, err)
+		}
+
+		if err := pc.Release(); err != nil {
+			return fmt.Errorf("error releasing warmed up connection %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Get checks out a connection.
+func (pool *Pool) Get(ctx context.Context) (*PooledConn, error) {
+	return pool.GetSession(ctx, SessionState{})
+}
+
+// GetSession checks out a connection, preferring idle connections
+// whose session state matches want.
+//
+// If no connection is idle and the maximum number of open connections
+// is reached GetSession blocks until a connection is released or ctx
+// is done.
+func (pool *Pool) GetSession(ctx context.Context, want SessionState) (*PooledConn, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		pool.lock.Lock()
+		if pool.closed {
+			pool.lock.Unlock()
+			return nil, ErrClosed
+		}
+
+		pc, expired := pool.takeIdleLocked(want)
+		pool.closeExpired(expired)
+
+		if pc != nil {
+			pool.lock.Unlock()
+
+			if err := pool.checkLiveness(ctx, pc); err != nil {
+				pc.Discard()
+				continue
+			}
+
+			return pc, nil
+		}
+
+		if pool.config.MaxOpen <= 0 || pool.numOpen < pool.config.MaxOpen {
+			// The slot is reserved under the same lock as the
+			// check, so concurrent callers cannot exceed MaxOpen.
+			pool.numOpen++
+			pool.lock.Unlock()
+			return pool.open(ctx)
+		}
+
+		notify := pool.notify
+		pool.lock.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// open establishes a new connection.
+//
+// The caller must have reserved the connection by incrementing
+// pool.numOpen, which is rolled back if the dial fails.
+func (pool *Pool) open(ctx context.Context) (*PooledConn, error) {
+	conn, err := pool.config.Dial(ctx, pool.config.DSN.Clone())
+	if err != nil {
+		pool.lock.Lock()
+		pool.numOpen--
+		pool.notifyLocked()
+		pool.lock.Unlock()
+		return nil, fmt.Errorf("error establishing connection: %w", err)
+	}
+
+	if limited, ok := conn.(Limited); ok && pool.config.Limiter != nil {
+		limited.SetLimiter(pool.config.Limiter)
+	}
+
+	now := pool.now()
+	pc := &PooledConn{
+		conn:      conn,
+		pool:      pool,
+		createdAt: now,
+		lastUsed:  now,
+	}
+
+	pool.lock.Lock()
+	pool.active[pc] = struct{}{}
+	pool.lock.Unlock()
+
+	return pc, nil
+}
+
+// takeIdleLocked removes and returns an idle connection, preferring
+// connections whose session state matches want, and the expired idle
+// connections.
+//
+// The caller must hold pool.lock.
+func (pool *Pool) takeIdleLocked(want SessionState) (*PooledConn, []*PooledConn) {
+	now := pool.now()
+
+	idle := pool.idle[:0]
+	expired := []*PooledConn{}
+	for _, pc := range pool.idle {
+		if pool.expired(pc, now) {
+			expired = append(expired, pc)
+		} else {
+			idle = append(idle, pc)
+		}
+	}
+	pool.idle = idle
+
+	if len(pool.idle) == 0 {
+		return nil, expired
+	}
+
+	// Prefer the most recently used matching connection.
+	index := len(pool.idle) - 1
+	for i := len(pool.idle) - 1; i >= 0; i-- {
+		if pool.idle[i].conn.State().Matches(want) {
+			index = i
+			break
+		}
+	}
+
+	pc := pool.idle[index]
+	pc.released = false
+	pool.active[pc] = struct{}{}
+	pool.idle = append(pool.idle[:index], pool.idle[index+1:]...)
+	return pc, expired
+}
+
+// closeExpired closes the passed connections in the background.
+//
+// The caller must hold pool.lock.
+func (pool *Pool) closeExpired(expired []*PooledConn) {
+	if len(expired) == 0 {
+		return
+	}
+
+	pool.numOpen -= len(expired)
+	pool.notifyLocked()
+
+	go func() {
+		for _, pc := range expired {
+			if err := pc.conn.Close(); err != nil {
+				logging.Default().Debug("error closing expired connection", "error", err)
+			}
+		}
+	}()
+}
+
+// expired reports whether pc exceeded the maximum lifetime or idle
+// time.
+func (pool *Pool) expired(pc *PooledConn, now time.Time) bool {
+	if pool.config.MaxLifetime > 0 && now.Sub(pc.createdAt) >= pool.config.MaxLifetime {
+		return true
+	}
+
+	if pool.config.MaxIdleTime > 0 && now.Sub(pc.lastUsed) >= pool.config.MaxIdleTime {
+		return true
+	}
+
+	return false
+}
+
+// checkLiveness pings pc if it has been idle for at least
+// config.PingAfter.
+func (pool *Pool) checkLiveness(ctx context.Context, pc *PooledConn) error {
+	if pool.config.PingAfter > 0 && pool.now().Sub(pc.lastUsed) < pool.config.PingAfter {
+		return nil
+	}
+
+	return pc.conn.Ping(ctx)
+}
+
+// notifyLocked wakes up waiting checkouts.
+//
+// The caller must hold pool.lock.
+func (pool *Pool) notifyLocked() {
+	close(pool.notify)
+	pool.notify = make(chan struct{})
+}
+
+// Stats returns the statistics of the pool.
+func (pool *Pool) Stats() Stats {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	return Stats{
+		Open:  pool.numOpen,
+		Idle:  len(pool.idle),
+		InUse: pool.numOpen - len(pool.idle),
+	}
+}
+
+// Close closes the pool and all idle connections. Connections in use
+// are closed when they are released.
+//
+// If an error is returned it is a *multierror.Error with all errors.
+func (pool *Pool) Close() error {
+	pool.lock.Lock()
+	if pool.closed {
+		pool.lock.Unlock()
+		return nil
+	}
+
+	pool.closed = true
+	idle := pool.idle
+	pool.idle = nil
+	pool.numOpen -= len(idle)
+	pool.notifyLocked()
+	pool.lock.Unlock()
+
+	var me error
+	for _, pc := range idle {
+		if err := pc.conn.Close(); err != nil {
+			me = multierror.Append(me, fmt.Errorf("error closing connection: %w", err))
+		}
+	}
+
+	return me
+}
+
+// PooledConn is a connection checked out from a Pool.
+type PooledConn struct {
+	conn      Conn
+	pool      *Pool
+	createdAt time.Time
+	lastUsed  time.Time
+	released  bool
+	// aborted is set if the connection was closed by Shutdown while
+	// it was checked out.
+	aborted bool
+}
+
+// Conn returns the underlying connection. It must not be used after
+// the connection has been released.
+func (pc *PooledConn) Conn() Conn {
+	return pc.conn
+}
+
+// State returns the session state of the connection.
+func (pc *PooledConn) State() SessionState {
+	return pc.conn.State()
+}
+
+// Release returns the connection to the pool.
+//
+// The connection is closed if the pool is closed, the connection
+// expired or the maximum number of idle connections is reached.
+func (pc *PooledConn) Release() error {
+	pool := pc.pool
+
+	pool.lock.Lock()
+	if pc.aborted {
+		pool.lock.Unlock()
+		return ErrClosed
+	}
+
+	if pc.released {
+		pool.lock.Unlock()
+		return errors.New("connection has already been released")
+	}
+	pc.released = true
+	delete(pool.active, pc)
+
+	now := pool.now()
+	if pool.closed || pool.expired(pc, now) || len(pool.idle) >= pool.config.MaxIdle {
+		pool.numOpen--
+		pool.notifyLocked()
+		pool.lock.Unlock()
+		return pc.conn.Close()
+	}
+
+	pc.lastUsed = now
+	pool.idle = append(pool.idle, pc)
+	pool.notifyLocked()
+	pool.lock.Unlock()
+
+	return nil
+}
+
+// Discard closes the connection instead of returning it to the pool,
+// e.g. after an error left the connection in an unknown state.
+func (pc *PooledConn) Discard() error {
+	pool := pc.pool
+
+	pool.lock.Lock()
+	if pc.aborted {
+		pool.lock.Unlock()
+		return ErrClosed
+	}
+
+	if pc.released {
+		pool.lock.Unlock()
+		return errors.New("connection has already been released")
+	}
+	pc.released = true
+	delete(pool.active, pc)
+
+	pool.numOpen--
+	pool.notifyLocked()
+	pool.lock.Unlock()
+
+	return pc.conn.Close()
+}
@@ -0,0 +1,453 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/tds"
+	"github.com/SAP/go-dblib/throttle"
+)
+
+type testConn struct {
+	lock    *sync.Mutex
+	state   SessionState
+	pingErr error
+	pings   int
+	closed  bool
+	limiter *throttle.Limiter
+}
+
+func (conn *testConn) Ping(ctx context.Context) error {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	conn.pings++
+	return conn.pingErr
+}
+
+func (conn *testConn) State() SessionState {
+	return conn.state.Clone()
+}
+
+func (conn *testConn) SetLimiter(limiter *throttle.Limiter) {
+	conn.limiter = limiter
+}
+
+func (conn *testConn) Close() error {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	conn.closed = true
+	return nil
+}
+
+type testDialer struct {
+	lock  *sync.Mutex
+	conns []*testConn
+}
+
+func newTestPool(t *testing.T, config Config) (*Pool, *testDialer) {
+	dialer := &testDialer{lock: &sync.Mutex{}}
+
+	config.DSN = dsn.NewInfo()
+	config.Dial = func(ctx context.Context, info *dsn.Info) (Conn, error) {
+		dialer.lock.Lock()
+		defer dialer.lock.Unlock()
+
+		conn := &testConn{lock: &sync.Mutex{}}
+		dialer.conns = append(dialer.conns, conn)
+		return conn, nil
+	}
+
+	pool, err := New(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Unexpected error creating pool: %v", err)
+	}
+
+	return pool, dialer
+}
+
+func TestPool_Reuse(t *testing.T) {
+	pool, dialer := newTestPool(t, Config{})
+	defer pool.Close()
+
+	pc, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := pc.Release(); err != nil {
+		t.Fatalf("Unexpected error releasing: %v", err)
+	}
+
+	if err := pc.Release(); err == nil {
+		t.Errorf("Expected error releasing twice")
+	}
+
+	pc2, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer pc2.Release()
+
+	if pc2.Conn() != pc.Conn() {
+		t.Errorf("Expected idle connection to be reused")
+	}
+
+	if len(dialer.conns) != 1 {
+		t.Errorf("Expected one connection to be dialed, dialed %d", len(dialer.conns))
+	}
+
+	if dialer.conns[0].pings != 1 {
+		t.Errorf("Expected reused connection to be pinged once, pinged %d times", dialer.conns[0].pings)
+	}
+}
+
+func TestPool_WarmUp(t *testing.T) {
+	pool, dialer := newTestPool(t, Config{WarmUp: 3, MaxIdle: 2})
+	defer pool.Close()
+
+	if len(dialer.conns) != 2 {
+		t.Errorf("Expected warm up to be limited to MaxIdle, dialed %d", len(dialer.conns))
+	}
+
+	if stats := pool.Stats(); stats != (Stats{Open: 2, Idle: 2}) {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestPool_GetSession(t *testing.T) {
+	pool, dialer := newTestPool(t, Config{WarmUp: 2})
+	defer pool.Close()
+
+	dialer.conns[0].state = SessionState{Database: "db1"}
+	dialer.conns[1].state = SessionState{Database: "db2"}
+
+	for _, db := range []string{"db1", "db2", "db1"} {
+		pc, err := pool.GetSession(context.Background(), SessionState{Database: db})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if pc.State().Database != db {
+			t.Errorf("Expected connection with database %s, received %s", db, pc.State().Database)
+		}
+
+		pc.Release()
+	}
+}
+
+func TestPool_Liveness(t *testing.T) {
+	pool, dialer := newTestPool(t, Config{WarmUp: 1})
+	defer pool.Close()
+
+	dialer.conns[0].pingErr = errors.New("connection reset")
+
+	pc, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer pc.Release()
+
+	if !dialer.conns[0].closed {
+		t.Errorf("Expected dead connection to be closed")
+	}
+
+	if pc.Conn() != dialer.conns[1] {
+		t.Errorf("Expected new connection to be dialed")
+	}
+
+	if stats := pool.Stats(); stats != (Stats{Open: 1, InUse: 1}) {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestPool_Expiry(t *testing.T) {
+	pool, dialer := newTestPool(t, Config{WarmUp: 1, MaxLifetime: time.Hour})
+	defer pool.Close()
+
+	now := time.Now()
+	pool.now = func() time.Time { return now.Add(2 * time.Hour) }
+
+	pc, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer pc.Release()
+
+	if pc.Conn() == dialer.conns[0] {
+		t.Errorf("Expected expired connection to be replaced")
+	}
+
+	if stats := pool.Stats(); stats.Open != 1 {
+		t.Errorf("Expected one open connection, received %+v", stats)
+	}
+}
+
+func TestPool_MaxOpen(t *testing.T) {
+	pool, _ := newTestPool(t, Config{MaxOpen: 1})
+	defer pool.Close()
+
+	pc, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := pool.Get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected checkout to block until deadline, received: %v", err)
+	}
+
+	received := make(chan *PooledConn)
+	go func() {
+		pc, err := pool.Get(context.Background())
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		received <- pc
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	pc.Release()
+
+	pc2 := <-received
+	if pc2 == nil || pc2.Conn() != pc.Conn() {
+		t.Errorf("Expected released connection to be handed to waiting checkout")
+	}
+}
+
+func TestPool_Close(t *testing.T) {
+	pool, dialer := newTestPool(t, Config{WarmUp: 1})
+
+	pc, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Unexpected error closing: %v", err)
+	}
+
+	if _, err := pool.Get(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, received: %v", err)
+	}
+
+	pc.Release()
+	if !dialer.conns[0].closed {
+		t.Errorf("Expected connection released after close to be closed")
+	}
+}
+
+func TestPool_Limiter(t *testing.T) {
+	limiter, err := throttle.New(throttle.Config{Rate: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	pool, dialer := newTestPool(t, Config{Limiter: limiter})
+	defer pool.Close()
+
+	pc, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer pc.Release()
+
+	if dialer.conns[0].limiter != limiter {
+		t.Errorf("Expected limiter of pool to be set on connection")
+	}
+}
+
+func TestSessionState_Matches(t *testing.T) {
+	state := SessionState{
+		Database: "db",
+		Charset:  "utf8",
+		Options:  map[string]string{"textsize": "1024"},
+	}
+
+	cases := map[string]struct {
+		want    SessionState
+		matches bool
+	}{
+		"empty":    {SessionState{}, true},
+		"database": {SessionState{Database: "db"}, true},
+		"other db": {SessionState{Database: "other"}, false},
+		"option":   {SessionState{Options: map[string]string{"textsize": "1024"}}, true},
+		"missing":  {SessionState{Options: map[string]string{"rowcount": "1"}}, false},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			if matches := state.Matches(cas.want); matches != cas.matches {
+				t.Errorf("Expected %t, received %t", cas.matches, matches)
+			}
+		})
+	}
+}
+
+func TestPool_Shutdown(t *testing.T) {
+	pool, dialer := newTestPool(t, Config{WarmUp: 1})
+
+	ctx := context.Background()
+	pc, err := pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Check out a second connection while the first is in use.
+	second, err := pool.Get(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second.Release()
+
+	type result struct {
+		report ShutdownReport
+		err    error
+	}
+	resultCh := make(chan result, 1)
+
+	go func() {
+		report, err := pool.Shutdown(ctx)
+		resultCh <- result{report, err}
+	}()
+
+	select {
+	case res := <-resultCh:
+		t.Fatalf("Expected shutdown to wait for connection in use, returned %+v", res)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if _, err := pool.Get(ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got: %v", err)
+	}
+
+	if err := pc.Release(); err != nil {
+		t.Fatalf("Unexpected error releasing: %v", err)
+	}
+
+	res := <-resultCh
+	if res.err != nil {
+		t.Errorf("Unexpected error: %v", res.err)
+	}
+
+	if expected := (ShutdownReport{Closed: 2}); res.report != expected {
+		t.Errorf("Expected report %+v, got %+v", expected, res.report)
+	}
+
+	for _, conn := range dialer.conns {
+		if !conn.closed {
+			t.Errorf("Expected all connections to be closed")
+		}
+	}
+}
+
+func TestPool_Shutdown_Aborted(t *testing.T) {
+	pool, dialer := newTestPool(t, Config{})
+
+	pc, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	report, err := pool.Shutdown(ctx)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if expected := (ShutdownReport{Aborted: 1}); report != expected {
+		t.Errorf("Expected report %+v, got %+v", expected, report)
+	}
+
+	if !dialer.conns[0].closed {
+		t.Errorf("Expected aborted connection to be closed")
+	}
+
+	if err := pc.Release(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed releasing aborted connection, got: %v", err)
+	}
+
+	if stats := pool.Stats(); stats.Open != 0 {
+		t.Errorf("Expected no open connections, got %+v", stats)
+	}
+}
+
+func TestTDSConn_Do(t *testing.T) {
+	conn := &TDSConn{requests: make(chan struct{}, 1)}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go conn.Do(context.Background(), func(channel *tds.Channel) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	// A concurrent request waits for the running one.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := conn.Do(ctx, func(channel *tds.Channel) error { return nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected request to wait for the channel, received %v", err)
+	}
+
+	close(release)
+
+	if err := conn.Do(context.Background(), func(channel *tds.Channel) error { return nil }); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestPool_MaxOpenConcurrent(t *testing.T) {
+	lock := &sync.Mutex{}
+	dials := 0
+
+	config := Config{
+		DSN:     dsn.NewInfo(),
+		MaxOpen: 3,
+		Dial: func(ctx context.Context, info *dsn.Info) (Conn, error) {
+			lock.Lock()
+			dials++
+			lock.Unlock()
+
+			// Dials overlap with the checks of concurrent callers.
+			time.Sleep(10 * time.Millisecond)
+			return &testConn{lock: &sync.Mutex{}}, nil
+		},
+	}
+
+	pool, err := New(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Unexpected error creating pool: %v", err)
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.Get(ctx)
+		}()
+	}
+	wg.Wait()
+
+	if dials > config.MaxOpen {
+		t.Errorf("Expected at most %d connections, dialed %d", config.MaxOpen, dials)
+	}
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pool
+
+// SessionState is the state of a session on the server.
+type SessionState struct {
+	Database string
+	Charset  string
+	Language string
+	// Options maps the names of options set with `set` to their
+	// values.
+	Options map[string]string
+}
+
+// Matches reports whether state satisfies want. Empty fields of want
+// match any value.
+func (state SessionState) Matches(want SessionState) bool {
+	if want.Database != "" && want.Database != state.Database {
+		return false
+	}
+
+	if want.Charset != "" && want.Charset != state.Charset {
+		return false
+	}
+
+	if want.Language != "" && want.Language != state.Language {
+		return false
+	}
+
+	for option, value := range want.Options {
+		if state.Options[option] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a deep copy of state.
+func (state SessionState) Clone() SessionState {
+	clone := state
+	clone.Options = make(map[string]string, len(state.Options))
+	for option, value := range state.Options {
+		clone.Options[option] = value
+	}
+	return clone
+}
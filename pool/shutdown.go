@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Shutdowner is implemented by connections that can be closed
+// gracefully.
+type Shutdowner interface {
+	// Shutdown waits for requests in flight until ctx is done and
+	// closes the connection.
+	Shutdown(ctx context.Context) error
+}
+
+// ShutdownReport describes the outcome of Pool.Shutdown.
+type ShutdownReport struct {
+	// Closed is the number of connections closed after they were idle
+	// or released.
+	Closed int
+	// Aborted is the number of connections still checked out when the
+	// context passed to Shutdown was done.
+	Aborted int
+}
+
+// Shutdown closes the pool gracefully.
+//
+// New checkouts fail with ErrClosed. Idle connections are closed
+// immediately, checked out connections are closed when they are
+// released. Once ctx is done the connections still checked out are
+// aborted: they are closed and Release and Discard return ErrClosed.
+//
+// Connections implementing Shutdowner are closed with Shutdown.
+//
+// If an error is returned it is a *multierror.Error with all errors.
+func (pool *Pool) Shutdown(ctx context.Context) (ShutdownReport, error) {
+	pool.lock.Lock()
+	pool.closed = true
+	idle := pool.idle
+	pool.idle = nil
+	pool.numOpen -= len(idle)
+	pool.notifyLocked()
+	pool.lock.Unlock()
+
+	report := ShutdownReport{Closed: len(idle)}
+
+	var me error
+	for _, pc := range idle {
+		if err := shutdownConn(ctx, pc.conn); err != nil {
+			me = multierror.Append(me, fmt.Errorf("error closing idle connection: %w", err))
+		}
+	}
+
+	for {
+		pool.lock.Lock()
+		if len(pool.active) == 0 {
+			pool.lock.Unlock()
+			return report, me
+		}
+		notify := pool.notify
+		active := len(pool.active)
+		pool.lock.Unlock()
+
+		select {
+		case <-notify:
+			// Released connections are closed by Release.
+			pool.lock.Lock()
+			report.Closed += active - len(pool.active)
+			pool.lock.Unlock()
+		case <-ctx.Done():
+			return pool.abortActive(ctx, report, me)
+		}
+	}
+}
+
+// abortActive closes the connections still checked out.
+func (pool *Pool) abortActive(ctx context.Context, report ShutdownReport, me error) (ShutdownReport, error) {
+	pool.lock.Lock()
+	aborted := make([]*PooledConn, 0, len(pool.active))
+	for pc := range pool.active {
+		pc.aborted = true
+		aborted = append(aborted, pc)
+	}
+	pool.active = map[*PooledConn]struct{}{}
+	pool.numOpen -= len(aborted)
+	pool.notifyLocked()
+	pool.lock.Unlock()
+
+	report.Aborted = len(aborted)
+
+	for _, pc := range aborted {
+		if err := shutdownConn(ctx, pc.conn); err != nil {
+			me = multierror.Append(me, fmt.Errorf("error aborting connection: %w", err))
+		}
+	}
+
+	return report, me
+}
+
+// shutdownConn closes conn, gracefully if it implements Shutdowner.
+func shutdownConn(ctx context.Context, conn Conn) error {
+	if shutdowner, ok := conn.(Shutdowner); ok {
+		return shutdowner.Shutdown(ctx)
+	}
+
+	return conn.Close()
+}
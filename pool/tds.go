@@ -0,0 +1,246 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/SAP/go-dblib/audit"
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/health"
+	"github.com/SAP/go-dblib/serverinfo"
+	"github.com/SAP/go-dblib/tds"
+	"github.com/SAP/go-dblib/throttle"
+	"github.com/SAP/go-dblib/trace"
+	"github.com/hashicorp/go-multierror"
+)
+
+// TDSConn is a Conn over a logged in tds.Conn.
+type TDSConn struct {
+	Conn *tds.Conn
+	// Channel is the logical channel requests are sent on. Requests
+	// must be sent within Do, as Ping and Exec may be called
+	// concurrently, e.g. by the liveness checks of failover.Manager.
+	Channel *tds.Channel
+
+	// requests serializes the requests on Channel.
+	requests chan struct{}
+
+	stateLock *sync.Mutex
+	state     SessionState
+
+	serverInfo serverinfo.ServerInfo
+	// identity is the audit identity of the connection. The database
+	// is taken from the session state.
+	identity audit.Identity
+}
+
+// DialTDS establishes a connection to the server of info and logs in.
+// info is resolved and validated by tds.NewConn.
+//
+// The session state is updated from the environment changes sent by
+// the server.
+func DialTDS(ctx context.Context, info *dsn.Info) (Conn, error) {
+	conn, err := tds.NewConn(ctx, info)
+	if err != nil {
+		return nil, fmt.Errorf("error opening connection: %w", err)
+	}
+	info = conn.DSN()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error opening logical channel: %w", err)
+	}
+
+	loginConfig, err := tds.NewLoginConfig(info)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error creating login config: %w", err)
+	}
+
+	tdsConn := &TDSConn{
+		Conn:      conn,
+		Channel:   channel,
+		requests:  make(chan struct{}, 1),
+		stateLock: &sync.Mutex{},
+		state: SessionState{
+			Charset:  loginConfig.CharSet,
+			Language: loginConfig.Language,
+			Options:  map[string]string{},
+		},
+	}
+
+	// Hooks must be registered before logging in as the server
+	// reports the initial environment during login.
+	if err := channel.RegisterEnvChangeHooks(tdsConn.handleEnvChange); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error registering env change hook: %w", err)
+	}
+
+	if err := channel.Login(ctx, loginConfig); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error logging in: %w", err)
+	}
+
+	tdsConn.serverInfo = serverinfo.FromLogin(conn)
+	tdsConn.identity = audit.IdentityFromDSN(info)
+	tdsConn.identity.Server = conn.ServerName()
+
+	if info.Database != "" {
+		if err := tdsConn.Exec(ctx, "use "+info.Database); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error switching to database %s: %w", info.Database, err)
+		}
+	}
+
+	return tdsConn, nil
+}
+
+func (conn *TDSConn) handleEnvChange(typ tds.EnvChangeType, oldValue, newValue string) {
+	conn.stateLock.Lock()
+	defer conn.stateLock.Unlock()
+
+	switch typ {
+	case tds.TDS_ENV_DB:
+		conn.state.Database = newValue
+	case tds.TDS_ENV_CHARSET:
+		conn.state.Charset = newValue
+	case tds.TDS_ENV_LANG:
+		conn.state.Language = newValue
+	}
+}
+
+// ServerInfo returns the information about the server sent during
+// login, see serverinfo.FromLogin.
+func (conn *TDSConn) ServerInfo() serverinfo.ServerInfo {
+	return conn.serverInfo
+}
+
+// SetLimiter implements the Limited interface.
+func (conn *TDSConn) SetLimiter(limiter *throttle.Limiter) {
+	conn.Conn.SetLimiter(limiter)
+}
+
+// State implements the Conn interface.
+func (conn *TDSConn) State() SessionState {
+	conn.stateLock.Lock()
+	defer conn.stateLock.Unlock()
+
+	return conn.state.Clone()
+}
+
+// SetOption executes `set <option> <value>` and records the option in
+// the session state.
+func (conn *TDSConn) SetOption(ctx context.Context, option, value string) error {
+	if err := conn.Exec(ctx, fmt.Sprintf("set %s %s", option, value)); err != nil {
+		return err
+	}
+
+	conn.stateLock.Lock()
+	defer conn.stateLock.Unlock()
+
+	conn.state.Options[option] = value
+	return nil
+}
+
+// Do calls fn with the Channel of conn. Calls of Do, Ping and Exec are
+// serialized, so the requests and responses of concurrent callers do
+// not interleave on the channel.
+//
+// An error is returned if ctx is done before the channel is available.
+func (conn *TDSConn) Do(ctx context.Context, fn func(channel *tds.Channel) error) error {
+	select {
+	case conn.requests <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting for channel: %w", ctx.Err())
+	}
+	defer func() { <-conn.requests }()
+
+	return fn(conn.Channel)
+}
+
+// Ping implements the Conn interface using health.Ping.
+func (conn *TDSConn) Ping(ctx context.Context) error {
+	return conn.Do(ctx, func(channel *tds.Channel) error {
+		return health.Ping(ctx, channel)
+	})
+}
+
+// Identity returns the audit identity of the connection.
+func (conn *TDSConn) Identity() audit.Identity {
+	identity := conn.identity
+	if database := conn.State().Database; database != "" {
+		identity.Database = database
+	}
+	return identity
+}
+
+// Exec executes the language command cmd and discards its results.
+//
+// The command is reported to the Auditor of ctx, see audit.Start.
+func (conn *TDSConn) Exec(ctx context.Context, cmd string) error {
+	entry := audit.Start(ctx, conn.Identity(), audit.KindLanguage, cmd)
+	ctx, span := trace.Start(ctx, trace.KindQuery, "language", trace.Attr{Key: "query", Value: cmd})
+	err := conn.Do(ctx, func(channel *tds.Channel) error {
+		return exec(ctx, channel, cmd)
+	})
+	span.End(err)
+	entry.End(err)
+	return err
+}
+
+func exec(ctx context.Context, channel *tds.Channel, cmd string) error {
+	defer channel.Reset()
+
+	if err := channel.SendPackage(ctx, &tds.LanguagePackage{Cmd: cmd}); err != nil {
+		return fmt.Errorf("error sending language command: %w", err)
+	}
+
+	eedError := &tds.EEDError{}
+	_, err := channel.NextPackageUntil(ctx, true,
+		func(pkg tds.Package) (bool, error) {
+			switch typed := pkg.(type) {
+			case *tds.EEDPackage:
+				eedError.Add(typed)
+			case *tds.DonePackage:
+				if typed.Status&tds.TDS_DONE_ERROR == tds.TDS_DONE_ERROR {
+					eedError.WrappedError = errors.New("error executing language command")
+				}
+				return typed.Status&tds.TDS_DONE_MORE != tds.TDS_DONE_MORE, nil
+			}
+			return false, nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	if eedError.WrappedError != nil {
+		return eedError
+	}
+
+	return nil
+}
+
+// Shutdown implements the Shutdowner interface using
+// tds.Conn.Shutdown. The aborted requests are reported as error.
+func (conn *TDSConn) Shutdown(ctx context.Context) error {
+	report, err := conn.Conn.Shutdown(ctx)
+	if len(report.Aborted) > 0 {
+		err = multierror.Append(err, fmt.Errorf("aborted %d requests in flight: %v",
+			len(report.Aborted), report.Aborted))
+	}
+
+	return err
+}
+
+// Close implements the Conn interface.
+func (conn *TDSConn) Close() error {
+	return conn.Conn.Close()
+}
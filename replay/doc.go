@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package replay records TDS sessions and plays them back, so protocol
+bugs reported from production can be reproduced deterministically
+without access to the original server.
+
+Sessions are recorded to new files in a directory if the property
+"record-dir" of the dsn is set:
+
+	info.ConnectProps.Set("record-dir", "/var/tmp/recordings")
+
+Recordings contain all packets in plain text, including the results of
+queries and, depending on the authentication mechanism, credentials.
+They must be handled as confidential as the data of the server.
+
+A Replayer plays back the server side of a recording. The client code
+connects to a listener served by the Replayer instead of the server:
+
+	replayer, err := replay.LoadFile("session-20200101T120000-1.tdsrec")
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	go replayer.ServeListener(ctx, l)
+
+	info.Host, info.Port, _ = net.SplitHostPort(l.Addr().String())
+	conn, err := tds.NewConn(ctx, info)
+
+The packets sent by the client are compared to the recording by their
+type, or byte for byte if .Strict is set.
+*/
+package replay
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Direction is the direction a packet was sent in.
+type Direction uint8
+
+// Directions of packets.
+const (
+	// DirectionClient is the direction of packets sent by the client
+	// to the server.
+	DirectionClient Direction = iota
+	// DirectionServer is the direction of packets sent by the server
+	// to the client.
+	DirectionServer
+)
+
+var directionNames = map[Direction]string{
+	DirectionClient: "client",
+	DirectionServer: "server",
+}
+
+func (direction Direction) String() string {
+	if name, ok := directionNames[direction]; ok {
+		return name
+	}
+	return fmt.Sprintf("Direction(%d)", int(direction))
+}
+
+// Record is a packet of a recorded session.
+type Record struct {
+	Direction Direction
+	// Offset is the time since the start of the recording.
+	Offset time.Duration
+	// Data is the packet including its header.
+	Data []byte
+}
+
+// magic identifies recordings.
+var magic = []byte("TDSREC\x00\x01")
+
+// ErrInvalidRecording is returned when reading data that is not a
+// recording.
+var ErrInvalidRecording = errors.New("invalid recording")
+
+// Writer writes records to a recording.
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter returns a Writer writing a recording to w.
+func NewWriter(w io.Writer) (*Writer, error) {
+	writer := &Writer{w: bufio.NewWriter(w)}
+
+	if _, err := writer.w.Write(magic); err != nil {
+		return nil, fmt.Errorf("error writing header: %w", err)
+	}
+
+	return writer, writer.w.Flush()
+}
+
+// Write writes record to the recording.
+func (writer *Writer) Write(record Record) error {
+	if err := writer.w.WriteByte(byte(record.Direction)); err != nil {
+		return err
+	}
+
+	if err := binary.Write(writer.w, binary.BigEndian, int64(record.Offset)); err != nil {
+		return err
+	}
+
+	if err := binary.Write(writer.w, binary.BigEndian, uint32(len(record.Data))); err != nil {
+		return err
+	}
+
+	if _, err := writer.w.Write(record.Data); err != nil {
+		return err
+	}
+
+	return writer.w.Flush()
+}
+
+// Reader reads records from a recording.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader returns a Reader reading a recording from r.
+// ErrInvalidRecording is returned if r does not start with the header
+// of a recording.
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{r: bufio.NewReader(r)}
+
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(reader.r, header); err != nil {
+		return nil, fmt.Errorf("error reading header: %w", err)
+	}
+
+	if !bytes.Equal(header, magic) {
+		return nil, ErrInvalidRecording
+	}
+
+	return reader, nil
+}
+
+// Next returns the next record. io.EOF is returned at the end of the
+// recording.
+func (reader *Reader) Next() (Record, error) {
+	record := Record{}
+
+	direction, err := reader.r.ReadByte()
+	if err != nil {
+		return record, err
+	}
+	record.Direction = Direction(direction)
+
+	var offset int64
+	if err := binary.Read(reader.r, binary.BigEndian, &offset); err != nil {
+		return record, fmt.Errorf("error reading offset: %w", unexpectedEOF(err))
+	}
+	record.Offset = time.Duration(offset)
+
+	var length uint32
+	if err := binary.Read(reader.r, binary.BigEndian, &length); err != nil {
+		return record, fmt.Errorf("error reading length: %w", unexpectedEOF(err))
+	}
+
+	record.Data = make([]byte, length)
+	if _, err := io.ReadFull(reader.r, record.Data); err != nil {
+		return record, fmt.Errorf("error reading data: %w", unexpectedEOF(err))
+	}
+
+	return record, nil
+}
+
+// unexpectedEOF returns io.ErrUnexpectedEOF for io.EOF, as a record must
+// not be truncated.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// ReadAll returns all records of the recording read from r.
+func ReadAll(r io.Reader) ([]Record, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []Record{}
+	for {
+		record, err := reader.Next()
+		if err != nil {
+			if err == io.EOF {
+				return records, nil
+			}
+			return nil, fmt.Errorf("error reading record %d: %w", len(records), err)
+		}
+
+		records = append(records, record)
+	}
+}
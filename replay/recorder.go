@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SAP/go-dblib/logging"
+)
+
+// headerSize is the size of the header of a TDS packet.
+const headerSize = 8
+
+// packetLength returns the length of the packet starting with header.
+func packetLength(header []byte) int {
+	return int(binary.BigEndian.Uint16(header[2:4]))
+}
+
+// splitter splits the data sent in one direction into packets.
+type splitter struct {
+	buf []byte
+}
+
+// add appends bs and returns the completed packets.
+func (split *splitter) add(bs []byte) [][]byte {
+	split.buf = append(split.buf, bs...)
+
+	packets := [][]byte{}
+	for len(split.buf) >= headerSize {
+		length := packetLength(split.buf)
+		if length < headerSize {
+			// Not a TDS packet, record the data as is.
+			length = len(split.buf)
+		}
+
+		if len(split.buf) < length {
+			break
+		}
+
+		packet := make([]byte, length)
+		copy(packet, split.buf)
+		packets = append(packets, packet)
+		split.buf = split.buf[length:]
+	}
+
+	return packets
+}
+
+// Recorder is a net.Conn recording the packets sent and received to a
+// recording.
+//
+// Errors writing the recording are logged and stop the recording, the
+// connection is not affected.
+type Recorder struct {
+	net.Conn
+
+	start time.Time
+
+	lock      *sync.Mutex
+	w         io.WriteCloser
+	writer    *Writer
+	failed    bool
+	splitters map[Direction]*splitter
+}
+
+// NewRecorder returns a Recorder recording the packets of conn to w.
+// w is closed when the Recorder is closed.
+func NewRecorder(conn net.Conn, w io.WriteCloser) (*Recorder, error) {
+	writer, err := NewWriter(w)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{
+		Conn:   conn,
+		start:  time.Now(),
+		lock:   &sync.Mutex{},
+		w:      w,
+		writer: writer,
+		splitters: map[Direction]*splitter{
+			DirectionClient: {},
+			DirectionServer: {},
+		},
+	}, nil
+}
+
+// Read implements the net.Conn interface.
+func (recorder *Recorder) Read(bs []byte) (int, error) {
+	n, err := recorder.Conn.Read(bs)
+	recorder.record(DirectionServer, bs[:n])
+	return n, err
+}
+
+// Write implements the net.Conn interface.
+func (recorder *Recorder) Write(bs []byte) (int, error) {
+	n, err := recorder.Conn.Write(bs)
+	recorder.record(DirectionClient, bs[:n])
+	return n, err
+}
+
+// Close closes the connection and the recording.
+func (recorder *Recorder) Close() error {
+	err := recorder.Conn.Close()
+
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+
+	if closeErr := recorder.w.Close(); closeErr != nil && err == nil {
+		err = fmt.Errorf("error closing recording: %w", closeErr)
+	}
+
+	recorder.failed = true
+	return err
+}
+
+// record records the complete packets of the data transferred in
+// direction.
+func (recorder *Recorder) record(direction Direction, bs []byte) {
+	if len(bs) == 0 {
+		return
+	}
+
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+
+	if recorder.failed {
+		return
+	}
+
+	offset := time.Since(recorder.start)
+	for _, packet := range recorder.splitters[direction].add(bs) {
+		if err := recorder.writer.Write(Record{Direction: direction, Offset: offset, Data: packet}); err != nil {
+			logging.Default().Warn("error writing recording, stopping recording", "error", err)
+			recorder.failed = true
+			return
+		}
+	}
+}
+
+// recordingCounter distinguishes the recordings of RecordToDir.
+var recordingCounter uint64
+
+// RecordToDir returns a function recording connections to new files
+// in dir, e.g. to be passed to netlib.WithConnWrapper.
+func RecordToDir(dir string) func(net.Conn) (net.Conn, error) {
+	return func(conn net.Conn) (net.Conn, error) {
+		name := fmt.Sprintf("session-%s-%d.tdsrec", time.Now().Format("20060102T150405"),
+			atomic.AddUint64(&recordingCounter, 1))
+
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("error creating recording: %w", err)
+		}
+
+		recorder, err := NewRecorder(conn, f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		return recorder, nil
+	}
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// packet returns a TDS packet of type msgType with body.
+func packet(msgType byte, body string) []byte {
+	length := headerSize + len(body)
+	bs := []byte{msgType, 0x1, byte(length >> 8), byte(length), 0, 0, 0, 0}
+	return append(bs, body...)
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestWriterReader(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := NewWriter(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	records := []Record{
+		{Direction: DirectionClient, Offset: time.Millisecond, Data: packet(0x1, "select 1")},
+		{Direction: DirectionServer, Offset: 2 * time.Millisecond, Data: packet(0x4, "done")},
+	}
+
+	for _, record := range records {
+		if err := writer.Write(record); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	read, err := ReadAll(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(read, records) {
+		t.Errorf("Expected %v, got %v", records, read)
+	}
+}
+
+func TestReader_Invalid(t *testing.T) {
+	if _, err := NewReader(bytes.NewBufferString("not a recording")); !errors.Is(err, ErrInvalidRecording) {
+		t.Errorf("Expected ErrInvalidRecording, got %v", err)
+	}
+}
+
+// recordSession records a session with a client sending request in two
+// writes and a server answering with response.
+func recordSession(t *testing.T, request, response []byte) *bytes.Buffer {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	recorder, err := NewRecorder(client, nopCloser{buf})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	go func() {
+		io.ReadFull(server, make([]byte, len(request)))
+		server.Write(response)
+	}()
+
+	// The packet is split over multiple writes.
+	recorder.Write(request[:3])
+	recorder.Write(request[3:])
+
+	if _, err := io.ReadFull(recorder, make([]byte, len(response))); err != nil {
+		t.Fatalf("Error reading response: %v", err)
+	}
+	recorder.Close()
+
+	return buf
+}
+
+func TestRecorder_Replayer(t *testing.T) {
+	request := packet(0x1, "select 1")
+	response := packet(0x4, "done")
+
+	replayer, err := Load(recordSession(t, request, response))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if n := len(replayer.Records); n != 2 {
+		t.Fatalf("Expected two records, got %d", n)
+	}
+
+	client, server := net.Pipe()
+	defer client.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- replayer.Serve(context.Background(), server)
+	}()
+
+	if _, err := client.Write(packet(0x1, "select 2")); err != nil {
+		t.Fatalf("Error writing request: %v", err)
+	}
+
+	received := make([]byte, len(response))
+	if _, err := io.ReadFull(client, received); err != nil {
+		t.Fatalf("Error reading response: %v", err)
+	}
+
+	if !bytes.Equal(received, response) {
+		t.Errorf("Expected recorded response % x, got % x", response, received)
+	}
+
+	if err := <-errCh; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestReplayer_Strict(t *testing.T) {
+	replayer, err := Load(recordSession(t, packet(0x1, "select 1"), packet(0x4, "done")))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	replayer.Strict = true
+
+	client, server := net.Pipe()
+	defer client.Close()
+	go io.Copy(ioutil.Discard, client)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- replayer.Serve(context.Background(), server)
+	}()
+
+	client.Write(packet(0x1, "select 2"))
+
+	var mismatch *MismatchError
+	if err := <-errCh; !errors.As(err, &mismatch) || mismatch.Index != 0 {
+		t.Errorf("Expected mismatch of record 0, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+)
+
+// MismatchError is returned by Replayer.Serve when the client sends a
+// packet differing from the recording.
+type MismatchError struct {
+	// Index is the index of the record of the expected packet.
+	Index    int
+	Expected []byte
+	Received []byte
+}
+
+func (err *MismatchError) Error() string {
+	return fmt.Sprintf("packet of record %d differs from recording: expected % x, received % x",
+		err.Index, err.Expected, err.Received)
+}
+
+// Replayer plays back the server side of a recorded session.
+type Replayer struct {
+	Records []Record
+	// Strict requires the packets sent by the client to equal the
+	// recorded packets. Otherwise only the packet types are compared,
+	// as e.g. login packets contain random values.
+	Strict bool
+}
+
+// Load returns a Replayer for the recording read from r.
+func Load(r io.Reader) (*Replayer, error) {
+	records, err := ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Replayer{Records: records}, nil
+}
+
+// LoadFile returns a Replayer for the recording in the file at path.
+func LoadFile(path string) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening recording: %w", err)
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// Serve plays back the recording on conn: Packets recorded from the
+// client are read from conn and compared, packets recorded from the
+// server are written to conn.
+//
+// Serve returns when the recording was played back completely, ctx is
+// done or an error occurred. conn is not closed.
+func (replayer *Replayer) Serve(ctx context.Context, conn net.Conn) error {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			// Abort blocked reads and writes.
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	for i, record := range replayer.Records {
+		var err error
+		switch record.Direction {
+		case DirectionClient:
+			err = replayer.expect(conn, i, record)
+		case DirectionServer:
+			_, err = conn.Write(record.Data)
+		default:
+			err = fmt.Errorf("invalid direction %s", record.Direction)
+		}
+
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("error replaying record %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// expect reads a packet from conn and compares it to the packet of the
+// record at index i.
+func (replayer *Replayer) expect(conn net.Conn, i int, record Record) error {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("error reading packet header: %w", err)
+	}
+
+	packet := header
+	if length := packetLength(header); length > headerSize {
+		packet = make([]byte, length)
+		copy(packet, header)
+		if _, err := io.ReadFull(conn, packet[headerSize:]); err != nil {
+			return fmt.Errorf("error reading packet body: %w", err)
+		}
+	}
+
+	if replayer.Strict {
+		if !bytes.Equal(packet, record.Data) {
+			return &MismatchError{Index: i, Expected: record.Data, Received: packet}
+		}
+		return nil
+	}
+
+	if len(record.Data) == 0 || packet[0] != record.Data[0] {
+		return &MismatchError{Index: i, Expected: record.Data, Received: packet}
+	}
+
+	return nil
+}
+
+// ServeListener accepts a single connection on l, plays back the
+// recording on it and closes it. l is closed once the connection was
+// accepted.
+func (replayer *Replayer) ServeListener(ctx context.Context, l net.Listener) error {
+	accepted := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.Close()
+		case <-accepted:
+		}
+	}()
+
+	conn, err := l.Accept()
+	close(accepted)
+	l.Close()
+	if err != nil {
+		return fmt.Errorf("error accepting connection: %w", err)
+	}
+	defer conn.Close()
+
+	return replayer.Serve(ctx, conn)
+}
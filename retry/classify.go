@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package retry
+
+import (
+	"errors"
+	"fmt"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/throttle"
+)
+
+// Class is the result of classifying an error.
+type Class int
+
+// Classes of errors.
+const (
+	// ClassFatal is the class of errors that fail again when the
+	// operation is retried, e.g. syntax errors or invalid
+	// configurations.
+	ClassFatal Class = iota
+	// ClassRetryable is the class of transient errors that may not
+	// occur when the operation is retried, e.g. deadlocks or reset
+	// connections.
+	ClassRetryable
+)
+
+var classNames = map[Class]string{
+	ClassFatal:     "fatal",
+	ClassRetryable: "retryable",
+}
+
+func (class Class) String() string {
+	if name, ok := classNames[class]; ok {
+		return name
+	}
+	return fmt.Sprintf("Class(%d)", int(class))
+}
+
+// Classifier returns the Class of an error.
+type Classifier func(err error) Class
+
+// Classify is the default Classifier.
+//
+// Errors reported as retryable by dberrors.IsRetryable are retryable,
+// e.g. deadlocks, lock timeouts and connection errors, as well as
+// requests rejected by a throttle.Limiter. All other errors are fatal.
+// Errors of the context passed to the operation are always fatal.
+func Classify(err error) Class {
+	if errors.Is(err, throttle.ErrThrottled) || dberrors.IsRetryable(err) {
+		return ClassRetryable
+	}
+
+	return ClassFatal
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package retry retries operations failing with transient errors.
+
+Do calls an operation until it succeeds or returns an error that is not
+retryable. The backoff between attempts grows exponentially and can be
+randomized to spread the retries of concurrent operations:
+
+	policy := retry.Policy{
+		MaxAttempts:    5,
+		InitialBackoff: 50 * time.Millisecond,
+		Jitter:         0.5,
+	}
+
+	err := retry.Do(ctx, policy, func(ctx context.Context) error {
+		_, err := db.ExecContext(ctx, "update accounts set ...")
+		return err
+	})
+
+The Classifier of a Policy decides which errors are retried. Classify
+treats deadlocks, lock timeouts and connection errors as retryable and
+all other errors, e.g. syntax errors or constraint violations, as
+fatal. Custom classifiers can extend it:
+
+	policy.Classifier = func(err error) retry.Class {
+		if errors.Is(err, errMaintenance) {
+			return retry.ClassRetryable
+		}
+		return retry.Classify(err)
+	}
+
+A Budget shared by multiple policies limits the number of retries in
+relation to successful operations, so retries cannot overload a server
+that is already failing:
+
+	budget := retry.NewBudget(10, 0.1)
+
+PolicyFromDSN reads a Policy from the properties "retry-attempts",
+"retry-backoff", "retry-max-backoff" and "retry-jitter" of a dsn.
+*/
+package retry
@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+func init() {
+	dsn.RegisterProps("retry-attempts", "retry-backoff", "retry-max-backoff", "retry-jitter")
+}
+
+// Defaults of Policy.
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+	DefaultMultiplier     = 2.0
+)
+
+// ErrBudgetExhausted is wrapped in the error returned by Do when a
+// retry was prevented by the Budget of the Policy.
+var ErrBudgetExhausted = errors.New("retry budget exhausted")
+
+// Policy configures how operations are retried.
+type Policy struct {
+	// MaxAttempts is the maximum number of attempts including the
+	// first. Defaults to DefaultMaxAttempts.
+	MaxAttempts int
+	// InitialBackoff is the backoff before the first retry. Defaults
+	// to DefaultInitialBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff limits the backoff between retries. Defaults to
+	// DefaultMaxBackoff.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the backoff grows by for each retry.
+	// Defaults to DefaultMultiplier.
+	Multiplier float64
+	// Jitter is the fraction of the backoff that is randomized to
+	// spread retries of concurrent operations, between 0 and 1.
+	Jitter float64
+	// Budget limits the retries of all operations sharing it. If
+	// Budget is nil retries are only limited by MaxAttempts.
+	Budget *Budget
+	// Classifier decides which errors are retried. Defaults to
+	// Classify.
+	Classifier Classifier
+}
+
+// withDefaults returns policy with the defaults applied.
+func (policy Policy) withDefaults() Policy {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = DefaultMaxAttempts
+	}
+
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultInitialBackoff
+	}
+
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultMaxBackoff
+	}
+
+	if policy.Multiplier < 1 {
+		policy.Multiplier = DefaultMultiplier
+	}
+
+	if policy.Classifier == nil {
+		policy.Classifier = Classify
+	}
+
+	return policy
+}
+
+// Backoff returns the backoff before the passed retry, starting at
+// one for the first retry.
+func (policy Policy) Backoff(retry int) time.Duration {
+	policy = policy.withDefaults()
+
+	backoff := float64(policy.InitialBackoff) * math.Pow(policy.Multiplier, float64(retry-1))
+	if backoff > float64(policy.MaxBackoff) {
+		backoff = float64(policy.MaxBackoff)
+	}
+
+	if policy.Jitter > 0 {
+		jitter := math.Min(policy.Jitter, 1)
+		backoff -= backoff * jitter * rand.Float64()
+	}
+
+	return time.Duration(backoff)
+}
+
+// PolicyFromDSN returns the Policy configured by the properties of
+// info:
+//
+//   - "retry-attempts" sets MaxAttempts.
+//   - "retry-backoff" sets InitialBackoff.
+//   - "retry-max-backoff" sets MaxBackoff.
+//   - "retry-jitter" sets Jitter.
+//
+// Unset properties are left at their defaults.
+func PolicyFromDSN(info *dsn.Info) (Policy, error) {
+	policy := Policy{}
+
+	var err error
+	if policy.MaxAttempts, err = info.PropInt("retry-attempts", 0); err != nil {
+		return policy, err
+	}
+
+	if policy.InitialBackoff, err = info.PropDuration("retry-backoff", 0); err != nil {
+		return policy, err
+	}
+
+	if policy.MaxBackoff, err = info.PropDuration("retry-max-backoff", 0); err != nil {
+		return policy, err
+	}
+
+	if prop := info.Prop("retry-jitter"); prop != "" {
+		jitter, err := strconv.ParseFloat(prop, 64)
+		if err != nil {
+			return policy, dberrors.Errorf(dberrors.CategoryConfig, "error parsing float from retry-jitter '%s': %w", prop, err)
+		}
+
+		if jitter < 0 || jitter > 1 {
+			return policy, dberrors.Errorf(dberrors.CategoryConfig, "retry-jitter must be between 0 and 1, got %g", jitter)
+		}
+		policy.Jitter = jitter
+	}
+
+	return policy, nil
+}
+
+// Do calls fn until it succeeds, returns a fatal error, the attempts
+// of policy are exhausted or ctx is done.
+//
+// fn must be safe to call repeatedly, e.g. by acquiring a new
+// connection for each attempt after connection errors.
+func Do(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	policy = policy.withDefaults()
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			if policy.Budget != nil {
+				policy.Budget.deposit()
+			}
+			return nil
+		}
+
+		if policy.Classifier(err) != ClassRetryable {
+			return err
+		}
+
+		if attempt >= policy.MaxAttempts {
+			return fmt.Errorf("failed after %d attempts: %w", attempt, err)
+		}
+
+		if policy.Budget != nil && !policy.Budget.withdraw() {
+			return fmt.Errorf("%w after %d attempts: %v", ErrBudgetExhausted, attempt, err)
+		}
+
+		timer := time.NewTimer(policy.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("aborted retrying after %d attempts: %w", attempt, err)
+		case <-timer.C:
+		}
+	}
+}
+
+// Budget limits retries to a fraction of the successful operations, so
+// retries cannot multiply the load of a server that is failing.
+//
+// A Budget holds up to a maximum of tokens. Each retry takes a token and
+// each successful operation adds a fraction of a token. Retries are
+// rejected when no token is left.
+type Budget struct {
+	max   float64
+	ratio float64
+
+	lock   *sync.Mutex
+	tokens float64
+}
+
+// NewBudget returns a full Budget holding up to max tokens. Each
+// successful operation adds ratio tokens.
+func NewBudget(max int, ratio float64) *Budget {
+	return &Budget{
+		max:    float64(max),
+		ratio:  ratio,
+		lock:   &sync.Mutex{},
+		tokens: float64(max),
+	}
+}
+
+// withdraw takes a token and reports whether one was available.
+func (budget *Budget) withdraw() bool {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	if budget.tokens < 1 {
+		return false
+	}
+
+	budget.tokens--
+	return true
+}
+
+// deposit adds the tokens of a successful operation.
+func (budget *Budget) deposit() {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	budget.tokens = math.Min(budget.tokens+budget.ratio, budget.max)
+}
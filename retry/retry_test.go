@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/tds"
+)
+
+func eedError(msgNumber uint32) error {
+	return &tds.EEDError{
+		EEDPackages:  []*tds.EEDPackage{{MsgNumber: msgNumber}},
+		WrappedError: errors.New("error executing language command"),
+	}
+}
+
+func TestClassify(t *testing.T) {
+	cases := map[string]struct {
+		err   error
+		class Class
+	}{
+		"deadlock victim":  {fmt.Errorf("error: %w", eedError(1205)), ClassRetryable},
+		"lock timeout":     {eedError(12205), ClassRetryable},
+		"syntax error":     {eedError(102), ClassFatal},
+		"connection reset": {fmt.Errorf("error reading: %w", syscall.ECONNRESET), ClassRetryable},
+		"unexpected EOF":   {io.ErrUnexpectedEOF, ClassRetryable},
+		"network category": {dberrors.New(dberrors.CategoryNetwork, "dial failed"), ClassRetryable},
+		"config category":  {dberrors.New(dberrors.CategoryConfig, "invalid DSN"), ClassFatal},
+		"context canceled": {context.Canceled, ClassFatal},
+		"unknown":          {errors.New("unknown"), ClassFatal},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			if class := Classify(cas.err); class != cas.class {
+				t.Errorf("Expected %s, got %s", cas.class, class)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	attempts := 0
+	err := Do(context.Background(), Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+		func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return eedError(1205)
+			}
+			return nil
+		},
+	)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestDo_Fatal(t *testing.T) {
+	attempts := 0
+	fatal := eedError(102)
+	err := Do(context.Background(), Policy{InitialBackoff: time.Millisecond},
+		func(ctx context.Context) error {
+			attempts++
+			return fatal
+		},
+	)
+
+	if !errors.Is(err, fatal) || attempts != 1 {
+		t.Errorf("Expected fatal error after one attempt, got %v after %d attempts", err, attempts)
+	}
+}
+
+func TestDo_Exhausted(t *testing.T) {
+	err := Do(context.Background(), Policy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+		func(ctx context.Context) error {
+			return syscall.ECONNRESET
+		},
+	)
+
+	if !errors.Is(err, syscall.ECONNRESET) {
+		t.Errorf("Expected last error to be wrapped, got %v", err)
+	}
+}
+
+func TestDo_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := Do(ctx, Policy{MaxAttempts: 10, InitialBackoff: time.Hour},
+		func(ctx context.Context) error {
+			cancel()
+			return syscall.ECONNRESET
+		},
+	)
+
+	if !errors.Is(err, syscall.ECONNRESET) {
+		t.Errorf("Expected abort with last error, got %v", err)
+	}
+}
+
+func TestDo_Budget(t *testing.T) {
+	budget := NewBudget(1, 0.5)
+	policy := Policy{MaxAttempts: 10, InitialBackoff: time.Millisecond, Budget: budget}
+
+	attempts := 0
+	err := Do(context.Background(), policy,
+		func(ctx context.Context) error {
+			attempts++
+			return syscall.ECONNRESET
+		},
+	)
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("Expected ErrBudgetExhausted, got %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected a single retry, got %d attempts", attempts)
+	}
+
+	// Two successful operations refill the token.
+	for i := 0; i < 2; i++ {
+		Do(context.Background(), policy, func(ctx context.Context) error { return nil })
+	}
+
+	if !budget.withdraw() {
+		t.Errorf("Expected successful operations to refill the budget")
+	}
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, backoff := range expected {
+		if b := policy.Backoff(i + 1); b != backoff {
+			t.Errorf("Expected backoff %s for retry %d, got %s", backoff, i+1, b)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if b := policy.Backoff(1); b < 50*time.Millisecond || b > 100*time.Millisecond {
+			t.Errorf("Expected jittered backoff between 50ms and 100ms, got %s", b)
+		}
+	}
+}
+
+func TestPolicyFromDSN(t *testing.T) {
+	info := dsn.NewInfo()
+	info.ConnectProps.Set("retry-attempts", "5")
+	info.ConnectProps.Set("retry-backoff", "10ms")
+	info.ConnectProps.Set("retry-jitter", "0.2")
+
+	policy, err := PolicyFromDSN(info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if policy.MaxAttempts != 5 || policy.InitialBackoff != 10*time.Millisecond || policy.Jitter != 0.2 {
+		t.Errorf("Unexpected policy %+v", policy)
+	}
+
+	info.ConnectProps.Set("retry-jitter", "2")
+	if _, err := PolicyFromDSN(info); !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error for invalid jitter, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package scan maps the columns of result rows to the fields of structs.
+
+Columns are matched to fields by the tag `ase:"col_name"`. Fields
+without a tag match columns with the same name, ignoring case. Fields
+tagged with `ase:"-"` and unexported fields are ignored, as are columns
+without a matching field:
+
+	type Employee struct {
+		ID      int64  `ase:"emp_id"`
+		Name    string `ase:"name"`
+		Manager *int64 `ase:"manager_id"`
+		Salary  asetypes.Decimal
+		Address *Address `ase:"addr"`
+	}
+
+	type Address struct {
+		Street string `ase:"street"`
+		City   string `ase:"city"`
+	}
+
+	employees := []Employee{}
+	if err := scan.All(rows, &employees); err != nil {
+		return err
+	}
+
+Fields of embedded structs are mapped as if they were fields of the
+outer struct. Fields of other struct fields are matched to the columns
+prefixed with the name of the struct field and a dot, e.g.
+"addr.street". Structs implementing sql.Scanner as well as time.Time
+and asetypes.Decimal are treated as single values.
+
+NULL is stored as nil in pointers, slices, maps and interfaces, see
+tds.ConvertAssign. Pointers to structs are only allocated if at least
+one of their columns is not NULL, so an Address without a street and
+city is nil.
+
+Rows is implemented by *sql.Rows. StreamRows adapts a tds.RowStream.
+*/
+package scan
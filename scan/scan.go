@@ -0,0 +1,262 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// Rows are result rows.
+type Rows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// Struct scans the current row of rows into dest, which must be
+// a pointer to a struct. dest is set to its zero value before the
+// columns are stored.
+func Struct(rows Rows, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() || destValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("destination must be a non-nil pointer to a struct, got %T", dest)
+	}
+
+	return scanRow(rows, destValue.Elem())
+}
+
+// All scans all remaining rows of rows and appends them to dest, which
+// must be a pointer to a slice of structs or of pointers to structs.
+func All(rows Rows, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() || destValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("destination must be a non-nil pointer to a slice, got %T", dest)
+	}
+	slice := destValue.Elem()
+
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("destination must be a slice of structs or pointers to structs, got %T", dest)
+	}
+
+	for rows.Next() {
+		elem := reflect.New(structType)
+		if err := scanRow(rows, elem.Elem()); err != nil {
+			return err
+		}
+
+		if elemType.Kind() == reflect.Ptr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+
+	return rows.Err()
+}
+
+// scanRow scans the current row of rows into target.
+func scanRow(rows Rows, target reflect.Value) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("error reading columns: %w", err)
+	}
+
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return fmt.Errorf("error scanning row: %w", err)
+	}
+
+	m := structMapOf(target.Type())
+	target.Set(reflect.Zero(target.Type()))
+
+	// Non-NULL values are stored first to allocate the pointers to
+	// structs they are stored in. NULL values are only stored in
+	// allocated structs.
+	for _, null := range []bool{false, true} {
+		for i, column := range columns {
+			if (values[i] == nil) != null {
+				continue
+			}
+
+			index, ok := m.lookup(column)
+			if !ok {
+				continue
+			}
+
+			field, ok := fieldByIndex(target, index, !null)
+			if !ok {
+				continue
+			}
+
+			if err := tds.ConvertAssign(field.Addr().Interface(), values[i]); err != nil {
+				return fmt.Errorf("error storing column %s in field of type %s: %w", column, field.Type(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// fieldByIndex returns the field of v with index, see
+// reflect.Value.FieldByIndex.
+//
+// Nil pointers to structs on the way to the field are allocated if
+// alloc is true. Otherwise false is returned.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+
+	return v, true
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(asetypes.Decimal{})
+)
+
+// isValue reports whether values of the struct type t are stored in
+// a single column.
+func isValue(t reflect.Type) bool {
+	return t == timeType || t == decimalType || reflect.PtrTo(t).Implements(scannerType)
+}
+
+// structMap maps column names to the index of struct fields.
+type structMap struct {
+	// exact contains the names of tagged fields, folded the lower
+	// case names of fields matched ignoring case.
+	exact, folded map[string][]int
+}
+
+var (
+	structMapsLock = &sync.Mutex{}
+	structMaps     = map[reflect.Type]*structMap{}
+)
+
+// structMapOf returns the structMap of the struct type t.
+func structMapOf(t reflect.Type) *structMap {
+	structMapsLock.Lock()
+	defer structMapsLock.Unlock()
+
+	if m, ok := structMaps[t]; ok {
+		return m
+	}
+
+	m := &structMap{
+		exact:  map[string][]int{},
+		folded: map[string][]int{},
+	}
+	m.add(t, "", false, nil, map[reflect.Type]bool{})
+
+	structMaps[t] = m
+	return m
+}
+
+// lookup returns the index of the field mapped to column.
+func (m *structMap) lookup(column string) ([]int, bool) {
+	if index, ok := m.exact[column]; ok {
+		return index, true
+	}
+
+	index, ok := m.folded[strings.ToLower(column)]
+	return index, ok
+}
+
+// add adds the fields of the struct type t with the column prefix. If
+// fold is true the columns are matched ignoring case.
+//
+// visited contains the struct types on the path to t to prevent
+// recursing into self-referencing types.
+func (m *structMap) add(t reflect.Type, prefix string, fold bool, index []int, visited map[reflect.Type]bool) {
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		// Unexported fields cannot be set, embedded structs with an
+		// unexported type can be if they are no pointers.
+		if field.PkgPath != "" && (!field.Anonymous || field.Type.Kind() == reflect.Ptr) {
+			continue
+		}
+
+		tag := field.Tag.Get("ase")
+		if tag == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		isStruct := fieldType.Kind() == reflect.Struct && !isValue(fieldType)
+
+		if isStruct && visited[fieldType] {
+			continue
+		}
+
+		if isStruct && field.Anonymous && tag == "" {
+			m.add(fieldType, prefix, fold, fieldIndex, visited)
+			continue
+		}
+
+		name, fieldFold := tag, fold
+		if tag == "" {
+			name, fieldFold = field.Name, true
+		}
+
+		if isStruct {
+			m.add(fieldType, prefix+name+".", fieldFold, fieldIndex, visited)
+			continue
+		}
+
+		if fieldFold {
+			m.set(m.folded, strings.ToLower(prefix+name), fieldIndex)
+		} else {
+			m.set(m.exact, prefix+name, fieldIndex)
+		}
+	}
+}
+
+// set maps column to index unless it is mapped to a field with a lower
+// depth, as with Go's rules for embedded fields.
+func (m *structMap) set(columns map[string][]int, column string, index []int) {
+	if existing, ok := columns[column]; ok && len(existing) <= len(index) {
+		return
+	}
+	columns[column] = index
+}
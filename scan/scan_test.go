@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/asetypes"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/tds"
+)
+
+type testRows struct {
+	columns []string
+	rows    [][]interface{}
+	current int
+}
+
+func newTestRows(columns []string, rows ...[]interface{}) *testRows {
+	return &testRows{columns: columns, rows: rows, current: -1}
+}
+
+func (rows *testRows) Columns() ([]string, error) { return rows.columns, nil }
+func (rows *testRows) Err() error                 { return nil }
+
+func (rows *testRows) Next() bool {
+	rows.current++
+	return rows.current < len(rows.rows)
+}
+
+func (rows *testRows) Scan(dest ...interface{}) error {
+	for i, value := range rows.rows[rows.current] {
+		if err := tds.ConvertAssign(dest[i], value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type Base struct {
+	ID int64 `ase:"id"`
+}
+
+type Address struct {
+	Street string `ase:"street"`
+	City   string
+}
+
+type Employee struct {
+	Base
+	Name    string
+	Manager *int32         `ase:"manager_id"`
+	Note    sql.NullString `ase:"note"`
+	Salary  asetypes.Decimal
+	Address *Address `ase:"addr"`
+	Ignored string   `ase:"-"`
+	private string
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestAll(t *testing.T) {
+	salary, err := asetypes.NewDecimalString(10, 2, "1000.50")
+	if err != nil {
+		t.Fatalf("Unexpected error creating decimal: %v", err)
+	}
+
+	rows := newTestRows(
+		[]string{"id", "NAME", "manager_id", "note", "salary", "addr.street", "addr.city", "ignored", "unmapped"},
+		[]interface{}{int64(1), "alice", nil, "first", salary, "Main St", "Springfield", "x", "y"},
+		[]interface{}{int32(2), "bob", int32(1), nil, salary, nil, nil, "x", "y"},
+	)
+
+	employees := []Employee{}
+	if err := All(rows, &employees); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Employee{
+		{
+			Base:    Base{ID: 1},
+			Name:    "alice",
+			Note:    sql.NullString{String: "first", Valid: true},
+			Salary:  *salary,
+			Address: &Address{Street: "Main St", City: "Springfield"},
+		},
+		{
+			Base:    Base{ID: 2},
+			Name:    "bob",
+			Manager: int32Ptr(1),
+			Salary:  *salary,
+		},
+	}
+
+	if !reflect.DeepEqual(employees, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, employees)
+	}
+}
+
+func TestAll_Pointers(t *testing.T) {
+	rows := newTestRows([]string{"id"}, []interface{}{int64(1)}, []interface{}{int64(2)})
+
+	bases := []*Base{}
+	if err := All(rows, &bases); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(bases) != 2 || bases[0].ID != 1 || bases[1].ID != 2 {
+		t.Errorf("Expected bases 1 and 2, got %v", bases)
+	}
+}
+
+func TestStruct_PartialNull(t *testing.T) {
+	rows := newTestRows([]string{"addr.street", "addr.city"}, []interface{}{nil, "Springfield"})
+	rows.Next()
+
+	employee := Employee{}
+	if err := Struct(rows, &employee); !errors.Is(err, dberrors.CategoryConversion) {
+		t.Errorf("Expected NULL in a string field to be a conversion error, got: %v", err)
+	}
+}
+
+func TestStruct_InvalidDestination(t *testing.T) {
+	rows := newTestRows([]string{"id"}, []interface{}{int64(1)})
+	rows.Next()
+
+	if err := Struct(rows, Base{}); err == nil {
+		t.Errorf("Expected error for non-pointer destination")
+	}
+}
+
+type Node struct {
+	Name   string `ase:"name"`
+	Parent *Node  `ase:"parent"`
+}
+
+func TestStruct_SelfReferencing(t *testing.T) {
+	rows := newTestRows([]string{"name"}, []interface{}{"leaf"})
+	rows.Next()
+
+	node := Node{}
+	if err := Struct(rows, &node); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if node.Name != "leaf" || node.Parent != nil {
+		t.Errorf("Expected leaf without parent, got %+v", node)
+	}
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"context"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+type streamRows struct {
+	ctx    context.Context
+	stream *tds.RowStream
+}
+
+// StreamRows returns Rows reading from stream with ctx.
+func StreamRows(ctx context.Context, stream *tds.RowStream) Rows {
+	return &streamRows{ctx: ctx, stream: stream}
+}
+
+// Columns implements the Rows interface.
+func (rows *streamRows) Columns() ([]string, error) {
+	return rows.stream.Columns(rows.ctx)
+}
+
+// Next implements the Rows interface.
+func (rows *streamRows) Next() bool {
+	return rows.stream.Next(rows.ctx)
+}
+
+// Scan implements the Rows interface.
+func (rows *streamRows) Scan(dest ...interface{}) error {
+	return rows.stream.Scan(dest...)
+}
+
+// Err implements the Rows interface.
+func (rows *streamRows) Err() error {
+	return rows.stream.Err()
+}
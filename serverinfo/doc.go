@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package serverinfo detects the version and features of a server.
+
+FromLogin uses the information sent by the server during login, Query
+additionally parses @@version for the service pack and patch level:
+
+	info, err := serverinfo.Query(ctx, conn, channel)
+	if err != nil {
+		return err
+	}
+
+	if info.Has(serverinfo.BigDateTime) {
+		// Use bigdatetime columns
+	}
+
+pool.TDSConn records the ServerInfo of its connection after login.
+*/
+package serverinfo
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package serverinfo
+
+import (
+	"fmt"
+
+	"github.com/SAP/go-dblib/version"
+)
+
+// Feature is a server feature whose support depends on the server
+// version or the capabilities granted during login.
+type Feature int
+
+// Features of a server.
+const (
+	// BigDateTime is support for the bigdatetime and bigtime data
+	// types. It is detected from the granted capability
+	// tds.TDS_DATA_BIGDATETIME.
+	BigDateTime Feature = iota
+	// InRowLOB is support for storing large objects in the row.
+	InRowLOB
+	// JSON is support for JSON functions.
+	JSON
+)
+
+var featureNames = map[Feature]string{
+	BigDateTime: "bigdatetime",
+	InRowLOB:    "in-row-lob",
+	JSON:        "json",
+}
+
+func (feature Feature) String() string {
+	if name, ok := featureNames[feature]; ok {
+		return name
+	}
+	return fmt.Sprintf("Feature(%d)", int(feature))
+}
+
+// MinVersions are the server versions introducing features that are
+// detected by the server version.
+var MinVersions = map[Feature]version.Version{
+	InRowLOB: {Major: 15, Minor: 7},
+	JSON:     {Major: 16, Minor: 0, ServicePack: 2},
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package serverinfo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/SAP/go-dblib/tds"
+	"github.com/SAP/go-dblib/version"
+)
+
+// VersionQuery is the query used by Query to retrieve the version
+// string of the server.
+const VersionQuery = "select @@version"
+
+// ServerInfo describes a server.
+type ServerInfo struct {
+	// Name is the program name of the server, e.g. "sql server".
+	Name string
+	// Version is the version of the server.
+	Version version.Version
+	// VersionString is the full version string as returned by
+	// @@version. It is empty if the ServerInfo was created by
+	// FromLogin.
+	VersionString string
+	// TDSVersion is the TDS version acknowledged by the server.
+	TDSVersion version.Version
+
+	features map[Feature]bool
+}
+
+// FromLogin returns the ServerInfo of the server conn is logged in to
+// from the login acknowledgement and the granted capabilities.
+func FromLogin(conn *tds.Conn) ServerInfo {
+	info := ServerInfo{
+		Name:       conn.ServerName(),
+		Version:    conn.ServerVersion(),
+		TDSVersion: conn.TDSVersion(),
+	}
+	info.detect(conn.HasCapability)
+	return info
+}
+
+// Query returns the ServerInfo of the server conn is logged in to.
+//
+// In addition to FromLogin the version is parsed from @@version, which
+// contains the service pack and patch level of the server.
+func Query(ctx context.Context, conn *tds.Conn, channel *tds.Channel) (ServerInfo, error) {
+	info := FromLogin(conn)
+
+	versionString, err := queryVersion(ctx, channel)
+	if err != nil {
+		return info, err
+	}
+
+	parsed, err := version.Parse(versionString)
+	if err != nil {
+		return info, fmt.Errorf("error parsing @@version: %w", err)
+	}
+
+	info.Version = parsed
+	info.VersionString = versionString
+	info.detect(conn.HasCapability)
+
+	return info, nil
+}
+
+func queryVersion(ctx context.Context, channel *tds.Channel) (string, error) {
+	defer channel.Reset()
+
+	if err := channel.SendPackage(ctx, &tds.LanguagePackage{Cmd: VersionQuery}); err != nil {
+		return "", fmt.Errorf("error sending version query: %w", err)
+	}
+
+	stream := tds.NewRowStream(channel)
+
+	var versionString string
+	if stream.Next(ctx) {
+		if err := stream.Scan(&versionString); err != nil {
+			stream.Close(ctx)
+			return "", fmt.Errorf("error scanning @@version: %w", err)
+		}
+	}
+
+	if err := stream.Close(ctx); err != nil {
+		return "", fmt.Errorf("error querying @@version: %w", err)
+	}
+
+	if versionString == "" {
+		return "", fmt.Errorf("no version returned by server")
+	}
+
+	return versionString, nil
+}
+
+// detect sets the features of info. hasCapability reports whether the
+// server granted a capability.
+func (info *ServerInfo) detect(hasCapability func(tds.RequestCapability) bool) {
+	info.features = map[Feature]bool{
+		BigDateTime: hasCapability(tds.TDS_DATA_BIGDATETIME),
+	}
+
+	for feature, minVersion := range MinVersions {
+		info.features[feature] = !info.Version.IsZero() && info.Version.AtLeast(minVersion)
+	}
+}
+
+// Has returns true if the server supports feature.
+func (info ServerInfo) Has(feature Feature) bool {
+	return info.features[feature]
+}
+
+// Features returns the features supported by the server.
+func (info ServerInfo) Features() []Feature {
+	features := []Feature{}
+	for feature, supported := range info.features {
+		if supported {
+			features = append(features, feature)
+		}
+	}
+
+	sort.Slice(features, func(i, j int) bool {
+		return features[i] < features[j]
+	})
+	return features
+}
+
+func (info ServerInfo) String() string {
+	return fmt.Sprintf("%s %s (TDS %s, features %v)", info.Name, info.Version, info.TDSVersion, info.Features())
+}
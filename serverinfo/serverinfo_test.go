@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package serverinfo
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+	"github.com/SAP/go-dblib/version"
+)
+
+func TestServerInfo_Detect(t *testing.T) {
+	cases := map[string]struct {
+		version     version.Version
+		bigDateTime bool
+		expected    []Feature
+	}{
+		"unknown version": {
+			expected: []Feature{},
+		},
+		"15.7": {
+			version:     version.Version{Major: 15, Minor: 7},
+			bigDateTime: true,
+			expected:    []Feature{BigDateTime, InRowLOB},
+		},
+		"16.0 SP03": {
+			version:  version.Version{Major: 16, ServicePack: 3},
+			expected: []Feature{InRowLOB, JSON},
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			info := ServerInfo{Version: cas.version}
+			info.detect(func(capability tds.RequestCapability) bool {
+				return capability == tds.TDS_DATA_BIGDATETIME && cas.bigDateTime
+			})
+
+			if features := info.Features(); !reflect.DeepEqual(features, cas.expected) {
+				t.Errorf("Expected features %v, got %v", cas.expected, features)
+			}
+
+			for _, feature := range cas.expected {
+				if !info.Has(feature) {
+					t.Errorf("Expected feature %s to be supported", feature)
+				}
+			}
+		})
+	}
+}
+
+func TestFeature_String(t *testing.T) {
+	if s := InRowLOB.String(); s != "in-row-lob" {
+		t.Errorf("Expected in-row-lob, got %s", s)
+	}
+
+	if s := Feature(99).String(); s != "Feature(99)" {
+		t.Errorf("Expected Feature(99), got %s", s)
+	}
+}
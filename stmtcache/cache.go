@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package stmtcache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/logging"
+	"github.com/hashicorp/go-multierror"
+)
+
+// DefaultSize is the default number of statements kept in a Cache.
+const DefaultSize = 64
+
+// InvalidationMsgNumbers are the numbers of server messages signaling
+// that a prepared statement no longer exists on the server.
+var InvalidationMsgNumbers = []uint32{
+	// Stored procedure not found.
+	2812,
+}
+
+// Statement is a prepared statement.
+type Statement interface {
+	// Close deallocates the statement on the server.
+	Close(ctx context.Context) error
+}
+
+// PrepareFunc prepares query.
+type PrepareFunc func(ctx context.Context, query string) (Statement, error)
+
+// Config configures a Cache.
+type Config struct {
+	Prepare PrepareFunc
+	// Size is the maximum number of cached statements. Defaults to
+	// DefaultSize.
+	Size int
+	// IsInvalidated reports whether an error returned when using
+	// a statement signals that the statement was invalidated.
+	// Defaults to IsInvalidated.
+	IsInvalidated func(error) bool
+}
+
+// Stats contains the statistics of a Cache.
+type Stats struct {
+	// Size is the number of cached statements.
+	Size int
+	// Hits and Misses are the number of lookups that found or did not
+	// find a cached statement.
+	Hits, Misses uint64
+	// Evictions is the number of statements removed to stay within
+	// the size limit.
+	Evictions uint64
+	// Reprepares is the number of statements prepared again after
+	// they were invalidated.
+	Reprepares uint64
+}
+
+// ErrClosed is returned when using a closed Cache.
+var ErrClosed = errors.New("statement cache is closed")
+
+type entry struct {
+	query string
+	stmt  Statement
+}
+
+// Cache is a least recently used cache of prepared statements.
+type Cache struct {
+	config Config
+
+	lock    *sync.Mutex
+	closed  bool
+	entries map[string]*list.Element
+	// lru holds the entries, the most recently used entry first.
+	lru   *list.List
+	stats Stats
+}
+
+// New returns a Cache for config.
+func New(config Config) (*Cache, error) {
+	if config.Prepare == nil {
+		return nil, errors.New("config has no prepare function")
+	}
+
+	if config.Size <= 0 {
+		config.Size = DefaultSize
+	}
+
+	if config.IsInvalidated == nil {
+		config.IsInvalidated = IsInvalidated
+	}
+
+	return &Cache{
+		config:  config,
+		lock:    &sync.Mutex{},
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}, nil
+}
+
+// Get returns the statement for query and prepares it if it is not
+// cached.
+//
+// If the cache is full the least recently used statement is evicted
+// and closed. Errors closing evicted statements are logged.
+func (cache *Cache) Get(ctx context.Context, query string) (Statement, error) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if cache.closed {
+		return nil, ErrClosed
+	}
+
+	if elem, ok := cache.entries[query]; ok {
+		cache.stats.Hits++
+		cache.lru.MoveToFront(elem)
+		return elem.Value.(*entry).stmt, nil
+	}
+
+	cache.stats.Misses++
+
+	stmt, err := cache.config.Prepare(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing statement: %w", err)
+	}
+
+	cache.entries[query] = cache.lru.PushFront(&entry{query: query, stmt: stmt})
+
+	for cache.lru.Len() > cache.config.Size {
+		evicted := cache.removeLocked(cache.lru.Back())
+		cache.stats.Evictions++
+
+		if err := evicted.stmt.Close(ctx); err != nil {
+			logging.FromContext(ctx).Warn("error closing evicted statement", "query", evicted.query, "error", err)
+		}
+	}
+
+	return stmt, nil
+}
+
+// Do calls fn with the statement for query.
+//
+// If fn returns an error for which config.IsInvalidated reports true
+// the statement is removed, prepared again and fn is called once more.
+func (cache *Cache) Do(ctx context.Context, query string, fn func(Statement) error) error {
+	stmt, err := cache.Get(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	err = fn(stmt)
+	if err == nil || !cache.config.IsInvalidated(err) {
+		return err
+	}
+
+	logging.FromContext(ctx).Debug("statement invalidated, preparing again", "query", query, "error", err)
+
+	// The statement no longer exists on the server, errors
+	// deallocating it are expected.
+	cache.Invalidate(ctx, query)
+
+	cache.lock.Lock()
+	cache.stats.Reprepares++
+	cache.lock.Unlock()
+
+	stmt, err = cache.Get(ctx, query)
+	if err != nil {
+		return fmt.Errorf("error preparing invalidated statement again: %w", err)
+	}
+
+	return fn(stmt)
+}
+
+// Invalidate removes the statement for query from the cache and
+// closes it.
+func (cache *Cache) Invalidate(ctx context.Context, query string) error {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	elem, ok := cache.entries[query]
+	if !ok {
+		return nil
+	}
+
+	return cache.removeLocked(elem).stmt.Close(ctx)
+}
+
+// Stats returns the statistics of the cache.
+func (cache *Cache) Stats() Stats {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	stats := cache.stats
+	stats.Size = cache.lru.Len()
+	return stats
+}
+
+// Close closes all cached statements. Subsequent calls to Get and Do
+// return ErrClosed.
+//
+// If an error is returned it is a *multierror.Error with all errors.
+func (cache *Cache) Close(ctx context.Context) error {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if cache.closed {
+		return nil
+	}
+	cache.closed = true
+
+	var me error
+	for cache.lru.Len() > 0 {
+		removed := cache.removeLocked(cache.lru.Front())
+		if err := removed.stmt.Close(ctx); err != nil {
+			me = multierror.Append(me, fmt.Errorf("error closing statement for query '%s': %w", removed.query, err))
+		}
+	}
+
+	return me
+}
+
+// removeLocked removes elem from the cache and returns its entry.
+//
+// The caller must hold cache.lock.
+func (cache *Cache) removeLocked(elem *list.Element) *entry {
+	removed := cache.lru.Remove(elem).(*entry)
+	delete(cache.entries, removed.query)
+	return removed
+}
+
+// IsInvalidated reports whether err contains a server message with one
+// of the InvalidationMsgNumbers.
+func IsInvalidated(err error) bool {
+	return dberrors.HasMsgNumber(err, InvalidationMsgNumbers...)
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package stmtcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+type testStmt struct {
+	query  string
+	closed bool
+}
+
+func (stmt *testStmt) Close(ctx context.Context) error {
+	stmt.closed = true
+	return nil
+}
+
+func newTestCache(t *testing.T, size int) (*Cache, *[]*testStmt) {
+	prepared := &[]*testStmt{}
+
+	cache, err := New(Config{
+		Size: size,
+		Prepare: func(ctx context.Context, query string) (Statement, error) {
+			stmt := &testStmt{query: query}
+			*prepared = append(*prepared, stmt)
+			return stmt, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating cache: %v", err)
+	}
+
+	return cache, prepared
+}
+
+func TestCache_Get(t *testing.T) {
+	cache, prepared := newTestCache(t, 2)
+	ctx := context.Background()
+
+	for _, query := range []string{"a", "b", "a", "c"} {
+		if _, err := cache.Get(ctx, query); err != nil {
+			t.Fatalf("Unexpected error getting %s: %v", query, err)
+		}
+	}
+
+	// b is the least recently used statement when c is added.
+	if len(*prepared) != 3 {
+		t.Fatalf("Expected 3 prepared statements, got %d", len(*prepared))
+	}
+
+	if b := (*prepared)[1]; !b.closed {
+		t.Errorf("Expected evicted statement %s to be closed", b.query)
+	}
+
+	expected := Stats{Size: 2, Hits: 1, Misses: 3, Evictions: 1}
+	if stats := cache.Stats(); stats != expected {
+		t.Errorf("Expected stats %+v, got %+v", expected, stats)
+	}
+}
+
+func TestCache_Do_Reprepare(t *testing.T) {
+	cache, prepared := newTestCache(t, 2)
+	ctx := context.Background()
+
+	invalidated := &tds.EEDError{
+		EEDPackages: []*tds.EEDPackage{{MsgNumber: 2812, Msg: "Stored procedure not found"}},
+	}
+
+	calls := 0
+	err := cache.Do(ctx, "a", func(stmt Statement) error {
+		calls++
+		if calls == 1 {
+			return fmt.Errorf("error executing: %w", invalidated)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if calls != 2 || len(*prepared) != 2 {
+		t.Errorf("Expected two calls and preparations, got %d and %d", calls, len(*prepared))
+	}
+
+	if stats := cache.Stats(); stats.Reprepares != 1 {
+		t.Errorf("Expected one reprepare, got %d", stats.Reprepares)
+	}
+}
+
+func TestCache_Do_OtherError(t *testing.T) {
+	cache, prepared := newTestCache(t, 2)
+
+	errFailed := errors.New("failed")
+	err := cache.Do(context.Background(), "a", func(stmt Statement) error {
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Errorf("Expected error to be returned, got %v", err)
+	}
+
+	if len(*prepared) != 1 {
+		t.Errorf("Expected statement not to be prepared again")
+	}
+}
+
+func TestCache_Close(t *testing.T) {
+	cache, prepared := newTestCache(t, 2)
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx, "a"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := cache.Close(ctx); err != nil {
+		t.Fatalf("Unexpected error closing: %v", err)
+	}
+
+	if !(*prepared)[0].closed {
+		t.Errorf("Expected statement to be closed")
+	}
+
+	if _, err := cache.Get(ctx, "a"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package stmtcache caches prepared dynamic statements by their SQL text,
+so repeated queries on a connection avoid the round trips to prepare
+them.
+
+A Cache is bound to a connection, as dynamic statements are only valid
+on the connection that prepared them. TDSPrepareFunc prepares
+statements on a tds.Channel:
+
+	cache, err := stmtcache.New(stmtcache.Config{
+		Prepare: stmtcache.TDSPrepareFunc(conn, channel),
+		Size:    128,
+	})
+	if err != nil {
+		return err
+	}
+	defer cache.Close(ctx)
+
+	err = cache.Do(ctx, "select * from t where a = ?", func(stmt stmtcache.Statement) error {
+		return execute(stmt.(*stmtcache.TDSStatement), args)
+	})
+
+If the cache is full the least recently used statement is evicted and
+deallocated. If the server invalidated a statement, e.g. because it was
+dropped, Do prepares the statement again and retries once.
+*/
+package stmtcache
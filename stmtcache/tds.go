@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package stmtcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SAP/go-dblib/namepool"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// stmtNames provides the IDs of dynamic statements.
+var stmtNames = namepool.Pool("dblib_stmt%d")
+
+// TDSStatement is a dynamic statement prepared on a tds.Channel.
+type TDSStatement struct {
+	Channel *tds.Channel
+	Query   string
+	// ParamFmt and RowFmt are the formats of the parameters and
+	// result rows as reported by the server. They are nil if the
+	// statement has no parameters or returns no rows.
+	ParamFmt *tds.ParamFmtPackage
+	RowFmt   *tds.RowFmtPackage
+
+	name *namepool.Name
+	wide bool
+}
+
+// TDSPrepareFunc returns a PrepareFunc preparing dynamic statements
+// on channel of conn.
+func TDSPrepareFunc(conn *tds.Conn, channel *tds.Channel) PrepareFunc {
+	return func(ctx context.Context, query string) (Statement, error) {
+		stmt := &TDSStatement{
+			Channel: channel,
+			Query:   query,
+			name:    stmtNames.Acquire(),
+			wide:    conn.HasCapability(tds.TDS_WIDETABLES),
+		}
+
+		if err := stmt.prepare(ctx); err != nil {
+			stmt.name.Release()
+			return nil, err
+		}
+
+		return stmt, nil
+	}
+}
+
+// ID returns the ID of the statement on the server.
+func (stmt *TDSStatement) ID() string {
+	return stmt.name.Name()
+}
+
+func (stmt *TDSStatement) prepare(ctx context.Context) error {
+	pkg := tds.NewDynamicPackage(stmt.wide)
+	pkg.Type = tds.TDS_DYN_PREPARE
+	pkg.ID = stmt.ID()
+	pkg.Stmt = fmt.Sprintf("create proc %s as %s", stmt.ID(), stmt.Query)
+
+	return stmt.roundTrip(ctx, pkg, func(pkg tds.Package) error {
+		switch typed := pkg.(type) {
+		case *tds.ParamFmtPackage:
+			stmt.ParamFmt = typed
+		case *tds.RowFmtPackage:
+			stmt.RowFmt = typed
+		}
+		return nil
+	})
+}
+
+// Close implements the Statement interface.
+func (stmt *TDSStatement) Close(ctx context.Context) error {
+	defer stmt.name.Release()
+
+	pkg := tds.NewDynamicPackage(stmt.wide)
+	pkg.Type = tds.TDS_DYN_DEALLOC
+	pkg.ID = stmt.ID()
+
+	return stmt.roundTrip(ctx, pkg, nil)
+}
+
+// roundTrip sends pkg and reads the response until the final done
+// package, passing all packages to handle.
+func (stmt *TDSStatement) roundTrip(ctx context.Context, pkg *tds.DynamicPackage, handle func(tds.Package) error) error {
+	defer stmt.Channel.Reset()
+
+	if err := stmt.Channel.SendPackage(ctx, pkg); err != nil {
+		return fmt.Errorf("error sending %s: %w", pkg.Type, err)
+	}
+
+	_, err := stmt.Channel.NextPackageUntil(ctx, true,
+		func(pkg tds.Package) (bool, error) {
+			switch typed := pkg.(type) {
+			case *tds.DynamicPackage:
+				if typed.Type&tds.TDS_DYN_ACK != tds.TDS_DYN_ACK {
+					return false, fmt.Errorf("expected TDS_DYN_ACK, received %s", typed.Type)
+				}
+			case *tds.DonePackage:
+				if typed.Status&tds.TDS_DONE_ERROR == tds.TDS_DONE_ERROR {
+					return false, errors.New("server reported an error")
+				}
+				return typed.Status&tds.TDS_DONE_MORE != tds.TDS_DONE_MORE, nil
+			}
+
+			if handle != nil {
+				if err := handle(pkg); err != nil {
+					return false, err
+				}
+			}
+			return false, nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error reading response to %s for %s: %w", pkg.Type, stmt.ID(), err)
+	}
+
+	return nil
+}
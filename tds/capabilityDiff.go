@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// capabilityTypes are the capability types in the order they are
+// diffed and rendered.
+var capabilityTypes = []CapabilityType{
+	CapabilityRequest,
+	CapabilityResponse,
+	CapabilitySecurity,
+}
+
+// CapabilityDiff describes a capability whose state differs between
+// the requested and the granted capabilities.
+type CapabilityDiff struct {
+	Type       CapabilityType
+	Capability int
+	Requested  bool
+	Granted    bool
+}
+
+// Name returns the name of the capability.
+func (diff CapabilityDiff) Name() string {
+	return capabilityName(diff.Type, diff.Capability)
+}
+
+func (diff CapabilityDiff) String() string {
+	if diff.Requested {
+		return fmt.Sprintf("%s %s: requested but not granted", diff.Type, diff.Name())
+	}
+	return fmt.Sprintf("%s %s: granted but not requested", diff.Type, diff.Name())
+}
+
+// DiffCapabilities returns the capabilities whose state differs between
+// requested and granted, ordered by capability type and capability.
+func DiffCapabilities(requested, granted *CapabilityPackage) []CapabilityDiff {
+	diffs := []CapabilityDiff{}
+
+	for _, capType := range capabilityTypes {
+		reqMask, grantMask := capabilityMasks(capType, requested, granted)
+
+		for capability := 1; capability < capabilityMaskLength(reqMask, grantMask); capability++ {
+			isRequested := reqMask.getCapability(capability)
+			isGranted := grantMask.getCapability(capability)
+
+			if isRequested == isGranted {
+				continue
+			}
+
+			diffs = append(diffs, CapabilityDiff{
+				Type:       capType,
+				Capability: capability,
+				Requested:  isRequested,
+				Granted:    isGranted,
+			})
+		}
+	}
+
+	return diffs
+}
+
+// CapabilityTable renders a table of all capabilities that are either
+// requested or granted.
+//
+// Capabilities whose state differs are marked with an asterisk.
+func CapabilityTable(requested, granted *CapabilityPackage) string {
+	sb := &strings.Builder{}
+	w := tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "\tType\tCapability\tRequested\tGranted\n")
+
+	for _, capType := range capabilityTypes {
+		reqMask, grantMask := capabilityMasks(capType, requested, granted)
+
+		for capability := 1; capability < capabilityMaskLength(reqMask, grantMask); capability++ {
+			isRequested := reqMask.getCapability(capability)
+			isGranted := grantMask.getCapability(capability)
+
+			if !isRequested && !isGranted {
+				continue
+			}
+
+			marker := ""
+			if isRequested != isGranted {
+				marker = "*"
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, capType,
+				capabilityName(capType, capability),
+				yesNo(isRequested), yesNo(isGranted))
+		}
+	}
+
+	w.Flush()
+	return sb.String()
+}
+
+// capabilityMasks returns the value masks of capType of both packages.
+// Either package may be nil.
+func capabilityMasks(capType CapabilityType, a, b *CapabilityPackage) (*valueMask, *valueMask) {
+	var maskA, maskB *valueMask
+
+	if a != nil {
+		maskA = a.Capabilities[capType]
+	}
+
+	if b != nil {
+		maskB = b.Capabilities[capType]
+	}
+
+	return maskA, maskB
+}
+
+// capabilityMaskLength returns the length of the longer value mask.
+func capabilityMaskLength(a, b *valueMask) int {
+	length := 0
+
+	if a != nil {
+		length = len(a.capabilities)
+	}
+
+	if b != nil && len(b.capabilities) > length {
+		length = len(b.capabilities)
+	}
+
+	return length
+}
+
+// capabilityName returns the name of the capability of capType.
+func capabilityName(capType CapabilityType, capability int) string {
+	switch capType {
+	case CapabilityRequest:
+		return RequestCapability(capability).String()
+	case CapabilityResponse:
+		return ResponseCapability(capability).String()
+	default:
+		return fmt.Sprintf("%d", capability)
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
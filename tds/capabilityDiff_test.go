@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffCapabilities(t *testing.T) {
+	requested, err := NewCapabilityPackage(
+		[]RequestCapability{TDS_REQ_LANG, TDS_DATA_XML, TDS_WIDETABLES},
+		[]ResponseCapability{TDS_RES_NO_TDSCONTROL},
+		nil,
+	)
+	if err != nil {
+		t.Errorf("Error creating requested capabilities: %v", err)
+		return
+	}
+
+	granted, err := NewCapabilityPackage(
+		[]RequestCapability{TDS_REQ_LANG, TDS_WIDETABLES, TDS_DATA_INT8},
+		[]ResponseCapability{TDS_RES_NO_TDSCONTROL},
+		nil,
+	)
+	if err != nil {
+		t.Errorf("Error creating granted capabilities: %v", err)
+		return
+	}
+
+	expected := []CapabilityDiff{
+		{Type: CapabilityRequest, Capability: int(TDS_DATA_INT8), Requested: false, Granted: true},
+		{Type: CapabilityRequest, Capability: int(TDS_DATA_XML), Requested: true, Granted: false},
+	}
+
+	recv := DiffCapabilities(requested, granted)
+	if !reflect.DeepEqual(recv, expected) {
+		t.Errorf("Received unexpected diff")
+		t.Errorf("Expected: %v", expected)
+		t.Errorf("Received: %v", recv)
+	}
+
+	table := CapabilityTable(requested, granted)
+	for _, line := range strings.Split(table, "\n") {
+		if !strings.Contains(line, TDS_DATA_XML.String()) {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "*") {
+			t.Errorf("Expected differing capability to be marked: %q", line)
+		}
+	}
+
+	if !strings.Contains(table, TDS_RES_NO_TDSCONTROL.String()) {
+		t.Errorf("Expected table to contain %s:\n%s", TDS_RES_NO_TDSCONTROL, table)
+	}
+}
+
+func TestDiffCapabilities_Nil(t *testing.T) {
+	requested, err := NewCapabilityPackage([]RequestCapability{TDS_REQ_LANG}, nil, nil)
+	if err != nil {
+		t.Errorf("Error creating requested capabilities: %v", err)
+		return
+	}
+
+	recv := DiffCapabilities(requested, nil)
+	if len(recv) != 1 || recv[0].Capability != int(TDS_REQ_LANG) || recv[0].Granted {
+		t.Errorf("Expected %s to be reported as not granted, received %v", TDS_REQ_LANG, recv)
+	}
+}
@@ -0,0 +1,373 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultCapabilityPreset is the name of the capability preset that is
+// requested if no preset is set with the property "capabilities".
+const DefaultCapabilityPreset = "default"
+
+// CapabilityPreset is a named set of capabilities requested during
+// login.
+type CapabilityPreset struct {
+	Name     string
+	Request  []RequestCapability
+	Response []ResponseCapability
+	Security []SecurityCapability
+}
+
+// minimalRequestCapabilities are the request capabilities required to
+// execute language and dynamic SQL statements with the basic data
+// types.
+var minimalRequestCapabilities = []RequestCapability{
+	TDS_REQ_LANG,
+	TDS_REQ_MSTMT,
+	TDS_REQ_DYNF,
+	TDS_REQ_MSG,
+	TDS_REQ_PARAM,
+
+	TDS_DATA_INT1,
+	TDS_DATA_INT2,
+	TDS_DATA_INT4,
+	TDS_DATA_BIT,
+	TDS_DATA_CHAR,
+	TDS_DATA_VCHAR,
+	TDS_DATA_BIN,
+	TDS_DATA_VBIN,
+	TDS_DATA_MNY8,
+	TDS_DATA_MNY4,
+	TDS_DATA_DATE8,
+	TDS_DATA_DATE4,
+	TDS_DATA_FLT4,
+	TDS_DATA_FLT8,
+	TDS_DATA_NUM,
+	TDS_DATA_TEXT,
+	TDS_DATA_IMAGE,
+	TDS_DATA_DEC,
+	TDS_DATA_LCHAR,
+	TDS_DATA_LBIN,
+	TDS_DATA_INTN,
+	TDS_DATA_DATETIMEN,
+	TDS_DATA_MONEYN,
+	TDS_DATA_FLTN,
+	TDS_DATA_BITN,
+
+	TDS_CON_INBAND,
+	TDS_DATA_COLUMNSTATUS,
+}
+
+// defaultRequestCapabilities are the request capabilities supported by
+// this package.
+var defaultRequestCapabilities = []RequestCapability{
+	// Support language requests
+	TDS_REQ_LANG,
+	// Support RPC requests
+	// TODO: TDS_REQ_RPC,
+	// Support procedure event notifications
+	// TODO: TDS_REQ_EVT,
+	// Support multiple commands per request
+	TDS_REQ_MSTMT,
+	// Support bulk copy
+	// TODO: TDS_REQ_BCP,
+	// Support cursors requests
+	// TODO: TDS_REQ_CURSOR,
+	// Support dynamic SQL
+	TDS_REQ_DYNF,
+	// Support MSG requests
+	TDS_REQ_MSG,
+	// RPC will use TDS_DBRPC and TDS_PARAMFMT / TDS_PARAM
+	TDS_REQ_PARAM,
+
+	// Enable all optional data types
+	TDS_DATA_INT1,
+	TDS_DATA_INT2,
+	TDS_DATA_INT4,
+	TDS_DATA_BIT,
+	TDS_DATA_CHAR,
+	TDS_DATA_VCHAR,
+	TDS_DATA_BIN,
+	TDS_DATA_VBIN,
+	TDS_DATA_MNY8,
+	TDS_DATA_MNY4,
+	TDS_DATA_DATE8,
+	TDS_DATA_DATE4,
+	TDS_DATA_FLT4,
+	TDS_DATA_FLT8,
+	TDS_DATA_NUM,
+	TDS_DATA_TEXT,
+	TDS_DATA_IMAGE,
+	TDS_DATA_DEC,
+	TDS_DATA_LCHAR,
+	TDS_DATA_LBIN,
+	TDS_DATA_INTN,
+	TDS_DATA_DATETIMEN,
+	TDS_DATA_MONEYN,
+	TDS_DATA_SENSITIVITY,
+	TDS_DATA_BOUNDARY,
+	TDS_DATA_FLTN,
+	TDS_DATA_BITN,
+	TDS_DATA_INT8,
+	TDS_DATA_UINT2,
+	TDS_DATA_UINT4,
+	TDS_DATA_UINT8,
+	TDS_DATA_UINTN,
+	TDS_DATA_NLBIN,
+	TDS_IMAGE_NCHAR,
+	TDS_BLOB_NCHAR_16,
+	TDS_BLOB_NCHAR_8,
+	TDS_BLOB_NCHAR_SCSU,
+	TDS_DATA_DATE,
+	TDS_DATA_TIME,
+	TDS_DATA_INTERVAL,
+	TDS_DATA_UNITEXT,
+	TDS_DATA_SINT1,
+	TDS_REQ_LARGEIDENT,
+	TDS_REQ_BLOB_NCHAR_16,
+	TDS_DATA_XML,
+	TDS_DATA_BIGDATETIME,
+	TDS_DATA_USECS,
+	//TODO: TDS_DATA_LOBLOCATOR,
+
+	// Support streaming
+	//TODO: TDS_OBJECT_CHAR,
+	//TODO: TDS_OBJECT_BINARY,
+
+	// Support expedited and non-expedited attentions
+	TDS_CON_OOB,
+	TDS_CON_INBAND,
+	// Use urgent notifications
+	TDS_REQ_URGEVT,
+
+	// Create procs from dynamic statements
+	TDS_PROTO_DYNPROC,
+
+	// Request status byte in TDS_PARAMS responses
+	// Allows to handel nullbytes
+	TDS_DATA_COLUMNSTATUS,
+	// Support newer versions of tokens
+	TDS_REQ_CURINFO3,
+	TDS_REQ_DBRPC2,
+	// TDS_PARAMFMT2
+	TDS_WIDETABLES,
+
+	// Support scrollable cursors
+	TDS_CSR_SCROLL,
+	TDS_CSR_SENSITIVE,
+	TDS_CSR_INSENSITIVE,
+	TDS_CSR_SEMISENSITIVE,
+	TDS_CSR_KEYSETDRIVEN,
+
+	// Renegotiate packet size after login negotiation
+	TDS_REQ_SRVPKTSIZE,
+
+	// Support cluster failover and migration
+	//TODO: TDS_CAP_CLUSTERFAILOVER,
+	//TODO: TDS_REQ_MIGRATE,
+
+	// Support batched parameters
+	TDS_REQ_DYN_BATCH,
+	TDS_REQ_LANG_BATCH,
+	TDS_REQ_RPC_BATCH,
+
+	// Support on demand encryption
+	TDS_REQ_COMMAND_ENCRYPTION,
+
+	// Client will only perform readonly operations
+	//TODO: TDS_REQ_READONLY,
+}
+
+// undecodableRequestCapabilities are the request capabilities whose
+// packages cannot be decoded by this package. They must not be
+// requested by any preset.
+var undecodableRequestCapabilities = []RequestCapability{
+	TDS_REQ_RPC,
+	TDS_REQ_EVT,
+	TDS_REQ_BCP,
+	TDS_REQ_CURSOR,
+	TDS_DATA_LOBLOCATOR,
+	TDS_OBJECT_CHAR,
+	TDS_OBJECT_BINARY,
+	TDS_CAP_CLUSTERFAILOVER,
+	TDS_REQ_MIGRATE,
+}
+
+var capabilityPresets = map[string]CapabilityPreset{
+	"minimal": {
+		Request:  minimalRequestCapabilities,
+		Response: []ResponseCapability{TDS_RES_NO_TDSCONTROL},
+	},
+	"default": {
+		Request: defaultRequestCapabilities,
+		Response: []ResponseCapability{
+			// Ignore format control
+			TDS_RES_NO_TDSCONTROL,
+		},
+	},
+	// full requests all capabilities of default and additionally
+	// the capabilities that only change the content of packages
+	// decoded by this package.
+	"full": {
+		Request: append(append([]RequestCapability{}, defaultRequestCapabilities...),
+			// Report the number of selected rows in TDS_DONE
+			TDS_REQ_ROWCOUNT_FOR_SELECT,
+		),
+		Response: []ResponseCapability{TDS_RES_NO_TDSCONTROL},
+	},
+	// legacy-12.5 requests only capabilities known to ASE 12.5.
+	"legacy-12.5": {
+		Request: append(append([]RequestCapability{}, minimalRequestCapabilities...),
+			TDS_DATA_SENSITIVITY,
+			TDS_DATA_BOUNDARY,
+			TDS_CON_OOB,
+			TDS_REQ_URGEVT,
+			TDS_PROTO_DYNPROC,
+			TDS_WIDETABLES,
+			TDS_DATA_DATE,
+			TDS_DATA_TIME,
+		),
+		Response: []ResponseCapability{TDS_RES_NO_TDSCONTROL},
+	},
+}
+
+// CapabilityPresets returns the names of the available capability
+// presets in alphabetical order.
+func CapabilityPresets() []string {
+	names := make([]string, 0, len(capabilityPresets))
+	for name := range capabilityPresets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// LookupCapabilityPreset returns the capability preset with the passed
+// name.
+//
+// The returned preset is a copy and may be modified.
+func LookupCapabilityPreset(name string) (CapabilityPreset, error) {
+	preset, ok := capabilityPresets[name]
+	if !ok {
+		return CapabilityPreset{}, fmt.Errorf("unknown capability preset '%s', available presets: %v",
+			name, CapabilityPresets())
+	}
+
+	return CapabilityPreset{
+		Name:     name,
+		Request:  append([]RequestCapability{}, preset.Request...),
+		Response: append([]ResponseCapability{}, preset.Response...),
+		Security: append([]SecurityCapability{}, preset.Security...),
+	}, nil
+}
+
+// CapabilityBuilder customizes a capability preset.
+type CapabilityBuilder struct {
+	preset CapabilityPreset
+}
+
+// NewCapabilityBuilder returns a CapabilityBuilder initialized with the
+// capabilities of the named preset.
+func NewCapabilityBuilder(preset string) (*CapabilityBuilder, error) {
+	p, err := LookupCapabilityPreset(preset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CapabilityBuilder{preset: p}, nil
+}
+
+// EnableRequest adds the passed request capabilities.
+func (builder *CapabilityBuilder) EnableRequest(capabilities ...RequestCapability) *CapabilityBuilder {
+	builder.preset.Request = append(builder.preset.Request, capabilities...)
+	return builder
+}
+
+// DisableRequest removes the passed request capabilities.
+func (builder *CapabilityBuilder) DisableRequest(capabilities ...RequestCapability) *CapabilityBuilder {
+	remaining := []RequestCapability{}
+	for _, capability := range builder.preset.Request {
+		if !containsRequestCapability(capabilities, capability) {
+			remaining = append(remaining, capability)
+		}
+	}
+
+	builder.preset.Request = remaining
+	return builder
+}
+
+// EnableResponse adds the passed response capabilities.
+func (builder *CapabilityBuilder) EnableResponse(capabilities ...ResponseCapability) *CapabilityBuilder {
+	builder.preset.Response = append(builder.preset.Response, capabilities...)
+	return builder
+}
+
+// DisableResponse removes the passed response capabilities.
+func (builder *CapabilityBuilder) DisableResponse(capabilities ...ResponseCapability) *CapabilityBuilder {
+	remaining := []ResponseCapability{}
+	for _, capability := range builder.preset.Response {
+		if !containsResponseCapability(capabilities, capability) {
+			remaining = append(remaining, capability)
+		}
+	}
+
+	builder.preset.Response = remaining
+	return builder
+}
+
+// EnableSecurity adds the passed security capabilities.
+func (builder *CapabilityBuilder) EnableSecurity(capabilities ...SecurityCapability) *CapabilityBuilder {
+	builder.preset.Security = append(builder.preset.Security, capabilities...)
+	return builder
+}
+
+// DisableSecurity removes the passed security capabilities.
+func (builder *CapabilityBuilder) DisableSecurity(capabilities ...SecurityCapability) *CapabilityBuilder {
+	remaining := []SecurityCapability{}
+	for _, capability := range builder.preset.Security {
+		if !containsSecurityCapability(capabilities, capability) {
+			remaining = append(remaining, capability)
+		}
+	}
+
+	builder.preset.Security = remaining
+	return builder
+}
+
+// Build returns a new CapabilityPackage with the configured
+// capabilities.
+func (builder *CapabilityBuilder) Build() (*CapabilityPackage, error) {
+	return NewCapabilityPackage(builder.preset.Request, builder.preset.Response, builder.preset.Security)
+}
+
+func containsRequestCapability(capabilities []RequestCapability, capability RequestCapability) bool {
+	for _, c := range capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func containsResponseCapability(capabilities []ResponseCapability, capability ResponseCapability) bool {
+	for _, c := range capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSecurityCapability(capabilities []SecurityCapability, capability SecurityCapability) bool {
+	for _, c := range capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
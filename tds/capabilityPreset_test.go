@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"testing"
+)
+
+func TestCapabilityPresets(t *testing.T) {
+	for _, name := range CapabilityPresets() {
+		t.Run(name, func(t *testing.T) {
+			builder, err := NewCapabilityBuilder(name)
+			if err != nil {
+				t.Errorf("Error creating builder: %v", err)
+				return
+			}
+
+			pkg, err := builder.Build()
+			if err != nil {
+				t.Errorf("Error building capability package: %v", err)
+				return
+			}
+
+			if !pkg.HasRequestCapability(TDS_REQ_LANG) {
+				t.Errorf("Expected preset to request %s", TDS_REQ_LANG)
+			}
+		})
+	}
+}
+
+func TestCapabilityPresets_Decodable(t *testing.T) {
+	for _, name := range CapabilityPresets() {
+		t.Run(name, func(t *testing.T) {
+			preset, err := LookupCapabilityPreset(name)
+			if err != nil {
+				t.Errorf("Error looking up preset: %v", err)
+				return
+			}
+
+			for _, capability := range preset.Request {
+				if containsRequestCapability(undecodableRequestCapabilities, capability) {
+					t.Errorf("Preset requests %s, which cannot be decoded", capability)
+				}
+			}
+		})
+	}
+}
+
+func TestLookupCapabilityPreset_Unknown(t *testing.T) {
+	if _, err := LookupCapabilityPreset("unknown"); err == nil {
+		t.Errorf("Expected error looking up unknown preset")
+	}
+}
+
+func TestCapabilityBuilder(t *testing.T) {
+	builder, err := NewCapabilityBuilder("minimal")
+	if err != nil {
+		t.Errorf("Error creating builder: %v", err)
+		return
+	}
+
+	pkg, err := builder.
+		EnableRequest(TDS_DATA_BIGDATETIME).
+		DisableRequest(TDS_DATA_TEXT).
+		DisableResponse(TDS_RES_NO_TDSCONTROL).
+		Build()
+	if err != nil {
+		t.Errorf("Error building capability package: %v", err)
+		return
+	}
+
+	if !pkg.HasRequestCapability(TDS_DATA_BIGDATETIME) {
+		t.Errorf("Expected %s to be enabled", TDS_DATA_BIGDATETIME)
+	}
+
+	if pkg.HasRequestCapability(TDS_DATA_TEXT) {
+		t.Errorf("Expected %s to be disabled", TDS_DATA_TEXT)
+	}
+
+	if pkg.HasResponseCapability(TDS_RES_NO_TDSCONTROL) {
+		t.Errorf("Expected %s to be disabled", TDS_RES_NO_TDSCONTROL)
+	}
+
+	preset, err := LookupCapabilityPreset("minimal")
+	if err != nil {
+		t.Errorf("Error looking up preset: %v", err)
+		return
+	}
+
+	for _, capability := range preset.Request {
+		if capability == TDS_DATA_BIGDATETIME {
+			t.Errorf("Builder modified the preset")
+		}
+	}
+}
@@ -13,12 +13,19 @@ import (
 	"sync"
 	"time"
 
+	"github.com/SAP/go-dblib/annotate"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/logging"
+	"github.com/SAP/go-dblib/trace"
 	"github.com/hashicorp/go-multierror"
 )
 
 var (
 	ErrNoPackageReady = errors.New("no package ready")
-	ErrChannelClosed  = errors.New("channel is closed")
+	ErrChannelClosed  = dberrors.New(dberrors.CategoryNetwork, "channel is closed")
+	// ErrStalled is returned when a channel did not consume its
+	// packages within the watchdog timeout of the connection.
+	ErrStalled = dberrors.New(dberrors.CategoryNetwork, "channel stalled")
 )
 
 // Channel is a channel in a multiplexed connection with a TDS
@@ -33,6 +40,10 @@ type Channel struct {
 	// using the channel when closing it.
 	sync.RWMutex
 	closed bool
+	// closing is closed before the write lock is acquired to abort
+	// goroutines blocking while holding a read lock.
+	closing     chan struct{}
+	closingOnce sync.Once
 
 	channelId int
 
@@ -56,9 +67,19 @@ type Channel struct {
 	// server
 	lastPkgRx, lastPkgTx Package
 	// packageCh stores Packages as they are parsed from Packets
-	packageCh chan Package
+	packageCh chan queuedPackage
+	// memory limits the memory held by packages that have been
+	// parsed but not consumed. Nil disables the limit.
+	memory *memoryBudget
+	// discarding is set when a response exceeded the memory limit of
+	// the channel. The remaining packages of the response are
+	// discarded.
+	discarding bool
 
 	errCh chan error
+
+	// logger adds the connection and channel id to all messages.
+	logger logging.Logger
 }
 
 // NewChannel communicates the creation of a new channel with the
@@ -69,12 +90,9 @@ func (tds *Conn) NewChannel() (*Channel, error) {
 		return nil, fmt.Errorf("error getting channel ID: %w", err)
 	}
 
-	queueSize := 100
-	if prop := tds.dsn.Prop("channel-package-queue-size"); prop != "" {
-		queueSize, err = strconv.Atoi(prop)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing number from channel-package-queue-size '%s': %w", prop, err)
-		}
+	queueSize, err := tds.dsn.PropInt("channel-package-queue-size", 100)
+	if err != nil {
+		return nil, err
 	}
 
 	tdsChan := &Channel{
@@ -88,11 +106,16 @@ func (tds *Conn) NewChannel() (*Channel, error) {
 		window:             0, // TODO
 		queueRx:            NewPacketQueue(tds.PacketSize),
 		queueTx:            NewPacketQueue(tds.PacketSize),
-		packageCh:          make(chan Package, queueSize),
+		closing:            make(chan struct{}),
+		packageCh:          make(chan queuedPackage, queueSize),
 		errCh:              make(chan error, 10),
+		logger:             logging.With(tds.logger, "channel", channelId),
+		memory:             tds.memory.forChannel(),
 	}
 
+	tds.tdsChannelsLock.Lock()
 	tds.tdsChannels[channelId] = tdsChan
+	tds.tdsChannelsLock.Unlock()
 
 	// channel 0 needs no setup
 	if channelId == 0 {
@@ -105,7 +128,7 @@ func (tds *Conn) NewChannel() (*Channel, error) {
 	setup.Data = nil
 
 	tdsChan.CurrentHeaderType = TDS_BUF_SETUP
-	if err := tdsChan.sendPacket(setup); err != nil {
+	if err := tdsChan.sendPacket(context.Background(), setup); err != nil {
 		return nil, fmt.Errorf("error sending setup for channel %d: %w",
 			tdsChan.channelId, err)
 	}
@@ -152,6 +175,8 @@ func (tdsChan *Channel) Reset() {
 func (tdsChan *Channel) Close() error {
 	var me error
 
+	tdsChan.logger.Debug("closing channel")
+
 	if tdsChan.channelId == 0 {
 		// Channel 0 is the main communication channel - send logout packages
 		if err := tdsChan.Logout(); err != nil {
@@ -166,7 +191,7 @@ func (tdsChan *Channel) Close() error {
 		teardown.Data = nil
 		tdsChan.CurrentHeaderType = TDS_BUF_CLOSE
 
-		if err := tdsChan.sendPacket(teardown); err != nil {
+		if err := tdsChan.sendPacket(context.Background(), teardown); err != nil {
 			me = multierror.Append(me,
 				fmt.Errorf("error sending teardown for channel %d: %w",
 					tdsChan.channelId, err))
@@ -175,21 +200,32 @@ func (tdsChan *Channel) Close() error {
 		// TODO process ack packet
 	}
 
+	// Abort goroutines blocking on the channel, otherwise acquiring
+	// the lock could deadlock.
+	tdsChan.closingOnce.Do(func() {
+		close(tdsChan.closing)
+	})
+
 	// Lock the channel and store the closed indicator.
 	tdsChan.Lock()
 	defer tdsChan.Unlock()
 
+	if tdsChan.closed {
+		return me
+	}
 	tdsChan.closed = true
 
 	// Channel closing has been communicated, remove channel from conn
 	tdsChan.tdsConn.tdsChannelsLock.Lock()
 	delete(tdsChan.tdsConn.tdsChannels, tdsChan.channelId)
 	tdsChan.tdsConn.tdsChannelsLock.Unlock()
+	tdsChan.tdsConn.endRequest(tdsChan.channelId)
 
 	close(tdsChan.packageCh)
 	for {
-		if pkg, ok := <-tdsChan.packageCh; ok {
-			me = multierror.Append(me, fmt.Errorf("package still queued: %v", pkg))
+		if queued, ok := <-tdsChan.packageCh; ok {
+			tdsChan.releaseMemory(queued.size)
+			me = multierror.Append(me, fmt.Errorf("package still queued: %v", queued.pkg))
 		} else {
 			break
 		}
@@ -252,6 +288,8 @@ func (tdsChan *Channel) handleSpecialPackage(pkg Package) (bool, error) {
 				tdsChan.tdsConn.packetSize = packSize
 			}
 
+			tdsChan.logger.Debug("environment changed", "type", member.Type,
+				"old", member.OldValue, "new", member.NewValue)
 			tdsChan.callEnvChangeHooks(member.Type, member.OldValue, member.NewValue)
 		}
 		return false, nil
@@ -281,6 +319,10 @@ func (tdsChan *Channel) handleSpecialPackage(pkg Package) (bool, error) {
 //
 // If multiple errors and a package are ready a random error or package
 // will be returned, as stated in the spec for select.
+//
+// If ctx has no deadline the response to a request is awaited until
+// the statement timeout of the connection is exceeded. The request is
+// then cancelled and ErrStatementTimeout is returned.
 func (tdsChan *Channel) NextPackage(ctx context.Context, wait bool) (Package, error) {
 	tdsChan.RLock()
 	defer tdsChan.RUnlock()
@@ -289,12 +331,37 @@ func (tdsChan *Channel) NextPackage(ctx context.Context, wait bool) (Package, er
 		return nil, ErrChannelClosed
 	}
 
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		if requestDeadline, ok := tdsChan.tdsConn.requestDeadline(tdsChan.channelId); ok {
+			timeoutCtx, cancel := context.WithDeadline(ctx, requestDeadline)
+			defer cancel()
+
+			pkg, err := tdsChan.nextPackage(timeoutCtx, wait)
+			if err != nil && ctx.Err() == nil && timeoutCtx.Err() != nil {
+				return nil, tdsChan.abortRequest()
+			}
+			return pkg, err
+		}
+	}
+
+	return tdsChan.nextPackage(ctx, wait)
+}
+
+// nextPackage implements NextPackage.
+//
+// The caller must hold a read lock on tdsChan.
+func (tdsChan *Channel) nextPackage(ctx context.Context, wait bool) (Package, error) {
 	// Try reading from the package channel once before setting up
 	// a loop. This prevents spurious errors due to random selection in
 	// select statements.
 	select {
-	case pkg := <-tdsChan.packageCh:
-		return pkg, nil
+	case queued := <-tdsChan.packageCh:
+		tdsChan.releaseMemory(queued.size)
+		return queued.pkg, nil
 	default:
 	}
 
@@ -307,22 +374,21 @@ func (tdsChan *Channel) NextPackage(ctx context.Context, wait bool) (Package, er
 		ch <- ErrNoPackageReady
 	}
 
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
 	select {
 	case <-ctx.Done():
 		return nil, fmt.Errorf("passed context is closed: %w", ctx.Err())
 	case <-tdsChan.tdsConn.ctx.Done():
-		return nil, fmt.Errorf("connection context is closed: %w", tdsChan.tdsConn.ctx.Err())
+		return nil, fmt.Errorf("connection context is closed: %w", tdsChan.tdsConn.cause())
+	case <-tdsChan.closing:
+		return nil, ErrChannelClosed
 	case err := <-tdsChan.tdsConn.errCh:
 		return nil, fmt.Errorf("error in TDS connection: %w", err)
 	case err := <-tdsChan.errCh:
 		return nil, fmt.Errorf("error in TDS channel %d: %w",
 			tdsChan.channelId, err)
-	case pkg := <-tdsChan.packageCh:
-		return pkg, nil
+	case queued := <-tdsChan.packageCh:
+		tdsChan.releaseMemory(queued.size)
+		return queued.pkg, nil
 	case err := <-ch:
 		return nil, err
 	}
@@ -445,7 +511,10 @@ type LastPkgAcceptor interface {
 
 // QueuePackage utilizes PacketQueue to convert a Package into packets.
 // Packets that have their Data exhausted are sent to the server.
-func (tdsChan *Channel) QueuePackage(ctx context.Context, pkg Package) error {
+//
+// ErrShutdown is returned if a new request is started on a connection
+// that is shutting down.
+func (tdsChan *Channel) QueuePackage(ctx context.Context, pkg Package) (err error) {
 	tdsChan.RLock()
 	defer tdsChan.RUnlock()
 	// TODO return proper error
@@ -453,6 +522,38 @@ func (tdsChan *Channel) QueuePackage(ctx context.Context, pkg Package) error {
 		return ErrChannelClosed
 	}
 
+	// Requests wait for the rate limiter of the connection before
+	// their first package is queued.
+	if limiter := tdsChan.tdsConn.limiter; limiter != nil && tdsChan.lastPkgTx == nil && isRequest(pkg) {
+		if err := limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("error waiting for rate limiter: %w", err)
+		}
+	}
+
+	// Language commands are annotated on a copy, as the package is
+	// owned by the caller.
+	if lang, ok := pkg.(*LanguagePackage); ok && tdsChan.lastPkgTx == nil {
+		if annotator := annotate.From(ctx, tdsChan.tdsConn.annotator); annotator != nil {
+			annotated := *lang
+			annotated.Cmd = annotator.Annotate(ctx, lang.Cmd)
+			pkg = &annotated
+		}
+	}
+
+	// Requests are in flight until their response has been received,
+	// see Conn.Shutdown.
+	if _, logout := pkg.(*LogoutPackage); tdsChan.lastPkgTx == nil && !logout {
+		if err := tdsChan.tdsConn.beginRequest(ctx, tdsChan.channelId, pkg); err != nil {
+			return err
+		}
+	}
+
+	defer func() {
+		if err != nil {
+			tdsChan.tdsConn.endRequest(tdsChan.channelId)
+		}
+	}()
+
 	if acceptor, ok := pkg.(LastPkgAcceptor); ok {
 		if err := acceptor.LastPkg(tdsChan.lastPkgTx); err != nil {
 			return fmt.Errorf("error calling LastPkg on %s: %w", pkg, err)
@@ -467,6 +568,19 @@ func (tdsChan *Channel) QueuePackage(ctx context.Context, pkg Package) error {
 	return tdsChan.sendPackets(ctx, true)
 }
 
+// isRequest reports whether pkg starts a request subject to rate
+// limiting.
+func isRequest(pkg Package) bool {
+	switch typed := pkg.(type) {
+	case *LanguagePackage:
+		return true
+	case *DynamicPackage:
+		return typed.Type != TDS_DYN_DEALLOC
+	default:
+		return false
+	}
+}
+
 // Send all remaining Packets in queue to the server.
 // This includes Packets whose Data isn't exhausted.
 func (tdsChan *Channel) SendRemainingPackets(ctx context.Context) error {
@@ -479,7 +593,13 @@ func (tdsChan *Channel) SendRemainingPackets(ctx context.Context) error {
 	// SendRemainingPackets is only called when completing sending
 	// packets to the server and preparing to receive the answer.
 	defer tdsChan.Reset()
-	return tdsChan.sendPackets(ctx, false)
+
+	if err := tdsChan.sendPackets(ctx, false); err != nil {
+		tdsChan.tdsConn.endRequest(tdsChan.channelId)
+		return err
+	}
+
+	return nil
 }
 
 // SendPackage combines calls to QueuePackage and SendRemainingPackets
@@ -501,7 +621,7 @@ func (tdsChan *Channel) sendPackets(ctx context.Context, onlyFull bool) error {
 		case <-ctx.Done():
 			return fmt.Errorf("passed context is closed: %w", ctx.Err())
 		case <-tdsChan.tdsConn.ctx.Done():
-			return fmt.Errorf("connection context is closed: %w", tdsChan.tdsConn.ctx.Err())
+			return fmt.Errorf("connection context is closed: %w", tdsChan.tdsConn.cause())
 		default:
 			// Only the last packet should not be full.
 			if i == tdsChan.queueTx.indexPacket && tdsChan.queueTx.indexData < tdsChan.tdsConn.PacketBodySize() {
@@ -519,7 +639,7 @@ func (tdsChan *Channel) sendPackets(ctx context.Context, onlyFull bool) error {
 
 			// TODO maybe check if data is empty - could be an issue
 
-			if err := tdsChan.sendPacket(packet); err != nil {
+			if err := tdsChan.sendPacket(ctx, packet); err != nil {
 				return fmt.Errorf("error sending packet %s: %w", packet, err)
 			}
 		}
@@ -528,7 +648,13 @@ func (tdsChan *Channel) sendPackets(ctx context.Context, onlyFull bool) error {
 	return nil
 }
 
-func (tdsChan *Channel) sendPacket(packet *Packet) error {
+// sendPacket writes packet to the server.
+//
+// If the connection supports write deadlines the write is aborted when
+// the deadline of ctx is exceeded. The connection is failed if the
+// packet was only written partially, as the stream cannot be
+// recovered.
+func (tdsChan *Channel) sendPacket(ctx context.Context, packet *Packet) error {
 	packet.Header.MsgType = tdsChan.CurrentHeaderType
 
 	// Channel 0 does not need PacketNr or Window
@@ -544,9 +670,23 @@ func (tdsChan *Channel) sendPacket(packet *Packet) error {
 		packet.Header.Status |= TDS_BUFSTAT_EOM
 	}
 
-	n, err := packet.WriteTo(tdsChan.tdsConn.conn)
+	if ctx := tdsChan.tdsConn.ctx; trace.Enabled(ctx) {
+		trace.Emit(ctx, trace.KindPacket, "send", packetAttrs(packet)...)
+	}
+
+	n, err := tdsChan.tdsConn.writePacket(ctx, packet)
 	if err != nil {
-		return fmt.Errorf("error writing packet to server: %w", err)
+		if isTimeout(err) {
+			if ctxErr := deadlineErr(ctx); ctxErr != nil {
+				err = ctxErr
+			}
+		}
+
+		err = fmt.Errorf("error writing packet to server: %w", err)
+		if n > 0 {
+			tdsChan.tdsConn.fail(dberrors.Wrap(dberrors.CategoryNetwork, err))
+		}
+		return err
 	}
 
 	if int(n) != int(packet.Header.Length) {
@@ -569,7 +709,7 @@ func (tdsChan *Channel) WritePacket(packet *Packet) {
 	// The packet is header-only - pass it directly into the package
 	// channel.
 	if packet.Header.Length == PacketHeaderSize {
-		tdsChan.packageCh <- HeaderOnlyPackage{Header: packet.Header}
+		tdsChan.deliver(HeaderOnlyPackage{Header: packet.Header}, 0)
 		return
 	}
 
@@ -602,18 +742,29 @@ func (tdsChan *Channel) WritePacket(packet *Packet) {
 
 // tryParsePackage attempts to parse a Package from the queued Packets.
 func (tdsChan *Channel) tryParsePackage() bool {
+	startPacket, startData := tdsChan.queueRx.Position()
+
 	// Attempt to process data from channel into a Package.
 	tokenByte, err := tdsChan.queueRx.Byte()
 	if err != nil {
 		if tdsChan.queueRx.IsEOM() {
+			// The response exceeding the memory limit has been
+			// discarded completely.
+			if tdsChan.discarding {
+				tdsChan.discarding = false
+				tdsChan.tdsConn.endRequest(tdsChan.channelId)
+				return false
+			}
+
 			// If the error is io.EOF then the payload from the server
 			// has been fully consumed.
 			// TDS doesn't always send a DonePackage with TDS_DONE_FINAL
 			// - usually only when a procedure with multiple commands is
 			// being executed.
 			if lastPkg, ok := tdsChan.lastPkgRx.(*DonePackage); !ok || lastPkg.Status != TDS_DONE_FINAL {
-				tdsChan.packageCh <- &DonePackage{Status: TDS_DONE_FINAL}
+				tdsChan.deliver(&DonePackage{Status: TDS_DONE_FINAL}, 0)
 			}
+			tdsChan.tdsConn.endRequest(tdsChan.channelId)
 		}
 		return false
 	}
@@ -621,7 +772,7 @@ func (tdsChan *Channel) tryParsePackage() bool {
 	// Create Package.
 	pkg, err := LookupPackage(Token(tokenByte))
 	if err != nil {
-		tdsChan.errCh <- err
+		tdsChan.reportError(err)
 		return false
 	}
 
@@ -632,7 +783,7 @@ func (tdsChan *Channel) tryParsePackage() bool {
 
 	if acceptor, ok := pkg.(LastPkgAcceptor); ok {
 		if err := acceptor.LastPkg(tdsChan.lastPkgRx); err != nil {
-			tdsChan.errCh <- fmt.Errorf("error in LastPkg: %w", err)
+			tdsChan.reportError(fmt.Errorf("error in LastPkg: %w", err))
 			return false
 		}
 	}
@@ -645,13 +796,13 @@ func (tdsChan *Channel) tryParsePackage() bool {
 		}
 
 		// Parsing went wrong, record as error
-		tdsChan.errCh <- fmt.Errorf("error parsing package %T: %w", pkg, err)
+		tdsChan.reportError(fmt.Errorf("error parsing package %T: %w", pkg, err))
 		return false
 	}
 
 	pass, err := tdsChan.handleSpecialPackage(pkg)
 	if err != nil {
-		tdsChan.errCh <- fmt.Errorf("error while handling special package: %w", err)
+		tdsChan.reportError(fmt.Errorf("error while handling special package: %w", err))
 		// Package handling errored, but the package could be parsed.
 		// Continue.
 		return true
@@ -662,7 +813,133 @@ func (tdsChan *Channel) tryParsePackage() bool {
 		return true
 	}
 
-	tdsChan.packageCh <- pkg
+	if tdsChan.discarding {
+		// Following packages may depend on the discarded package,
+		// e.g. rows on their row format.
+		tdsChan.lastPkgRx = pkg
+		return true
+	}
+
+	if !tdsChan.deliver(pkg, tdsChan.queueRx.bytesSince(startPacket, startData)) {
+		return false
+	}
 	tdsChan.lastPkgRx = pkg
 	return true
 }
+
+// queuedPackage is a package in the package channel and the number of
+// bytes it was parsed from.
+type queuedPackage struct {
+	pkg  Package
+	size int64
+}
+
+// deliver passes pkg parsed from size bytes to the package channel.
+//
+// deliver blocks until the package is consumed, the channel is closing
+// or the connection is closed. If the connection has a watchdog
+// timeout and the package is not consumed within the timeout the
+// connection is failed with ErrStalled.
+//
+// If the channel has a memory limit deliver also blocks until the
+// memory for the package is available. As the memory is accounted per
+// channel only packages of this channel must be consumed to release
+// it. With the policy MemoryFail
+// a *MemoryLimitError is reported instead and the remaining packages
+// of the response are discarded.
+//
+// The returned boolean reports if the package was delivered or
+// discarded.
+func (tdsChan *Channel) deliver(pkg Package, size int64) bool {
+	// The watchdog timer is only started if the package cannot be
+	// delivered immediately.
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	watchdog := func() <-chan time.Time {
+		timeout := tdsChan.tdsConn.watchdogTimeout
+		if timeout <= 0 {
+			return nil
+		}
+
+		if timer == nil {
+			timer = time.NewTimer(timeout)
+		}
+		return timer.C
+	}
+
+	if budget := tdsChan.memory; budget != nil && size > 0 {
+		for {
+			ok, released := budget.tryAcquire(size)
+			if ok {
+				break
+			}
+
+			if budget.policy == MemoryFail {
+				tdsChan.discarding = true
+				tdsChan.reportError(budget.exceeded(size))
+				return true
+			}
+
+			select {
+			case <-released:
+			case <-tdsChan.closing:
+				return false
+			case <-tdsChan.tdsConn.ctx.Done():
+				return false
+			case <-watchdog():
+				tdsChan.stall(pkg)
+				return false
+			}
+		}
+	}
+
+	queued := queuedPackage{pkg: pkg, size: size}
+
+	select {
+	case tdsChan.packageCh <- queued:
+		return true
+	default:
+	}
+
+	select {
+	case tdsChan.packageCh <- queued:
+		return true
+	case <-tdsChan.closing:
+	case <-tdsChan.tdsConn.ctx.Done():
+	case <-watchdog():
+		tdsChan.stall(pkg)
+	}
+
+	tdsChan.releaseMemory(size)
+	return false
+}
+
+// stall fails the connection with ErrStalled as pkg was not consumed
+// within the watchdog timeout.
+func (tdsChan *Channel) stall(pkg Package) {
+	tdsChan.tdsConn.fail(fmt.Errorf("channel %d did not consume %T within %s with %d packages queued: %w",
+		tdsChan.channelId, pkg, tdsChan.tdsConn.watchdogTimeout, len(tdsChan.packageCh), ErrStalled))
+}
+
+// releaseMemory returns the memory of a consumed package of size bytes
+// to the memory budget of the channel.
+func (tdsChan *Channel) releaseMemory(size int64) {
+	if budget := tdsChan.memory; budget != nil {
+		budget.release(size)
+	}
+}
+
+// reportError passes err to the error channel. If the error channel is
+// full the error is logged and dropped to not block reading.
+func (tdsChan *Channel) reportError(err error) {
+	select {
+	case tdsChan.errCh <- err:
+	default:
+		tdsChan.logger.Warn("dropping error, error channel is full", "error", err)
+	}
+}
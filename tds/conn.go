@@ -6,24 +6,31 @@ package tds
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
-	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"math"
 	"net"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/SAP/go-dblib/annotate"
 	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/logging"
+	"github.com/SAP/go-dblib/netlib"
+	"github.com/SAP/go-dblib/throttle"
+	"github.com/SAP/go-dblib/trace"
+	"github.com/SAP/go-dblib/version"
 	"github.com/hashicorp/go-multierror"
 )
 
+func init() {
+	dsn.RegisterProps("capabilities", "auth", "watchdog-timeout", "statement-timeout", "login-timeout",
+		"memory-limit", "memory-policy", "channel-package-queue-size")
+}
+
 // Conn handles a TDS-based connection.
 //
 // Note: This is not the underlying structure for driver.Conn - that is
@@ -33,6 +40,18 @@ type Conn struct {
 	Caps *CapabilityPackage
 	dsn  *dsn.Info
 
+	// requestedCaps are the capabilities requested during login.
+	requestedCaps *CapabilityPackage
+	// grantedCaps are the capabilities the server responded with
+	// during login.
+	grantedCaps *CapabilityPackage
+
+	// tdsVersion, serverName and serverVersion are sent by the server
+	// in the login acknowledgement.
+	tdsVersion    version.Version
+	serverName    string
+	serverVersion version.Version
+
 	odce odceCipher
 
 	ctx                 context.Context
@@ -42,87 +61,300 @@ type Conn struct {
 	tdsChannelsLock     *sync.RWMutex
 	errCh               chan error
 
+	// readerDone is closed when the goroutine reading from conn
+	// returns.
+	readerDone chan struct{}
+
+	// watchdogTimeout is the duration the reader waits for a channel
+	// to accept a package before the connection is failed. Zero
+	// disables the watchdog.
+	watchdogTimeout time.Duration
+
+	// limiter limits the rate of requests sent over the connection.
+	// Nil disables the limit.
+	limiter *throttle.Limiter
+
+	// memory is the memory limit of the channels, which each account
+	// their memory with a copy, see memoryBudget.forChannel. Nil
+	// disables the limit.
+	memory *memoryBudget
+
+	// annotator annotates the language commands sent over the
+	// connection, see annotate.From.
+	annotator *annotate.Annotator
+
+	// inFlight maps the ids of channels waiting for a response to the
+	// first package of their request. requestsDone is closed and
+	// replaced whenever a request completes. shutdown is set by
+	// Shutdown to reject new requests.
+	requestsLock *sync.Mutex
+	inFlight     map[int]Package
+	requestsDone chan struct{}
+	shutdown     bool
+
+	// statementTimeout limits the duration of requests sent without
+	// a context deadline, loginTimeout the duration of logins. Zero
+	// disables the limits. deadlines maps the ids of channels to the
+	// deadlines of their requests and is guarded by requestsLock.
+	// ctxDeadlines maps the ids of channels to the context deadlines of
+	// their requests, which bound reading the responses.
+	statementTimeout time.Duration
+	loginTimeout     time.Duration
+	deadlines        map[int]time.Time
+	ctxDeadlines     map[int]time.Time
+
+	// failErr records the error the connection was failed with.
+	failErr  error
+	failOnce sync.Once
+	failLock sync.Mutex
+
+	closeErr  error
+	closeOnce sync.Once
+
 	// packetSize is the negotiated packet size
 	packetSize int
+
+	// writeLock serializes writes to conn, so the write deadline of
+	// one write does not apply to another.
+	writeLock sync.Mutex
+
+	// logger adds the connection id to all messages.
+	logger logging.Logger
 }
 
+// connCounter is used to assign connection ids for logging.
+var connCounter uint64
+
 // Dial returns a prepared and dialed Conn.
 //
 // A new child context will be created from the passed context and used
 // to abort any interaction with the server - hence closing the parent
 // context will abort all interaction with the server.
+//
+// If dsn lists multiple servers, see dsn.Info.Endpoints, they are
+// dialed in order until a connection is established. Failed
+// connection attempts are retried as configured by .ConnectRetries,
+// .ConnectRetryDelay and .ConnectRetryBackoff of dsn. Each dial is
+// limited by .DialTimeout of dsn. .ReadTimeout limits reading the
+// payload of a packet and takes precedence over .PacketReadTimeout.
+//
+// The requested capabilities are selected by the property
+// "capabilities" of dsn, see CapabilityPresets for the available
+// presets. If the property is not set DefaultCapabilityPreset is used.
+//
+// The property "watchdog-timeout" sets the duration a channel may
+// stall the reading of packets from the server by not consuming its
+// packages. If the duration is exceeded the connection is failed with
+// ErrStalled. The watchdog is disabled by default.
+//
+// The rate of requests is limited by the properties of dsn read by
+// throttle.FromDSN, see also SetLimiter.
+//
+// The property "memory-limit" sets the number of bytes the packages
+// received but not yet consumed may hold per channel. The property "memory-policy"
+// selects the MemoryPolicy applied when the limit is exceeded, either
+// "block" (the default) or "fail". Memory is not limited by default.
+//
+// The property "statement-timeout" limits the duration of requests
+// whose context has no deadline. Requests exceeding the timeout are
+// cancelled and fail with ErrStatementTimeout. The property
+// "login-timeout" limits the duration of Channel.Login if its context
+// has no deadline.
+//
+// Language commands are annotated with a comment describing their
+// origin if the property "annotate" is set, see annotate.FromDSN.
+//
+// The secrets, the userstore key and the server name of dsn are
+// resolved with dsn.Info.Resolve and the result is validated with
+// dsn.Info.Validate before dialing. The resolved copy is returned by
+// DSN and must be passed to NewLoginConfig.
 func NewConn(ctx context.Context, dsn *dsn.Info) (*Conn, error) {
-	network := "tcp"
-	if prop := dsn.Prop("network"); prop != "" {
-		network = prop
+	dsn, err := dsn.Resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dsn.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+
+	c, endpointDSN, err := dialRetry(ctx, dsn)
+	if err != nil {
+		return nil, err
 	}
 
-	// Dial returns a prepared and dialed Conn.
-	c, err := net.Dial(network, fmt.Sprintf("%s:%s", dsn.Host, dsn.Port))
+	tds, err := newConn(ctx, dsn, c)
 	if err != nil {
-		return nil, fmt.Errorf("error opening connection: %w", err)
+		c.Close()
+		return nil, err
 	}
 
-	if dsn.TLSEnable || strings.TrimSpace(strings.Replace(dsn.Port, "0", "", -1)) == "443" {
-		tlsConfig := &tls.Config{}
-		tlsConfig.ServerName = dsn.Host
-		tlsConfig.InsecureSkipVerify = dsn.TLSSkipValidation
+	tds.logger.Debug("connection established", "address", netlib.Address(endpointDSN))
+	return tds, nil
+}
 
-		if dsn.TLSHostname != "" {
-			hostname := dsn.TLSHostname
-			if strings.HasPrefix(hostname, "CN=") {
-				hostname = strings.TrimPrefix(hostname, "CN=")
-			}
+// Defaults of the connect retry options of dsn.Info.
+const (
+	DefaultConnectRetryDelay   = time.Second
+	DefaultConnectRetryBackoff = 2.0
+)
+
+// dialRetry calls dialEndpoints and retries failed attempts with
+// a backoff as configured by .ConnectRetries, .ConnectRetryDelay and
+// .ConnectRetryBackoff of info.
+//
+// Configuration errors are not retried.
+func dialRetry(ctx context.Context, info *dsn.Info) (net.Conn, *dsn.Info, error) {
+	delay := info.ConnectRetryDelay
+	if delay <= 0 {
+		delay = DefaultConnectRetryDelay
+	}
+
+	backoff := info.ConnectRetryBackoff
+	if backoff < 1 {
+		backoff = DefaultConnectRetryBackoff
+	}
 
-			tlsConfig.ServerName = hostname
+	c, endpointDSN, err := dialEndpoints(ctx, info)
+	for retry := 0; err != nil && retry < info.ConnectRetries; retry++ {
+		if errors.Is(err, dberrors.CategoryConfig) {
+			return nil, nil, err
 		}
 
-		if dsn.TLSCAFile != "" {
-			bs, err := ioutil.ReadFile(dsn.TLSCAFile)
-			if err != nil {
-				return nil, fmt.Errorf("error reading file at ssl-ca path '%s': %w",
-					dsn.TLSCAFile, err)
-			}
+		logging.FromContext(ctx).Debug("connection failed, retrying", "retry", retry+1, "delay", delay, "error", err)
 
-			tlsConfig.RootCAs = x509.NewCertPool()
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, fmt.Errorf("aborted retrying after %d retries: %w", retry, err)
+		case <-timer.C:
+		}
+		delay = time.Duration(float64(delay) * backoff)
 
-			for {
-				var block *pem.Block
-				block, bs = pem.Decode(bs)
-				if block == nil {
-					break
-				}
+		c, endpointDSN, err = dialEndpoints(ctx, info)
+	}
 
-				caCert, err := x509.ParseCertificate(block.Bytes)
-				if err != nil {
-					return nil, fmt.Errorf("error parsing CA PEM at ssl-ca path '%s': %w",
-						dsn.TLSCAFile, err)
-				}
+	if err != nil && info.ConnectRetries > 0 {
+		return nil, nil, fmt.Errorf("failed after %d retries: %w", info.ConnectRetries, err)
+	}
 
-				tlsConfig.RootCAs.AddCert(caCert)
+	return c, endpointDSN, err
+}
 
-				if len(bs) == 0 {
-					break
-				}
-			}
+// dialEndpoints dials the servers of info in order of their priority,
+// see dsn.Info.Endpoints, and returns the first established connection
+// and the dsn.Info of its server.
+func dialEndpoints(ctx context.Context, info *dsn.Info) (net.Conn, *dsn.Info, error) {
+	endpoints, err := dsn.NewEndpointIterator(info)
+	if err != nil {
+		return nil, nil, err
+	}
 
-			if len(tlsConfig.RootCAs.Subjects()) == 0 {
-				return nil, fmt.Errorf("could not parse any valid CA certificate from file '%s'", dsn.TLSCAFile)
-			}
+	var me error
+	for endpoint, ok := endpoints.Next(); ok; endpoint, ok = endpoints.Next() {
+		endpointDSN := info
+		if endpoint != (dsn.Endpoint{Host: info.Host, Port: info.Port}) {
+			endpointDSN = netlib.WithEndpoint(info, endpoint)
+		}
+
+		c, err := dial(ctx, endpointDSN)
+		if err == nil {
+			return c, endpointDSN, nil
+		}
+
+		if endpoints.Len() == 1 {
+			return nil, nil, err
 		}
 
-		tlsClient := tls.Client(c, tlsConfig)
-		if err := tlsClient.Handshake(); err != nil {
-			return nil, fmt.Errorf("error during TLS handshake with server: %w", err)
+		logging.FromContext(ctx).Debug("connection failed, trying next server", "address", endpoint, "error", err)
+		me = multierror.Append(me, err)
+		if ctx.Err() != nil {
+			break
 		}
-		c = tlsClient
 	}
 
+	return nil, nil, dberrors.Wrap(dberrors.CategoryNetwork, me)
+}
+
+// dial opens a connection to the server of info.
+func dial(ctx context.Context, info *dsn.Info) (net.Conn, error) {
+	dialer, err := netlib.DialerFromDSN(info)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dialer: %w", err)
+	}
+
+	if info.DialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, info.DialTimeout)
+		defer cancel()
+	}
+
+	dialCtx, span := trace.Start(ctx, trace.KindConnect, "dial",
+		append(trace.DSNAttrs(info),
+			trace.Attr{Key: "network", Value: netlib.Network(info)},
+			trace.Attr{Key: "address", Value: netlib.Address(info)},
+		)...,
+	)
+	c, err := dialer.DialContext(dialCtx, netlib.Network(info), netlib.Address(info))
+	span.End(err)
+	if err != nil {
+		return nil, fmt.Errorf("error opening connection: %w", dberrors.Wrap(dberrors.CategoryNetwork, err))
+	}
+
+	return c, nil
+}
+
+// NewConnFrom returns a Conn communicating over the established
+// connection c instead of dialing the server described by dsn, e.g. a
+// connection to the mock server of the package tdstest. The properties
+// of dsn are applied as in NewConn.
+//
+// c is closed when the Conn is closed.
+func NewConnFrom(ctx context.Context, dsn *dsn.Info, c net.Conn) (*Conn, error) {
+	return newConn(ctx, dsn, c)
+}
+
+// newConn prepares a Conn communicating over the established
+// connection c and starts the goroutine reading from it.
+func newConn(ctx context.Context, dsn *dsn.Info, c io.ReadWriteCloser) (*Conn, error) {
 	tds := &Conn{
 		dsn:        dsn,
 		conn:       c,
 		packetSize: 512,
+		logger:     logging.With(logging.FromContext(ctx), "conn", atomic.AddUint64(&connCounter, 1)),
+	}
+
+	var err error
+	if tds.watchdogTimeout, err = dsn.PropDuration("watchdog-timeout", 0); err != nil {
+		return nil, err
+	}
+
+	if tds.statementTimeout, err = dsn.PropDuration("statement-timeout", 0); err != nil {
+		return nil, err
+	}
+
+	if tds.loginTimeout, err = dsn.PropDuration("login-timeout", 0); err != nil {
+		return nil, err
+	}
+
+	limiter, err := throttle.FromDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error creating rate limiter: %w", err)
 	}
+	tds.limiter = limiter
+
+	memory, err := memoryBudgetFromDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error creating memory limit: %w", err)
+	}
+	tds.memory = memory
+
+	annotator, err := annotate.FromDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error creating annotator: %w", err)
+	}
+	tds.annotator = annotator
 
 	if err := tds.setCapabilities(); err != nil {
 		return nil, fmt.Errorf("error setting capabilities on connection: %w", err)
@@ -137,47 +369,155 @@ func NewConn(ctx context.Context, dsn *dsn.Info) (*Conn, error) {
 	tds.tdsChannels = make(map[int]*Channel)
 	tds.tdsChannelsLock = &sync.RWMutex{}
 	tds.errCh = make(chan error, 10)
+	tds.readerDone = make(chan struct{})
+	tds.requestsLock = &sync.Mutex{}
+	tds.inFlight = map[int]Package{}
+	tds.deadlines = map[int]time.Time{}
+	tds.ctxDeadlines = map[int]time.Time{}
+	tds.requestsDone = make(chan struct{})
 
 	// A goroutine automatically reads payloads from the server and
 	// passes them to the corresponding channel.
 	// Payloads sent to the server are sent in the thread the client
 	// uses.
-	go tds.ReadFrom()
+	go tds.readLoop()
 
 	return tds, nil
 }
 
+// DefaultCloseTimeout is the duration Close waits for channels to
+// close before the connection is closed forcibly.
+const DefaultCloseTimeout = time.Minute
+
+// closeTimeout is the duration Close and Shutdown wait for channels to
+// close.
+var closeTimeout = DefaultCloseTimeout
+
+// readerGracePeriod is the duration CloseContext waits for the reading
+// goroutine to return after the connection was closed.
+var readerGracePeriod = 5 * time.Second
+
 // Close closes a Conn and its unclosed Channels.
 //
-// Teardown and closing on the client side is guaranteed, even if Close
-// returns an error. An error is only returned if the communication with
-// the server fails or if channels report errors during closing.
+// Close is a shorthand for CloseContext with a context limited by
+// DefaultCloseTimeout.
+func (tds *Conn) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+
+	return tds.CloseContext(ctx)
+}
+
+// CloseContext closes a Conn and its unclosed Channels.
+//
+// Teardown and closing on the client side is guaranteed, even if
+// CloseContext returns an error. An error is only returned if the
+// communication with the server fails, if channels report errors during
+// closing or if the channels could not be closed before ctx is done.
+// In the latter case the connection is closed forcibly.
+//
+// Subsequent calls return the error of the first call.
 //
 // If an error is returned it is a *multierror.Error with all errors.
-func (tds *Conn) Close() error {
+func (tds *Conn) CloseContext(ctx context.Context) error {
+	tds.closeOnce.Do(func() {
+		tds.closeErr = tds.close(ctx)
+	})
+	return tds.closeErr
+}
+
+func (tds *Conn) close(ctx context.Context) error {
 	var me error
 
-	var tdsChannels []*Channel
-	// tdsChannels := make([]*Channel, len(tds.tdsChannels))
+	tds.tdsChannelsLock.RLock()
+	tdsChannels := make([]*Channel, 0, len(tds.tdsChannels))
 	for _, channel := range tds.tdsChannels {
 		tdsChannels = append(tdsChannels, channel)
 	}
+	tds.tdsChannelsLock.RUnlock()
+
+	// The channels are closed in a separate goroutine as closing
+	// requires communication with the server, which may not respond.
+	// Cancelling the context and closing the connection below aborts
+	// the communication, so the goroutine returns in any case.
+	channelsErrCh := make(chan error, 1)
+	go func() {
+		var me error
+		for _, channel := range tdsChannels {
+			if err := channel.Close(); err != nil {
+				me = multierror.Append(me, fmt.Errorf("error closing channel: %w", err))
+			}
+		}
+		channelsErrCh <- me
+	}()
 
-	for _, channel := range tdsChannels {
-		if err := channel.Close(); err != nil {
-			me = multierror.Append(me, fmt.Errorf("error closing channel: %w", err))
+	select {
+	case err := <-channelsErrCh:
+		if err != nil {
+			me = multierror.Append(me, err)
 		}
+	case <-ctx.Done():
+		me = multierror.Append(me, fmt.Errorf("channels not closed in time, closing connection forcibly: %w", ctx.Err()))
 	}
 
+	trace.Emit(tds.ctx, trace.KindConnect, "close")
 	tds.ctxCancel()
+	tds.logger.Debug("closing connection")
 
 	if err := tds.conn.Close(); err != nil {
 		me = multierror.Append(me, fmt.Errorf("error closing connection: %w", err))
 	}
 
+	select {
+	case <-tds.readerDone:
+	case <-time.After(readerGracePeriod):
+		me = multierror.Append(me, fmt.Errorf("reader did not stop within %s after closing connection", readerGracePeriod))
+	}
+
 	return me
 }
 
+// fail marks the connection as failed with err and cancels its
+// context, which aborts all interaction with the server.
+//
+// Only the first error is recorded. Errors after the connection was
+// closed are ignored.
+func (tds *Conn) fail(err error) {
+	if tds.ctx.Err() != nil {
+		return
+	}
+
+	tds.failOnce.Do(func() {
+		tds.failLock.Lock()
+		tds.failErr = err
+		tds.failLock.Unlock()
+
+		tds.logger.Error("connection failed", "error", err)
+		tds.ctxCancel()
+	})
+}
+
+// cause returns the error the connection was failed with or the error
+// of its context.
+func (tds *Conn) cause() error {
+	tds.failLock.Lock()
+	defer tds.failLock.Unlock()
+
+	if tds.failErr != nil {
+		return tds.failErr
+	}
+	return tds.ctx.Err()
+}
+
+// SetLimiter sets the Limiter consulted before sending requests. The
+// Limiter may be shared with other connections to limit the rate of
+// all their requests. Passing nil disables the limit.
+//
+// SetLimiter must be called before the connection is used.
+func (tds *Conn) SetLimiter(limiter *throttle.Limiter) {
+	tds.limiter = limiter
+}
+
 // PacketSize returns the negotiated packet size.
 func (tds *Conn) PacketSize() int {
 	// Must be pointer-receive as it is passed to Channels to acquire
@@ -193,6 +533,77 @@ func (tds *Conn) PacketBodySize() int {
 	return tds.packetSize - PacketHeaderSize
 }
 
+// DSN returns the DSN the connection was established with.
+func (tds *Conn) DSN() *dsn.Info {
+	return tds.dsn
+}
+
+// GrantedCapabilities returns the capabilities granted by the server
+// during login or nil if the login has not finished yet.
+func (tds *Conn) GrantedCapabilities() *CapabilityPackage {
+	return tds.grantedCaps
+}
+
+// RequestedCapabilities returns the capabilities requested during
+// login.
+func (tds *Conn) RequestedCapabilities() *CapabilityPackage {
+	return tds.requestedCaps
+}
+
+// CapabilityTable returns a table of the requested and granted
+// capabilities. See CapabilityTable.
+func (tds *Conn) CapabilityTable() string {
+	return CapabilityTable(tds.requestedCaps, tds.grantedCaps)
+}
+
+// TDSVersion returns the TDS version acknowledged by the server or the
+// zero version if the login has not finished yet.
+func (tds *Conn) TDSVersion() version.Version {
+	return tds.tdsVersion
+}
+
+// ServerVersion returns the program version sent by the server in the
+// login acknowledgement or the zero version if the login has not
+// finished yet.
+func (tds *Conn) ServerVersion() version.Version {
+	return tds.serverVersion
+}
+
+// ServerName returns the program name sent by the server in the login
+// acknowledgement or an empty string if the login has not finished
+// yet.
+func (tds *Conn) ServerName() string {
+	return tds.serverName
+}
+
+// setLoginAck records the program name and versions of the login
+// acknowledgement.
+func (tds *Conn) setLoginAck(loginAck *LoginAckPackage) {
+	tds.serverName = loginAck.ProgramName
+
+	if loginAck.Version != nil {
+		tds.tdsVersion, _ = version.FromBytes(loginAck.Version.Bytes())
+	}
+
+	if loginAck.ProgramVersion != nil {
+		tds.serverVersion, _ = version.FromBytes(loginAck.ProgramVersion.Bytes())
+	}
+
+	tds.logger.Debug("logged in", "tds-version", tds.tdsVersion, "server-name", tds.serverName, "server-version", tds.serverVersion)
+}
+
+// HasCapability returns whether the server granted the passed request
+// capability during login.
+//
+// HasCapability returns false if the login has not finished yet.
+func (tds *Conn) HasCapability(capability RequestCapability) bool {
+	if tds.grantedCaps == nil {
+		return false
+	}
+
+	return tds.grantedCaps.HasRequestCapability(capability)
+}
+
 func (tds *Conn) getValidChannelId() (int, error) {
 	curId := int(tds.tdsChannelCurFreeId)
 
@@ -204,7 +615,11 @@ func (tds *Conn) getValidChannelId() (int, error) {
 	// increment ID before recursing or returning
 	atomic.AddUint32(&tds.tdsChannelCurFreeId, 1)
 
-	if _, ok := tds.tdsChannels[curId]; ok {
+	tds.tdsChannelsLock.RLock()
+	_, ok := tds.tdsChannels[curId]
+	tds.tdsChannelsLock.RUnlock()
+
+	if ok {
 		// ChannelId is already used, recurse
 		return tds.getValidChannelId()
 	}
@@ -212,8 +627,45 @@ func (tds *Conn) getValidChannelId() (int, error) {
 	return curId, nil
 }
 
+// writePacket writes packet to the server. The write deadline is the
+// deadline of ctx if conn supports write deadlines.
+func (tds *Conn) writePacket(ctx context.Context, packet *Packet) (int64, error) {
+	tds.writeLock.Lock()
+	defer tds.writeLock.Unlock()
+
+	if deadliner, ok := tds.conn.(writeDeadliner); ok {
+		if err := deadliner.SetWriteDeadline(deadline(ctx, 0)); err != nil {
+			return 0, fmt.Errorf("error setting write deadline: %w", err)
+		}
+		defer deadliner.SetWriteDeadline(time.Time{})
+	}
+
+	return packet.WriteTo(tds.conn)
+}
+
+// readLoop runs ReadFrom and signals its return.
+func (tds *Conn) readLoop() {
+	defer close(tds.readerDone)
+	tds.ReadFrom()
+}
+
+// readTimeout returns the timeout for reading the payload of
+// a packet, which is .ReadTimeout of the DSN or .PacketReadTimeout in
+// seconds if .ReadTimeout is not set.
+func (tds *Conn) readTimeout() time.Duration {
+	if tds.dsn.ReadTimeout > 0 {
+		return tds.dsn.ReadTimeout
+	}
+
+	return time.Duration(tds.dsn.PacketReadTimeout) * time.Second
+}
+
 // ReadFrom creates packets from payloads from the server and writes
 // them to the corresponding Channel.
+//
+// ReadFrom returns when the connection is closed or failed. Errors
+// reading from the server fail the connection as the stream cannot be
+// recovered.
 func (tds *Conn) ReadFrom() {
 	for {
 		if err := tds.ctx.Err(); err != nil {
@@ -221,20 +673,35 @@ func (tds *Conn) ReadFrom() {
 		}
 
 		packet := &Packet{}
-		_, err := packet.ReadFrom(tds.ctx, tds.conn, time.Duration(tds.dsn.PacketReadTimeout)*time.Second)
+		if _, err := packet.Header.ReadFrom(tds.conn); err != nil {
+			if !errors.Is(err, io.EOF) {
+				tds.fail(dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error reading packet header: %w", err)))
+			}
+			return
+		}
+
+		// Reading the body is bound by the deadline of the request
+		// the packet responds to.
+		ctx, cancel := tds.readContext(int(packet.Header.Channel))
+		_, err := packet.readBody(ctx, tds.conn, tds.readTimeout())
+		cancel()
 		if err != nil && !errors.Is(err, io.EOF) {
-			tds.errCh <- fmt.Errorf("error reading packet: %w", err)
-			continue
+			tds.fail(dberrors.Wrap(dberrors.CategoryNetwork, fmt.Errorf("error reading packet: %w", err)))
+			return
 		}
 
 		tds.tdsChannelsLock.RLock()
 		tdsChan, ok := tds.tdsChannels[int(packet.Header.Channel)]
 		tds.tdsChannelsLock.RUnlock()
 		if !ok {
-			tds.errCh <- fmt.Errorf("received packet for invalid channel %d", packet.Header.Channel)
+			tds.reportError(fmt.Errorf("received packet for invalid channel %d", packet.Header.Channel))
 			continue
 		}
 
+		if trace.Enabled(tds.ctx) {
+			trace.Emit(tds.ctx, trace.KindPacket, "receive", packetAttrs(packet)...)
+		}
+
 		// Errors are recorded in the channels' error channel.
 		tdsChan.WritePacket(packet)
 
@@ -245,136 +712,28 @@ func (tds *Conn) ReadFrom() {
 	}
 }
 
+// reportError passes err to the channels. If the error channel is full
+// the error is logged and dropped to not block reading.
+func (tds *Conn) reportError(err error) {
+	select {
+	case tds.errCh <- err:
+	default:
+		tds.logger.Warn("dropping error, error channel is full", "error", err)
+	}
+}
+
 func (tds *Conn) setCapabilities() error {
-	caps, err := NewCapabilityPackage(
-		[]RequestCapability{
-			// Support language requests
-			TDS_REQ_LANG,
-			// Support RPC requests
-			// TODO: TDS_REQ_RPC,
-			// Support procedure event notifications
-			// TODO: TDS_REQ_EVT,
-			// Support multiple commands per request
-			TDS_REQ_MSTMT,
-			// Support bulk copy
-			// TODO: TDS_REQ_BCP,
-			// Support cursors requests
-			// TODO: TDS_REQ_CURSOR,
-			// Support dynamic SQL
-			TDS_REQ_DYNF,
-			// Support MSG requests
-			TDS_REQ_MSG,
-			// RPC will use TDS_DBRPC and TDS_PARAMFMT / TDS_PARAM
-			TDS_REQ_PARAM,
-
-			// Enable all optional data types
-			TDS_DATA_INT1,
-			TDS_DATA_INT2,
-			TDS_DATA_INT4,
-			TDS_DATA_BIT,
-			TDS_DATA_CHAR,
-			TDS_DATA_VCHAR,
-			TDS_DATA_BIN,
-			TDS_DATA_VBIN,
-			TDS_DATA_MNY8,
-			TDS_DATA_MNY4,
-			TDS_DATA_DATE8,
-			TDS_DATA_DATE4,
-			TDS_DATA_FLT4,
-			TDS_DATA_FLT8,
-			TDS_DATA_NUM,
-			TDS_DATA_TEXT,
-			TDS_DATA_IMAGE,
-			TDS_DATA_DEC,
-			TDS_DATA_LCHAR,
-			TDS_DATA_LBIN,
-			TDS_DATA_INTN,
-			TDS_DATA_DATETIMEN,
-			TDS_DATA_MONEYN,
-			TDS_DATA_SENSITIVITY,
-			TDS_DATA_BOUNDARY,
-			TDS_DATA_FLTN,
-			TDS_DATA_BITN,
-			TDS_DATA_INT8,
-			TDS_DATA_UINT2,
-			TDS_DATA_UINT4,
-			TDS_DATA_UINT8,
-			TDS_DATA_UINTN,
-			TDS_DATA_NLBIN,
-			TDS_IMAGE_NCHAR,
-			TDS_BLOB_NCHAR_16,
-			TDS_BLOB_NCHAR_8,
-			TDS_BLOB_NCHAR_SCSU,
-			TDS_DATA_DATE,
-			TDS_DATA_TIME,
-			TDS_DATA_INTERVAL,
-			TDS_DATA_UNITEXT,
-			TDS_DATA_SINT1,
-			TDS_REQ_LARGEIDENT,
-			TDS_REQ_BLOB_NCHAR_16,
-			TDS_DATA_XML,
-			TDS_DATA_BIGDATETIME,
-			TDS_DATA_USECS,
-			//TODO: TDS_DATA_LOBLOCATOR,
-
-			// Support streaming
-			//TODO: TDS_OBJECT_CHAR,
-			//TODO: TDS_OBJECT_BINARY,
-
-			// Support expedited and non-expedited attentions
-			TDS_CON_OOB,
-			TDS_CON_INBAND,
-			// Use urgent notifications
-			TDS_REQ_URGEVT,
-
-			// Create procs from dynamic statements
-			TDS_PROTO_DYNPROC,
-
-			// Request status byte in TDS_PARAMS responses
-			// Allows to handel nullbytes
-			TDS_DATA_COLUMNSTATUS,
-			// Support newer versions of tokens
-			TDS_REQ_CURINFO3,
-			TDS_REQ_DBRPC2,
-			// TDS_PARAMFMT2
-			TDS_WIDETABLES,
-
-			// Support scrollable cursors
-			TDS_CSR_SCROLL,
-			TDS_CSR_SENSITIVE,
-			TDS_CSR_INSENSITIVE,
-			TDS_CSR_SEMISENSITIVE,
-			TDS_CSR_KEYSETDRIVEN,
-
-			// Renegotiate packet size after login negotiation
-			TDS_REQ_SRVPKTSIZE,
-
-			// Support cluster failover and migration
-			//TODO: TDS_CAP_CLUSTERFAILOVER,
-			//TODO: TDS_REQ_MIGRATE,
-
-			// Support batched parameters
-			TDS_REQ_DYN_BATCH,
-			TDS_REQ_LANG_BATCH,
-			TDS_REQ_RPC_BATCH,
-
-			// Support on demand encryption
-			TDS_REQ_COMMAND_ENCRYPTION,
-
-			// Client will only perform readonly operations
-			//TODO: TDS_REQ_READONLY,
-		},
-		[]ResponseCapability{
-			// Ignore format control
-			TDS_RES_NO_TDSCONTROL,
-		},
-		[]SecurityCapability{},
-	)
+	builder, err := NewCapabilityBuilder(tds.dsn.PropDefault("capabilities", DefaultCapabilityPreset))
+	if err != nil {
+		return err
+	}
 
+	caps, err := builder.Build()
 	if err != nil {
 		return fmt.Errorf("error creating capability package: %w", err)
 	}
 
 	tds.Caps = caps
+	tds.requestedCaps = caps
 	return nil
 }
@@ -0,0 +1,468 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/throttle"
+	"github.com/SAP/go-dblib/trace"
+)
+
+// pipeConn wraps a net.Conn from net.Pipe. Reads into empty buffers
+// return immediately as with network connections instead of blocking
+// until the other side writes.
+type pipeConn struct {
+	net.Conn
+}
+
+func (c pipeConn) Read(bs []byte) (int, error) {
+	if len(bs) == 0 {
+		return 0, nil
+	}
+	return c.Conn.Read(bs)
+}
+
+// newTestConn returns a Conn communicating over a pipe and the server
+// side of the pipe.
+func newTestConn(t *testing.T, props map[string]string) (*Conn, net.Conn) {
+	info := dsn.NewInfo()
+	for key, value := range props {
+		info.ConnectProps.Set(key, value)
+	}
+
+	client, server := net.Pipe()
+
+	conn, err := newConn(context.Background(), info, pipeConn{client})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+
+	return conn, server
+}
+
+// writeHeaderOnly writes n header-only packets for channel 0 to w.
+func writeHeaderOnly(w io.Writer, n int) error {
+	for i := 0; i < n; i++ {
+		header := PacketHeader{
+			MsgType: TDS_BUF_NORMAL,
+			Status:  TDS_BUFSTAT_EOM,
+			Length:  PacketHeaderSize,
+		}
+
+		if _, err := header.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assertReaderStopped fails the test if the reading goroutine of conn
+// has not returned.
+func assertReaderStopped(t *testing.T, conn *Conn) {
+	select {
+	case <-conn.readerDone:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Reader did not stop")
+	}
+}
+
+func TestNewConn_InvalidWatchdogTimeout(t *testing.T) {
+	info := dsn.NewInfo()
+	info.ConnectProps.Set("watchdog-timeout", "invalid")
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	_, err := newConn(context.Background(), info, client)
+	if err == nil {
+		t.Fatalf("Expected error for invalid watchdog-timeout")
+	}
+
+	if category := dberrors.CategoryOf(err); category != dberrors.CategoryConfig {
+		t.Errorf("Expected category %s, got %s", dberrors.CategoryConfig, category)
+	}
+}
+
+func TestNewConn_Hosts(t *testing.T) {
+	// The first server is not listening.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closed.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer l.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if c, err := l.Accept(); err == nil {
+			accepted <- c
+		}
+	}()
+
+	info := dsn.NewInfo()
+	info.Username = "user"
+	info.Password = "pass"
+	info.Hosts, err = dsn.ParseEndpoints(closed.Addr().String() + "," + l.Addr().String())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Fatalf("Expected connection to second server, got %v", err)
+	}
+	defer conn.Close()
+
+	select {
+	case c := <-accepted:
+		c.Close()
+	case <-time.After(5 * time.Second):
+		t.Errorf("Second server did not accept the connection")
+	}
+
+	info.Hosts = info.Hosts[:1]
+	if _, err := NewConn(context.Background(), info); !errors.Is(err, dberrors.CategoryNetwork) {
+		t.Errorf("Expected network error, got %v", err)
+	}
+}
+
+func TestNewConn_ConnectRetries(t *testing.T) {
+	// Reserve an address the server starts listening on later.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	info := dsn.NewInfo()
+	info.Host, info.Port, _ = net.SplitHostPort(addr)
+	info.Username = "user"
+	info.Password = "pass"
+	info.ConnectRetryDelay = 20 * time.Millisecond
+	info.ConnectRetryBackoff = 1
+
+	if _, err := NewConn(context.Background(), info); !errors.Is(err, dberrors.CategoryNetwork) {
+		t.Fatalf("Expected network error without retries, got %v", err)
+	}
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return
+		}
+		defer l.Close()
+
+		if c, err := l.Accept(); err == nil {
+			accepted <- c
+		}
+	}()
+
+	info.ConnectRetries = 50
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Fatalf("Expected connection after retries, got %v", err)
+	}
+	defer conn.Close()
+
+	select {
+	case c := <-accepted:
+		c.Close()
+	case <-time.After(5 * time.Second):
+		t.Errorf("Server did not accept the connection")
+	}
+}
+
+func TestNewConn_Invalid(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer l.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if c, err := l.Accept(); err == nil {
+			accepted <- c
+		}
+	}()
+
+	info := dsn.NewInfo()
+	info.Host, info.Port, _ = net.SplitHostPort(l.Addr().String())
+
+	if _, err := NewConn(context.Background(), info); err == nil {
+		t.Fatalf("Expected error for DSN without credentials")
+	}
+
+	select {
+	case c := <-accepted:
+		c.Close()
+		t.Errorf("Expected invalid DSN to be rejected before dialing")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestNewConn_PasswordFile(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer l.Close()
+
+	go func() {
+		if c, err := l.Accept(); err == nil {
+			c.Close()
+		}
+	}()
+
+	f, err := ioutil.TempFile("", "password")
+	if err != nil {
+		t.Fatalf("Failed to create password file: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString("secret\n"); err != nil {
+		t.Fatalf("Failed to write password file: %v", err)
+	}
+	f.Close()
+
+	info := dsn.NewInfo()
+	info.Host, info.Port, _ = net.SplitHostPort(l.Addr().String())
+	info.Username = "user"
+	info.PasswordFile = f.Name()
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	if conn.DSN().Password != "secret" {
+		t.Errorf("Expected password to be read from file, got %q", conn.DSN().Password)
+	}
+
+	if info.Password != "" {
+		t.Errorf("Expected passed DSN to be unchanged, got password %q", info.Password)
+	}
+
+	if _, err := NewLoginConfig(conn.DSN()); err != nil {
+		t.Errorf("Unexpected error creating login config: %v", err)
+	}
+}
+
+func TestConn_CloseContext_Unresponsive(t *testing.T) {
+	conn, server := newTestConn(t, nil)
+	defer server.Close()
+
+	if _, err := conn.NewChannel(); err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	// The server never reads, so sending the logout blocks.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := conn.CloseContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected forced close with deadline exceeded, got: %v", err)
+	}
+
+	assertReaderStopped(t, conn)
+
+	if second := conn.CloseContext(context.Background()); second != err {
+		t.Errorf("Expected subsequent close to return the first error, got: %v", second)
+	}
+}
+
+func TestConn_Close_SlowConsumer(t *testing.T) {
+	conn, server := newTestConn(t, map[string]string{"channel-package-queue-size": "1"})
+	defer server.Close()
+
+	if _, err := conn.NewChannel(); err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	go io.Copy(ioutil.Discard, server)
+
+	// More packages than the queue holds are sent, so the reader
+	// blocks delivering them as nothing consumes the packages.
+	go writeHeaderOnly(server, 3)
+
+	closeErrCh := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		closeErrCh <- conn.CloseContext(ctx)
+	}()
+
+	select {
+	case err := <-closeErrCh:
+		if errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected close without forcing, got: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Close deadlocked with a slow consumer")
+	}
+
+	assertReaderStopped(t, conn)
+}
+
+func TestConn_Watchdog(t *testing.T) {
+	conn, server := newTestConn(t, map[string]string{
+		"channel-package-queue-size": "1",
+		"watchdog-timeout":           "50ms",
+	})
+	defer server.Close()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	go writeHeaderOnly(server, 2)
+
+	select {
+	case <-conn.ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Watchdog did not fail the connection")
+	}
+
+	if err := conn.cause(); !errors.Is(err, ErrStalled) {
+		t.Errorf("Expected ErrStalled, got: %v", err)
+	}
+
+	// The queued package is still returned, afterwards the failure
+	// is reported.
+	if _, err := channel.NextPackage(context.Background(), true); err != nil {
+		t.Errorf("Expected queued package, got error: %v", err)
+	}
+
+	if _, err := channel.NextPackage(context.Background(), true); !errors.Is(err, ErrStalled) {
+		t.Errorf("Expected ErrStalled from NextPackage, got: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn.CloseContext(ctx)
+
+	assertReaderStopped(t, conn)
+}
+
+func TestConn_ServerClosed(t *testing.T) {
+	conn, server := newTestConn(t, nil)
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	server.Close()
+
+	if _, err := channel.NextPackage(context.Background(), true); err == nil {
+		t.Errorf("Expected error after server closed the connection")
+	}
+
+	assertReaderStopped(t, conn)
+
+	if category := dberrors.CategoryOf(conn.cause()); category != dberrors.CategoryNetwork {
+		t.Errorf("Expected category %s, got %s", dberrors.CategoryNetwork, category)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn.CloseContext(ctx)
+}
+
+func TestChannel_RateLimit(t *testing.T) {
+	conn, server := newTestConn(t, map[string]string{
+		"rate-limit":         "0.001",
+		"rate-queue-timeout": "1ms",
+	})
+	defer server.Close()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		conn.CloseContext(ctx)
+	}()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	go io.Copy(ioutil.Discard, server)
+
+	ctx := context.Background()
+	if err := channel.SendPackage(ctx, &LanguagePackage{Cmd: "select 1"}); err != nil {
+		t.Fatalf("Unexpected error sending first request: %v", err)
+	}
+
+	if err := channel.SendPackage(ctx, &LanguagePackage{Cmd: "select 1"}); !errors.Is(err, throttle.ErrThrottled) {
+		t.Errorf("Expected ErrThrottled, got: %v", err)
+	}
+}
+
+func TestChannel_Annotate(t *testing.T) {
+	conn, server := newTestConn(t, map[string]string{
+		"annotate":         "true",
+		"application-name": "billing",
+	})
+	defer server.Close()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		conn.CloseContext(ctx)
+	}()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	packetCh := make(chan *Packet, 1)
+	go func() {
+		packet := &Packet{}
+		if _, err := packet.ReadFrom(context.Background(), server, time.Second); err != nil {
+			t.Errorf("Error reading packet: %v", err)
+		}
+		packetCh <- packet
+		io.Copy(ioutil.Discard, server)
+	}()
+
+	pkg := &LanguagePackage{Cmd: "select 1"}
+	ctx := trace.WithTraceID(context.Background(), "abc")
+	if err := channel.SendPackage(ctx, pkg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "/* application='billing',trace_id='abc' */ select 1"
+	if packet := <-packetCh; !bytes.Contains(packet.Data, []byte(expected)) {
+		t.Errorf("Expected command '%s', got: %q", expected, packet.Data)
+	}
+
+	if pkg.Cmd != "select 1" {
+		t.Errorf("Expected package of caller to be unchanged, got '%s'", pkg.Cmd)
+	}
+}
@@ -8,29 +8,9 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
-	"crypto/rsa"
-	"crypto/sha1"
-	"crypto/x509"
-	"encoding/pem"
 	"fmt"
 )
 
-// rsaEncrypt encrypts a password with the given public key using the
-// given nonce.
-func rsaEncrypt(pemPubKey, nonce, password []byte) ([]byte, error) {
-	pubKeyBlock, rest := pem.Decode(pemPubKey)
-	if len(rest) > 0 {
-		return nil, fmt.Errorf("trailing bytes in public key: %#v", rest)
-	}
-
-	publicKey, err := x509.ParsePKCS1PublicKey(pubKeyBlock.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse PKCS#1 public key: %w", err)
-	}
-
-	return rsa.EncryptOAEP(sha1.New(), rand.Reader, publicKey, append(nonce, []byte(password)...), []byte{})
-}
-
 // generateSymmetricKey creates a cryptographically secure key to use
 // for the odceCipher.
 func generateSymmetricKey(odce odceCipher) ([]byte, error) {
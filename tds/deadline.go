@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"time"
+)
+
+// readDeadliner is implemented by connections supporting read
+// deadlines, e.g. net.Conn.
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// writeDeadliner is implemented by connections supporting write
+// deadlines, e.g. net.Conn.
+type writeDeadliner interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// deadline returns the earlier of the deadline of ctx and now plus
+// timeout. A timeout of zero or less is ignored. If neither is set the
+// zero time is returned.
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	var t time.Time
+	if timeout > 0 {
+		t = time.Now().Add(timeout)
+	}
+
+	if ctxDeadline, ok := ctx.Deadline(); ok && (t.IsZero() || ctxDeadline.Before(t)) {
+		t = ctxDeadline
+	}
+
+	return t
+}
+
+// isTimeout reports whether err was caused by an exceeded deadline.
+func isTimeout(err error) bool {
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// deadlineErr returns the error of ctx if it is done or its deadline
+// has passed. The context of an exceeded deadline may not be done yet
+// when a connection deadline derived from it triggers.
+func deadlineErr(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if ctxDeadline, ok := ctx.Deadline(); ok && !time.Now().Before(ctxDeadline) {
+		return context.DeadlineExceeded
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDeadline(t *testing.T) {
+	if d := deadline(context.Background(), 0); !d.IsZero() {
+		t.Errorf("Expected zero deadline, got %s", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ctxDeadline, _ := ctx.Deadline()
+
+	if d := deadline(ctx, time.Hour); !d.Equal(ctxDeadline) {
+		t.Errorf("Expected deadline of context %s, got %s", ctxDeadline, d)
+	}
+
+	if d := deadline(ctx, time.Millisecond); !d.Before(ctxDeadline) {
+		t.Errorf("Expected deadline of timeout before %s, got %s", ctxDeadline, d)
+	}
+}
+
+// stalledPacket returns a reader serving the header of a packet whose
+// body never arrives.
+func stalledPacket(t *testing.T) net.Conn {
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	go func() {
+		header := PacketHeader{MsgType: TDS_BUF_NORMAL, Length: PacketHeaderSize + 10}
+		header.WriteTo(server)
+	}()
+
+	return client
+}
+
+func TestPacket_ReadFrom_Timeout(t *testing.T) {
+	packet := &Packet{}
+	_, err := packet.ReadFrom(context.Background(), stalledPacket(t), 50*time.Millisecond)
+	if !isTimeout(err) {
+		t.Errorf("Expected timeout error, got: %v", err)
+	}
+}
+
+func TestPacket_ReadFrom_ContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	packet := &Packet{}
+	_, err := packet.ReadFrom(ctx, stalledPacket(t), time.Minute)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected read to abort at the deadline, took %s", elapsed)
+	}
+}
+
+func TestChannel_SendPackage_Deadline(t *testing.T) {
+	conn, server := newTestConn(t, nil)
+	defer server.Close()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	// The server never reads, so sending blocks until the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = channel.SendPackage(ctx, &LanguagePackage{Cmd: "select 1"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+	}
+
+	// Nothing was written, so the connection is still usable.
+	if err := conn.ctx.Err(); err != nil {
+		t.Errorf("Expected connection to be intact, got: %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	conn.CloseContext(ctx)
+}
+
+func TestConn_ReadFrom_ContextDeadline(t *testing.T) {
+	conn, server := newTestConn(t, nil)
+	defer server.Close()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	// The server responds with the header of a packet whose body
+	// never arrives.
+	go func() {
+		packet := &Packet{}
+		if _, err := packet.ReadFrom(context.Background(), pipeConn{server}, time.Minute); err != nil {
+			return
+		}
+
+		header := PacketHeader{MsgType: TDS_BUF_RESPONSE, Length: PacketHeaderSize + 10}
+		header.WriteTo(server)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := channel.SendPackage(ctx, &LanguagePackage{Cmd: "select 1"}); err != nil {
+		t.Fatalf("Unexpected error sending request: %v", err)
+	}
+
+	// The read timeout of the connection is far longer than the
+	// deadline of the request.
+	select {
+	case <-conn.readerDone:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected stalled read to end at the deadline of the request")
+	}
+
+	if err := conn.cause(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected connection to fail with context.DeadlineExceeded, got: %v", err)
+	}
+}
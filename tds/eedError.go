@@ -7,6 +7,8 @@ package tds
 import (
 	"errors"
 	"fmt"
+
+	dberrors "github.com/SAP/go-dblib/errors"
 )
 
 // EEDError contains the extended error data packages and the wrapped
@@ -21,8 +23,28 @@ func (err *EEDError) Add(eed *EEDPackage) {
 	err.EEDPackages = append(err.EEDPackages, eed)
 }
 
-// Is reports whether any wrapped EEDError in errs chain matches other.
+// Category returns dberrors.CategoryServer, as EEDErrors contain
+// messages sent by the server.
+func (err EEDError) Category() dberrors.Category {
+	return dberrors.CategoryServer
+}
+
+// MsgNumbers returns the numbers of the messages of EEDPackages.
+func (err EEDError) MsgNumbers() []uint32 {
+	msgNumbers := make([]uint32, 0, len(err.EEDPackages))
+	for _, eed := range err.EEDPackages {
+		msgNumbers = append(msgNumbers, eed.MsgNumber)
+	}
+	return msgNumbers
+}
+
+// Is reports whether any wrapped EEDError in errs chain matches other
+// or whether other is dberrors.CategoryServer.
 func (err EEDError) Is(other error) bool {
+	if category, ok := other.(dberrors.Category); ok {
+		return category == err.Category()
+	}
+
 	if err.WrappedError == nil {
 		return false
 	}
@@ -177,6 +177,8 @@ type FieldData interface {
 
 	Value() interface{}
 	SetValue(interface{})
+	// IsNull reports whether the server sent NULL for the field.
+	IsNull() bool
 }
 
 // Base structs and methods
@@ -342,6 +344,7 @@ type fieldDataBase struct {
 	fmt    FieldFmt
 	status DataStatus
 	value  interface{}
+	null   bool
 }
 
 func (field *fieldDataBase) setFormat(f FieldFmt) {
@@ -369,6 +372,11 @@ func (field *fieldDataBase) SetValue(value interface{}) {
 	field.value = value
 }
 
+// IsNull implements the tds.FieldData interface.
+func (field fieldDataBase) IsNull() bool {
+	return field.null
+}
+
 func (field *fieldDataBase) readFromStatus(ch BytesChannel) (int, error) {
 	if fmtStatus(field.fmt.Status())&tdsFmtColumnStatus != tdsFmtColumnStatus {
 		return 0, nil
@@ -408,6 +416,9 @@ func (field *fieldDataBase) readFrom(ch BytesChannel) (int, error) {
 			return n, fmt.Errorf("failed to read %d bytes of length: %w", field.fmt.LengthBytes(), err)
 		}
 		n += field.fmt.LengthBytes()
+
+		// Nullable data types signal NULL with a length of zero.
+		field.null = length == 0
 	}
 
 	bs, err := ch.Bytes(length)
@@ -1134,6 +1145,14 @@ func (field *fieldDataTxtPtr) ReadFrom(ch BytesChannel) (int, error) {
 	}
 	n++
 
+	// A text pointer with a length of zero signals NULL, no further
+	// data is sent.
+	field.null = txtPtrLen == 0
+	if field.null {
+		field.value = nil
+		return n, nil
+	}
+
 	field.txtPtr, err = ch.Bytes(int(txtPtrLen))
 	if err != nil {
 		return 0, ErrNotEnoughBytes
@@ -10,25 +10,67 @@ import (
 	"fmt"
 
 	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/auth"
+	"github.com/SAP/go-dblib/trace"
 )
 
 // Login uses a passed config to handle packages while logging in to the
 // server.
+//
+// If ctx has no deadline the login is limited by the login timeout of
+// the connection, see NewConn.
 func (tdsChan *Channel) Login(ctx context.Context, config *LoginConfig) error {
+	ctx, cancel := withDefaultTimeout(ctx, tdsChan.tdsConn.loginTimeout)
+	defer cancel()
+
+	ctx, span := trace.Start(ctx, trace.KindLogin, "login")
+	err := tdsChan.login(ctx, config)
+	span.End(err)
+	return err
+}
+
+func (tdsChan *Channel) login(ctx context.Context, config *LoginConfig) error {
 	if config == nil {
 		return errors.New("passed config is nil")
 	}
 
 	tdsChan.CurrentHeaderType = TDS_BUF_LOGIN
 
-	var withoutEncryption bool
 	switch config.Encrypt {
 	case TDS_MSG_SEC_ENCRYPT, TDS_MSG_SEC_ENCRYPT2, TDS_MSG_SEC_ENCRYPT3:
 		return fmt.Errorf("encryption methods below TDS_MSG_SEC_ENCRYPT4 are not supported by go-ase")
-	case TDS_MSG_SEC_ENCRYPT4:
-		withoutEncryption = false
-	default:
-		withoutEncryption = true
+	}
+
+	mech := config.Auth
+	if mech == nil {
+		var err error
+		mech, err = config.mechanism()
+		if err != nil {
+			return fmt.Errorf("error creating authentication mechanism: %w", err)
+		}
+	}
+
+	if config.FIPS {
+		if err := auth.CheckFIPS(mech); err != nil {
+			return err
+		}
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttr("username", config.DSN.Username)
+	span.SetAttr("mechanism", mech.Name())
+
+	// The mechanism determines whether the password is negotiated.
+	withoutEncryption := !mech.Negotiate()
+	if withoutEncryption {
+		config.Encrypt = 0
+	} else {
+		config.Encrypt = TDS_MSG_SEC_ENCRYPT4
+	}
+
+	initialToken, err := mech.Initial()
+	if err != nil {
+		return fmt.Errorf("error creating initial token of %s authentication: %w", mech.Name(), err)
 	}
 
 	// Add servername/password combination to remote servers
@@ -41,7 +83,7 @@ func (tdsChan *Channel) Login(ctx context.Context, config *LoginConfig) error {
 		config.RemoteServers = append([]LoginConfigRemoteServer{firstRemoteServer}, config.RemoteServers...)
 	}
 
-	pack, err := config.pack()
+	pack, err := config.pack(initialToken)
 	if err != nil {
 		return fmt.Errorf("error building login payload: %w", err)
 	}
@@ -74,6 +116,7 @@ func (tdsChan *Channel) Login(ctx context.Context, config *LoginConfig) error {
 		if loginack.Status != TDS_LOG_SUCCEED {
 			return fmt.Errorf("login failed: %s", loginack.Status)
 		}
+		tdsChan.tdsConn.setLoginAck(loginack)
 
 		pkg, err = tdsChan.NextPackage(ctx, true)
 		if err != nil {
@@ -162,6 +205,10 @@ func (tdsChan *Channel) Login(ctx context.Context, config *LoginConfig) error {
 	}
 
 	if asymmetricType != 1 {
+		if config.FIPS {
+			return fmt.Errorf("asymmetric encryption %b requested by server is not approved in FIPS mode",
+				asymmetricType)
+		}
 		return fmt.Errorf("unhandled asymmetric encryption: %b", asymmetricType)
 	}
 
@@ -190,9 +237,9 @@ func (tdsChan *Channel) Login(ctx context.Context, config *LoginConfig) error {
 			paramNonce.Value())
 	}
 
-	encryptedPass, err := rsaEncrypt(paramPubKeyData, paramNonceData, []byte(config.DSN.Password))
+	encryptedPass, err := mech.Continue(auth.EncodeChallenge(paramPubKeyData, paramNonceData))
 	if err != nil {
-		return fmt.Errorf("error encrypting password: %w", err)
+		return fmt.Errorf("error answering challenge of %s authentication: %w", mech.Name(), err)
 	}
 
 	// Prepare response
@@ -235,7 +282,7 @@ func (tdsChan *Channel) Login(ctx context.Context, config *LoginConfig) error {
 			remnameData.SetValue([]byte(remoteServer.Name))
 			params[i] = remnameData
 
-			encryptedServerPass, err := rsaEncrypt(paramPubKeyData, paramNonceData,
+			encryptedServerPass, err := config.encryptRSA(paramPubKeyData, paramNonceData,
 				[]byte(remoteServer.Password))
 			if err != nil {
 				return fmt.Errorf("error encryption remote server password: %w", err)
@@ -265,7 +312,7 @@ func (tdsChan *Channel) Login(ctx context.Context, config *LoginConfig) error {
 		return fmt.Errorf("error generating session key: %w", err)
 	}
 
-	encryptedSymKey, err := rsaEncrypt(paramPubKeyData, paramNonceData, symmetricKey)
+	encryptedSymKey, err := config.encryptRSA(paramPubKeyData, paramNonceData, symmetricKey)
 	if err != nil {
 		return fmt.Errorf("error encrypting session key: %w", err)
 	}
@@ -303,6 +350,7 @@ func (tdsChan *Channel) Login(ctx context.Context, config *LoginConfig) error {
 				return false, fmt.Errorf("expected login ack with status TDS_LOG_SUCCEED, received %s",
 					loginAck.Status)
 			}
+			tdsChan.tdsConn.setLoginAck(loginAck)
 
 			return true, nil
 		},
@@ -337,12 +385,14 @@ func (tdsChan *Channel) Login(ctx context.Context, config *LoginConfig) error {
 		}
 
 		if allZeroed {
-			return fmt.Errorf("server did not understand capability requests for %s, aborting", capType)
+			return fmt.Errorf("server did not understand capability requests for %s, aborting:\n%s",
+				capType, CapabilityTable(tdsChan.tdsConn.requestedCaps, capsResponse))
 		}
 	}
 
 	// Override requested capabilities with server response
 	tdsChan.tdsConn.Caps = capsResponse
+	tdsChan.tdsConn.grantedCaps = capsResponse
 
 	pkg, err = tdsChan.NextPackage(ctx, true)
 	if err != nil {
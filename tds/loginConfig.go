@@ -10,7 +10,9 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/SAP/go-dblib/auth"
 	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/version"
 )
 
 // LoginConfigRemoteServer contains the name and the password to the
@@ -37,6 +39,24 @@ type LoginConfig struct {
 	// Encrypt allows any TDSMsgId but only negotiation-relevant security
 	// bits such as TDS_MSG_SEC_ENCRYPT will be recognized.
 	Encrypt TDSMsgId
+
+	// Auth is the mechanism used to authenticate. If Auth is nil the
+	// mechanism is selected by the property "auth" of DSN and defaults
+	// to plaintext if Encrypt is zero and encrypted-password
+	// otherwise. Encryption methods below TDS_MSG_SEC_ENCRYPT4 are
+	// rejected.
+	//
+	// Encrypt is overwritten with the value required by the
+	// mechanism.
+	Auth auth.Mechanism
+
+	// FIPS restricts the login to FIPS-approved primitives. The
+	// mechanism must implement auth.FIPSApprover and passwords and the
+	// session key are only encrypted with public keys of at least
+	// auth.MinFIPSKeyBits bits.
+	//
+	// FIPS is set by the property "fips" of DSN.
+	FIPS bool
 }
 
 // NewLoginConfig creates a new login-configuration by using dsn
@@ -75,9 +95,36 @@ func NewLoginConfig(dsn *dsn.Info) (*LoginConfig, error) {
 
 	conf.Encrypt = TDS_MSG_SEC_ENCRYPT4
 
+	fips, err := auth.FIPSMode(dsn)
+	if err != nil {
+		return nil, err
+	}
+	conf.FIPS = fips
+
 	return conf, nil
 }
 
+// mechanism returns the authentication mechanism selected by the
+// property "auth" of the DSN.
+func (config *LoginConfig) mechanism() (auth.Mechanism, error) {
+	name := auth.EncryptedPassword
+	if config.Encrypt == 0 {
+		name = auth.Plaintext
+	}
+
+	return auth.New(config.DSN.PropDefault("auth", name), config.DSN)
+}
+
+// encryptRSA encrypts plaintext with auth.EncryptRSAFIPS in FIPS mode
+// and auth.EncryptRSA otherwise.
+func (config *LoginConfig) encryptRSA(pemPubKey, nonce, plaintext []byte) ([]byte, error) {
+	if config.FIPS {
+		return auth.EncryptRSAFIPS(pemPubKey, nonce, plaintext)
+	}
+
+	return auth.EncryptRSA(pemPubKey, nonce, plaintext)
+}
+
 // TDS default login-configuration values.
 const (
 	TDS_MAXNAME   = 30
@@ -92,7 +139,7 @@ const (
 	TDS_DUMMY     = 4
 )
 
-func (config *LoginConfig) pack() (Package, error) {
+func (config *LoginConfig) pack(initialToken []byte) (Package, error) {
 	buf := &bytes.Buffer{}
 
 	// lhostname, lhostlen
@@ -111,7 +158,7 @@ func (config *LoginConfig) pack() (Package, error) {
 	case TDS_MSG_SEC_ENCRYPT, TDS_MSG_SEC_ENCRYPT2, TDS_MSG_SEC_ENCRYPT3, TDS_MSG_SEC_ENCRYPT4:
 		err = writeString(buf, "", TDS_MAXNAME)
 	default:
-		err = writeString(buf, config.DSN.Password, TDS_MAXNAME)
+		err = writeString(buf, string(initialToken), TDS_MAXNAME)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("error writing password: %w", err)
@@ -202,7 +249,7 @@ func (config *LoginConfig) pack() (Package, error) {
 	}
 
 	// ltds
-	if _, err := buf.Write([]byte{0x5, 0x0, 0x0, 0x0}); err != nil {
+	if _, err := buf.Write(version.TDS50.Bytes()); err != nil {
 		return nil, fmt.Errorf("error writing tds version: %w", err)
 	}
 
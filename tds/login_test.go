@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/SAP/go-dblib/auth"
+	"github.com/SAP/go-dblib/dsn"
+)
+
+func TestLoginConfig_mechanism(t *testing.T) {
+	cases := map[string]struct {
+		encrypt   TDSMsgId
+		mechanism string
+	}{
+		"no encryption": {0, auth.Plaintext},
+		"encrypt4":      {TDS_MSG_SEC_ENCRYPT4, auth.EncryptedPassword},
+	}
+
+	for name, cas := range cases {
+		t.Run(name,
+			func(t *testing.T) {
+				config := &LoginConfig{DSN: dsn.NewInfo(), Encrypt: cas.encrypt}
+
+				mech, err := config.mechanism()
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				if mech.Name() != cas.mechanism {
+					t.Errorf("Expected mechanism %s, received %s", cas.mechanism, mech.Name())
+				}
+			},
+		)
+	}
+}
+
+func TestChannel_LoginRejectsWeakEncryption(t *testing.T) {
+	for _, encrypt := range []TDSMsgId{TDS_MSG_SEC_ENCRYPT, TDS_MSG_SEC_ENCRYPT2, TDS_MSG_SEC_ENCRYPT3} {
+		config := &LoginConfig{DSN: dsn.NewInfo(), Encrypt: encrypt}
+
+		err := (&Channel{}).login(context.Background(), config)
+		if err == nil || !strings.Contains(err.Error(), "below TDS_MSG_SEC_ENCRYPT4") {
+			t.Errorf("Expected %s to be rejected, received %v", encrypt, err)
+		}
+
+		if config.Encrypt != encrypt {
+			t.Errorf("Expected Encrypt to be unchanged, received %s", config.Encrypt)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// MemoryPolicy selects how a channel handles responses exceeding its
+// memory limit.
+type MemoryPolicy int
+
+// Policies for exceeded memory limits.
+const (
+	// MemoryBlock stops reading from the server until queued packages
+	// are consumed.
+	MemoryBlock MemoryPolicy = iota
+	// MemoryFail fails the request with a *MemoryLimitError and
+	// discards the remaining response.
+	MemoryFail
+)
+
+var memoryPolicyNames = map[MemoryPolicy]string{
+	MemoryBlock: "block",
+	MemoryFail:  "fail",
+}
+
+func (policy MemoryPolicy) String() string {
+	if name, ok := memoryPolicyNames[policy]; ok {
+		return name
+	}
+	return fmt.Sprintf("MemoryPolicy(%d)", int(policy))
+}
+
+// MemoryLimitError is returned when a response exceeds the memory
+// limit of a channel with policy MemoryFail.
+type MemoryLimitError struct {
+	// Limit is the memory limit of the channel in bytes.
+	Limit int64
+	// Used is the number of bytes held by queued packages.
+	Used int64
+	// Requested is the size of the package exceeding the limit.
+	Requested int64
+}
+
+func (err *MemoryLimitError) Error() string {
+	return fmt.Sprintf("memory limit of %d bytes exceeded: %d bytes queued, %d bytes requested",
+		err.Limit, err.Used, err.Requested)
+}
+
+// memoryBudget accounts the memory held by the packages of a channel
+// that have been parsed but not yet consumed.
+type memoryBudget struct {
+	limit  int64
+	policy MemoryPolicy
+
+	lock *sync.Mutex
+	used int64
+	// notify is closed and replaced whenever memory is released to
+	// wake up the waiting reader.
+	notify chan struct{}
+}
+
+// memoryBudgetFromDSN returns the memoryBudget configured by the
+// properties "memory-limit" and "memory-policy" of info or nil if no
+// limit is set.
+func memoryBudgetFromDSN(info *dsn.Info) (*memoryBudget, error) {
+	prop := info.Prop("memory-limit")
+	if prop == "" {
+		return nil, nil
+	}
+
+	limit, err := strconv.ParseInt(prop, 10, 64)
+	if err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error parsing int from memory-limit '%s': %w", prop, err)
+	}
+
+	if limit <= 0 {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "memory-limit must be positive, got %d", limit)
+	}
+
+	budget := &memoryBudget{
+		limit:  limit,
+		lock:   &sync.Mutex{},
+		notify: make(chan struct{}),
+	}
+
+	switch policy := info.PropDefault("memory-policy", MemoryBlock.String()); policy {
+	case MemoryBlock.String():
+		budget.policy = MemoryBlock
+	case MemoryFail.String():
+		budget.policy = MemoryFail
+	default:
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "unknown memory-policy '%s'", policy)
+	}
+
+	return budget, nil
+}
+
+// forChannel returns a new memoryBudget with the limit and policy of
+// budget for a channel or nil if budget is nil.
+//
+// Each channel has its own budget, so the reader of the connection
+// only waits for memory held by the channel it delivers to.
+func (budget *memoryBudget) forChannel() *memoryBudget {
+	if budget == nil {
+		return nil
+	}
+
+	return &memoryBudget{
+		limit:  budget.limit,
+		policy: budget.policy,
+		lock:   &sync.Mutex{},
+		notify: make(chan struct{}),
+	}
+}
+
+// tryAcquire takes n bytes of the budget. If the budget is exhausted
+// false and a channel closed on the next release are returned.
+//
+// A package exceeding the limit on its own is accepted if no other
+// packages are queued.
+func (budget *memoryBudget) tryAcquire(n int64) (bool, <-chan struct{}) {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	if budget.used > 0 && budget.used+n > budget.limit {
+		return false, budget.notify
+	}
+
+	budget.used += n
+	return true, nil
+}
+
+// exceeded returns the error for a package of n bytes exceeding the
+// budget.
+func (budget *memoryBudget) exceeded(n int64) error {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	return &MemoryLimitError{Limit: budget.limit, Used: budget.used, Requested: n}
+}
+
+// release returns n bytes to the budget.
+func (budget *memoryBudget) release(n int64) {
+	if n == 0 {
+		return
+	}
+
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	budget.used -= n
+	close(budget.notify)
+	budget.notify = make(chan struct{})
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// rowTokens returns the tokens of a result set with n rows.
+func rowTokens(n int) [][]byte {
+	tokens := [][]byte{encodeRowFmt2("a")}
+	for i := 0; i < n; i++ {
+		tokens = append(tokens, encodeRow(int32Ptr(int32(i))))
+	}
+	return append(tokens, encodeDone(TDS_DONE_FINAL))
+}
+
+func TestMemoryLimit_Block(t *testing.T) {
+	const rows = 1000
+
+	stream := newTestStream(t, map[string]string{"memory-limit": "64"}, rowTokens(rows)...)
+	budget := stream.channel.memory
+	ctx := context.Background()
+
+	n := 0
+	for stream.Next(ctx) {
+		budget.lock.Lock()
+		used := budget.used
+		budget.lock.Unlock()
+
+		if used > budget.limit {
+			t.Fatalf("Expected at most %d bytes to be queued, got %d", budget.limit, used)
+		}
+		n++
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if n != rows {
+		t.Errorf("Expected %d rows, got %d", rows, n)
+	}
+}
+
+func TestMemoryLimit_Fail(t *testing.T) {
+	stream := newTestStream(t, map[string]string{
+		"memory-limit":  "64",
+		"memory-policy": "fail",
+	}, rowTokens(1000)...)
+
+	// Wait for the reader to exceed the limit before consuming.
+	deadline := time.Now().Add(5 * time.Second)
+	for len(stream.channel.errCh) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Memory limit was not exceeded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for stream.Next(context.Background()) {
+	}
+
+	var limitErr *MemoryLimitError
+	if err := stream.Err(); !errors.As(err, &limitErr) {
+		t.Fatalf("Expected *MemoryLimitError, got: %v", err)
+	}
+
+	if limitErr.Limit != 64 {
+		t.Errorf("Expected limit of 64 bytes, got %d", limitErr.Limit)
+	}
+}
+
+func TestMemoryBudgetFromDSN(t *testing.T) {
+	info := dsn.NewInfo()
+
+	if budget, err := memoryBudgetFromDSN(info); err != nil || budget != nil {
+		t.Errorf("Expected no memory limit, got %v, %v", budget, err)
+	}
+
+	for props, valid := range map[[2]string]bool{
+		{"1024", ""}:      true,
+		{"1024", "fail"}:  true,
+		{"0", ""}:         false,
+		{"many", ""}:      false,
+		{"1024", "spill"}: false,
+	} {
+		info.ConnectProps.Set("memory-limit", props[0])
+		info.ConnectProps.Set("memory-policy", props[1])
+
+		_, err := memoryBudgetFromDSN(info)
+		if valid && err != nil {
+			t.Errorf("Unexpected error for %v: %v", props, err)
+		}
+
+		if !valid && !errors.Is(err, dberrors.CategoryConfig) {
+			t.Errorf("Expected config error for %v, got: %v", props, err)
+		}
+	}
+}
+
+func TestMemoryLimit_PerChannel(t *testing.T) {
+	conn, _ := newTestConn(t, map[string]string{"memory-limit": "64"})
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	other := conn.memory.forChannel()
+	if channel.memory == conn.memory || channel.memory == other {
+		t.Fatalf("Expected channels to account memory separately")
+	}
+
+	if ok, _ := channel.memory.tryAcquire(64); !ok {
+		t.Fatalf("Failed to acquire memory on channel")
+	}
+
+	if ok, _ := other.tryAcquire(64); !ok {
+		t.Errorf("Expected other channel to be unaffected by the channel's usage")
+	}
+}
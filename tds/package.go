@@ -5,14 +5,15 @@
 package tds
 
 import (
-	"errors"
 	"fmt"
 	"io"
+
+	dberrors "github.com/SAP/go-dblib/errors"
 )
 
 // ErrNotEnoughBytes is returned by packages' ReadFrom if the
 // BytesChannel does not have enough bytes to parse the package fully.
-var ErrNotEnoughBytes = errors.New("not enough bytes in channel to parse package")
+var ErrNotEnoughBytes = dberrors.New(dberrors.CategoryProtocol, "not enough bytes in channel to parse package")
 
 // Package is the interface providing the ReadFrom and WriteTo methods.
 type Package interface {
@@ -307,6 +307,45 @@ func (pkg *CapabilityPackage) HasSecurityCapability(capability SecurityCapabilit
 	return pkg.HasCapability(CapabilitySecurity, int(capability))
 }
 
+// RequestCapabilities returns the set request capabilities in
+// ascending order.
+func (pkg *CapabilityPackage) RequestCapabilities() []RequestCapability {
+	set := pkg.Capabilities[CapabilityRequest].setCapabilities()
+
+	capabilities := make([]RequestCapability, len(set))
+	for i, capability := range set {
+		capabilities[i] = RequestCapability(capability)
+	}
+
+	return capabilities
+}
+
+// ResponseCapabilities returns the set response capabilities in
+// ascending order.
+func (pkg *CapabilityPackage) ResponseCapabilities() []ResponseCapability {
+	set := pkg.Capabilities[CapabilityResponse].setCapabilities()
+
+	capabilities := make([]ResponseCapability, len(set))
+	for i, capability := range set {
+		capabilities[i] = ResponseCapability(capability)
+	}
+
+	return capabilities
+}
+
+// SecurityCapabilities returns the set security capabilities in
+// ascending order.
+func (pkg *CapabilityPackage) SecurityCapabilities() []SecurityCapability {
+	set := pkg.Capabilities[CapabilitySecurity].setCapabilities()
+
+	capabilities := make([]SecurityCapability, len(set))
+	for i, capability := range set {
+		capabilities[i] = SecurityCapability(capability)
+	}
+
+	return capabilities
+}
+
 // ReadFrom implements the tds.Package interface.
 func (pkg *CapabilityPackage) ReadFrom(ch BytesChannel) error {
 	totalLength, err := ch.Uint16()
@@ -459,7 +498,7 @@ func (vm *valueMask) isEmpty() bool {
 }
 
 func (vm *valueMask) setCapability(capability int, state bool) error {
-	if capability > len(vm.capabilities) {
+	if capability < 0 || capability >= len(vm.capabilities) {
 		return fmt.Errorf("invalid capability: %d", capability)
 	}
 
@@ -469,13 +508,32 @@ func (vm *valueMask) setCapability(capability int, state bool) error {
 }
 
 func (vm *valueMask) getCapability(capability int) bool {
-	if capability >= len(vm.capabilities) {
+	// The server may omit capability types in its response.
+	if vm == nil || capability >= len(vm.capabilities) {
 		return false
 	}
 
 	return vm.capabilities[capability]
 }
 
+// setCapabilities returns the capabilities that are set in ascending
+// order.
+func (vm *valueMask) setCapabilities() []int {
+	capabilities := []int{}
+	if vm == nil {
+		return capabilities
+	}
+
+	// The index zero is not a valid capability.
+	for capability := 1; capability < len(vm.capabilities); capability++ {
+		if vm.capabilities[capability] {
+			capabilities = append(capabilities, capability)
+		}
+	}
+
+	return capabilities
+}
+
 func parseValueMask(bs []byte) *valueMask {
 	max := len(bs) * 8
 
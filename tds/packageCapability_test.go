@@ -105,3 +105,47 @@ func TestValueMask_Bytes(t *testing.T) {
 		)
 	}
 }
+
+func TestCapabilityPackage_RequestCapabilities(t *testing.T) {
+	expected := []RequestCapability{TDS_REQ_LANG, TDS_DATA_INT8, TDS_REQ_COMMAND_ENCRYPTION}
+
+	pkg, err := NewCapabilityPackage(
+		[]RequestCapability{TDS_REQ_COMMAND_ENCRYPTION, TDS_REQ_LANG, TDS_DATA_INT8},
+		nil, nil,
+	)
+	if err != nil {
+		t.Errorf("Error creating capability package: %v", err)
+		return
+	}
+
+	recv := pkg.RequestCapabilities()
+	if !reflect.DeepEqual(recv, expected) {
+		t.Errorf("Received unexpected capabilities")
+		t.Errorf("Expected: %v", expected)
+		t.Errorf("Received: %v", recv)
+	}
+
+	if caps := pkg.ResponseCapabilities(); len(caps) != 0 {
+		t.Errorf("Expected no response capabilities, received %v", caps)
+	}
+}
+
+func TestCapabilityPackage_MissingType(t *testing.T) {
+	pkg := &CapabilityPackage{
+		Capabilities: map[CapabilityType]*valueMask{
+			CapabilityRequest: parseValueMask([]byte{0b00000010}),
+		},
+	}
+
+	if !pkg.HasRequestCapability(TDS_REQ_LANG) {
+		t.Errorf("Expected request capability %s to be set", TDS_REQ_LANG)
+	}
+
+	if pkg.HasSecurityCapability(1) {
+		t.Errorf("Expected missing security capabilities to be unset")
+	}
+
+	if caps := pkg.SecurityCapabilities(); len(caps) != 0 {
+		t.Errorf("Expected no security capabilities, received %v", caps)
+	}
+}
@@ -145,14 +145,18 @@ func (pkg EEDPackage) WriteTo(ch BytesChannel) error {
 	// 4 msgnumber
 	// 1 state
 	// 1 class
+	// 1 sqlstate length
 	// x sqlstate
 	// 1 status
 	// 2 transtate
+	// 2 msg length
 	// x msg
+	// 1 servername length
 	// x servername
+	// 1 procname length
 	// x procname
 	// 2 linenr
-	length := 11 + len(pkg.SQLState) + len(pkg.Msg) + len(pkg.ServerName) + len(pkg.ProcName)
+	length := 16 + len(pkg.SQLState) + len(pkg.Msg) + len(pkg.ServerName) + len(pkg.ProcName)
 
 	if err := ch.WriteUint16(uint16(length)); err != nil {
 		return fmt.Errorf("failed to write length: %w", err)
@@ -32,6 +32,15 @@ func NewParamsPackage(data ...FieldData) *ParamsPackage {
 	}
 }
 
+// NewRowPackage creates a row-package with field-data.
+func NewRowPackage(data ...FieldData) *RowPackage {
+	return &RowPackage{
+		ParamsPackage: ParamsPackage{
+			DataFields: data,
+		},
+	}
+}
+
 // LastPkg implements the tds.LastPkgAcceptor interface.
 func (pkg *ParamsPackage) LastPkg(other Package) error {
 	switch otherPkg := other.(type) {
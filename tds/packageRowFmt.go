@@ -23,6 +23,11 @@ type RowFmtPackage struct {
 	wide bool
 }
 
+// NewRowFmtPackage creates a new row-format-package.
+func NewRowFmtPackage(wide bool, fmts ...FieldFmt) *RowFmtPackage {
+	return &RowFmtPackage{wide: wide, Fmts: fmts}
+}
+
 // ReadFrom implements the tds.Package interface.
 func (pkg *RowFmtPackage) ReadFrom(ch BytesChannel) error {
 	totalLength, err := ch.Uint32()
@@ -190,9 +195,133 @@ func (pkg *RowFmtPackage) ReadFromField(ch BytesChannel) (FieldFmt, int, error)
 	return fieldFmt, n, nil
 }
 
-// WriteTo is not implemented.
+// WriteTo implements the tds.Package interface.
 func (pkg *RowFmtPackage) WriteTo(ch BytesChannel) error {
-	return fmt.Errorf("not implemented")
+	var err error
+	if pkg.wide {
+		err = ch.WriteByte(byte(TDS_ROWFMT2))
+	} else {
+		err = ch.WriteByte(byte(TDS_ROWFMT))
+	}
+	if err != nil {
+		return fmt.Errorf("error occurred writing TDS Token %s: %w", TDS_ROWFMT, err)
+	}
+
+	// 2 bytes column count, x bytes for columns
+	length := 2
+	for _, field := range pkg.Fmts {
+		// 1 namelength
+		// x name
+		// 4 or 1 status (wide)
+		// 4 usertype
+		// 1 token
+		// x FormatByteLength
+		// 1 locale len
+		// x locale
+		length += 1 + len(field.Name()) + 1 + 4 + 1 + field.FormatByteLength() + 1 + len(field.LocaleInfo())
+		if pkg.wide {
+			// 1 label length, x label, 1 catalogue length,
+			// x catalogue, 1 schema length, x schema, 1 table
+			// length, x table
+			length += 3 + 4 + len(field.ColumnLabel()) + len(field.Catalogue()) +
+				len(field.Schema()) + len(field.Table())
+		}
+	}
+
+	// The length is always written with four bytes, see ReadFrom.
+	if err := ch.WriteUint32(uint32(length)); err != nil {
+		return fmt.Errorf("error occurred writing package length: %w", err)
+	}
+
+	if err := ch.WriteUint16(uint16(len(pkg.Fmts))); err != nil {
+		return fmt.Errorf("error occurred writing column count: %w", err)
+	}
+	n := 2
+
+	for i, field := range pkg.Fmts {
+		writtenBytes, err := pkg.WriteToField(ch, field)
+		if err != nil {
+			return fmt.Errorf("error writing column %d: %w", i, err)
+		}
+		n += writtenBytes
+	}
+
+	if n != length {
+		return fmt.Errorf("expected to write %d bytes, wrote %d bytes instead",
+			length, n)
+	}
+
+	return nil
+}
+
+// WriteToField writes the format of a column to the passed channel and
+// returns the amount of written bytes.
+func (pkg RowFmtPackage) WriteToField(ch BytesChannel, field FieldFmt) (int, error) {
+	n := 0
+
+	if pkg.wide {
+		for _, s := range []string{field.ColumnLabel(), field.Catalogue(), field.Schema(), field.Table()} {
+			if err := ch.WriteUint8(uint8(len(s))); err != nil {
+				return n, fmt.Errorf("failed to write length: %w", err)
+			}
+			n++
+
+			if err := ch.WriteString(s); err != nil {
+				return n, fmt.Errorf("failed to write string: %w", err)
+			}
+			n += len(s)
+		}
+	}
+
+	if err := ch.WriteUint8(uint8(len(field.Name()))); err != nil {
+		return n, fmt.Errorf("failed to write Name length: %w", err)
+	}
+	n++
+
+	if err := ch.WriteString(field.Name()); err != nil {
+		return n, fmt.Errorf("failed to write name: %w", err)
+	}
+	n += len(field.Name())
+
+	if pkg.wide {
+		if err := ch.WriteUint32(uint32(field.Status())); err != nil {
+			return n, fmt.Errorf("failed to write status: %w", err)
+		}
+		n += 4
+	} else {
+		if err := ch.WriteUint8(uint8(field.Status())); err != nil {
+			return n, fmt.Errorf("failed to write status: %w", err)
+		}
+		n++
+	}
+
+	if err := ch.WriteInt32(field.UserType()); err != nil {
+		return n, fmt.Errorf("failed to write usertype: %w", err)
+	}
+	n += 4
+
+	if err := ch.WriteByte(byte(field.DataType())); err != nil {
+		return n, fmt.Errorf("failed to write token: %w", err)
+	}
+	n++
+
+	n2, err := field.WriteTo(ch)
+	if err != nil {
+		return n, fmt.Errorf("error writing column format field: %w", err)
+	}
+	n += n2
+
+	if err := ch.WriteUint8(uint8(len(field.LocaleInfo()))); err != nil {
+		return n, fmt.Errorf("failed to write locale info length: %w", err)
+	}
+	n++
+
+	if err := ch.WriteString(field.LocaleInfo()); err != nil {
+		return n, fmt.Errorf("failed to write locale info: %w", err)
+	}
+	n += len(field.LocaleInfo())
+
+	return n, nil
 }
 
 func (pkg RowFmtPackage) String() string {
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"testing"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+func TestRowFmtPackage_WriteTo(t *testing.T) {
+	for _, wide := range []bool{false, true} {
+		idFmt, err := LookupFieldFmt(asetypes.INT4)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		idFmt.SetName("id")
+
+		nameFmt, err := LookupFieldFmt(asetypes.VARCHAR)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		nameFmt.SetName("name")
+		nameFmt.SetColumnLabel("label")
+		nameFmt.SetTable("t")
+
+		queue := NewPacketQueue(func() int { return 512 })
+		if err := NewRowFmtPackage(wide, idFmt, nameFmt).WriteTo(queue); err != nil {
+			t.Fatalf("Unexpected error writing row format: %v", err)
+		}
+
+		queue.SetPosition(0, 0)
+
+		token, err := queue.Byte()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		pkg, err := LookupPackage(Token(token))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		rowFmt, ok := pkg.(*RowFmtPackage)
+		if !ok || rowFmt.wide != wide {
+			t.Fatalf("Expected row format with wide=%t, got %v", wide, pkg)
+		}
+
+		if err := rowFmt.ReadFrom(queue); err != nil {
+			t.Fatalf("Unexpected error reading row format: %v", err)
+		}
+
+		if len(rowFmt.Fmts) != 2 || rowFmt.Fmts[0].Name() != "id" || rowFmt.Fmts[1].Name() != "name" {
+			t.Fatalf("Expected columns id and name, got %v", rowFmt)
+		}
+
+		if label := rowFmt.Fmts[1].ColumnLabel(); wide && label != "label" {
+			t.Errorf("Expected column label of wide row format, got %q", label)
+		}
+	}
+}
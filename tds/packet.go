@@ -10,10 +10,13 @@ import (
 	"fmt"
 	"io"
 	"time"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/trace"
 )
 
 var (
-	ErrEOFAfterZeroRead = errors.New("received io.EOF after reading 0 bytes")
+	ErrEOFAfterZeroRead = dberrors.New(dberrors.CategoryNetwork, "received io.EOF after reading 0 bytes")
 )
 
 // Packet represents a single packet in a message.
@@ -43,19 +46,35 @@ func (packet Packet) Bytes() ([]byte, error) {
 }
 
 // ReadFrom reads the packet-data and returns the amount of read bytes.
+//
+// If reader supports read deadlines, e.g. a net.Conn, reading the body
+// fails once no data was received within timeout or the deadline of
+// ctx is exceeded, whichever is earlier.
 func (packet *Packet) ReadFrom(ctx context.Context, reader io.Reader, timeout time.Duration) (int64, error) {
-	var totalBytes int64
-
 	packet.Header = PacketHeader{}
 	n, err := packet.Header.ReadFrom(reader)
 	if err != nil {
 		return n, fmt.Errorf("failed to read header: %w", err)
 	}
 
-	totalBytes += n
+	m, err := packet.readBody(ctx, reader, timeout)
+	return n + m, err
+}
+
+// readBody reads the body of the packet described by the already read
+// header and returns the amount of read bytes.
+//
+// See ReadFrom for the handling of timeout and the deadline of ctx.
+func (packet *Packet) readBody(ctx context.Context, reader io.Reader, timeout time.Duration) (int64, error) {
+	var totalBytes int64
 
 	packet.Data = make([]byte, packet.Header.Length-PacketHeaderSize)
 
+	deadliner, hasDeadline := reader.(readDeadliner)
+	if hasDeadline {
+		defer deadliner.SetReadDeadline(time.Time{})
+	}
+
 	// The timeout will be refreshed (replaced) on every successful
 	// read. This is done so the timeout only triggers if there was
 	// actually no data read from the server to prevent failures when
@@ -65,20 +84,35 @@ func (packet *Packet) ReadFrom(ctx context.Context, reader io.Reader, timeout ti
 	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	refresh := true
 	for {
 		if err := ctx.Err(); err != nil {
 			return totalBytes, err
 		}
 
-		m, err := reader.Read(packet.Data[totalBytes-n:])
+		if hasDeadline && refresh {
+			if err := deadliner.SetReadDeadline(deadline(ctx, timeout)); err != nil {
+				return totalBytes, fmt.Errorf("error setting read deadline: %w", err)
+			}
+		}
+
+		m, err := reader.Read(packet.Data[totalBytes:])
 		totalBytes += int64(m)
 
-		if m > 0 {
+		refresh = m > 0
+		if refresh {
 			timeoutCtx, cancel = context.WithTimeout(ctx, timeout)
 			defer cancel()
 		}
 
 		if err != nil {
+			if isTimeout(err) {
+				if ctxErr := deadlineErr(ctx); ctxErr != nil {
+					return totalBytes, fmt.Errorf("error reading body: %w", ctxErr)
+				}
+				return totalBytes, fmt.Errorf("no data received within %s: %w", timeout, err)
+			}
+
 			if errors.Is(err, io.EOF) {
 				// Check if the timeout was exceeded _and_ if the last
 				// read returned 0 bytes. So the timeout may be
@@ -89,7 +123,7 @@ func (packet *Packet) ReadFrom(ctx context.Context, reader io.Reader, timeout ti
 				}
 
 				// The PDU is split over multiple responses
-				if totalBytes != int64(packet.Header.Length) {
+				if totalBytes != int64(len(packet.Data)) {
 					continue
 				}
 
@@ -101,7 +135,7 @@ func (packet *Packet) ReadFrom(ctx context.Context, reader io.Reader, timeout ti
 			return totalBytes, fmt.Errorf("error reading body: %w", err)
 		}
 
-		if totalBytes == int64(packet.Header.Length) {
+		if totalBytes == int64(len(packet.Data)) {
 			// Read the expected amount of bytes
 			break
 		}
@@ -138,3 +172,13 @@ func (packet Packet) String() string {
 		len(packet.Data),
 	)
 }
+
+// packetAttrs returns the trace attributes describing packet.
+func packetAttrs(packet *Packet) []trace.Attr {
+	return []trace.Attr{
+		{Key: "channel", Value: packet.Header.Channel},
+		{Key: "type", Value: packet.Header.MsgType},
+		{Key: "status", Value: packet.Header.Status},
+		{Key: "length", Value: packet.Header.Length},
+	}
+}
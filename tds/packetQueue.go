@@ -73,6 +73,21 @@ func (queue *PacketQueue) Position() (int, int) {
 	return queue.indexPacket, queue.indexData
 }
 
+// bytesSince returns the number of bytes between the position
+// indexPacket and indexData and the current position. Only the packets
+// between the positions are visited.
+func (queue *PacketQueue) bytesSince(indexPacket, indexData int) int64 {
+	queue.Lock()
+	defer queue.Unlock()
+
+	n := int64(queue.indexData - indexData)
+	for i := indexPacket; i < queue.indexPacket && i < len(queue.queue); i++ {
+		n += int64(len(queue.queue[i].Data))
+	}
+
+	return n
+}
+
 // SetPosition sets the two indizes used by PacketQueue.
 // See Position for more details.
 func (queue *PacketQueue) SetPosition(indexPacket, indexData int) {
@@ -256,3 +256,24 @@ func TestPacketQueue_AllPacketsConsumed(t *testing.T) {
 		})
 	}
 }
+
+func TestPacketQueue_bytesSince(t *testing.T) {
+	queue := prepQueue(0, 1,
+		&Packet{Data: []byte{0x1, 0x2}},
+		&Packet{Data: []byte{0x3}},
+		&Packet{Data: []byte{0x4, 0x5}},
+	)
+
+	if _, err := queue.Bytes(3); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	indexPacket, indexData := queue.Position()
+	if n := queue.bytesSince(0, 1); n != 3 {
+		t.Errorf("Expected 3 bytes since start, got %d", n)
+	}
+
+	if n := queue.bytesSince(indexPacket, indexData); n != 0 {
+		t.Errorf("Expected 0 bytes since current position, got %d", n)
+	}
+}
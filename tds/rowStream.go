@@ -0,0 +1,333 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// ErrNoRow is returned by RowStream.Scan if Next was not called or
+// returned false.
+var ErrNoRow = errors.New("no current row, Next must be called and return true")
+
+// RowStream is a pull-based iterator over the rows sent by the server
+// in response to a request.
+//
+// Rows are decoded from the packages received on the channel as they
+// are consumed and only the current row is retained. The number of
+// packages decoded ahead of the consumer is bounded by the connection
+// property "channel-package-queue-size". Once the queue is full the
+// connection stops reading from the server until the consumer calls
+// Next again, so result sets of any size are processed with bounded
+// memory.
+//
+// If the response contains multiple result sets their rows are
+// returned in sequence. Columns returns the columns of the result set
+// of the current row.
+//
+// A RowStream must be consumed until Next returns false or closed
+// before the channel is used for another request.
+type RowStream struct {
+	channel *Channel
+
+	rowFmt   *RowFmtPackage
+	row      *RowPackage
+	eedError *EEDError
+	err      error
+	done     bool
+}
+
+// NewRowStream returns a RowStream reading the response to the last
+// request sent on channel.
+//
+// Example:
+//
+//	if err := channel.SendPackage(ctx, &tds.LanguagePackage{Cmd: "select * from t"}); err != nil {
+//		return err
+//	}
+//
+//	stream := tds.NewRowStream(channel)
+//	defer stream.Close(ctx)
+//
+//	for stream.Next(ctx) {
+//		var id int32
+//		var name string
+//		if err := stream.Scan(&id, &name); err != nil {
+//			return err
+//		}
+//	}
+//	return stream.Err()
+func NewRowStream(channel *Channel) *RowStream {
+	return &RowStream{
+		channel:  channel,
+		eedError: &EEDError{},
+	}
+}
+
+// Columns returns the names of the columns of the current result set.
+//
+// If no row format was received yet packages are read until the row
+// format or the end of the response is received.
+func (stream *RowStream) Columns(ctx context.Context) ([]string, error) {
+	for stream.rowFmt == nil && !stream.done && stream.err == nil {
+		pkg, err := stream.nextPackage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := pkg.(*RowPackage); ok {
+			// Rows are always preceded by their format.
+			return nil, dberrors.New(dberrors.CategoryProtocol, "received TDS_ROW without preceding TDS_ROWFMT")
+		}
+	}
+
+	if stream.err != nil {
+		return nil, stream.err
+	}
+
+	if stream.rowFmt == nil {
+		return []string{}, nil
+	}
+
+	columns := make([]string, len(stream.rowFmt.Fmts))
+	for i, fieldFmt := range stream.rowFmt.Fmts {
+		columns[i] = fieldFmt.Name()
+	}
+	return columns, nil
+}
+
+// Next advances the stream to the next row and reports whether a row
+// is available.
+//
+// Next returns false at the end of the response or if an error
+// occurred, which is returned by Err.
+func (stream *RowStream) Next(ctx context.Context) bool {
+	stream.row = nil
+
+	for !stream.done {
+		pkg, err := stream.nextPackage(ctx)
+		if err != nil {
+			return false
+		}
+
+		// Rows of statements following a failed statement are
+		// discarded until the end of the response.
+		if row, ok := pkg.(*RowPackage); ok && stream.err == nil {
+			stream.row = row
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextPackage reads the next package and updates the state of the
+// stream.
+func (stream *RowStream) nextPackage(ctx context.Context) (Package, error) {
+	pkg, err := stream.channel.NextPackage(ctx, true)
+	if err != nil {
+		stream.setErr(fmt.Errorf("error reading next package: %w", err))
+		// The response cannot be consumed any further.
+		stream.done = true
+		return nil, stream.err
+	}
+
+	switch typed := pkg.(type) {
+	case *RowFmtPackage:
+		stream.rowFmt = typed
+	case *EEDPackage:
+		stream.eedError.Add(typed)
+	case *DonePackage:
+		if typed.Status&TDS_DONE_ERROR == TDS_DONE_ERROR {
+			stream.setErr(errors.New("server reported an error"))
+		}
+
+		if typed.Status&TDS_DONE_MORE != TDS_DONE_MORE {
+			stream.done = true
+		}
+	}
+
+	return pkg, nil
+}
+
+// setErr records err if no error was recorded before. Received
+// EEDPackages are attached to the recorded error.
+func (stream *RowStream) setErr(err error) {
+	if stream.err != nil {
+		return
+	}
+
+	if len(stream.eedError.EEDPackages) == 0 {
+		stream.err = err
+		return
+	}
+
+	stream.eedError.WrappedError = err
+	stream.err = stream.eedError
+}
+
+// Scan copies the values of the current row into dest, which must
+// contain one pointer per column. The values are stored with
+// ConvertAssign.
+func (stream *RowStream) Scan(dest ...interface{}) error {
+	if stream.row == nil {
+		return ErrNoRow
+	}
+
+	if len(dest) != len(stream.row.DataFields) {
+		return fmt.Errorf("expected %d destinations, got %d", len(stream.row.DataFields), len(dest))
+	}
+
+	for i, field := range stream.row.DataFields {
+		var value interface{}
+		if !field.IsNull() {
+			value = field.Value()
+		}
+
+		if err := ConvertAssign(dest[i], value); err != nil {
+			return fmt.Errorf("error scanning column %d (%s): %w", i, field.Format().Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Values returns the values of the current row. NULL is returned as
+// nil.
+func (stream *RowStream) Values() ([]interface{}, error) {
+	if stream.row == nil {
+		return nil, ErrNoRow
+	}
+
+	values := make([]interface{}, len(stream.row.DataFields))
+	for i, field := range stream.row.DataFields {
+		if !field.IsNull() {
+			values[i] = field.Value()
+		}
+	}
+
+	return values, nil
+}
+
+// Err returns the error that caused Next to return false, if any.
+func (stream *RowStream) Err() error {
+	return stream.err
+}
+
+// Close consumes the remaining packages of the response and returns
+// Err.
+func (stream *RowStream) Close(ctx context.Context) error {
+	for stream.Next(ctx) {
+	}
+
+	return stream.err
+}
+
+// ConvertAssign stores value in dest, which must be a non-nil
+// pointer. nil is treated as NULL.
+//
+// Values are stored if dest implements sql.Scanner, is an *interface{}
+// or if the value is assignable or convertible to the type dest points
+// to:
+//   - NULL is only stored in pointers, slices, maps and interfaces and
+//     sets them to nil. Other pointers are allocated for non-NULL
+//     values.
+//   - Values of pointer types such as *asetypes.Decimal are also
+//     stored in destinations of their element type.
+//   - Numeric values are converted if they fit the destination without
+//     overflow or loss of their fraction.
+//   - Text and binary values are converted between string and []byte.
+func ConvertAssign(dest, value interface{}) error {
+	switch typed := dest.(type) {
+	case sql.Scanner:
+		return typed.Scan(value)
+	case *interface{}:
+		*typed = value
+		return nil
+	}
+
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		return fmt.Errorf("destination must be a non-nil pointer, got %T", dest)
+	}
+	destValue = destValue.Elem()
+
+	srcValue := reflect.ValueOf(value)
+	if value == nil || (srcValue.Kind() == reflect.Ptr && srcValue.IsNil()) {
+		switch destValue.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			destValue.Set(reflect.Zero(destValue.Type()))
+			return nil
+		}
+		return dberrors.Errorf(dberrors.CategoryConversion, "cannot store NULL in %s", destValue.Type())
+	}
+
+	if srcValue.Type().AssignableTo(destValue.Type()) {
+		destValue.Set(srcValue)
+		return nil
+	}
+
+	if destValue.Kind() == reflect.Ptr {
+		if destValue.IsNil() {
+			destValue.Set(reflect.New(destValue.Type().Elem()))
+		}
+		return ConvertAssign(destValue.Interface(), value)
+	}
+
+	if srcValue.Kind() == reflect.Ptr {
+		return ConvertAssign(dest, srcValue.Elem().Interface())
+	}
+
+	if isNumericKind(srcValue.Kind()) && isNumericKind(destValue.Kind()) {
+		converted := srcValue.Convert(destValue.Type())
+		// Converting back must yield the original value and sign,
+		// otherwise the value overflows the destination or loses its
+		// fraction.
+		if converted.Convert(srcValue.Type()).Interface() != value || isNegative(srcValue) != isNegative(converted) {
+			return dberrors.Errorf(dberrors.CategoryConversion, "value %v overflows %s", value, destValue.Type())
+		}
+		destValue.Set(converted)
+		return nil
+	}
+
+	sameKind := srcValue.Kind() == destValue.Kind() || (isTextType(srcValue.Type()) && isTextType(destValue.Type()))
+	if sameKind && srcValue.Type().ConvertibleTo(destValue.Type()) {
+		destValue.Set(srcValue.Convert(destValue.Type()))
+		return nil
+	}
+
+	return dberrors.Errorf(dberrors.CategoryConversion, "cannot store %T in %s", value, destValue.Type())
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isNegative(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() < 0
+	}
+	return false
+}
+
+// isTextType reports whether t is a string or byte slice type.
+func isTextType(t reflect.Type) bool {
+	return t.Kind() == reflect.String || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8)
+}
@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// encodeRowFmt2 returns a TDS_ROWFMT2 token with a nullable four byte
+// integer column for each name.
+func encodeRowFmt2(names ...string) []byte {
+	body := &bytes.Buffer{}
+	binary.Write(body, endian, uint16(len(names)))
+
+	for _, name := range names {
+		// Empty label, catalogue, schema and table.
+		body.Write([]byte{0, 0, 0, 0})
+		body.WriteByte(byte(len(name)))
+		body.WriteString(name)
+		// Status and user type.
+		binary.Write(body, endian, uint32(0))
+		binary.Write(body, endian, int32(0))
+		// Data type INTN with a length of four bytes and no locale.
+		body.Write([]byte{0x26, 4, 0})
+	}
+
+	bs := []byte{byte(TDS_ROWFMT2)}
+	bs = append(bs, 0, 0, 0, 0)
+	endian.PutUint32(bs[1:], uint32(body.Len()))
+	return append(bs, body.Bytes()...)
+}
+
+// encodeRow returns a TDS_ROW token for values encoded as INTN. nil
+// values are encoded as NULL.
+func encodeRow(values ...*int32) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(byte(TDS_ROW))
+
+	for _, value := range values {
+		if value == nil {
+			buf.WriteByte(0)
+			continue
+		}
+		buf.WriteByte(4)
+		binary.Write(buf, endian, *value)
+	}
+
+	return buf.Bytes()
+}
+
+// encodeDone returns a TDS_DONE token with status.
+func encodeDone(status DoneState) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(byte(TDS_DONE))
+	binary.Write(buf, endian, uint16(status))
+	binary.Write(buf, endian, uint16(0))
+	binary.Write(buf, endian, int32(0))
+	return buf.Bytes()
+}
+
+// writeMessage writes the tokens as a single packet for channel 0 to
+// w.
+func writeMessage(w io.Writer, tokens ...[]byte) error {
+	data := bytes.Join(tokens, nil)
+
+	header := PacketHeader{
+		MsgType: TDS_BUF_RESPONSE,
+		Status:  TDS_BUFSTAT_EOM,
+		Length:  uint16(PacketHeaderSize + len(data)),
+	}
+
+	if _, err := header.WriteTo(w); err != nil {
+		return err
+	}
+
+	_, err := w.Write(data)
+	return err
+}
+
+// newTestStream returns a RowStream reading the passed tokens.
+func newTestStream(t *testing.T, props map[string]string, tokens ...[]byte) *RowStream {
+	conn, server := newTestConn(t, props)
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	go io.Copy(ioutil.Discard, server)
+	go writeMessage(server, tokens...)
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		conn.CloseContext(ctx)
+		server.Close()
+	})
+
+	return NewRowStream(channel)
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestRowStream(t *testing.T) {
+	stream := newTestStream(t, nil,
+		encodeRowFmt2("a", "b"),
+		encodeRow(int32Ptr(1), int32Ptr(2)),
+		encodeRow(int32Ptr(3), nil),
+		encodeDone(TDS_DONE_FINAL),
+	)
+	ctx := context.Background()
+
+	columns, err := stream.Columns(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error reading columns: %v", err)
+	}
+
+	if expected := []string{"a", "b"}; !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Expected columns %v, got %v", expected, columns)
+	}
+
+	type row struct {
+		a int64
+		b *int32
+	}
+
+	rows := []row{}
+	for stream.Next(ctx) {
+		r := row{}
+		if err := stream.Scan(&r.a, &r.b); err != nil {
+			t.Fatalf("Unexpected error scanning: %v", err)
+		}
+		rows = append(rows, r)
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []row{{a: 1, b: int32Ptr(2)}, {a: 3}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected rows %v, got %v", expected, rows)
+	}
+
+	if err := stream.Scan(); !errors.Is(err, ErrNoRow) {
+		t.Errorf("Expected ErrNoRow after the last row, got: %v", err)
+	}
+}
+
+func TestRowStream_Error(t *testing.T) {
+	stream := newTestStream(t, nil,
+		encodeRowFmt2("a"),
+		encodeRow(int32Ptr(1)),
+		encodeDone(TDS_DONE_ERROR|TDS_DONE_MORE),
+		encodeRowFmt2("a"),
+		encodeRow(int32Ptr(2)),
+		encodeDone(TDS_DONE_FINAL),
+	)
+	ctx := context.Background()
+
+	n := 0
+	for stream.Next(ctx) {
+		n++
+	}
+
+	if n != 1 {
+		t.Errorf("Expected rows after the error to be discarded, got %d rows", n)
+	}
+
+	if stream.Err() == nil {
+		t.Errorf("Expected error reported by the server")
+	}
+
+	if err := stream.Close(ctx); err != stream.Err() {
+		t.Errorf("Expected Close to return the error, got: %v", err)
+	}
+}
+
+func TestRowStream_BoundedQueue(t *testing.T) {
+	const rows = 1000
+
+	tokens := [][]byte{encodeRowFmt2("a")}
+	for i := 0; i < rows; i++ {
+		tokens = append(tokens, encodeRow(int32Ptr(int32(i))))
+	}
+	tokens = append(tokens, encodeDone(TDS_DONE_FINAL))
+
+	// The queue holds a single package, the reader is blocked until
+	// the stream consumed the previous row.
+	stream := newTestStream(t, map[string]string{"channel-package-queue-size": "1"}, tokens...)
+	ctx := context.Background()
+
+	n := int32(0)
+	for stream.Next(ctx) {
+		var a int32
+		if err := stream.Scan(&a); err != nil {
+			t.Fatalf("Unexpected error scanning: %v", err)
+		}
+
+		if a != n {
+			t.Fatalf("Expected row %d, got %d", n, a)
+		}
+		n++
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if n != rows {
+		t.Errorf("Expected %d rows, got %d", rows, n)
+	}
+}
+
+func TestConvertAssign(t *testing.T) {
+	var i8 int8
+	if err := ConvertAssign(&i8, int32(300)); !errors.Is(err, dberrors.CategoryConversion) {
+		t.Errorf("Expected overflow to be a conversion error, got: %v", err)
+	}
+
+	var u32 uint32
+	if err := ConvertAssign(&u32, int32(-1)); !errors.Is(err, dberrors.CategoryConversion) {
+		t.Errorf("Expected negative value in unsigned destination to be a conversion error, got: %v", err)
+	}
+
+	var f64 float64
+	if err := ConvertAssign(&f64, int32(3)); err != nil || f64 != 3 {
+		t.Errorf("Expected 3, got %v (%v)", f64, err)
+	}
+
+	var s string
+	if err := ConvertAssign(&s, nil); !errors.Is(err, dberrors.CategoryConversion) {
+		t.Errorf("Expected NULL in string to be a conversion error, got: %v", err)
+	}
+
+	var iface interface{} = 1
+	if err := ConvertAssign(&iface, nil); err != nil || iface != nil {
+		t.Errorf("Expected nil, got %v (%v)", iface, err)
+	}
+
+	if err := ConvertAssign(&s, []byte("text")); err != nil || s != "text" {
+		t.Errorf("Expected text, got %q (%v)", s, err)
+	}
+
+	// Pointer values are stored in destinations of their element
+	// type.
+	var i32 int32
+	if err := ConvertAssign(&i32, int32Ptr(5)); err != nil || i32 != 5 {
+		t.Errorf("Expected 5, got %d (%v)", i32, err)
+	}
+
+	if err := ConvertAssign(s, "value"); err == nil {
+		t.Errorf("Expected error for non-pointer destination")
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// ErrShutdown is returned for requests on a connection that is shutting
+// down.
+var ErrShutdown = dberrors.New(dberrors.CategoryNetwork, "connection is shutting down")
+
+// AbortedRequest is a request whose response had not been received
+// completely when the connection was shut down.
+type AbortedRequest struct {
+	Channel int
+	// Package is the first package of the request.
+	Package Package
+}
+
+func (req AbortedRequest) String() string {
+	return fmt.Sprintf("channel %d: %T", req.Channel, req.Package)
+}
+
+// ShutdownReport describes the outcome of Shutdown.
+type ShutdownReport struct {
+	// Aborted are the requests still in flight when the context
+	// passed to Shutdown was done, ordered by channel.
+	Aborted []AbortedRequest
+}
+
+// Shutdown closes the connection gracefully.
+//
+// New requests are rejected with ErrShutdown. Shutdown waits until the
+// responses to all requests in flight have been received or ctx is
+// done, logs out and closes the connection, see Close. The requests
+// whose responses were not received are reported as aborted.
+//
+// Logging out is not bound by ctx, which is usually done when requests
+// were aborted, but by DefaultCloseTimeout.
+func (tds *Conn) Shutdown(ctx context.Context) (ShutdownReport, error) {
+	tds.requestsLock.Lock()
+	tds.shutdown = true
+	tds.requestsLock.Unlock()
+
+	tds.logger.Debug("shutting down connection")
+
+	report := ShutdownReport{}
+
+wait:
+	for {
+		tds.requestsLock.Lock()
+		if len(tds.inFlight) == 0 {
+			tds.requestsLock.Unlock()
+			break
+		}
+		done := tds.requestsDone
+		tds.requestsLock.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			break wait
+		case <-tds.ctx.Done():
+			break wait
+		}
+	}
+
+	tds.requestsLock.Lock()
+	for channelId, pkg := range tds.inFlight {
+		report.Aborted = append(report.Aborted, AbortedRequest{Channel: channelId, Package: pkg})
+	}
+	tds.requestsLock.Unlock()
+
+	sort.Slice(report.Aborted, func(i, j int) bool {
+		return report.Aborted[i].Channel < report.Aborted[j].Channel
+	})
+
+	return report, tds.Close()
+}
+
+// beginRequest records the request of channelId starting with pkg as
+// in flight. ErrShutdown is returned if the connection is shutting
+// down.
+//
+// If ctx has no deadline the statement timeout of the connection
+// applies to the request, see requestDeadline.
+func (tds *Conn) beginRequest(ctx context.Context, channelId int, pkg Package) error {
+	tds.requestsLock.Lock()
+	defer tds.requestsLock.Unlock()
+
+	if tds.shutdown {
+		return ErrShutdown
+	}
+
+	tds.inFlight[channelId] = pkg
+
+	delete(tds.deadlines, channelId)
+	delete(tds.ctxDeadlines, channelId)
+	if ctxDeadline, ok := ctx.Deadline(); ok {
+		tds.ctxDeadlines[channelId] = ctxDeadline
+	} else if tds.statementTimeout > 0 && isRequest(pkg) {
+		tds.deadlines[channelId] = time.Now().Add(tds.statementTimeout)
+	}
+
+	return nil
+}
+
+// endRequest records that the request of channelId is no longer in
+// flight, either because the response was received or because it could
+// not be sent.
+func (tds *Conn) endRequest(channelId int) {
+	tds.requestsLock.Lock()
+	defer tds.requestsLock.Unlock()
+
+	delete(tds.deadlines, channelId)
+	delete(tds.ctxDeadlines, channelId)
+
+	if _, ok := tds.inFlight[channelId]; !ok {
+		return
+	}
+
+	delete(tds.inFlight, channelId)
+	close(tds.requestsDone)
+	tds.requestsDone = make(chan struct{})
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestConn_Shutdown(t *testing.T) {
+	conn, server := newTestConn(t, nil)
+	defer server.Close()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	go io.Copy(ioutil.Discard, server)
+
+	ctx := context.Background()
+	if err := channel.SendPackage(ctx, &LanguagePackage{Cmd: "select 1"}); err != nil {
+		t.Fatalf("Unexpected error sending request: %v", err)
+	}
+
+	type result struct {
+		report ShutdownReport
+		err    error
+	}
+	resultCh := make(chan result, 1)
+
+	go func() {
+		shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		report, err := conn.Shutdown(shutdownCtx)
+		resultCh <- result{report, err}
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn.requestsLock.Lock()
+		shutdown := conn.shutdown
+		conn.requestsLock.Unlock()
+
+		if shutdown {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("Connection did not start shutting down")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := channel.SendPackage(ctx, &LanguagePackage{Cmd: "select 2"}); !errors.Is(err, ErrShutdown) {
+		t.Errorf("Expected ErrShutdown for new request, got: %v", err)
+	}
+
+	// The response completes the request in flight, the logout
+	// consumes its final done.
+	if err := writeMessage(server, encodeDone(TDS_DONE_FINAL)); err != nil {
+		t.Fatalf("Error writing response: %v", err)
+	}
+
+	res := <-resultCh
+	if res.err != nil {
+		t.Errorf("Unexpected error: %v", res.err)
+	}
+
+	if len(res.report.Aborted) != 0 {
+		t.Errorf("Expected no aborted requests, got %v", res.report.Aborted)
+	}
+}
+
+func TestConn_Shutdown_Aborted(t *testing.T) {
+	conn, server := newTestConn(t, nil)
+	defer server.Close()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	go io.Copy(ioutil.Discard, server)
+
+	if err := channel.SendPackage(context.Background(), &LanguagePackage{Cmd: "select 1"}); err != nil {
+		t.Fatalf("Unexpected error sending request: %v", err)
+	}
+
+	// The server never responds, so logging out fails as well.
+	defer func(timeout time.Duration) { closeTimeout = timeout }(closeTimeout)
+	closeTimeout = 50 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	report, _ := conn.Shutdown(ctx)
+	if len(report.Aborted) != 1 {
+		t.Fatalf("Expected one aborted request, got %v", report.Aborted)
+	}
+
+	aborted := report.Aborted[0]
+	if pkg, ok := aborted.Package.(*LanguagePackage); aborted.Channel != 0 || !ok || pkg.Cmd != "select 1" {
+		t.Errorf("Unexpected aborted request: %v", aborted)
+	}
+
+	assertReaderStopped(t, conn)
+}
+
+func TestConn_Shutdown_LogoutAfterDeadline(t *testing.T) {
+	conn, server := newTestConn(t, nil)
+	defer server.Close()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	// The server never responds to the request but acknowledges the
+	// logout.
+	logoutCh := make(chan error, 1)
+	go func() {
+		reader := pipeConn{server}
+
+		for {
+			packet := &Packet{}
+			if _, err := packet.ReadFrom(context.Background(), reader, 5*time.Second); err != nil {
+				logoutCh <- err
+				return
+			}
+
+			if len(packet.Data) > 0 && packet.Data[0] == byte(TDS_LOGOUT) {
+				logoutCh <- writeMessage(server, encodeDone(TDS_DONE_FINAL))
+				io.Copy(ioutil.Discard, server)
+				return
+			}
+		}
+	}()
+
+	if err := channel.SendPackage(context.Background(), &LanguagePackage{Cmd: "select 1"}); err != nil {
+		t.Fatalf("Unexpected error sending request: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	report, _ := conn.Shutdown(ctx)
+	if len(report.Aborted) != 1 {
+		t.Errorf("Expected one aborted request, got %v", report.Aborted)
+	}
+
+	select {
+	case err := <-logoutCh:
+		if err != nil {
+			t.Errorf("Error waiting for logout: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected logout to be sent after the deadline of the shutdown")
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/trace"
+)
+
+// AttentionTimeout is the duration the server has to acknowledge an
+// attention before the connection is failed.
+const AttentionTimeout = 10 * time.Second
+
+// ErrStatementTimeout is returned when the response to a request was
+// not received within the statement timeout of the connection. The
+// request was cancelled with an attention.
+var ErrStatementTimeout = errors.New("statement timeout exceeded")
+
+// withDefaultTimeout returns a context with timeout if ctx has no
+// deadline and timeout is positive.
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// requestDeadline returns the deadline of the request in flight on
+// channelId derived from the statement timeout. The returned boolean
+// is false if the request has no such deadline.
+func (tds *Conn) requestDeadline(channelId int) (time.Time, bool) {
+	tds.requestsLock.Lock()
+	defer tds.requestsLock.Unlock()
+
+	deadline, ok := tds.deadlines[channelId]
+	return deadline, ok
+}
+
+// readContext returns the context for reading the body of a packet for
+// channelId. It is bound by the context deadline of the request in
+// flight on the channel, so a stalled read does not outlive the
+// request. Deadlines that have already passed are ignored, as the
+// caller of the request has already returned.
+func (tds *Conn) readContext(channelId int) (context.Context, context.CancelFunc) {
+	tds.requestsLock.Lock()
+	ctxDeadline, ok := tds.ctxDeadlines[channelId]
+	tds.requestsLock.Unlock()
+
+	if !ok || !time.Now().Before(ctxDeadline) {
+		return tds.ctx, func() {}
+	}
+
+	return context.WithDeadline(tds.ctx, ctxDeadline)
+}
+
+// sendAttention sends an attention to the server, which cancels the
+// request in flight on the channel.
+func (tdsChan *Channel) sendAttention(ctx context.Context) error {
+	packet := &Packet{
+		Header: PacketHeader{
+			MsgType: TDS_BUF_ATTN,
+			Status:  TDS_BUFSTAT_EOM,
+			Length:  PacketHeaderSize,
+			Channel: uint16(tdsChan.channelId),
+		},
+	}
+
+	if ctx := tdsChan.tdsConn.ctx; trace.Enabled(ctx) {
+		trace.Emit(ctx, trace.KindPacket, "send", packetAttrs(packet)...)
+	}
+
+	if _, err := tdsChan.tdsConn.writePacket(ctx, packet); err != nil {
+		return fmt.Errorf("error writing attention to server: %w", err)
+	}
+
+	return nil
+}
+
+// abortRequest cancels the request in flight after its statement
+// timeout was exceeded. The response is discarded until the server
+// acknowledges the attention and ErrStatementTimeout is returned.
+//
+// If the attention cannot be sent or is not acknowledged within
+// AttentionTimeout the connection is failed, as the state of the
+// channel is unknown.
+func (tdsChan *Channel) abortRequest() error {
+	tds := tdsChan.tdsConn
+	tds.logger.Debug("statement timeout exceeded, sending attention", "channel", tdsChan.channelId)
+
+	ctx, cancel := context.WithTimeout(tds.ctx, AttentionTimeout)
+	defer cancel()
+
+	if err := tdsChan.sendAttention(ctx); err != nil {
+		tds.fail(dberrors.Wrap(dberrors.CategoryNetwork, err))
+		return err
+	}
+
+	for {
+		pkg, err := tdsChan.nextPackage(ctx, true)
+		if err != nil {
+			err = fmt.Errorf("error waiting for acknowledgement of attention: %w", err)
+			tds.fail(dberrors.Wrap(dberrors.CategoryNetwork, err))
+			return err
+		}
+
+		if done, ok := pkg.(*DonePackage); ok && done.Status&TDS_DONE_ATTN == TDS_DONE_ATTN {
+			return ErrStatementTimeout
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tds
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestWithDefaultTimeout(t *testing.T) {
+	ctx, cancel := withDefaultTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); !ok {
+		t.Errorf("Expected default timeout to be applied")
+	}
+
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Hour)
+	defer parentCancel()
+	parentDeadline, _ := parent.Deadline()
+
+	ctx, cancel = withDefaultTimeout(parent, time.Second)
+	defer cancel()
+
+	if d, _ := ctx.Deadline(); !d.Equal(parentDeadline) {
+		t.Errorf("Expected deadline of context to take precedence, got %s", d)
+	}
+}
+
+func TestChannel_StatementTimeout(t *testing.T) {
+	conn, server := newTestConn(t, map[string]string{"statement-timeout": "50ms"})
+	defer server.Close()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		conn.CloseContext(ctx)
+	}()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	attnCh := make(chan error, 1)
+	go func() {
+		// The attention is a packet without body.
+		reader := pipeConn{server}
+
+		for {
+			packet := &Packet{}
+			if _, err := packet.ReadFrom(context.Background(), reader, 5*time.Second); err != nil {
+				attnCh <- err
+				return
+			}
+
+			if packet.Header.MsgType == TDS_BUF_ATTN {
+				attnCh <- writeMessage(server, encodeDone(TDS_DONE_ATTN))
+				io.Copy(ioutil.Discard, server)
+				return
+			}
+		}
+	}()
+
+	ctx := context.Background()
+	if err := channel.SendPackage(ctx, &LanguagePackage{Cmd: "waitfor delay '00:01:00'"}); err != nil {
+		t.Fatalf("Unexpected error sending request: %v", err)
+	}
+
+	if _, err := channel.NextPackage(ctx, true); !errors.Is(err, ErrStatementTimeout) {
+		t.Errorf("Expected ErrStatementTimeout, got: %v", err)
+	}
+
+	if err := <-attnCh; err != nil {
+		t.Errorf("Error waiting for attention: %v", err)
+	}
+
+	// The acknowledged attention leaves the connection intact.
+	if err := conn.ctx.Err(); err != nil {
+		t.Errorf("Expected connection to be intact, got: %v", err)
+	}
+}
+
+func TestChannel_StatementTimeout_ContextDeadline(t *testing.T) {
+	conn, server := newTestConn(t, map[string]string{"statement-timeout": "1ms"})
+	defer server.Close()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		conn.CloseContext(ctx)
+	}()
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	go io.Copy(ioutil.Discard, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := channel.SendPackage(ctx, &LanguagePackage{Cmd: "select 1"}); err != nil {
+		t.Fatalf("Unexpected error sending request: %v", err)
+	}
+
+	if _, ok := conn.requestDeadline(channel.channelId); ok {
+		t.Errorf("Expected deadline of context to take precedence over statement timeout")
+	}
+}
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package tdstest provides a scriptable TDS server, so code using the
+package tds can be unit-tested without an ASE instance.
+
+The Server accepts logins and answers language commands with the
+packages registered for them:
+
+	server := tdstest.NewServer()
+
+	resultSet, err := tdstest.ResultSet(
+		[]tdstest.Column{{Name: "id", DataType: asetypes.INT4}},
+		[]interface{}{int32(1)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Respond("select id from t", resultSet...)
+	server.Respond("drop table t", tdstest.Error(3701, 11, "Cannot drop the table 't'")...)
+
+Clients connect either over an in-memory connection:
+
+	conn, err := tds.NewConnFrom(ctx, info, server.Pipe(ctx))
+
+or over a listener on the loopback interface:
+
+	info, err := server.Listen(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info.Username = "user"
+	info.Password = "pass"
+	conn, err := tds.NewConn(ctx, info)
+
+Passwords are accepted in plain text and encrypted, the credentials can
+be checked with .Authenticate. The received requests are recorded and
+returned by .Requests.
+*/
+package tdstest
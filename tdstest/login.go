@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tdstest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// Offsets in the login record, see tds.LoginConfig.
+const (
+	usernameOffset = tds.TDS_MAXNAME + 1
+	passwordOffset = 2 * (tds.TDS_MAXNAME + 1)
+	// secLoginOffset is the offset of the flags requesting the
+	// encryption of the password.
+	secLoginOffset = 514
+	// loginRecordLength is the length of the login record, which is
+	// followed by the capabilities of the client.
+	loginRecordLength = 568
+)
+
+// secLoginEncrypt is set in the flags at secLoginOffset if the client
+// requests the encryption of the password.
+const secLoginEncrypt = 0x1
+
+// loginFailedMsgNumber is the message number of the error sent when
+// the authentication fails.
+const loginFailedMsgNumber = 4002
+
+// readName returns the name of up to tds.TDS_MAXNAME bytes stored at
+// offset in record.
+func readName(record []byte, offset int) string {
+	length := int(record[offset+tds.TDS_MAXNAME])
+	if length > tds.TDS_MAXNAME {
+		length = tds.TDS_MAXNAME
+	}
+
+	return string(record[offset : offset+length])
+}
+
+// login handles the login sequence on conn. The returned boolean is
+// false if the authentication failed.
+func (server *Server) login(conn io.ReadWriter) (bool, error) {
+	msg, err := readMessage(conn)
+	if err != nil {
+		return false, fmt.Errorf("error reading login record: %w", err)
+	}
+
+	if msg.header.MsgType != tds.TDS_BUF_LOGIN {
+		return false, fmt.Errorf("expected login record, received message of type %s", msg.header.MsgType)
+	}
+
+	if len(msg.data) < loginRecordLength {
+		return false, fmt.Errorf("login record of %d bytes is too short", len(msg.data))
+	}
+
+	record := msg.data[:loginRecordLength]
+	pkgs, err := parsePackages(msg.data[loginRecordLength:])
+	if err != nil {
+		return false, fmt.Errorf("error parsing capabilities: %w", err)
+	}
+
+	var caps *tds.CapabilityPackage
+	for _, pkg := range pkgs {
+		if typed, ok := pkg.(*tds.CapabilityPackage); ok {
+			caps = typed
+		}
+	}
+
+	username := readName(record, usernameOffset)
+	password := readName(record, passwordOffset)
+
+	encrypted := record[secLoginOffset]&secLoginEncrypt == secLoginEncrypt
+	if encrypted {
+		password, err = server.negotiate(conn)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := server.authenticate(username, password); err != nil {
+		response := append([]tds.Package{server.loginAck(tds.TDS_LOG_FAIL)},
+			Error(loginFailedMsgNumber, 14, fmt.Sprintf("Login failed: %v", err))...)
+		return false, server.respond(conn, 0, response)
+	}
+
+	response := []tds.Package{server.loginAck(tds.TDS_LOG_SUCCEED)}
+	// The capabilities are only acknowledged after a negotiation, all
+	// requested capabilities are granted.
+	if encrypted && caps != nil {
+		response = append(response, caps)
+	}
+	response = append(response, &tds.DonePackage{})
+
+	return true, server.respond(conn, 0, response)
+}
+
+// authenticate checks the credentials with .Authenticate.
+func (server *Server) authenticate(username, password string) error {
+	if server.Authenticate == nil {
+		return nil
+	}
+
+	return server.Authenticate(username, password)
+}
+
+// loginAck returns the login acknowledgement with status.
+func (server *Server) loginAck(status tds.LoginAckStatus) *tds.LoginAckPackage {
+	tdsVersion, _ := tds.NewVersion([]byte{5, 0, 0, 0})
+	serverVersion, _ := tds.NewVersion([]byte{16, 0, 0, 0})
+
+	return &tds.LoginAckPackage{
+		Length:         uint16(10 + len(server.Name)),
+		Status:         status,
+		Version:        tdsVersion,
+		NameLength:     uint8(len(server.Name)),
+		ProgramName:    server.Name,
+		ProgramVersion: serverVersion,
+	}
+}
+
+// negotiate sends the challenge to encrypt the password with
+// TDS_MSG_SEC_ENCRYPT4 and returns the decrypted password.
+func (server *Server) negotiate(conn io.ReadWriter) (string, error) {
+	key, err := server.privateKey()
+	if err != nil {
+		return "", err
+	}
+
+	pemPubKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PUBLIC KEY",
+		Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey),
+	})
+
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	typeFmt, typeData, err := tds.LookupFieldFmtData(asetypes.INT4)
+	if err != nil {
+		return "", err
+	}
+	typeData.SetValue(int32(1))
+
+	keyFmt, keyData, err := tds.LookupFieldFmtData(asetypes.LONGBINARY)
+	if err != nil {
+		return "", err
+	}
+	keyData.SetValue(pemPubKey)
+
+	nonceFmt, nonceData, err := tds.LookupFieldFmtData(asetypes.LONGBINARY)
+	if err != nil {
+		return "", err
+	}
+	nonceData.SetValue(nonce)
+
+	challenge := []tds.Package{
+		server.loginAck(tds.TDS_LOG_NEGOTIATE),
+		tds.NewMsgPackage(tds.TDS_MSG_HASARGS, tds.TDS_MSG_SEC_ENCRYPT4),
+		tds.NewParamFmtPackage(false, typeFmt, keyFmt, nonceFmt),
+		tds.NewParamsPackage(typeData, keyData, nonceData),
+		&tds.DonePackage{},
+	}
+	if err := server.respond(conn, 0, challenge); err != nil {
+		return "", err
+	}
+
+	msg, err := readMessage(conn)
+	if err != nil {
+		return "", fmt.Errorf("error reading encrypted password: %w", err)
+	}
+
+	pkgs, err := parsePackages(msg.data)
+	if err != nil {
+		return "", fmt.Errorf("error parsing encrypted password: %w", err)
+	}
+
+	// The password is the parameter following TDS_MSG_SEC_LOGPWD3.
+	var msgId tds.TDSMsgId
+	for _, pkg := range pkgs {
+		switch typed := pkg.(type) {
+		case *tds.MsgPackage:
+			msgId = typed.MsgId
+		case *tds.ParamsPackage:
+			if msgId != tds.TDS_MSG_SEC_LOGPWD3 || len(typed.DataFields) != 1 {
+				continue
+			}
+
+			encrypted, ok := typed.DataFields[0].Value().([]byte)
+			if !ok {
+				return "", fmt.Errorf("encrypted password is of type %T instead of []byte",
+					typed.DataFields[0].Value())
+			}
+
+			return decrypt(key, nonce, encrypted)
+		}
+	}
+
+	return "", errors.New("no encrypted password received")
+}
+
+// privateKey returns the key the password is encrypted with. The key is
+// generated once per Server.
+func (server *Server) privateKey() (*rsa.PrivateKey, error) {
+	server.keyOnce.Do(func() {
+		server.key, server.keyErr = rsa.GenerateKey(rand.Reader, 2048)
+	})
+
+	if server.keyErr != nil {
+		return nil, fmt.Errorf("error generating key: %w", server.keyErr)
+	}
+
+	return server.key, nil
+}
+
+// decrypt returns the password encrypted with auth.EncryptRSA.
+func decrypt(key *rsa.PrivateKey, nonce, encrypted []byte) (string, error) {
+	plaintext, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, key, encrypted, []byte{})
+	if err != nil {
+		return "", fmt.Errorf("error decrypting password: %w", err)
+	}
+
+	if !bytes.HasPrefix(plaintext, nonce) {
+		return "", errors.New("encrypted password is not prefixed with the nonce")
+	}
+
+	return string(plaintext[len(nonce):]), nil
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tdstest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// packetSize is the size of the packets sent by the server. It matches
+// the packet size of clients before the size is negotiated.
+const packetSize = 512
+
+// message is a message received from the client.
+type message struct {
+	// header is the header of the first packet of the message.
+	header tds.PacketHeader
+	data   []byte
+}
+
+// readMessage reads the packets of a message from r until a packet
+// with TDS_BUFSTAT_EOM is read.
+//
+// The packets are read with io.ReadFull, so header-only packets do not
+// block on connections from net.Pipe.
+func readMessage(r io.Reader) (*message, error) {
+	msg := &message{}
+
+	for first := true; ; first = false {
+		bs := make([]byte, tds.PacketHeaderSize)
+		if _, err := io.ReadFull(r, bs); err != nil {
+			if !first && errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		header := tds.PacketHeader{}
+		if _, err := header.Write(bs); err != nil {
+			return nil, fmt.Errorf("error parsing packet header: %w", err)
+		}
+
+		if header.Length < tds.PacketHeaderSize {
+			return nil, fmt.Errorf("invalid packet length %d", header.Length)
+		}
+
+		if first {
+			msg.header = header
+		}
+
+		body := make([]byte, int(header.Length)-tds.PacketHeaderSize)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return nil, fmt.Errorf("error reading packet body: %w", err)
+		}
+		msg.data = append(msg.data, body...)
+
+		if header.Status&tds.TDS_BUFSTAT_EOM == tds.TDS_BUFSTAT_EOM {
+			return msg, nil
+		}
+	}
+}
+
+// parsePackages parses the packages in data.
+func parsePackages(data []byte) ([]tds.Package, error) {
+	queue := tds.NewPacketQueue(func() int { return packetSize })
+	queue.AddPacket(&tds.Packet{
+		Header: tds.PacketHeader{Status: tds.TDS_BUFSTAT_EOM},
+		Data:   data,
+	})
+
+	pkgs := []tds.Package{}
+	var last tds.Package
+	for !queue.AllPacketsConsumed() {
+		token, err := queue.Byte()
+		if err != nil {
+			return nil, fmt.Errorf("error reading token: %w", err)
+		}
+
+		pkg, err := tds.LookupPackage(tds.Token(token))
+		if err != nil {
+			return nil, err
+		}
+
+		// Tokenless packages consume the remaining data.
+		if tokenless, ok := pkg.(*tds.TokenlessPackage); ok {
+			tokenless.Data.WriteByte(token)
+		}
+
+		if acceptor, ok := pkg.(tds.LastPkgAcceptor); ok {
+			if err := acceptor.LastPkg(last); err != nil {
+				return nil, fmt.Errorf("error in LastPkg of %s: %w", tds.Token(token), err)
+			}
+		}
+
+		if err := pkg.ReadFrom(queue); err != nil {
+			return nil, fmt.Errorf("error parsing package %s: %w", tds.Token(token), err)
+		}
+
+		pkgs = append(pkgs, pkg)
+		last = pkg
+	}
+
+	return pkgs, nil
+}
+
+// encodePackages returns the encoded packages.
+func encodePackages(pkgs []tds.Package) ([]byte, error) {
+	queue := tds.NewPacketQueue(func() int { return packetSize })
+
+	var last tds.Package
+	for _, pkg := range pkgs {
+		if acceptor, ok := pkg.(tds.LastPkgAcceptor); ok {
+			if err := acceptor.LastPkg(last); err != nil {
+				return nil, fmt.Errorf("error in LastPkg of %s: %w", pkg, err)
+			}
+		}
+
+		if err := pkg.WriteTo(queue); err != nil {
+			return nil, fmt.Errorf("error encoding package %s: %w", pkg, err)
+		}
+		last = pkg
+	}
+
+	indexPacket, indexData := queue.Position()
+	length := indexPacket*(packetSize-tds.PacketHeaderSize) + indexData
+
+	queue.SetPosition(0, 0)
+	return queue.Bytes(length)
+}
+
+// writeMessage writes data as a response message on channel to w.
+func writeMessage(w io.Writer, channel uint16, data []byte) error {
+	for {
+		n := len(data)
+		if n > packetSize-tds.PacketHeaderSize {
+			n = packetSize - tds.PacketHeaderSize
+		}
+
+		packet := tds.Packet{
+			Header: tds.PacketHeader{
+				MsgType: tds.TDS_BUF_RESPONSE,
+				Length:  uint16(tds.PacketHeaderSize + n),
+				Channel: channel,
+			},
+			Data: data[:n],
+		}
+		data = data[n:]
+
+		if len(data) == 0 {
+			packet.Header.Status = tds.TDS_BUFSTAT_EOM
+		}
+
+		if _, err := packet.WriteTo(w); err != nil {
+			return fmt.Errorf("error writing packet: %w", err)
+		}
+
+		if len(data) == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tdstest
+
+import (
+	"fmt"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// Column describes a column of a result set.
+type Column struct {
+	Name     string
+	DataType asetypes.DataType
+}
+
+// ResultSet returns the packages of a result set with columns and rows
+// followed by a done package with the number of rows.
+//
+// The values of the rows must be of the Go type the data type of their
+// column is parsed into, e.g. int32 for asetypes.INT4 or string for
+// asetypes.VARCHAR. NULL values are not supported.
+func ResultSet(columns []Column, rows ...[]interface{}) ([]tds.Package, error) {
+	fmts := make([]tds.FieldFmt, len(columns))
+	for i, column := range columns {
+		fieldFmt, err := tds.LookupFieldFmt(column.DataType)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up format of column %s: %w", column.Name, err)
+		}
+		fieldFmt.SetName(column.Name)
+		fmts[i] = fieldFmt
+	}
+
+	pkgs := []tds.Package{tds.NewRowFmtPackage(true, fmts...)}
+
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("row %d has %d values, expected %d", i, len(row), len(columns))
+		}
+
+		data := make([]tds.FieldData, len(row))
+		for j, value := range row {
+			fieldData, err := tds.LookupFieldData(fmts[j])
+			if err != nil {
+				return nil, fmt.Errorf("error looking up data of column %s: %w", columns[j].Name, err)
+			}
+			fieldData.SetValue(value)
+			data[j] = fieldData
+		}
+
+		pkgs = append(pkgs, tds.NewRowPackage(data...))
+	}
+
+	return append(pkgs, &tds.DonePackage{Status: tds.TDS_DONE_COUNT, Count: int32(len(rows))}), nil
+}
+
+// Error returns the packages of an error with msgNumber, severity and
+// msg followed by a done package signaling the error.
+func Error(msgNumber uint32, severity uint8, msg string) []tds.Package {
+	return []tds.Package{
+		&tds.EEDPackage{
+			MsgNumber: msgNumber,
+			Class:     severity,
+			Msg:       msg,
+		},
+		&tds.DonePackage{Status: tds.TDS_DONE_ERROR},
+	}
+}
+
+// Done returns a done package for a command affecting count rows.
+func Done(count int) *tds.DonePackage {
+	return &tds.DonePackage{Status: tds.TDS_DONE_COUNT, Count: int32(count)}
+}
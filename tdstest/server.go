@@ -0,0 +1,311 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tdstest
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/logging"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// DefaultName is the name the Server reports in the login
+// acknowledgement.
+const DefaultName = "tdstest"
+
+// Request is a request received by the Server.
+type Request struct {
+	// Type is the type of the message, e.g. tds.TDS_BUF_NORMAL.
+	Type     tds.PacketHeaderType
+	Packages []tds.Package
+}
+
+// Command returns the command of the language package of the request
+// or an empty string if the request contains no language package.
+func (req *Request) Command() string {
+	for _, pkg := range req.Packages {
+		if lang, ok := pkg.(*tds.LanguagePackage); ok {
+			return lang.Cmd
+		}
+	}
+
+	return ""
+}
+
+// Handler returns the response to a request. A done package is
+// appended to the response if it does not end with one.
+type Handler func(req *Request) []tds.Package
+
+// Server is a scriptable TDS server for unit tests.
+//
+// A Server accepts logins with plaintext and encrypted passwords and
+// answers requests with the responses of its handlers. Requests are
+// answered in the order they are received, logical channels are not
+// supported.
+type Server struct {
+	// Name is reported in the login acknowledgement.
+	Name string
+	// Authenticate checks the credentials of logins. All logins are
+	// accepted if it is nil.
+	Authenticate func(username, password string) error
+
+	keyOnce sync.Once
+	key     *rsa.PrivateKey
+	keyErr  error
+
+	lock     *sync.Mutex
+	handlers map[string]Handler
+	fallback Handler
+	requests []*Request
+}
+
+// NewServer returns a Server without handlers. Requests without a
+// handler are answered with an error.
+func NewServer() *Server {
+	return &Server{
+		Name:     DefaultName,
+		lock:     &sync.Mutex{},
+		handlers: map[string]Handler{},
+	}
+}
+
+// Handle registers handler for language commands equal to cmd.
+func (server *Server) Handle(cmd string, handler Handler) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	server.handlers[cmd] = handler
+}
+
+// Respond registers response for language commands equal to cmd.
+func (server *Server) Respond(cmd string, response ...tds.Package) {
+	server.Handle(cmd, func(*Request) []tds.Package {
+		return response
+	})
+}
+
+// HandleDefault registers handler for requests without a handler for
+// their command, e.g. dynamic SQL.
+func (server *Server) HandleDefault(handler Handler) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	server.fallback = handler
+}
+
+// Requests returns the requests received by the server.
+func (server *Server) Requests() []*Request {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	requests := make([]*Request, len(server.requests))
+	copy(requests, server.requests)
+	return requests
+}
+
+// handle records req and returns the response of its handler.
+func (server *Server) handle(req *Request) []tds.Package {
+	server.lock.Lock()
+	server.requests = append(server.requests, req)
+	handler, ok := server.handlers[req.Command()]
+	if !ok {
+		handler = server.fallback
+	}
+	server.lock.Unlock()
+
+	if handler == nil {
+		return Error(0, 16, fmt.Sprintf("tdstest: no handler for request %q", req.Command()))
+	}
+
+	response := handler(req)
+	if len(response) == 0 || !tds.IsDone(response[len(response)-1]) {
+		response = append(response, &tds.DonePackage{})
+	}
+
+	return response
+}
+
+// respond writes response on channel to w.
+func (server *Server) respond(w io.Writer, channel uint16, response []tds.Package) error {
+	data, err := server.encode(response)
+	if err != nil {
+		// The client is sent an error instead of waiting for a
+		// response that is never sent.
+		logging.Default().Warn("error encoding response", "error", err)
+		data, err = server.encode(Error(0, 16, fmt.Sprintf("tdstest: error encoding response: %v", err)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeMessage(w, channel, data)
+}
+
+// encode encodes pkgs while holding the lock of the server, as the
+// packages of registered responses are shared between connections and
+// encoding sets the formats of rows.
+func (server *Server) encode(pkgs []tds.Package) ([]byte, error) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	return encodePackages(pkgs)
+}
+
+// ServeConn handles the login and the requests of a client on conn
+// until the client logs out, ctx is done or an error occurred. conn is
+// closed when ServeConn returns.
+func (server *Server) ServeConn(ctx context.Context, conn net.Conn) error {
+	defer conn.Close()
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			// Abort blocked reads and writes.
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	err := server.serve(conn)
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+func (server *Server) serve(conn net.Conn) error {
+	if ok, err := server.login(conn); !ok || err != nil {
+		return err
+	}
+
+	for {
+		msg, err := readMessage(conn)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("error reading request: %w", err)
+		}
+
+		switch msg.header.MsgType {
+		case tds.TDS_BUF_ATTN:
+			// The request was answered already, acknowledge the
+			// attention.
+			if err := server.respond(conn, msg.header.Channel,
+				[]tds.Package{&tds.DonePackage{Status: tds.TDS_DONE_ATTN}}); err != nil {
+				return err
+			}
+			continue
+		case tds.TDS_BUF_CLOSE:
+			continue
+		}
+
+		pkgs, err := parsePackages(msg.data)
+		if err != nil {
+			return fmt.Errorf("error parsing request: %w", err)
+		}
+
+		if len(pkgs) > 0 {
+			if _, ok := pkgs[0].(*tds.LogoutPackage); ok {
+				return server.respond(conn, msg.header.Channel, []tds.Package{&tds.DonePackage{}})
+			}
+		}
+
+		response := server.handle(&Request{Type: msg.header.MsgType, Packages: pkgs})
+		if err := server.respond(conn, msg.header.Channel, response); err != nil {
+			return err
+		}
+	}
+}
+
+// Serve serves the connections accepted on l until ctx is done. l is
+// closed when Serve returns.
+func (server *Server) Serve(ctx context.Context, l net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error accepting connection: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.serveLogged(ctx, conn)
+		}()
+	}
+}
+
+// serveLogged serves conn and logs the returned error.
+func (server *Server) serveLogged(ctx context.Context, conn net.Conn) {
+	if err := server.ServeConn(ctx, conn); err != nil && ctx.Err() == nil {
+		logging.Default().Warn("error serving connection", "error", err)
+	}
+}
+
+// Listen serves the connections accepted on a new listener on the
+// loopback interface until ctx is done. The returned dsn.Info has the
+// host and port of the listener set.
+func (server *Server) Listen(ctx context.Context) (*dsn.Info, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("error listening: %w", err)
+	}
+
+	info := dsn.NewInfo()
+	info.Host, info.Port, err = net.SplitHostPort(l.Addr().String())
+	if err != nil {
+		l.Close()
+		return nil, fmt.Errorf("error parsing address of listener: %w", err)
+	}
+
+	go server.Serve(ctx, l)
+
+	return info, nil
+}
+
+// Pipe returns the client side of an in-memory connection served by
+// the server until ctx is done or the connection is closed, e.g. to be
+// passed to tds.NewConnFrom.
+func (server *Server) Pipe(ctx context.Context) net.Conn {
+	client, conn := net.Pipe()
+	go server.serveLogged(ctx, conn)
+	return pipeConn{client}
+}
+
+// pipeConn wraps a net.Conn from net.Pipe. Reads into empty buffers
+// return immediately as with network connections instead of blocking
+// until the other side writes.
+type pipeConn struct {
+	net.Conn
+}
+
+func (c pipeConn) Read(bs []byte) (int, error) {
+	if len(bs) == 0 {
+		return 0, nil
+	}
+	return c.Conn.Read(bs)
+}
@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tdstest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/dsn"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// newInfo returns the dsn.Info with the credentials of the tests.
+func newInfo(props map[string]string) *dsn.Info {
+	info := dsn.NewInfo()
+	info.Host = "localhost"
+	info.Username = "user"
+	info.Password = "secret"
+	for key, value := range props {
+		info.ConnectProps.Set(key, value)
+	}
+	return info
+}
+
+// login logs in on a new channel of conn.
+func login(t *testing.T, ctx context.Context, conn *tds.Conn, info *dsn.Info) (*tds.Channel, error) {
+	t.Cleanup(func() { conn.Close() })
+
+	channel, err := conn.NewChannel()
+	if err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+
+	config, err := tds.NewLoginConfig(info)
+	if err != nil {
+		t.Fatalf("Failed to create login config: %v", err)
+	}
+	config.AppName = "tdstest"
+
+	return channel, channel.Login(ctx, config)
+}
+
+// connect returns a connection and its channel logged in to server
+// over a pipe.
+func connect(t *testing.T, ctx context.Context, server *Server, props map[string]string) (*tds.Conn, *tds.Channel, error) {
+	info := newInfo(props)
+
+	conn, err := tds.NewConnFrom(ctx, info, server.Pipe(ctx))
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+
+	channel, err := login(t, ctx, conn, info)
+	return conn, channel, err
+}
+
+// send sends a language command on channel and returns the packages
+// of the response up to the final done package.
+func send(t *testing.T, ctx context.Context, channel *tds.Channel, cmd string) []tds.Package {
+	if err := channel.SendPackage(ctx, &tds.LanguagePackage{Cmd: cmd}); err != nil {
+		t.Fatalf("Error sending command: %v", err)
+	}
+
+	pkgs := []tds.Package{}
+	for {
+		pkg, err := channel.NextPackage(ctx, true)
+		if err != nil {
+			t.Fatalf("Error reading response: %v", err)
+		}
+		pkgs = append(pkgs, pkg)
+
+		if done, ok := pkg.(*tds.DonePackage); ok && done.Status == tds.TDS_DONE_FINAL {
+			return pkgs
+		}
+	}
+}
+
+func TestServer_Login(t *testing.T) {
+	cases := map[string]map[string]string{
+		"encrypted": nil,
+		"plaintext": {"auth": "plaintext"},
+	}
+
+	for name, props := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			server := NewServer()
+
+			var username, password string
+			server.Authenticate = func(user, pass string) error {
+				username, password = user, pass
+				return nil
+			}
+
+			conn, _, err := connect(t, ctx, server, props)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if username != "user" || password != "secret" {
+				t.Errorf("Expected credentials user/secret, got %s/%s", username, password)
+			}
+
+			if name := conn.ServerName(); name != DefaultName {
+				t.Errorf("Expected server name %s, got %s", DefaultName, name)
+			}
+		})
+	}
+}
+
+func TestServer_LoginFailed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := NewServer()
+	server.Authenticate = func(username, password string) error {
+		return errors.New("invalid password")
+	}
+
+	if _, _, err := connect(t, ctx, server, nil); err == nil {
+		t.Errorf("Expected login to fail")
+	}
+}
+
+func TestServer_Respond(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := NewServer()
+
+	resultSet, err := ResultSet(
+		[]Column{{Name: "id", DataType: asetypes.INT4}, {Name: "name", DataType: asetypes.VARCHAR}},
+		[]interface{}{int32(1), "one"},
+		[]interface{}{int32(2), "two"},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server.Respond("select id, name from t", resultSet...)
+
+	_, channel, err := connect(t, ctx, server, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	pkgs := send(t, ctx, channel, "select id, name from t")
+	if len(pkgs) != 5 {
+		t.Fatalf("Expected row format, two rows and two dones, got %v", pkgs)
+	}
+
+	rowFmt, ok := pkgs[0].(*tds.RowFmtPackage)
+	if !ok {
+		t.Fatalf("Expected row format, got %v", pkgs[0])
+	}
+
+	if name := rowFmt.Fmts[1].Name(); name != "name" {
+		t.Errorf("Expected column name, got %s", name)
+	}
+
+	values := [][]interface{}{}
+	for _, pkg := range pkgs[1:3] {
+		row, ok := pkg.(*tds.RowPackage)
+		if !ok {
+			t.Fatalf("Expected row, got %v", pkg)
+		}
+
+		rowValues := []interface{}{}
+		for _, field := range row.DataFields {
+			rowValues = append(rowValues, field.Value())
+		}
+		values = append(values, rowValues)
+	}
+
+	expected := [][]interface{}{{int32(1), "one"}, {int32(2), "two"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected rows %v, got %v", expected, values)
+	}
+
+	if done, ok := pkgs[3].(*tds.DonePackage); !ok || done.Count != 2 {
+		t.Errorf("Expected done with count 2, got %v", pkgs[3])
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 || requests[0].Command() != "select id, name from t" {
+		t.Errorf("Expected the command to be recorded, got %v", requests)
+	}
+}
+
+func TestServer_Error(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := NewServer()
+	server.Respond("drop table t", Error(3701, 11, "Cannot drop the table 't'")...)
+
+	_, channel, err := connect(t, ctx, server, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	pkgs := send(t, ctx, channel, "drop table t")
+	if len(pkgs) != 3 {
+		t.Fatalf("Expected error and two dones, got %v", pkgs)
+	}
+
+	if eed, ok := pkgs[0].(*tds.EEDPackage); !ok || eed.MsgNumber != 3701 {
+		t.Errorf("Expected error 3701, got %v", pkgs[0])
+	}
+
+	if done, ok := pkgs[1].(*tds.DonePackage); !ok || done.Status&tds.TDS_DONE_ERROR != tds.TDS_DONE_ERROR {
+		t.Errorf("Expected done with error, got %v", pkgs[1])
+	}
+
+	// Commands without a handler are answered with an error.
+	pkgs = send(t, ctx, channel, "select 1")
+	if _, ok := pkgs[0].(*tds.EEDPackage); !ok {
+		t.Errorf("Expected error for command without handler, got %v", pkgs)
+	}
+}
+
+func TestServer_Listen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := NewServer()
+	server.HandleDefault(func(req *Request) []tds.Package {
+		return []tds.Package{Done(1)}
+	})
+
+	info, err := server.Listen(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	info.Username = "user"
+	info.Password = "pass"
+
+	conn, err := tds.NewConn(ctx, info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	channel, err := login(t, ctx, conn, info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	pkgs := send(t, ctx, channel, "update t set x = 1")
+	if done, ok := pkgs[0].(*tds.DonePackage); !ok || done.Count != 1 {
+		t.Errorf("Expected done with count 1, got %v", pkgs)
+	}
+}
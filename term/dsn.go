@@ -7,73 +7,97 @@ package term
 import (
 	"flag"
 	"fmt"
-	"strings"
 
+	"github.com/SAP/go-dblib/config"
 	"github.com/SAP/go-dblib/dsn"
 	"github.com/SAP/go-dblib/flagslice"
 )
 
 var (
-	fHost         = flag.String("H", "", "database hostname")
-	fPort         = flag.String("P", "", "database sql port")
-	fUser         = flag.String("u", "", "database user name")
-	fPass         = flag.String("p", "", "database user password")
-	fUserstorekey = flag.String("k", "", "userstorekey")
-	fDatabase     = flag.String("D", "", "database")
-
-	fOpts = &flagslice.FlagStringSlice{}
+	fConfigFile = flag.String("c", "", "Read connection settings from file")
+
+	// dsnArgs records the dsn values passed as flags in command-line
+	// order, so the last flag setting a value wins.
+	dsnArgs []dsnArg
 )
 
-func init() {
-	flag.Var(fOpts, "o", "Connection properties")
-	flag.Parse()
+// dsnArg is a dsn value passed as flag.
+type dsnArg struct {
+	flag, key, value string
 }
 
-// Dsn sets dsn information from environment variables or flags
-// into a dsn.Info-struct.
-func Dsn() (*dsn.Info, error) {
-	dsn, err := dsn.NewInfoFromEnv("")
-	if err != nil {
-		return nil, fmt.Errorf("error reading DSN info from env: %w", err)
-	}
+// dsnFlag implements the flag.Value interface for flags setting the
+// dsn value key.
+type dsnFlag struct {
+	name, key string
+}
 
-	if *fHost != "" {
-		dsn.Host = *fHost
-	}
+func (f dsnFlag) String() string {
+	return ""
+}
 
-	if *fPort != "" {
-		dsn.Port = *fPort
-	}
+func (f dsnFlag) Set(value string) error {
+	dsnArgs = append(dsnArgs, dsnArg{flag: f.name, key: f.key, value: value})
+	return nil
+}
 
-	if *fUser != "" {
-		dsn.Username = *fUser
-	}
+// propsFlag implements the flag.Value interface for the flag setting
+// dsn values in the form key=value.
+type propsFlag struct {
+	name  string
+	props flagslice.FlagMap
+}
 
-	if *fPass != "" {
-		dsn.Password = *fPass
-	}
+func (f *propsFlag) String() string {
+	return f.props.String()
+}
 
-	if *fUserstorekey != "" {
-		dsn.Userstorekey = *fUserstorekey
+func (f *propsFlag) Set(value string) error {
+	if err := f.props.Set(value); err != nil {
+		return err
 	}
 
-	if *fDatabase != "" {
-		dsn.Database = *fDatabase
-	}
+	entries := f.props.Entries()
+	entry := entries[len(entries)-1]
+	dsnArgs = append(dsnArgs, dsnArg{flag: f.name, key: entry.Key, value: entry.Value})
+	return nil
+}
 
-	for _, fOpt := range fOpts.Slice() {
-		split := strings.SplitN(fOpt, "=", 2)
-		opt := split[0]
-		value := ""
-		if len(split) > 1 {
-			value = split[1]
-		}
+func init() {
+	flag.Var(dsnFlag{name: "H", key: "host"}, "H", "database hostname")
+	flag.Var(dsnFlag{name: "P", key: "port"}, "P", "database sql port")
+	flag.Var(dsnFlag{name: "u", key: "username"}, "u", "database user name")
+	flag.Var(dsnFlag{name: "p", key: "password"}, "p", "database user password")
+	flag.Var(dsnFlag{name: "k", key: "userstorekey"}, "k", "userstorekey")
+	flag.Var(dsnFlag{name: "D", key: "database"}, "D", "database")
+	flag.Var(&propsFlag{name: "o"}, "o", "Connection properties")
+	flag.Parse()
+}
+
+// Dsn sets dsn information from a configuration file, environment
+// variables or flags into a dsn.Info-struct.
+//
+// Flags take precedence over environment variables, which take
+// precedence over the configuration file. If a value is set by
+// multiple flags the last flag on the command line wins.
+func Dsn() (*dsn.Info, error) {
+	resolver := config.NewResolver()
 
-		if err := dsn.SetField(opt, value); err != nil {
-			return nil, fmt.Errorf("term: error setting field %s with value '%v': %w",
-				opt, value, err)
+	if *fConfigFile != "" {
+		if err := resolver.AddFile(*fConfigFile); err != nil {
+			return nil, fmt.Errorf("term: error reading config file: %w", err)
 		}
 	}
 
-	return dsn, nil
+	resolver.AddEnv("")
+	for _, arg := range dsnArgs {
+		resolver.AddFlag(arg.flag, arg.key, arg.value)
+	}
+
+	info, _, err := resolver.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("term: error resolving DSN: %w", err)
+	}
+
+	return info, nil
 }
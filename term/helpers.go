@@ -17,6 +17,8 @@ import (
 	"strconv"
 
 	"github.com/SAP/go-dblib/asetypes"
+	dberrors "github.com/SAP/go-dblib/errors"
+	"github.com/SAP/go-dblib/trace"
 )
 
 var (
@@ -31,24 +33,30 @@ type GenericExecer interface {
 }
 
 func process(db *sql.DB, query string) error {
-	conn, err := db.Conn(context.Background())
+	ctx, span := trace.Start(context.Background(), trace.KindQuery, "query",
+		trace.Attr{Key: "query", Value: query})
+
+	conn, err := db.Conn(ctx)
 	if err != nil {
+		span.End(err)
 		return fmt.Errorf("error getting sql.Conn: %w", err)
 	}
 	defer conn.Close()
 
-	return conn.Raw(func(driverConn interface{}) error {
-		return rawProcess(driverConn, query)
+	err = conn.Raw(func(driverConn interface{}) error {
+		return rawProcess(ctx, driverConn, query)
 	})
+	span.End(err)
+	return err
 }
 
-func rawProcess(driverConn interface{}, query string) error {
+func rawProcess(ctx context.Context, driverConn interface{}, query string) error {
 	execer, ok := driverConn.(GenericExecer)
 	if !ok {
-		return fmt.Errorf("invalid driver, must support GenericExecer")
+		return dberrors.New(dberrors.CategoryConfig, "invalid driver, must support GenericExecer")
 	}
 
-	rows, result, err := execer.GenericExec(context.Background(), query, nil)
+	rows, result, err := execer.GenericExec(ctx, query, nil)
 	if err != nil {
 		return fmt.Errorf("GenericExec failed: %w", err)
 	}
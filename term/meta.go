@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package term
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+// CapabilityTabler is the interface providing the CapabilityTable
+// method.
+type CapabilityTabler interface {
+	// CapabilityTable returns a human-readable table of the requested
+	// and granted capabilities of the connection.
+	CapabilityTable() string
+}
+
+// metaCommands maps the names of meta commands to their handlers.
+var metaCommands = map[string]func(db *sql.DB) error{
+	`\caps`: printCapabilities,
+}
+
+// isMetaCommand returns whether line is a meta command.
+func isMetaCommand(line string) bool {
+	return strings.HasPrefix(line, `\`)
+}
+
+// processMetaCommand executes the meta command line.
+func processMetaCommand(db *sql.DB, line string) error {
+	name := strings.TrimSuffix(strings.TrimSpace(line), ";")
+
+	fn, ok := metaCommands[name]
+	if !ok {
+		return fmt.Errorf("term: unknown meta command '%s'", name)
+	}
+
+	return fn(db)
+}
+
+func printCapabilities(db *sql.DB) error {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return fmt.Errorf("error getting sql.Conn: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		tabler, ok := driverConn.(CapabilityTabler)
+		if !ok {
+			return dberrors.New(dberrors.CategoryConfig, "term: invalid driver, must support CapabilityTabler")
+		}
+
+		fmt.Print(tabler.CapabilityTable())
+		return nil
+	})
+}
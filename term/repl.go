@@ -5,13 +5,15 @@
 package term
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"strings"
 
+	"github.com/SAP/go-dblib/logging"
+	"github.com/SAP/go-dblib/trace"
 	"github.com/chzyer/readline"
 )
 
@@ -67,6 +69,19 @@ func Repl(db *sql.DB) error {
 		}
 
 		line = strings.TrimSpace(line)
+
+		// Meta commands are executed immediately.
+		if len(cmds) == 0 && isMetaCommand(line) {
+			if err := traceCommand(line, func() error { return processMetaCommand(db, line) }); err != nil {
+				logging.Default().Error("meta command failed", "command", line, "error", err)
+			}
+
+			if exitAfterExecution {
+				return nil
+			}
+			continue
+		}
+
 		if line != "" {
 			cmds = append(cmds, line)
 		}
@@ -82,13 +97,22 @@ func Repl(db *sql.DB) error {
 		line = strings.Join(cmds, " ")
 		cmds = []string{}
 
-		err = ParseAndExecQueries(db, line)
+		err = traceCommand(line, func() error { return ParseAndExecQueries(db, line) })
 		if exitAfterExecution {
 			return err
 		}
 
 		if err != nil {
-			log.Println(err)
+			logging.Default().Error("command failed", "error", err)
 		}
 	}
 }
+
+// traceCommand calls fn in a span for the REPL command line.
+func traceCommand(line string, fn func() error) error {
+	_, span := trace.Start(context.Background(), trace.KindCommand, "command",
+		trace.Attr{Key: "line", Value: line})
+	err := fn()
+	span.End(err)
+	return err
+}
@@ -8,13 +8,20 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"log"
+	"os"
 	"strings"
 
 	"database/sql"
+
+	"github.com/SAP/go-dblib/logging"
+	"github.com/SAP/go-dblib/trace"
 )
 
 var (
 	fInputFile = flag.String("f", "", "Read SQL commands from file")
+	fTrace     = flag.Bool("trace", false, "Log connections, logins, queries and commands to stderr")
+	fDebug     = flag.Bool("debug", false, "Log debug messages")
 )
 
 // Entrypoint controls the execution of the program by starting the
@@ -22,6 +29,14 @@ var (
 func Entrypoint(db *sql.DB) error {
 	flag.Parse()
 
+	if *fDebug {
+		logging.SetDefault(logging.NewStdLogger(nil, logging.LevelDebug))
+	}
+
+	if *fTrace {
+		trace.SetTracer(&trace.LogTracer{Logger: log.New(os.Stderr, "trace: ", log.LstdFlags|log.Lmicroseconds)})
+	}
+
 	if len(flag.Args()) == 0 && *fInputFile == "" {
 		return Repl(db)
 	}
@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package throttle limits the rate of requests sent to a server, so
+misbehaving application code cannot overload a shared server.
+
+A Limiter is a token bucket refilled with Config.Rate tokens per
+second and holding up to Config.Burst tokens. Each request takes a
+token and waits until one is available. Requests that would wait longer
+than Config.QueueTimeout fail immediately with ErrThrottled.
+
+Connections of the tds package consult the Limiter configured by the
+properties "rate-limit", "rate-burst" and "rate-queue-timeout" of the
+dsn before sending language and dynamic requests:
+
+	info.ConnectProps.Set("rate-limit", "100")
+	info.ConnectProps.Set("rate-queue-timeout", "5s")
+
+A Limiter shared by all connections of a pool limits the rate of the
+pool:
+
+	limiter, err := throttle.New(throttle.Config{Rate: 100, Burst: 10})
+	if err != nil {
+		return err
+	}
+
+	p, err := pool.New(ctx, pool.Config{DSN: info, Limiter: limiter})
+*/
+package throttle
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package throttle
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+func init() {
+	dsn.RegisterProps("rate-limit", "rate-burst", "rate-queue-timeout")
+}
+
+// ErrThrottled is returned by Limiter.Wait if a request would have to
+// wait longer than the queue timeout.
+var ErrThrottled = errors.New("request throttled: queue timeout exceeded")
+
+// Config configures a Limiter.
+type Config struct {
+	// Rate is the number of requests per second.
+	Rate float64
+	// Burst is the number of requests that may be sent at once.
+	// Defaults to 1.
+	Burst int
+	// QueueTimeout is the maximum duration a request waits to be sent.
+	// Zero waits until the context of the request is done.
+	QueueTimeout time.Duration
+}
+
+// Limiter limits the rate of requests with a token bucket.
+//
+// A Limiter is safe for concurrent use and may be shared by
+// connections to limit the rate of all their requests.
+type Limiter struct {
+	config Config
+	now    func() time.Time
+
+	lock *sync.Mutex
+	// tokens is the number of available tokens at last. The number is
+	// negative if requests are waiting for tokens.
+	tokens float64
+	last   time.Time
+}
+
+// New returns a Limiter with a full bucket.
+func New(config Config) (*Limiter, error) {
+	if config.Rate <= 0 || math.IsInf(config.Rate, 0) || math.IsNaN(config.Rate) {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "rate must be a positive number, got %v", config.Rate)
+	}
+
+	if config.Burst < 0 {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "burst must not be negative, got %d", config.Burst)
+	}
+
+	if config.Burst == 0 {
+		config.Burst = 1
+	}
+
+	return &Limiter{
+		config: config,
+		now:    time.Now,
+		lock:   &sync.Mutex{},
+		tokens: float64(config.Burst),
+	}, nil
+}
+
+// FromDSN returns the Limiter configured by the properties of info or
+// nil if the property "rate-limit" is not set.
+//
+// The properties are:
+//   - rate-limit: requests per second, see Config.Rate
+//   - rate-burst: see Config.Burst
+//   - rate-queue-timeout: duration, see Config.QueueTimeout
+func FromDSN(info *dsn.Info) (*Limiter, error) {
+	prop := info.Prop("rate-limit")
+	if prop == "" {
+		return nil, nil
+	}
+
+	config := Config{}
+
+	rate, err := strconv.ParseFloat(prop, 64)
+	if err != nil {
+		return nil, dberrors.Errorf(dberrors.CategoryConfig, "error parsing float from rate-limit '%s': %w", prop, err)
+	}
+	config.Rate = rate
+
+	if config.Burst, err = info.PropInt("rate-burst", 0); err != nil {
+		return nil, err
+	}
+
+	if config.QueueTimeout, err = info.PropDuration("rate-queue-timeout", 0); err != nil {
+		return nil, err
+	}
+
+	return New(config)
+}
+
+// Wait blocks until a request may be sent.
+//
+// ErrThrottled is returned without waiting if the request would have
+// to wait longer than the queue timeout. If ctx is done before the
+// request may be sent the error of ctx is returned.
+func (limiter *Limiter) Wait(ctx context.Context) error {
+	wait, err := limiter.reserve()
+	if err != nil {
+		return err
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		limiter.cancel()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token and returns the duration until it is
+// available.
+func (limiter *Limiter) reserve() (time.Duration, error) {
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+
+	limiter.advanceLocked()
+
+	wait := time.Duration(0)
+	if limiter.tokens < 1 {
+		wait = time.Duration((1 - limiter.tokens) / limiter.config.Rate * float64(time.Second))
+	}
+
+	if limiter.config.QueueTimeout > 0 && wait > limiter.config.QueueTimeout {
+		return 0, ErrThrottled
+	}
+
+	limiter.tokens--
+	return wait, nil
+}
+
+// cancel returns the token of an aborted reservation.
+func (limiter *Limiter) cancel() {
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+
+	limiter.advanceLocked()
+	limiter.tokens = math.Min(limiter.tokens+1, float64(limiter.config.Burst))
+}
+
+// advanceLocked adds the tokens accumulated since the last call.
+//
+// The caller must hold limiter.lock.
+func (limiter *Limiter) advanceLocked() {
+	now := limiter.now()
+	if !limiter.last.IsZero() {
+		elapsed := now.Sub(limiter.last).Seconds()
+		limiter.tokens = math.Min(limiter.tokens+elapsed*limiter.config.Rate, float64(limiter.config.Burst))
+	}
+	limiter.last = now
+}
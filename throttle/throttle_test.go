@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package throttle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+	dberrors "github.com/SAP/go-dblib/errors"
+)
+
+func newTestLimiter(t *testing.T, config Config) (*Limiter, *time.Time) {
+	limiter, err := New(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	return limiter, &now
+}
+
+func TestLimiter_Burst(t *testing.T) {
+	limiter, now := newTestLimiter(t, Config{Rate: 1, Burst: 3, QueueTimeout: time.Millisecond})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Unexpected error for request %d: %v", i, err)
+		}
+	}
+
+	if err := limiter.Wait(ctx); !errors.Is(err, ErrThrottled) {
+		t.Errorf("Expected ErrThrottled, received: %v", err)
+	}
+
+	// The bucket is refilled at the rate.
+	*now = now.Add(time.Second)
+	if err := limiter.Wait(ctx); err != nil {
+		t.Errorf("Unexpected error after refill: %v", err)
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	limiter, err := New(Config{Rate: 100})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("Expected requests to be delayed, took %s", elapsed)
+	}
+}
+
+func TestLimiter_WaitCancelled(t *testing.T) {
+	limiter, _ := newTestLimiter(t, Config{Rate: 0.001})
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, received: %v", err)
+	}
+
+	// The token of the cancelled request is returned.
+	if limiter.tokens != 0 {
+		t.Errorf("Expected no tokens to be reserved, got %f", limiter.tokens)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	for _, config := range []Config{{}, {Rate: -1}, {Rate: 1, Burst: -1}} {
+		if _, err := New(config); !errors.Is(err, dberrors.CategoryConfig) {
+			t.Errorf("Expected config error for %+v, received: %v", config, err)
+		}
+	}
+}
+
+func TestFromDSN(t *testing.T) {
+	info := dsn.NewInfo()
+
+	limiter, err := FromDSN(info)
+	if err != nil || limiter != nil {
+		t.Errorf("Expected no limiter, received %v, %v", limiter, err)
+	}
+
+	info.ConnectProps.Set("rate-limit", "50")
+	info.ConnectProps.Set("rate-burst", "5")
+	info.ConnectProps.Set("rate-queue-timeout", "2s")
+
+	limiter, err = FromDSN(info)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if expected := (Config{Rate: 50, Burst: 5, QueueTimeout: 2 * time.Second}); limiter.config != expected {
+		t.Errorf("Expected config %+v, received %+v", expected, limiter.config)
+	}
+
+	info.ConnectProps.Set("rate-burst", "many")
+	if _, err := FromDSN(info); !errors.Is(err, dberrors.CategoryConfig) {
+		t.Errorf("Expected config error, received: %v", err)
+	}
+}
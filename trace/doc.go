@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package trace provides the tracing facade used by all packages of
+go-dblib.
+
+The packages report spans for establishing connections, logging in,
+executing queries and REPL commands, as well as events for each packet
+sent and received. Diagnostics are enabled in one place by setting
+a Tracer:
+
+	trace.SetTracer(&trace.LogTracer{Logger: log.New(os.Stderr, "", log.LstdFlags)})
+
+A Tracer can also be set for a single context with WithTracer, which
+takes precedence over the global Tracer.
+
+Hooks adapts functions to the Tracer interface, e.g. to forward spans
+to a tracing system:
+
+	trace.SetTracer(trace.Hooks{
+		OnSpanEnd: func(ctx context.Context, span *trace.Span) {
+			...
+		},
+	})
+
+If no Tracer is set tracing is disabled and Start returns a nil
+*Span, whose methods are no-ops.
+*/
+package trace
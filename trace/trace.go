@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/SAP/go-dblib/dsn"
+)
+
+// Kind is the kind of operation a span or event describes.
+type Kind int
+
+// Kinds of spans and events.
+const (
+	KindConnect Kind = iota
+	KindLogin
+	KindQuery
+	KindPacket
+	KindCommand
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case KindConnect:
+		return "connect"
+	case KindLogin:
+		return "login"
+	case KindQuery:
+		return "query"
+	case KindPacket:
+		return "packet"
+	case KindCommand:
+		return "command"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(kind))
+	}
+}
+
+// Attr is a key/value pair describing a span or event.
+type Attr struct {
+	Key   string
+	Value interface{}
+}
+
+func (attr Attr) String() string {
+	return fmt.Sprintf("%s=%v", attr.Key, attr.Value)
+}
+
+// DSNAttrs returns the attributes describing info. The password is
+// never included.
+func DSNAttrs(info *dsn.Info) []Attr {
+	return []Attr{
+		{"host", info.Host},
+		{"port", info.Port},
+		{"username", info.Username},
+		{"database", info.Database},
+	}
+}
+
+// Tracer receives spans and events.
+//
+// Tracers must be safe for concurrent use.
+type Tracer interface {
+	SpanStart(ctx context.Context, span *Span)
+	SpanEnd(ctx context.Context, span *Span)
+	Event(ctx context.Context, event *Event)
+}
+
+// tracerHolder allows to store a nil Tracer in an atomic.Value.
+type tracerHolder struct {
+	tracer Tracer
+}
+
+var globalTracer atomic.Value
+
+// SetTracer sets the global Tracer. Passing nil disables tracing.
+func SetTracer(tracer Tracer) {
+	globalTracer.Store(tracerHolder{tracer: tracer})
+}
+
+type contextKey int
+
+const (
+	tracerKey contextKey = iota
+	spanKey
+	traceIDKey
+)
+
+// WithTracer returns a context whose spans and events are reported to
+// tracer instead of the global Tracer.
+func WithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey, tracerHolder{tracer: tracer})
+}
+
+// tracerFrom returns the Tracer for ctx.
+func tracerFrom(ctx context.Context) Tracer {
+	if ctx != nil {
+		if holder, ok := ctx.Value(tracerKey).(tracerHolder); ok {
+			return holder.tracer
+		}
+	}
+
+	holder, _ := globalTracer.Load().(tracerHolder)
+	return holder.tracer
+}
+
+// WithTraceID returns a context carrying the id of the trace of an
+// external tracing system, e.g. to correlate the requests of a trace
+// with monitoring data of the server.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey, id)
+}
+
+// TraceID returns the trace id set with WithTraceID or an empty
+// string.
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(traceIDKey).(string)
+	return id
+}
+
+// Enabled reports whether spans and events of ctx are reported. It
+// allows to skip preparing attributes if tracing is disabled.
+func Enabled(ctx context.Context) bool {
+	return tracerFrom(ctx) != nil
+}
+
+// Span is an operation with a duration.
+type Span struct {
+	Kind      Kind
+	Name      string
+	Attrs     []Attr
+	Parent    *Span
+	StartTime time.Time
+	EndTime   time.Time
+	Err       error
+
+	ctx    context.Context
+	tracer Tracer
+}
+
+// Start starts a span and returns a context carrying the span as
+// parent for further spans and events.
+//
+// If tracing is disabled ctx and a nil *Span are returned.
+func Start(ctx context.Context, kind Kind, name string, attrs ...Attr) (context.Context, *Span) {
+	tracer := tracerFrom(ctx)
+	if tracer == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		Kind:      kind,
+		Name:      name,
+		Attrs:     attrs,
+		Parent:    SpanFromContext(ctx),
+		StartTime: time.Now(),
+		tracer:    tracer,
+	}
+
+	span.ctx = context.WithValue(ctx, spanKey, span)
+	tracer.SpanStart(span.ctx, span)
+
+	return span.ctx, span
+}
+
+// SpanFromContext returns the span started last in ctx or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+
+	span, _ := ctx.Value(spanKey).(*Span)
+	return span
+}
+
+// SetAttr adds an attribute to the span.
+func (span *Span) SetAttr(key string, value interface{}) {
+	if span == nil {
+		return
+	}
+
+	span.Attrs = append(span.Attrs, Attr{key, value})
+}
+
+// End ends the span with the result err of the operation.
+func (span *Span) End(err error) {
+	if span == nil {
+		return
+	}
+
+	span.EndTime = time.Now()
+	span.Err = err
+	span.tracer.SpanEnd(span.ctx, span)
+}
+
+// Duration returns the duration of an ended span.
+func (span *Span) Duration() time.Duration {
+	if span == nil {
+		return 0
+	}
+
+	return span.EndTime.Sub(span.StartTime)
+}
+
+// Event is an operation without a duration.
+type Event struct {
+	Kind  Kind
+	Name  string
+	Attrs []Attr
+	Span  *Span
+	Time  time.Time
+}
+
+// Emit reports an event.
+func Emit(ctx context.Context, kind Kind, name string, attrs ...Attr) {
+	tracer := tracerFrom(ctx)
+	if tracer == nil {
+		return
+	}
+
+	tracer.Event(ctx, &Event{
+		Kind:  kind,
+		Name:  name,
+		Attrs: attrs,
+		Span:  SpanFromContext(ctx),
+		Time:  time.Now(),
+	})
+}
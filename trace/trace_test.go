@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/SAP/go-dblib/dsn"
+)
+
+type recorder struct {
+	started, ended []*Span
+	events         []*Event
+}
+
+func (rec *recorder) hooks() Hooks {
+	return Hooks{
+		OnSpanStart: func(ctx context.Context, span *Span) { rec.started = append(rec.started, span) },
+		OnSpanEnd:   func(ctx context.Context, span *Span) { rec.ended = append(rec.ended, span) },
+		OnEvent:     func(ctx context.Context, event *Event) { rec.events = append(rec.events, event) },
+	}
+}
+
+func TestStart_Disabled(t *testing.T) {
+	ctx := context.Background()
+
+	if Enabled(ctx) {
+		t.Errorf("Expected tracing to be disabled")
+	}
+
+	spanCtx, span := Start(ctx, KindQuery, "query")
+	if span != nil {
+		t.Errorf("Expected nil span, received %v", span)
+	}
+
+	if spanCtx != ctx {
+		t.Errorf("Expected passed context to be returned")
+	}
+
+	// Methods of nil spans are no-ops.
+	span.SetAttr("key", "value")
+	span.End(nil)
+}
+
+func TestWithTracer(t *testing.T) {
+	rec := &recorder{}
+	ctx := WithTracer(context.Background(), rec.hooks())
+
+	ctx, parent := Start(ctx, KindLogin, "login", Attr{"username", "sa"})
+	childCtx, child := Start(ctx, KindQuery, "query")
+	Emit(childCtx, KindPacket, "send", Attr{"length", 512})
+	child.End(nil)
+
+	expectedErr := errors.New("login failed")
+	parent.End(expectedErr)
+
+	if len(rec.started) != 2 || len(rec.ended) != 2 || len(rec.events) != 1 {
+		t.Fatalf("Expected two spans and one event, received %d/%d spans and %d events",
+			len(rec.started), len(rec.ended), len(rec.events))
+	}
+
+	if child.Parent != parent {
+		t.Errorf("Expected parent span to be set")
+	}
+
+	if rec.events[0].Span != child {
+		t.Errorf("Expected event to reference enclosing span")
+	}
+
+	if !errors.Is(parent.Err, expectedErr) {
+		t.Errorf("Expected error to be recorded, received: %v", parent.Err)
+	}
+
+	if parent.Duration() < 0 {
+		t.Errorf("Expected non-negative duration, received %s", parent.Duration())
+	}
+}
+
+func TestSetTracer(t *testing.T) {
+	rec := &recorder{}
+	SetTracer(rec.hooks())
+	defer SetTracer(nil)
+
+	_, span := Start(context.Background(), KindConnect, "dial")
+	span.End(nil)
+
+	if len(rec.ended) != 1 {
+		t.Errorf("Expected span to be reported to global tracer")
+	}
+
+	// Tracers set on the context take precedence.
+	ctx := WithTracer(context.Background(), nil)
+	if Enabled(ctx) {
+		t.Errorf("Expected tracing to be disabled by context")
+	}
+}
+
+func TestLogTracer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tracer := &LogTracer{Logger: log.New(buf, "", 0)}
+	ctx := WithTracer(context.Background(), tracer)
+
+	_, span := Start(ctx, KindCommand, "command", Attr{"line", "select 1"})
+	Emit(ctx, KindPacket, "send")
+	span.End(errors.New("failed"))
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "command command line=select 1 duration=") {
+		t.Errorf("Unexpected output: %q", out)
+	}
+
+	if !strings.HasSuffix(out, " error=failed\n") {
+		t.Errorf("Expected error in output: %q", out)
+	}
+
+	if strings.Contains(out, "packet") {
+		t.Errorf("Expected packet events to be skipped: %q", out)
+	}
+}
+
+func TestDSNAttrs(t *testing.T) {
+	info := dsn.NewInfo()
+	info.Host = "localhost"
+	info.Password = "secret"
+
+	for _, attr := range DSNAttrs(info) {
+		if attr.Value == "secret" {
+			t.Errorf("Password included in attribute %s", attr.Key)
+		}
+	}
+}
+
+func TestMulti(t *testing.T) {
+	rec1, rec2 := &recorder{}, &recorder{}
+	ctx := WithTracer(context.Background(), Multi(rec1.hooks(), rec2.hooks()))
+
+	_, span := Start(ctx, KindQuery, "query")
+	Emit(ctx, KindPacket, "send")
+	span.End(nil)
+
+	for i, rec := range []*recorder{rec1, rec2} {
+		if len(rec.started) != 1 || len(rec.ended) != 1 || len(rec.events) != 1 {
+			t.Errorf("Expected tracer %d to receive span and event", i)
+		}
+	}
+}
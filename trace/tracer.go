@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// Hooks implements Tracer by calling the set functions. Unset
+// functions are skipped.
+type Hooks struct {
+	OnSpanStart func(ctx context.Context, span *Span)
+	OnSpanEnd   func(ctx context.Context, span *Span)
+	OnEvent     func(ctx context.Context, event *Event)
+}
+
+// SpanStart implements the Tracer interface.
+func (hooks Hooks) SpanStart(ctx context.Context, span *Span) {
+	if hooks.OnSpanStart != nil {
+		hooks.OnSpanStart(ctx, span)
+	}
+}
+
+// SpanEnd implements the Tracer interface.
+func (hooks Hooks) SpanEnd(ctx context.Context, span *Span) {
+	if hooks.OnSpanEnd != nil {
+		hooks.OnSpanEnd(ctx, span)
+	}
+}
+
+// Event implements the Tracer interface.
+func (hooks Hooks) Event(ctx context.Context, event *Event) {
+	if hooks.OnEvent != nil {
+		hooks.OnEvent(ctx, event)
+	}
+}
+
+// multi is a Tracer forwarding to multiple Tracers.
+type multi []Tracer
+
+// Multi returns a Tracer forwarding spans and events to all passed
+// Tracers in order.
+func Multi(tracers ...Tracer) Tracer {
+	return multi(tracers)
+}
+
+// SpanStart implements the Tracer interface.
+func (tracers multi) SpanStart(ctx context.Context, span *Span) {
+	for _, tracer := range tracers {
+		tracer.SpanStart(ctx, span)
+	}
+}
+
+// SpanEnd implements the Tracer interface.
+func (tracers multi) SpanEnd(ctx context.Context, span *Span) {
+	for _, tracer := range tracers {
+		tracer.SpanEnd(ctx, span)
+	}
+}
+
+// Event implements the Tracer interface.
+func (tracers multi) Event(ctx context.Context, event *Event) {
+	for _, tracer := range tracers {
+		tracer.Event(ctx, event)
+	}
+}
+
+// LogTracer logs ended spans and events.
+type LogTracer struct {
+	// Logger is the logger to write to. Defaults to the standard
+	// logger.
+	Logger *log.Logger
+	// Packets enables logging events of KindPacket.
+	Packets bool
+}
+
+// SpanStart implements the Tracer interface.
+func (tracer *LogTracer) SpanStart(ctx context.Context, span *Span) {}
+
+// SpanEnd implements the Tracer interface.
+func (tracer *LogTracer) SpanEnd(ctx context.Context, span *Span) {
+	msg := span.Kind.String() + " " + span.Name + formatAttrs(span.Attrs) +
+		" duration=" + span.Duration().String()
+	if span.Err != nil {
+		msg += " error=" + span.Err.Error()
+	}
+
+	tracer.output(msg)
+}
+
+// Event implements the Tracer interface.
+func (tracer *LogTracer) Event(ctx context.Context, event *Event) {
+	if event.Kind == KindPacket && !tracer.Packets {
+		return
+	}
+
+	tracer.output(event.Kind.String() + " " + event.Name + formatAttrs(event.Attrs))
+}
+
+func (tracer *LogTracer) output(msg string) {
+	if tracer.Logger == nil {
+		log.Print(msg)
+		return
+	}
+
+	tracer.Logger.Print(msg)
+}
+
+func formatAttrs(attrs []Attr) string {
+	sb := &strings.Builder{}
+	for _, attr := range attrs {
+		sb.WriteString(" ")
+		sb.WriteString(attr.String())
+	}
+	return sb.String()
+}
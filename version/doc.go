@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package version provides the versions of the TDS protocol and
+// parses and compares versions of ASE servers.
+//
+// Server versions can be parsed from @@version or from the program
+// version sent by the server in the login acknowledgement:
+//
+//	v, err := version.Parse("Adaptive Server Enterprise/16.0 SP03 PL02/...")
+//	if err != nil {
+//		return err
+//	}
+//
+//	if v.AtLeast(version.Version{Major: 16, ServicePack: 3}) {
+//		// Use feature introduced in 16.0 SP03
+//	}
+package version
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Version is a version consisting of four parts, e.g. of the TDS
+// protocol or of an ASE server.
+type Version struct {
+	Major, Minor, ServicePack, PatchLevel int
+}
+
+// Versions of the TDS protocol.
+var (
+	TDS42 = Version{Major: 4, Minor: 2}
+	TDS46 = Version{Major: 4, Minor: 6}
+	TDS50 = Version{Major: 5}
+)
+
+var reVersion = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:\s+SP(\d+))?(?:\s+PL(\d+))?`)
+
+// Parse parses the first version found in s. Recognized are versions in
+// the form "<major>.<minor>[ SP<sp>][ PL<pl>]" as found in @@version
+// and dotted versions in the form "<major>.<minor>[.<sp>[.<pl>]]".
+//
+// Additional text, e.g. the full @@version string, is ignored.
+func Parse(s string) (Version, error) {
+	match := reVersion.FindStringSubmatch(s)
+	if match == nil {
+		return Version{}, fmt.Errorf("no version found in '%s'", s)
+	}
+
+	parts := make([]int, len(match)-1)
+	for i, part := range match[1:] {
+		if part == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("error parsing version '%s': %w", s, err)
+		}
+		parts[i] = n
+	}
+
+	v := Version{Major: parts[0], Minor: parts[1], ServicePack: parts[2], PatchLevel: parts[3]}
+
+	// Service pack and patch level in the form of @@version take
+	// precedence over the dotted form.
+	if match[5] != "" {
+		v.ServicePack = parts[4]
+	}
+
+	if match[6] != "" {
+		v.PatchLevel = parts[5]
+	}
+
+	return v, nil
+}
+
+// FromBytes returns the version encoded in four bytes as sent in login
+// requests and acknowledgements.
+func FromBytes(bs []byte) (Version, error) {
+	if len(bs) != 4 {
+		return Version{}, fmt.Errorf("expected 4 byte array, received %d byte array: %v", len(bs), bs)
+	}
+
+	return Version{
+		Major:       int(bs[0]),
+		Minor:       int(bs[1]),
+		ServicePack: int(bs[2]),
+		PatchLevel:  int(bs[3]),
+	}, nil
+}
+
+// Compare returns 0 if v and other are equal, -1 if v is older than
+// other and 1 if v is newer than other.
+func (v Version) Compare(other Version) int {
+	a := []int{v.Major, v.Minor, v.ServicePack, v.PatchLevel}
+	b := []int{other.Major, other.Minor, other.ServicePack, other.PatchLevel}
+
+	for i := range a {
+		if a[i] < b[i] {
+			return -1
+		}
+
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// Less returns true if v is older than other.
+func (v Version) Less(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+// AtLeast returns true if v is the same as or newer than other.
+func (v Version) AtLeast(other Version) bool {
+	return v.Compare(other) >= 0
+}
+
+// IsZero returns true if v has not been set.
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// String returns the version in the form
+// "<major>.<minor> SP<sp> PL<pl>" as used by ASE.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d SP%02d PL%02d", v.Major, v.Minor, v.ServicePack, v.PatchLevel)
+}
+
+// Bytes returns the version encoded in four bytes.
+func (v Version) Bytes() []byte {
+	return []byte{byte(v.Major), byte(v.Minor), byte(v.ServicePack), byte(v.PatchLevel)}
+}
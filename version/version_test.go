@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2020 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		s       string
+		version Version
+	}{
+		"@@version": {
+			s:       "Adaptive Server Enterprise/16.0 SP03 PL02/EBF 27413 SMP/P/x86_64/SLES 11.1/ase160sp03pl02x/3096/64-bit/FBO/Wed Jul 17 03:37:05 2019",
+			version: Version{Major: 16, Minor: 0, ServicePack: 3, PatchLevel: 2},
+		},
+		"without patch level": {
+			s:       "16.0 SP04",
+			version: Version{Major: 16, Minor: 0, ServicePack: 4},
+		},
+		"major and minor": {
+			s:       "15.7",
+			version: Version{Major: 15, Minor: 7},
+		},
+		"dotted": {
+			s:       "16.0.3.2",
+			version: Version{Major: 16, Minor: 0, ServicePack: 3, PatchLevel: 2},
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			recv, err := Parse(cas.s)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if recv != cas.version {
+				t.Errorf("Expected %s, received %s", cas.version, recv)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, err := Parse("no version"); err == nil {
+		t.Errorf("Expected error parsing string without version")
+	}
+}
+
+func TestFromBytes(t *testing.T) {
+	recv, err := FromBytes([]byte{5, 0, 0, 0})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+		return
+	}
+
+	if recv != TDS50 {
+		t.Errorf("Expected %s, received %s", TDS50, recv)
+	}
+
+	if _, err := FromBytes([]byte{5, 0}); err == nil {
+		t.Errorf("Expected error parsing two bytes")
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	cases := map[string]struct {
+		a, b   Version
+		result int
+	}{
+		"equal":       {Version{16, 0, 3, 2}, Version{16, 0, 3, 2}, 0},
+		"major":       {Version{15, 7, 0, 0}, Version{16, 0, 0, 0}, -1},
+		"minor":       {Version{15, 7, 0, 0}, Version{15, 5, 0, 0}, 1},
+		"servicepack": {Version{16, 0, 2, 9}, Version{16, 0, 3, 0}, -1},
+		"patchlevel":  {Version{16, 0, 3, 3}, Version{16, 0, 3, 2}, 1},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := cas.a.Compare(cas.b); recv != cas.result {
+				t.Errorf("Expected %d comparing %s and %s, received %d", cas.result, cas.a, cas.b, recv)
+			}
+
+			if cas.a.Less(cas.b) != (cas.result < 0) {
+				t.Errorf("Less returned %t for %s and %s", cas.a.Less(cas.b), cas.a, cas.b)
+			}
+
+			if cas.a.AtLeast(cas.b) != (cas.result >= 0) {
+				t.Errorf("AtLeast returned %t for %s and %s", cas.a.AtLeast(cas.b), cas.a, cas.b)
+			}
+		})
+	}
+}